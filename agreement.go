@@ -0,0 +1,205 @@
+package datautils
+
+import (
+	"math"
+	"sort"
+)
+
+// CohensKappa calculates Cohen's kappa measuring the agreement between two raters who have each assigned one
+// of a set of categories to the same items, where a[i] and b[i] are the categories assigned to item i by each
+// rater.  Kappa corrects the observed proportion of agreement for the agreement expected by chance given each
+// rater's distribution of categories.  1 represents perfect agreement and 0 agreement no better than chance.  If
+// both raters assign every item the same single category, the agreement expected by chance is 1 and kappa is
+// undefined (NaN).  See ConfusionMatrix.Kappa for agreement between binary predictions and labels.
+func CohensKappa(a, b []string) float64 {
+	if len(a) != len(b) {
+		panic(ErrLengthMismatch)
+	}
+
+	countsA := make(map[string]float64)
+	countsB := make(map[string]float64)
+	var agreed float64
+	for i := range a {
+		countsA[a[i]]++
+		countsB[b[i]]++
+		if a[i] == b[i] {
+			agreed++
+		}
+	}
+
+	n := float64(len(a))
+	var expected float64
+	for _, c := range sortedKeys(countsA) {
+		expected += countsA[c] / n * countsB[c] / n
+	}
+	observed := agreed / n
+	return (observed - expected) / (1 - expected)
+}
+
+// FleissKappa calculates Fleiss' kappa measuring the agreement between multiple raters who have each assigned one
+// of a set of categories to the same items, where annotations[i] contains the categories assigned to item i by
+// each of the raters.  Every item must have been rated by the same number (at least 2) of raters although the
+// raters need not be the same individuals for every item.  As with CohensKappa, 1 represents perfect agreement
+// and 0 agreement no better than chance and if every rating is the same category, kappa is undefined (NaN).
+func FleissKappa(annotations [][]string) float64 {
+	if len(annotations) == 0 {
+		return math.NaN()
+	}
+	raters := len(annotations[0])
+	if raters < 2 {
+		panic("datautils: each item must be rated by at least 2 raters")
+	}
+
+	totals := make(map[string]float64)
+	var agreement float64
+	for _, item := range annotations {
+		if len(item) != raters {
+			panic(ErrLengthMismatch)
+		}
+		counts := make(map[string]float64)
+		for _, c := range item {
+			counts[c]++
+			totals[c]++
+		}
+		var pairs float64
+		for _, c := range sortedKeys(counts) {
+			pairs += counts[c] * (counts[c] - 1)
+		}
+		agreement += pairs / float64(raters*(raters-1))
+	}
+
+	n := float64(len(annotations) * raters)
+	var expected float64
+	for _, c := range sortedKeys(totals) {
+		expected += (totals[c] / n) * (totals[c] / n)
+	}
+	observed := agreement / float64(len(annotations))
+	return (observed - expected) / (1 - expected)
+}
+
+// sortedKeys returns the keys of m in sorted order so that sums over the map are deterministic.
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// AlphaMetric specifies the level of measurement of the values being rated, determining the difference function
+// used to calculate Krippendorff's alpha.
+type AlphaMetric int
+
+const (
+	// NominalAlpha treats values as unordered categories so that any two different values disagree equally
+	NominalAlpha AlphaMetric = iota
+
+	// OrdinalAlpha treats values as ordered categories (ranks) so that the disagreement between two values depends
+	// upon how many values were rated between them
+	OrdinalAlpha
+
+	// IntervalAlpha treats values as points on an interval scale so that the disagreement between two values is
+	// their squared difference
+	IntervalAlpha
+)
+
+// KrippendorffsAlpha calculates Krippendorff's alpha measuring the agreement between multiple raters, where
+// annotations[i][j] is the value assigned to item i by rater j.  Unlike Fleiss' kappa, raters need not have rated
+// every item and missing ratings should be specified as NaN.  Items rated by fewer than 2 raters are not pairable
+// and are ignored.  Categorical annotations may be converted to numeric values using a LabelEncoder with metric
+// specifying how differences between values are measured (see NominalAlpha, OrdinalAlpha and IntervalAlpha).  1
+// represents perfect agreement and 0 agreement no better than chance.  If all the pairable values are the same,
+// alpha is undefined (NaN).
+func KrippendorffsAlpha(annotations [][]float64, metric AlphaMetric) float64 {
+	// collect the pairable values of each item and the distinct values across all items
+	var units [][]float64
+	index := make(map[float64]int)
+	var values []float64
+	for _, item := range annotations {
+		var unit []float64
+		for _, v := range item {
+			if !math.IsNaN(v) {
+				unit = append(unit, v)
+			}
+		}
+		if len(unit) < 2 {
+			continue
+		}
+		for _, v := range unit {
+			if _, ok := index[v]; !ok {
+				index[v] = 0
+				values = append(values, v)
+			}
+		}
+		units = append(units, unit)
+	}
+	sort.Float64s(values)
+	for i, v := range values {
+		index[v] = i
+	}
+
+	// build the coincidence matrix of pairs of values assigned to the same item
+	coincidences := make([][]float64, len(values))
+	for i := range coincidences {
+		coincidences[i] = make([]float64, len(values))
+	}
+	for _, unit := range units {
+		w := 1 / float64(len(unit)-1)
+		for i, a := range unit {
+			for j, b := range unit {
+				if i != j {
+					coincidences[index[a]][index[b]] += w
+				}
+			}
+		}
+	}
+	marginals := make([]float64, len(values))
+	var n float64
+	for c := range coincidences {
+		for k := range coincidences[c] {
+			marginals[c] += coincidences[c][k]
+		}
+		n += marginals[c]
+	}
+
+	delta := func(c, k int) float64 {
+		switch metric {
+		case NominalAlpha:
+			if c == k {
+				return 0
+			}
+			return 1
+		case OrdinalAlpha:
+			if c > k {
+				c, k = k, c
+			}
+			var sum float64
+			for g := c; g <= k; g++ {
+				sum += marginals[g]
+			}
+			d := sum - (marginals[c]+marginals[k])/2
+			return d * d
+		case IntervalAlpha:
+			d := values[c] - values[k]
+			return d * d
+		default:
+			panic("datautils: unknown alpha metric")
+		}
+	}
+
+	var observed, expected float64
+	for c := range values {
+		for k := range values {
+			d := delta(c, k)
+			observed += coincidences[c][k] * d
+			expected += marginals[c] * marginals[k] * d
+		}
+	}
+	observed /= n
+	expected /= n * (n - 1)
+	if expected == 0 {
+		return math.NaN()
+	}
+	return 1 - observed/expected
+}
@@ -0,0 +1,104 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestCohensKappa(t *testing.T) {
+	// 20 items rated yes by both raters, 5 yes/no, 10 no/yes and 15 no by both raters
+	var a, b []string
+	for _, cell := range []struct {
+		a, b  string
+		count int
+	}{{"yes", "yes", 20}, {"yes", "no", 5}, {"no", "yes", 10}, {"no", "no", 15}} {
+		for i := 0; i < cell.count; i++ {
+			a = append(a, cell.a)
+			b = append(b, cell.b)
+		}
+	}
+
+	tests := []struct {
+		a, b  []string
+		kappa float64
+	}{
+		{a: a, b: b, kappa: 0.4},
+		{a: []string{"x", "y", "z"}, b: []string{"x", "y", "z"}, kappa: 1},
+		{a: []string{"x", "x"}, b: []string{"x", "x"}, kappa: math.NaN()},
+	}
+
+	for i, test := range tests {
+		kappa := datautils.CohensKappa(test.a, test.b)
+		if math.IsNaN(test.kappa) != math.IsNaN(kappa) || math.Abs(kappa-test.kappa) > 1e-12 {
+			t.Errorf("Test %d: Expected kappa: %v but received %v", i+1, test.kappa, kappa)
+		}
+	}
+}
+
+func TestFleissKappa(t *testing.T) {
+	// 10 items each rated by 14 raters into 5 categories (Fleiss, 1971)
+	counts := [][]int{
+		{0, 0, 0, 0, 14},
+		{0, 2, 6, 4, 2},
+		{0, 0, 3, 5, 6},
+		{0, 3, 9, 2, 0},
+		{2, 2, 8, 1, 1},
+		{7, 7, 0, 0, 0},
+		{3, 2, 6, 3, 0},
+		{2, 5, 3, 2, 2},
+		{6, 5, 2, 1, 0},
+		{0, 2, 2, 3, 7},
+	}
+	categories := []string{"a", "b", "c", "d", "e"}
+	annotations := make([][]string, len(counts))
+	for i, row := range counts {
+		for c, n := range row {
+			for j := 0; j < n; j++ {
+				annotations[i] = append(annotations[i], categories[c])
+			}
+		}
+	}
+
+	expected := 0.20993
+	if kappa := datautils.FleissKappa(annotations); math.Abs(kappa-expected) > 1e-5 {
+		t.Errorf("Expected kappa: %v but received %v", expected, kappa)
+	}
+}
+
+func TestKrippendorffsAlpha(t *testing.T) {
+	// reliability data for 12 items rated by 4 raters with missing ratings (Krippendorff, 2011)
+	nan := math.NaN()
+	raters := [][]float64{
+		{1, 2, 3, 3, 2, 1, 4, 1, 2, nan, nan, nan},
+		{1, 2, 3, 3, 2, 2, 4, 1, 2, 5, nan, 3},
+		{nan, 3, 3, 3, 2, 3, 4, 2, 2, 5, 1, nan},
+		{1, 2, 3, 3, 2, 4, 4, 1, 2, 5, 1, nan},
+	}
+	annotations := make([][]float64, len(raters[0]))
+	for i := range annotations {
+		for _, r := range raters {
+			annotations[i] = append(annotations[i], r[i])
+		}
+	}
+
+	tests := []struct {
+		metric datautils.AlphaMetric
+		alpha  float64
+	}{
+		{metric: datautils.NominalAlpha, alpha: 0.743},
+		{metric: datautils.OrdinalAlpha, alpha: 0.815},
+		{metric: datautils.IntervalAlpha, alpha: 0.849},
+	}
+
+	for i, test := range tests {
+		if alpha := datautils.KrippendorffsAlpha(annotations, test.metric); math.Abs(alpha-test.alpha) > 5e-4 {
+			t.Errorf("Test %d: Expected alpha: %v but received %v", i+1, test.alpha, alpha)
+		}
+	}
+
+	if alpha := datautils.KrippendorffsAlpha([][]float64{{1, 1}, {1, nan}}, datautils.NominalAlpha); !math.IsNaN(alpha) {
+		t.Errorf("Expected alpha: NaN but received %v", alpha)
+	}
+}
@@ -0,0 +1,449 @@
+// Package arrowio reads Apache Arrow IPC and Parquet files into datautils Datasets so that data held in columnar
+// feature stores can be evaluated without first converting it to CSV.  Datasets may be read whole or, for files
+// too large to hold in memory, a chunk (record batch) at a time e.g.
+//
+//	f, err := os.Open("features.parquet")
+//	...
+//	r, err := arrowio.NewParquetReader(f, arrowio.Options{LabelColumn: "label", BatchSize: 100000})
+//	...
+//	defer r.Release()
+//	for r.Next() {
+//		chunk := r.Dataset()
+//		...
+//	}
+//	if err := r.Err(); err != nil {
+//		...
+//	}
+package arrowio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+// DefaultBatchSize is the number of rows read from a Parquet file per chunk if Options.BatchSize is not specified.
+const DefaultBatchSize = 64 * 1024
+
+// Options configures which columns are read into a Dataset and how they are read.
+type Options struct {
+	// LabelColumn is the name of the (numeric) column containing the labels.  If empty, the data is assumed to be
+	// unlabelled
+	LabelColumn string
+
+	// Columns are the names of the numeric columns to read as features, in order.  If nil, all numeric columns
+	// apart from the label column are read as features in schema order and any other (non-numeric) columns not
+	// listed within CategoricalColumns are ignored
+	Columns []string
+
+	// CategoricalColumns are the names of the columns whose values are read, as strings, into
+	// Dataset.Categorical rather than Features.  Null values are read as empty strings
+	CategoricalColumns []string
+
+	// BatchSize is the maximum number of rows read from a Parquet file per chunk.  If 0, DefaultBatchSize is used.
+	// The chunks of Arrow IPC files are the record batches written to the file and so BatchSize does not apply
+	BatchSize int
+}
+
+// recordReader is the subset of array.RecordReader used by Reader allowing other sources of records (e.g. the
+// record batches of an Arrow IPC file) to be adapted.
+type recordReader interface {
+	Next() bool
+	Record() arrow.Record
+	Err() error
+	Release()
+}
+
+// Reader reads a Dataset from a stream of Arrow records one chunk (record batch) at a time allowing datasets too
+// large to hold in memory to be processed incrementally, in the same way as bufio.Scanner.  Numeric columns of any
+// integer, floating point or boolean (read as 1 or 0) type may be read as features or labels with null values read
+// as NaN.  Only flat (non-nested) schemas are supported.  A Reader is not safe for concurrent use.
+type Reader struct {
+	layout
+	records recordReader
+	chunk   datautils.Dataset
+	err     error
+}
+
+// NewReader creates a new Reader of the records from rr, with the specified schema, according to opts.  The Reader
+// takes ownership of rr which is released along with the Reader.
+func NewReader(rr array.RecordReader, opts Options) (*Reader, error) {
+	return newReader(rr, rr.Schema(), opts)
+}
+
+// NewIPCReader creates a new Reader of the Arrow IPC stream format data read from r.  Each chunk read is a single
+// record batch of the stream.
+func NewIPCReader(r io.Reader, opts Options) (*Reader, error) {
+	rr, err := ipc.NewReader(r, ipc.WithAllocator(memory.DefaultAllocator))
+	if err != nil {
+		return nil, fmt.Errorf("arrowio: failed to read Arrow IPC stream: %w", err)
+	}
+	return newReader(rr, rr.Schema(), opts)
+}
+
+// NewIPCFileReader creates a new Reader of the Arrow IPC file format data (e.g. a .arrow or Feather V2 file) read
+// from r.  Each chunk read is a single record batch of the file.
+func NewIPCFileReader(r ipc.ReadAtSeeker, opts Options) (*Reader, error) {
+	f, err := ipc.NewFileReader(r, ipc.WithAllocator(memory.DefaultAllocator))
+	if err != nil {
+		return nil, fmt.Errorf("arrowio: failed to read Arrow IPC file: %w", err)
+	}
+	return newReader(&fileRecords{file: f}, f.Schema(), opts)
+}
+
+// NewParquetReader creates a new Reader of the Parquet data read from r.  Only the columns required by opts are
+// read from the file and each chunk contains up to opts.BatchSize rows.
+func NewParquetReader(r parquet.ReaderAtSeeker, opts Options) (*Reader, error) {
+	pf, err := file.NewParquetReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("arrowio: failed to read Parquet file: %w", err)
+	}
+	batchSize := opts.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: int64(batchSize)}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, fmt.Errorf("arrowio: failed to read Parquet file: %w", err)
+	}
+	schema, err := fr.Schema()
+	if err != nil {
+		return nil, fmt.Errorf("arrowio: failed to read Parquet schema: %w", err)
+	}
+
+	// resolve the columns against the full schema so that only the required columns are read from the file
+	proj, err := resolve(schema, opts)
+	if err != nil {
+		return nil, err
+	}
+	rr, err := fr.GetRecordReader(context.Background(), proj.indices(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("arrowio: failed to read Parquet file: %w", err)
+	}
+	return newReader(rr, rr.Schema(), opts)
+}
+
+// newReader creates a new Reader of the records from rr, with the specified schema, resolving the columns to read
+// according to opts.
+func newReader(rr recordReader, schema *arrow.Schema, opts Options) (*Reader, error) {
+	if opts.BatchSize < 0 {
+		rr.Release()
+		return nil, fmt.Errorf("arrowio: batch size must not be negative")
+	}
+	l, err := resolve(schema, opts)
+	if err != nil {
+		rr.Release()
+		return nil, err
+	}
+	return &Reader{layout: l, records: rr}, nil
+}
+
+// layout contains the indices, within a schema, of the columns to read into a Dataset.
+type layout struct {
+	// columns and features are the names and indices of the feature columns
+	columns  []string
+	features []int
+
+	// label is the index of the label column or -1 if unlabelled
+	label int
+
+	// names and categorical are the names and indices of the categorical columns
+	names       []string
+	categorical []int
+}
+
+// indices returns the indices of all the columns to be read in ascending order.
+func (l layout) indices() []int {
+	inds := append(append([]int(nil), l.features...), l.categorical...)
+	if l.label != -1 {
+		inds = append(inds, l.label)
+	}
+	sort.Ints(inds)
+
+	// remove columns read both as features and categorical values
+	var n int
+	for k, i := range inds {
+		if k == 0 || i != inds[n-1] {
+			inds[n] = i
+			n++
+		}
+	}
+	return inds[:n]
+}
+
+// resolve finds the columns of the schema to read as features, labels and categorical values according to opts.
+func resolve(schema *arrow.Schema, opts Options) (layout, error) {
+	l := layout{label: -1}
+	index := func(name, kind string) (int, error) {
+		inds := schema.FieldIndices(name)
+		if len(inds) == 0 {
+			return -1, fmt.Errorf("arrowio: %s column %q not found", kind, name)
+		}
+		return inds[0], nil
+	}
+
+	if opts.LabelColumn != "" {
+		i, err := index(opts.LabelColumn, "label")
+		if err != nil {
+			return l, err
+		}
+		if !numeric(schema.Field(i).Type) {
+			return l, fmt.Errorf("arrowio: label column %q has non-numeric type %s", opts.LabelColumn, schema.Field(i).Type.Name())
+		}
+		l.label = i
+	}
+
+	isCategorical := make(map[int]bool, len(opts.CategoricalColumns))
+	for _, name := range opts.CategoricalColumns {
+		i, err := index(name, "categorical")
+		if err != nil {
+			return l, err
+		}
+		l.categorical = append(l.categorical, i)
+		l.names = append(l.names, name)
+		isCategorical[i] = true
+	}
+
+	if opts.Columns != nil {
+		for _, name := range opts.Columns {
+			i, err := index(name, "feature")
+			if err != nil {
+				return l, err
+			}
+			if !numeric(schema.Field(i).Type) {
+				return l, fmt.Errorf("arrowio: feature column %q has non-numeric type %s", name, schema.Field(i).Type.Name())
+			}
+			l.features = append(l.features, i)
+			l.columns = append(l.columns, name)
+		}
+	} else {
+		for i, f := range schema.Fields() {
+			if i != l.label && !isCategorical[i] && numeric(f.Type) {
+				l.features = append(l.features, i)
+				l.columns = append(l.columns, f.Name)
+			}
+		}
+	}
+	if len(l.features) == 0 {
+		return l, fmt.Errorf("arrowio: no feature columns found")
+	}
+	return l, nil
+}
+
+// numeric returns true if values of the data type can be read as float64.
+func numeric(t arrow.DataType) bool {
+	switch t.ID() {
+	case arrow.BOOL, arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64, arrow.UINT8, arrow.UINT16, arrow.UINT32,
+		arrow.UINT64, arrow.FLOAT32, arrow.FLOAT64:
+		return true
+	}
+	return false
+}
+
+// value returns element i of the numeric array a as a float64 or NaN if the element is null.
+func value(a arrow.Array, i int) float64 {
+	if a.IsNull(i) {
+		return math.NaN()
+	}
+	switch a := a.(type) {
+	case *array.Float64:
+		return a.Value(i)
+	case *array.Float32:
+		return float64(a.Value(i))
+	case *array.Int64:
+		return float64(a.Value(i))
+	case *array.Int32:
+		return float64(a.Value(i))
+	case *array.Int16:
+		return float64(a.Value(i))
+	case *array.Int8:
+		return float64(a.Value(i))
+	case *array.Uint64:
+		return float64(a.Value(i))
+	case *array.Uint32:
+		return float64(a.Value(i))
+	case *array.Uint16:
+		return float64(a.Value(i))
+	case *array.Uint8:
+		return float64(a.Value(i))
+	case *array.Boolean:
+		if a.Value(i) {
+			return 1
+		}
+		return 0
+	}
+	panic(fmt.Sprintf("arrowio: unsupported array type %s", a.DataType().Name()))
+}
+
+// Columns returns the names of the feature columns read into each chunk.
+func (r *Reader) Columns() []string {
+	return r.columns
+}
+
+// Next reads the next non-empty chunk of records making it available through Dataset.  Next returns false once
+// there are no more records or an error occurs, after which Err should be checked.
+func (r *Reader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	for r.records.Next() {
+		rec := r.records.Record()
+		if rec.NumRows() == 0 {
+			continue
+		}
+		r.chunk = r.convert(rec)
+		return true
+	}
+	r.chunk = datautils.Dataset{}
+	if err := r.records.Err(); err != nil && err != io.EOF {
+		r.err = fmt.Errorf("arrowio: failed to read records: %w", err)
+	}
+	return false
+}
+
+// convert converts a record into a Dataset.
+func (r *Reader) convert(rec arrow.Record) datautils.Dataset {
+	n := int(rec.NumRows())
+	d := datautils.Dataset{
+		Features: mat.NewDense(n, len(r.features), nil),
+		Columns:  r.columns,
+	}
+	for j, c := range r.features {
+		col := rec.Column(c)
+		for i := 0; i < n; i++ {
+			d.Features.Set(i, j, value(col, i))
+		}
+	}
+	if r.label != -1 {
+		col := rec.Column(r.label)
+		d.Labels = make([]float64, n)
+		for i := range d.Labels {
+			d.Labels[i] = value(col, i)
+		}
+	}
+	if len(r.categorical) > 0 {
+		d.Categorical = make(map[string][]string, len(r.categorical))
+		for k, c := range r.categorical {
+			col := rec.Column(c)
+			values := make([]string, n)
+			for i := range values {
+				if !col.IsNull(i) {
+					values[i] = col.ValueStr(i)
+				}
+			}
+			d.Categorical[r.names[k]] = values
+		}
+	}
+	return d
+}
+
+// Dataset returns the chunk most recently read by Next.  The Dataset remains valid after subsequent calls to Next.
+func (r *Reader) Dataset() datautils.Dataset {
+	return r.chunk
+}
+
+// Err returns the first error encountered while reading records, if any.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// ReadAll reads all the remaining chunks and combines them into a single Dataset.
+func (r *Reader) ReadAll() (datautils.Dataset, error) {
+	var chunks []datautils.Dataset
+	var rows int
+	for r.Next() {
+		chunk := r.Dataset()
+		chunks = append(chunks, chunk)
+		n, _ := chunk.Features.Dims()
+		rows += n
+	}
+	if err := r.Err(); err != nil {
+		return datautils.Dataset{}, err
+	}
+	if rows == 0 {
+		return datautils.Dataset{}, fmt.Errorf("arrowio: no records found")
+	}
+
+	d := datautils.Dataset{
+		Features: mat.NewDense(rows, len(r.features), nil),
+		Columns:  r.columns,
+	}
+	if r.label != -1 {
+		d.Labels = make([]float64, 0, rows)
+	}
+	if len(r.categorical) > 0 {
+		d.Categorical = make(map[string][]string, len(r.categorical))
+	}
+	var offset int
+	for _, chunk := range chunks {
+		n, c := chunk.Features.Dims()
+		d.Features.Slice(offset, offset+n, 0, c).(*mat.Dense).Copy(chunk.Features)
+		offset += n
+		d.Labels = append(d.Labels, chunk.Labels...)
+		for _, name := range r.names {
+			d.Categorical[name] = append(d.Categorical[name], chunk.Categorical[name]...)
+		}
+	}
+	return d, nil
+}
+
+// Release releases the underlying record reader.  The Reader must not be used once released.
+func (r *Reader) Release() {
+	r.records.Release()
+}
+
+// ReadIPC reads all the records of the Arrow IPC stream format data read from r into a single Dataset.
+func ReadIPC(r io.Reader, opts Options) (datautils.Dataset, error) {
+	rd, err := NewIPCReader(r, opts)
+	if err != nil {
+		return datautils.Dataset{}, err
+	}
+	defer rd.Release()
+	return rd.ReadAll()
+}
+
+// ReadParquet reads all the rows of the Parquet data read from r into a single Dataset.
+func ReadParquet(r parquet.ReaderAtSeeker, opts Options) (datautils.Dataset, error) {
+	rd, err := NewParquetReader(r, opts)
+	if err != nil {
+		return datautils.Dataset{}, err
+	}
+	defer rd.Release()
+	return rd.ReadAll()
+}
+
+// fileRecords adapts the record batches of an Arrow IPC file to the recordReader interface.
+type fileRecords struct {
+	file *ipc.FileReader
+	next int
+	rec  arrow.Record
+	err  error
+}
+
+func (f *fileRecords) Next() bool {
+	if f.err != nil || f.next >= f.file.NumRecords() {
+		f.rec = nil
+		return false
+	}
+	f.rec, f.err = f.file.Record(f.next)
+	f.next++
+	return f.err == nil
+}
+
+func (f *fileRecords) Record() arrow.Record { return f.rec }
+
+func (f *fileRecords) Err() error { return f.err }
+
+func (f *fileRecords) Release() { f.file.Close() }
@@ -0,0 +1,192 @@
+package arrowio_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/james-bowman/datautils/arrowio"
+	"gonum.org/v1/gonum/mat"
+)
+
+var schema = arrow.NewSchema([]arrow.Field{
+	{Name: "score", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "segment", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "clicks", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	{Name: "label", Type: arrow.FixedWidthTypes.Boolean},
+}, nil)
+
+// newRecord builds a record of the schema from the specified column values.  Null clicks are represented by -1
+// and null segments by the empty string.
+func newRecord(scores []float64, segments []string, clicks []int64, labels []bool) arrow.Record {
+	mem := memory.NewGoAllocator()
+
+	s := array.NewFloat64Builder(mem)
+	defer s.Release()
+	s.AppendValues(scores, nil)
+
+	g := array.NewStringBuilder(mem)
+	defer g.Release()
+	for _, v := range segments {
+		if v == "" {
+			g.AppendNull()
+			continue
+		}
+		g.Append(v)
+	}
+
+	c := array.NewInt64Builder(mem)
+	defer c.Release()
+	for _, v := range clicks {
+		if v < 0 {
+			c.AppendNull()
+			continue
+		}
+		c.Append(v)
+	}
+
+	l := array.NewBooleanBuilder(mem)
+	defer l.Release()
+	l.AppendValues(labels, nil)
+
+	cols := []arrow.Array{s.NewArray(), g.NewArray(), c.NewArray(), l.NewArray()}
+	return array.NewRecord(schema, cols, int64(len(scores)))
+}
+
+func newRecordReader(t *testing.T) array.RecordReader {
+	records := []arrow.Record{
+		newRecord([]float64{0.9, 0.2}, []string{"a", "b"}, []int64{3, -1}, []bool{true, false}),
+		newRecord(nil, nil, nil, nil),
+		newRecord([]float64{0.6}, []string{""}, []int64{1}, []bool{true}),
+	}
+	rr, err := array.NewRecordReader(schema, records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rr
+}
+
+func TestReaderChunks(t *testing.T) {
+	r, err := arrowio.NewReader(newRecordReader(t), arrowio.Options{LabelColumn: "label", CategoricalColumns: []string{"segment"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Release()
+
+	if expected := []string{"score", "clicks"}; !reflect.DeepEqual(r.Columns(), expected) {
+		t.Errorf("Expected columns: %v but received %v", expected, r.Columns())
+	}
+
+	// the empty record batch is skipped
+	expected := []struct {
+		features    []float64
+		labels      []float64
+		categorical []string
+	}{
+		{features: []float64{0.9, 3, 0.2, math.NaN()}, labels: []float64{1, 0}, categorical: []string{"a", "b"}},
+		{features: []float64{0.6, 1}, labels: []float64{1}, categorical: []string{""}},
+	}
+	var chunks int
+	for r.Next() {
+		if chunks >= len(expected) {
+			t.Fatalf("Expected %d chunks but received more", len(expected))
+		}
+		d, e := r.Dataset(), expected[chunks]
+		if !equalWithNaN(flatten(d.Features), e.features) {
+			t.Errorf("Chunk %d: Expected features: %v but received %v", chunks+1, e.features, flatten(d.Features))
+		}
+		if !reflect.DeepEqual(d.Labels, e.labels) {
+			t.Errorf("Chunk %d: Expected labels: %v but received %v", chunks+1, e.labels, d.Labels)
+		}
+		if !reflect.DeepEqual(d.Categorical["segment"], e.categorical) {
+			t.Errorf("Chunk %d: Expected segments: %q but received %q", chunks+1, e.categorical, d.Categorical["segment"])
+		}
+		chunks++
+	}
+	if err := r.Err(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if chunks != len(expected) {
+		t.Errorf("Expected %d chunks but received %d", len(expected), chunks)
+	}
+}
+
+func TestReaderReadAll(t *testing.T) {
+	r, err := arrowio.NewReader(newRecordReader(t), arrowio.Options{Columns: []string{"clicks", "label"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Release()
+
+	d, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{3, 1, math.NaN(), 0, 1, 1}
+	if r, c := d.Features.Dims(); r != 3 || c != 2 || !equalWithNaN(flatten(d.Features), expected) {
+		t.Errorf("Expected 3 x 2 features: %v but received %d x %d: %v", expected, r, c, flatten(d.Features))
+	}
+	if d.Labels != nil || d.Categorical != nil {
+		t.Errorf("Expected unlabelled dataset without categorical columns but received %v and %v", d.Labels, d.Categorical)
+	}
+	if expected := []string{"clicks", "label"}; !reflect.DeepEqual(d.Columns, expected) {
+		t.Errorf("Expected columns: %v but received %v", expected, d.Columns)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	tests := []arrowio.Options{
+		{LabelColumn: "missing"},
+		{LabelColumn: "segment"},
+		{Columns: []string{"segment"}},
+		{CategoricalColumns: []string{"missing"}},
+		{Columns: []string{}},
+		{BatchSize: -1},
+	}
+
+	for i, opts := range tests {
+		if _, err := arrowio.NewReader(newRecordReader(t), opts); err == nil {
+			t.Errorf("Test %d: Expected error for options %+v", i+1, opts)
+		}
+	}
+
+	rr, err := array.NewRecordReader(schema, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := arrowio.NewReader(rr, arrowio.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Release()
+	if _, err := r.ReadAll(); err == nil {
+		t.Errorf("Expected error reading no records")
+	}
+}
+
+// flatten returns the elements of m in row major order.
+func flatten(m mat.Matrix) []float64 {
+	r, c := m.Dims()
+	s := make([]float64, 0, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			s = append(s, m.At(i, j))
+		}
+	}
+	return s
+}
+
+func equalWithNaN(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] && !(math.IsNaN(a[i]) && math.IsNaN(b[i])) {
+			return false
+		}
+	}
+	return true
+}
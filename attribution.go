@@ -0,0 +1,261 @@
+package datautils
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette/moreland"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// beeswarmBins is the number of bins across the range of attributions within which the points of a beeswarm plot
+// are stacked to avoid overlapping.
+const beeswarmBins = 100
+
+// MeanAbsoluteAttributions returns the mean absolute value of each column of a matrix of feature attributions
+// (e.g. SHAP values), with a row per observation and a column per feature, giving the overall importance of each
+// feature to a model's predictions.
+func MeanAbsoluteAttributions(attributions mat.Matrix) []float64 {
+	r, c := attributions.Dims()
+	means := make([]float64, c)
+	for j := range means {
+		for i := 0; i < r; i++ {
+			means[j] += math.Abs(attributions.At(i, j))
+		}
+		means[j] /= float64(r)
+	}
+	return means
+}
+
+// validateAttributions checks that the matrix of feature values (if specified) and the names of the features (if
+// specified) match the dimensions of the matrix of attributions.
+func validateAttributions(attributions, features mat.Matrix, names []string) error {
+	r, c := attributions.Dims()
+	if features != nil {
+		if fr, fc := features.Dims(); fr != r || fc != c {
+			return fmt.Errorf("datautils: %d x %d feature values specified for %d x %d attributions", fr, fc, r, c)
+		}
+	}
+	if names != nil && len(names) != c {
+		return fmt.Errorf("datautils: %d names specified for attributions of %d features", len(names), c)
+	}
+	return nil
+}
+
+// rankAttributions returns the indices of the features in descending order of mean absolute attribution, limited
+// to the first maxDisplay features if maxDisplay is greater than 0.
+func rankAttributions(attributions mat.Matrix, maxDisplay int) []int {
+	means := MeanAbsoluteAttributions(attributions)
+	order := allIndices(len(means))
+	sort.SliceStable(order, func(i, j int) bool { return means[order[i]] > means[order[j]] })
+	if maxDisplay > 0 && maxDisplay < len(order) {
+		order = order[:maxDisplay]
+	}
+	return order
+}
+
+// valueColors returns a function colouring values on a blue (low) to red (high) scale spanning the range of the
+// specified values.  NaN values are coloured grey.
+func valueColors(values []float64) func(v float64) color.Color {
+	min, max := valueRange(values)
+	cm := moreland.SmoothBlueRed()
+	cm.SetMin(min)
+	cm.SetMax(max)
+	return func(v float64) color.Color {
+		if math.IsNaN(v) {
+			return color.Gray{Y: 128}
+		}
+		c, err := cm.At(math.Max(min, math.Min(max, v)))
+		if err != nil {
+			return color.Gray{Y: 128}
+		}
+		return c
+	}
+}
+
+// PlotAttributionBar renders the mean absolute attribution of each feature (see MeanAbsoluteAttributions) as a bar
+// chart, in descending order, summarising the overall importance of the features to a model.  attributions is a
+// matrix of externally computed per observation feature attributions (e.g. SHAP values) with a row per observation
+// and a column per feature.  names contains the name of each feature and if nil, features are labelled by index.
+// If maxDisplay is greater than 0, only the maxDisplay most important features are shown.
+func PlotAttributionBar(attributions mat.Matrix, names []string, maxDisplay int) (*plot.Plot, error) {
+	if err := validateAttributions(attributions, nil, names); err != nil {
+		return nil, err
+	}
+	means := MeanAbsoluteAttributions(attributions)
+	order := rankAttributions(attributions, maxDisplay)
+
+	p, err := newPlot()
+	if err != nil {
+		return nil, err
+	}
+	p.Title.Text = PlotTheme.title("Feature Attributions")
+	p.Y.Label.Text = "Mean |Attribution|"
+
+	labels := make([]string, len(order))
+	values := make(plotter.Values, len(order))
+	for i, j := range order {
+		labels[i] = featureName(names, j)
+		values[i] = means[j]
+	}
+	if len(values) > 0 {
+		bars, err := plotter.NewBarChart(values, vg.Points(10))
+		if err != nil {
+			return nil, err
+		}
+		bars.Color = PlotTheme.Primary
+		bars.LineStyle.Width = 0
+		p.Add(bars)
+	}
+
+	p.X.Tick.Label.Rotation = 1.5
+	p.X.Tick.Label.XAlign = draw.XRight
+	p.X.Tick.Marker = ticks{labels: labels, n: len(order)}
+	return p, nil
+}
+
+// PlotAttributionBeeswarm renders a summary (beeswarm) plot of feature attributions showing, for each feature, the
+// attribution of every observation as a point coloured by the observation's value of the feature from blue (low)
+// to red (high).  Features are arranged from most important (see MeanAbsoluteAttributions) at the top to least
+// important at the bottom and points with similar attributions are stacked vertically so that their density is
+// visible.  This shows both the magnitude and direction of each feature's effect e.g. whether high values of the
+// feature increase or decrease predictions.  attributions and features are matrices, with a row per observation
+// and a column per feature, of externally computed attributions (e.g. SHAP values) and the corresponding feature
+// values.  names contains the name of each feature and if nil, features are labelled by index.  If maxDisplay is
+// greater than 0, only the maxDisplay most important features are shown.
+func PlotAttributionBeeswarm(attributions, features mat.Matrix, names []string, maxDisplay int) (*plot.Plot, error) {
+	if err := validateAttributions(attributions, features, names); err != nil {
+		return nil, err
+	}
+	r, _ := attributions.Dims()
+	order := rankAttributions(attributions, maxDisplay)
+
+	p, err := newPlot()
+	if err != nil {
+		return nil, err
+	}
+	p.Title.Text = PlotTheme.title("Feature Attributions")
+	p.X.Label.Text = "Attribution"
+
+	var all []float64
+	for _, j := range order {
+		all = append(all, mat.Col(nil, j, attributions)...)
+	}
+	min, max := valueRange(all)
+	width := (max - min) / beeswarmBins
+
+	labels := make([]string, len(order))
+	for rank, j := range order {
+		row := len(order) - 1 - rank
+		labels[row] = featureName(names, j)
+
+		attr := mat.Col(nil, j, attributions)
+		values := mat.Col(nil, j, features)
+		colorOf := valueColors(values)
+
+		// stack the points of each bin alternately above and below the feature's row in order of attribution
+		inds := allIndices(r)
+		sort.SliceStable(inds, func(a, b int) bool { return attr[inds[a]] < attr[inds[b]] })
+		offsets := make([]float64, r)
+		counts := make(map[int]int)
+		var maxCount int
+		for _, i := range inds {
+			bin := int((attr[i] - min) / width)
+			k := counts[bin]
+			counts[bin]++
+			if counts[bin] > maxCount {
+				maxCount = counts[bin]
+			}
+			offsets[i] = float64((k+1)/2) * float64(1-2*(k%2))
+		}
+		scale := 0.4 / math.Max(float64(maxCount)/2, 1)
+
+		pts := make(plotter.XYs, r)
+		for i := range pts {
+			pts[i].X, pts[i].Y = attr[i], float64(row)+offsets[i]*scale
+		}
+		s, err := plotter.NewScatter(pts)
+		if err != nil {
+			return nil, err
+		}
+		s.GlyphStyle.Radius = vg.Points(1.5)
+		s.GlyphStyle.Shape = draw.CircleGlyph{}
+		s.GlyphStyleFunc = func(i int) draw.GlyphStyle {
+			style := s.GlyphStyle
+			style.Color = colorOf(values[i])
+			return style
+		}
+		p.Add(s)
+	}
+
+	p.Y.Tick.Marker = ticks{labels: labels, n: len(order)}
+	return p, nil
+}
+
+// PlotAttributionDependence renders a dependence plot of a single feature, plotting the attribution of the feature
+// for each observation (y axis) against the observation's value of the feature (x axis) to show how the feature's
+// effect on predictions varies with its value.  If interaction is not negative, each point is coloured by the
+// observation's value of the interaction feature from blue (low) to red (high) revealing interactions between the
+// features.  attributions and features are matrices, with a row per observation and a column per feature, of
+// externally computed attributions (e.g. SHAP values) and the corresponding feature values.  names contains the
+// name of each feature and if nil, features are labelled by index.
+func PlotAttributionDependence(attributions, features mat.Matrix, names []string, feature, interaction int) (*plot.Plot, error) {
+	if err := validateAttributions(attributions, features, names); err != nil {
+		return nil, err
+	}
+	r, c := attributions.Dims()
+	if feature < 0 || feature >= c || interaction >= c {
+		return nil, fmt.Errorf("datautils: feature %d or interaction feature %d out of range for %d features", feature, interaction, c)
+	}
+
+	p, err := newPlot()
+	if err != nil {
+		return nil, err
+	}
+	name := featureName(names, feature)
+	title := "Dependence of " + name
+	if interaction >= 0 {
+		title += " (coloured by " + featureName(names, interaction) + ")"
+	}
+	p.Title.Text = PlotTheme.title(title)
+	p.X.Label.Text = name
+	p.Y.Label.Text = "Attribution for " + name
+
+	pts := make(plotter.XYs, 0, r)
+	var inds []int
+	for i := 0; i < r; i++ {
+		// observations missing a value for the feature cannot be positioned
+		if x := features.At(i, feature); !math.IsNaN(x) {
+			pts = append(pts, plotter.XY{X: x, Y: attributions.At(i, feature)})
+			inds = append(inds, i)
+		}
+	}
+	if len(pts) == 0 {
+		return p, nil
+	}
+
+	s, err := plotter.NewScatter(pts)
+	if err != nil {
+		return nil, err
+	}
+	s.GlyphStyle.Radius = vg.Points(1.5)
+	s.GlyphStyle.Shape = draw.CircleGlyph{}
+	s.GlyphStyle.Color = PlotTheme.Primary
+	if interaction >= 0 {
+		values := mat.Col(nil, interaction, features)
+		colorOf := valueColors(values)
+		s.GlyphStyleFunc = func(k int) draw.GlyphStyle {
+			style := s.GlyphStyle
+			style.Color = colorOf(values[inds[k]])
+			return style
+		}
+	}
+	p.Add(s)
+	return p, nil
+}
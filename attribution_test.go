@@ -0,0 +1,60 @@
+package datautils_test
+
+import (
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	attributions = mat.NewDense(4, 3, []float64{
+		0.5, -0.1, 0,
+		-1.5, 0.1, 0,
+		1, 0.2, 0.1,
+		-1, -0.2, -0.1,
+	})
+	attributionFeatures = mat.NewDense(4, 3, []float64{
+		1, 10, 0,
+		-3, 20, 1,
+		2, 30, 0,
+		-2, 40, 1,
+	})
+)
+
+func TestMeanAbsoluteAttributions(t *testing.T) {
+	expected := []float64{1, 0.15, 0.05}
+	if means := datautils.MeanAbsoluteAttributions(attributions); !floats.EqualApprox(means, expected, 1e-12) {
+		t.Errorf("Expected mean absolute attributions: %v but received %v", expected, means)
+	}
+}
+
+func TestPlotAttributions(t *testing.T) {
+	names := []string{"a", "b", "c"}
+
+	if p, err := datautils.PlotAttributionBar(attributions, names, 2); err != nil || p == nil {
+		t.Errorf("Unexpected error plotting attribution bar chart: %v", err)
+	}
+	if p, err := datautils.PlotAttributionBeeswarm(attributions, attributionFeatures, names, 0); err != nil || p == nil {
+		t.Errorf("Unexpected error plotting attribution beeswarm: %v", err)
+	}
+	if p, err := datautils.PlotAttributionDependence(attributions, attributionFeatures, names, 0, 2); err != nil || p.Title.Text != "Dependence of a (coloured by c)" {
+		t.Errorf("Unexpected dependence plot: %v", err)
+	}
+	if p, err := datautils.PlotAttributionDependence(attributions, attributionFeatures, nil, 1, -1); err != nil || p.Title.Text != "Dependence of 1" {
+		t.Errorf("Unexpected dependence plot: %v", err)
+	}
+}
+
+func TestPlotAttributionsValidation(t *testing.T) {
+	if _, err := datautils.PlotAttributionBar(attributions, []string{"a"}, 0); err == nil {
+		t.Errorf("Expected error for mismatched names but received none")
+	}
+	if _, err := datautils.PlotAttributionBeeswarm(attributions, mat.NewDense(4, 2, nil), nil, 0); err == nil {
+		t.Errorf("Expected error for mismatched feature values but received none")
+	}
+	if _, err := datautils.PlotAttributionDependence(attributions, attributionFeatures, nil, 3, -1); err == nil {
+		t.Errorf("Expected error for out of range feature but received none")
+	}
+}
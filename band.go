@@ -0,0 +1,153 @@
+package datautils
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+// bandPoints is the number of evenly spaced points, from 0 to 1 inclusive, at which confidence bands are
+// estimated.
+const bandPoints = 101
+
+// ConfidenceBand represents a pointwise confidence band around a curve e.g. a ROC or precision recall curve.
+// Lower[i] and Upper[i] are the bounds of the confidence interval of the curve at X[i].
+type ConfidenceBand struct {
+	X     []float64 `json:"x"`
+	Lower []float64 `json:"lower"`
+	Upper []float64 `json:"upper"`
+
+	// Confidence is the confidence level of the band e.g. 0.95 for a 95% confidence band
+	Confidence float64 `json:"confidence"`
+}
+
+// BootstrapROCBand estimates a pointwise confidence band for the ROC curve of the supplied predictions and labels
+// using the bootstrap percentile method (see Bootstrap).  The (prediction, label) pairs are resampled with
+// replacement n times and the true positive rate of each resampled curve evaluated (see ROCCurve.TPRAt) at 101
+// evenly spaced false positive rates from 0 to 1.  Resamples without both positive and negative observations are
+// excluded.  The band may be rendered with ROCCurve.PlotWithBand.  The seed is used to initialise the random
+// number generator so that results are reproducible.
+func BootstrapROCBand(predictions, labels []float64, n int, confidence float64, seed int64) ConfidenceBand {
+	return bootstrapBand(predictions, labels, n, confidence, seed, func(predictions, labels []float64, x []float64) []float64 {
+		c := NewROCCurve(predictions, labels)
+		if math.IsNaN(c.FPR[len(c.FPR)-1]) || math.IsNaN(c.TPR[len(c.TPR)-1]) {
+			return nil
+		}
+		y := make([]float64, len(x))
+		for i, fpr := range x {
+			y[i] = c.TPRAt(fpr)
+		}
+		return y
+	})
+}
+
+// BootstrapPrecisionRecallBand estimates a pointwise confidence band for the precision recall curve of the
+// supplied predictions and labels using the bootstrap percentile method (see Bootstrap).  The (prediction, label)
+// pairs are resampled with replacement n times and the interpolated precision of each resampled curve evaluated
+// (see PrecisionRecallCurve.InterpolatedPrecisionAt) at 101 evenly spaced recalls from 0 to 1.  Resamples without
+// any positive observations are excluded.  The band may be rendered with PrecisionRecallCurve.PlotWithBand.  The
+// seed is used to initialise the random number generator so that results are reproducible.
+func BootstrapPrecisionRecallBand(predictions, labels []float64, n int, confidence float64, seed int64) ConfidenceBand {
+	return bootstrapBand(predictions, labels, n, confidence, seed, func(predictions, labels []float64, x []float64) []float64 {
+		c := NewPrecisionRecallCurve(predictions, labels)
+		if c.positives == 0 {
+			return nil
+		}
+		y := make([]float64, len(x))
+		for i, r := range x {
+			y[i] = c.InterpolatedPrecisionAt(r)
+		}
+		return y
+	})
+}
+
+// bootstrapBand resamples the predictions and labels n times evaluating the curve for each resample at each of
+// the points of the band using curve.  curve returns nil if the curve is undefined for the resample.
+func bootstrapBand(predictions, labels []float64, n int, confidence float64, seed int64, curve func(predictions, labels, x []float64) []float64) ConfidenceBand {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+	if n < 1 {
+		panic("datautils: number of resamples must be at least 1")
+	}
+	if confidence <= 0 || confidence >= 1 {
+		panic("datautils: confidence must be between 0 and 1")
+	}
+
+	band := ConfidenceBand{
+		X:          make([]float64, bandPoints),
+		Lower:      make([]float64, bandPoints),
+		Upper:      make([]float64, bandPoints),
+		Confidence: confidence,
+	}
+	for i := range band.X {
+		band.X[i] = float64(i) / (bandPoints - 1)
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+	preds := make([]float64, len(predictions))
+	labs := make([]float64, len(labels))
+	samples := make([][]float64, bandPoints)
+
+	for i := 0; i < n; i++ {
+		for j := range preds {
+			k := rnd.Intn(len(predictions))
+			preds[j] = predictions[k]
+			labs[j] = labels[k]
+		}
+		y := curve(preds, labs, band.X)
+		if y == nil {
+			continue
+		}
+		for j, v := range y {
+			samples[j] = append(samples[j], v)
+		}
+	}
+
+	alpha := (1 - confidence) / 2
+	for j, s := range samples {
+		if len(s) == 0 {
+			band.Lower[j], band.Upper[j] = math.NaN(), math.NaN()
+			continue
+		}
+		sort.Float64s(s)
+		band.Lower[j] = stat.Quantile(alpha, stat.Empirical, s, nil)
+		band.Upper[j] = stat.Quantile(1-alpha, stat.Empirical, s, nil)
+	}
+
+	return band
+}
+
+// addBand adds the band to the plot as a shaded polygon, enclosed by the upper bound from left to right and the
+// lower bound from right to left, with a corresponding legend entry.  Points where the band is undefined (NaN)
+// are omitted.
+func addBand(p *plot.Plot, band ConfidenceBand) {
+	var upper, lower plotter.XYs
+	for i, x := range band.X {
+		if math.IsNaN(band.Lower[i]) || math.IsNaN(band.Upper[i]) {
+			continue
+		}
+		upper = append(upper, plotter.XY{X: x, Y: band.Upper[i]})
+		lower = append(lower, plotter.XY{X: x, Y: band.Lower[i]})
+	}
+	if len(upper) == 0 {
+		return
+	}
+	for i := len(lower) - 1; i >= 0; i-- {
+		upper = append(upper, lower[i])
+	}
+
+	polygon, err := plotter.NewPolygon(upper)
+	if err != nil {
+		panic(err)
+	}
+	polygon.Color = translucent(PlotTheme.Primary, 64)
+	polygon.LineStyle.Width = 0
+	p.Add(polygon)
+	p.Legend.Add(fmt.Sprintf("%g%% confidence band", band.Confidence*100), polygon)
+}
@@ -0,0 +1,57 @@
+package datautils_test
+
+import (
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestBootstrapBands(t *testing.T) {
+	tests := []struct {
+		predictions []float64
+		labels      []float64
+		perfect     bool
+	}{
+		{
+			predictions: []float64{0.9, 0.85, 0.8, 0.75, 0.3, 0.25, 0.2, 0.15},
+			labels:      []float64{1, 1, 1, 1, 0, 0, 0, 0},
+			perfect:     true,
+		},
+		{
+			predictions: []float64{0.9, 0.3, 0.8, 0.25, 0.75, 0.85, 0.2, 0.15},
+			labels:      []float64{1, 1, 0, 1, 0, 0, 1, 0},
+		},
+	}
+
+	for ti, test := range tests {
+		bands := map[string]datautils.ConfidenceBand{
+			"ROC": datautils.BootstrapROCBand(test.predictions, test.labels, 200, 0.9, 1),
+			"PR":  datautils.BootstrapPrecisionRecallBand(test.predictions, test.labels, 200, 0.9, 1),
+		}
+		for name, band := range bands {
+			if len(band.X) != 101 || len(band.Lower) != 101 || len(band.Upper) != 101 || band.X[0] != 0 || band.X[100] != 1 {
+				t.Errorf("Test %d: Expected %s band of 101 points from 0 to 1 but received %v", ti+1, name, band)
+				continue
+			}
+			for i := range band.X {
+				if band.Lower[i] > band.Upper[i] || band.Lower[i] < 0 || band.Upper[i] > 1 {
+					t.Errorf("Test %d: Expected %s band bounds within [0, 1] at %v but received [%v, %v]", ti+1, name, band.X[i], band.Lower[i], band.Upper[i])
+				}
+				// a perfect ranking remains perfect in every resample
+				if test.perfect && (band.Lower[i] != 1 || band.Upper[i] != 1) {
+					t.Errorf("Test %d: Expected %s band [1, 1] at %v but received [%v, %v]", ti+1, name, band.X[i], band.Lower[i], band.Upper[i])
+				}
+			}
+			if !test.perfect && band.Lower[50] == band.Upper[50] {
+				t.Errorf("Test %d: Expected %s band with non zero width at 0.5 but received [%v, %v]", ti+1, name, band.Lower[50], band.Upper[50])
+			}
+		}
+
+		if p := datautils.NewROCCurve(test.predictions, test.labels).PlotWithBand(bands["ROC"]); p == nil {
+			t.Errorf("Test %d: Expected ROC plot but received nil", ti+1)
+		}
+		if p := datautils.NewPrecisionRecallCurve(test.predictions, test.labels).PlotWithBand(bands["PR"]); p == nil {
+			t.Errorf("Test %d: Expected precision recall plot but received nil", ti+1)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package datautils
+
+// BinaryEvaluation evaluates the predictions of a binary classifier against ground truth labels, sorting the
+// predictions once so that the precision recall curve, ROC curve, average precision, AUC and confusion matrices
+// at any number of thresholds can all be derived from the same sorted data rather than each constructor copying
+// and sorting the predictions again.
+type BinaryEvaluation struct {
+	// sorted contains the predictions in ascending order and ind their original indices
+	sorted []float64
+	ind    []int
+
+	labels               []float64
+	positives, negatives int
+}
+
+// NewBinaryEvaluation creates a new BinaryEvaluation of the supplied predictions and ground truth labels.  Both
+// slices can be in any order providing they are identical lengths and their order matches e.g. predictions[5]
+// corresponds to the ground truth labels[5].  As with NewPrecisionRecallCurve and NewROCCurve, any label value
+// greater than 0 is assumed to represent a positive observation.  As with the curves, the predictions should not
+// contain NaN values (see HandleNonFinite).  The predictions and labels are copied so may be modified after the
+// evaluation is created.
+func NewBinaryEvaluation[P, L Float](predictions []P, labels []L) BinaryEvaluation {
+	return must(NewBinaryEvaluationE(predictions, labels))
+}
+
+// NewBinaryEvaluationE creates a new BinaryEvaluation in the same way as NewBinaryEvaluation but returns
+// ErrLengthMismatch, rather than panicking, if the lengths of the predictions and labels do not match.
+func NewBinaryEvaluationE[P, L Float](predictions []P, labels []L) (BinaryEvaluation, error) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		return BinaryEvaluation{}, err
+	}
+
+	e := BinaryEvaluation{labels: make([]float64, len(labels))}
+	for i, v := range labels {
+		e.labels[i] = float64(v)
+		if v > 0 {
+			e.positives++
+		} else {
+			e.negatives++
+		}
+	}
+	e.sorted, e.ind = sortedPredictions(predictions)
+	return e, nil
+}
+
+// PrecisionRecallCurve returns the precision recall curve of the predictions, identical to the curve created by
+// NewPrecisionRecallCurve.
+func (e BinaryEvaluation) PrecisionRecallCurve() PrecisionRecallCurve {
+	n := len(e.sorted)
+	c := precisionRecallCurve(e.sorted, e.ind, e.labels, e.positives, make([]float64, n), make([]float64, n))
+	// copy the thresholds so that modifying the curve does not affect the evaluation
+	c.Thresholds = append([]float64{}, c.Thresholds...)
+	return c
+}
+
+// ROCCurve returns the ROC curve of the predictions, identical to the curve created by NewROCCurve.
+func (e BinaryEvaluation) ROCCurve() ROCCurve {
+	return rocCurve(e.sorted, e.ind, e.labels, float64(e.positives), float64(e.negatives))
+}
+
+// AveragePrecision returns the average precision of the predictions (see PrecisionRecallCurve.AveragePrecision).
+func (e BinaryEvaluation) AveragePrecision() float64 {
+	return e.PrecisionRecallCurve().AveragePrecision()
+}
+
+// AUC returns the area under the ROC curve of the predictions (see ROCCurve.AUC).
+func (e BinaryEvaluation) AUC() float64 {
+	return e.ROCCurve().AUC()
+}
+
+// ConfusionMatrices creates a ConfusionMatrix for each of the specified thresholds, returned in the same order as
+// thresholds, equivalent to calling NewConfusionMatrix for each threshold.  The matrices are looked up from a
+// single sweep down the sorted predictions in the same way as NewConfusionMatrices.
+func (e BinaryEvaluation) ConfusionMatrices(thresholds []float64) []ConfusionMatrix {
+	candidates, sweep := sweepSorted(e.sorted, e.ind, e.labels)
+	return matricesAt(candidates, sweep, thresholds)
+}
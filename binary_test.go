@@ -0,0 +1,72 @@
+package datautils_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestBinaryEvaluation(t *testing.T) {
+	tests := []struct {
+		predictions []float64
+		labels      []float64
+	}{
+		{
+			predictions: []float64{0.1, 0.4, 0.35, 0.8},
+			labels:      []float64{0, 0, 1, 1},
+		},
+		{
+			predictions: []float64{0.5, 0.5, 0.9, 0.1, 0.5, 0.7},
+			labels:      []float64{1, 0, 1, 0, 0, 1},
+		},
+		{
+			predictions: []float64{0.2, 0.6, 0.3},
+			labels:      []float64{0, 0, 0},
+		},
+	}
+	thresholds := []float64{0.5, math.Inf(1), 0, 0.35, math.NaN(), 0.9}
+
+	for i, test := range tests {
+		e := datautils.NewBinaryEvaluation(test.predictions, test.labels)
+
+		if pr, expected := e.PrecisionRecallCurve(), datautils.NewPrecisionRecallCurve(test.predictions, test.labels); !reflect.DeepEqual(pr, expected) {
+			t.Errorf("Test %d: Expected precision recall curve: %v but received %v", i+1, expected, pr)
+		}
+		if ap, expected := e.AveragePrecision(), datautils.NewPrecisionRecallCurve(test.predictions, test.labels).AveragePrecision(); ap != expected {
+			t.Errorf("Test %d: Expected average precision: %v but received %v", i+1, expected, ap)
+		}
+		roc, expected := e.ROCCurve(), datautils.NewROCCurve(test.predictions, test.labels)
+		if !equalWithNaN(roc.FPR, expected.FPR) || !equalWithNaN(roc.TPR, expected.TPR) || !reflect.DeepEqual(roc.Thresholds, expected.Thresholds) {
+			t.Errorf("Test %d: Expected ROC curve: %v but received %v", i+1, expected, roc)
+		}
+		if auc, expectedAUC := e.AUC(), expected.AUC(); auc != expectedAUC && !(math.IsNaN(auc) && math.IsNaN(expectedAUC)) {
+			t.Errorf("Test %d: Expected AUC: %v but received %v", i+1, expectedAUC, auc)
+		}
+
+		matrices := e.ConfusionMatrices(thresholds)
+		for j, threshold := range thresholds {
+			if expected := datautils.NewConfusionMatrix(test.predictions, test.labels, threshold); matrices[j] != expected {
+				t.Errorf("Test %d: Threshold %v: Expected %v but received %v", i+1, threshold, expected, matrices[j])
+			}
+		}
+	}
+}
+
+func TestBinaryEvaluationCopiesInputs(t *testing.T) {
+	predictions := []float32{0.9, 0.2, 0.7}
+	labels := []float32{1, 0, 0}
+	e := datautils.NewBinaryEvaluation(predictions, labels)
+	predictions[0], labels[0] = 0, 0
+
+	pr := e.PrecisionRecallCurve()
+	pr.Thresholds[0] = -1
+
+	if ap := e.AveragePrecision(); ap != 1 {
+		t.Errorf("Expected average precision: 1 but received %v", ap)
+	}
+	if pr := e.PrecisionRecallCurve(); pr.Thresholds[0] != float64(float32(0.9)) {
+		t.Errorf("Expected threshold: %v but received %v", float32(0.9), pr.Thresholds[0])
+	}
+}
@@ -0,0 +1,164 @@
+package datautils
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// valueRange returns the minimum and maximum of the values ignoring NaN values.  If all the values are equal (or
+// there are no values), the range is widened by 0.5 either side so that it has non zero width.
+func valueRange(values []float64) (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			min, max = math.Min(min, v), math.Max(max, v)
+		}
+	}
+	if math.IsInf(min, 1) {
+		min, max = 0, 0
+	}
+	if min == max {
+		min, max = min-0.5, max+0.5
+	}
+	return min, max
+}
+
+// EqualWidthEdges returns the edges of the specified number of equal width bins spanning the range of the values
+// (see NewHistogram).  NaN values are ignored.  The edges are in ascending order and there is one more edge than
+// the number of bins.
+func EqualWidthEdges(values []float64, bins int) []float64 {
+	if bins < 1 {
+		panic("datautils: number of bins must be at least 1")
+	}
+	min, max := valueRange(values)
+	edges := make([]float64, bins+1)
+	width := (max - min) / float64(bins)
+	for i := range edges {
+		edges[i] = min + float64(i)*width
+	}
+	// avoid floating point error in the final edge
+	edges[bins] = max
+	return edges
+}
+
+// EqualFrequencyEdges returns the edges of (up to) the specified number of bins, each containing as near as
+// possible the same number of the values, by placing an edge at every len(values)/bins'th value in sorted order.
+// NaN values are ignored.  Where many values are equal, several edges may coincide in which case the duplicate
+// edges are removed and fewer bins are returned.  If all the values are equal (or there are no values), a single bin
+// spanning the value ±0.5 is returned.
+func EqualFrequencyEdges(values []float64, bins int) []float64 {
+	if bins < 1 {
+		panic("datautils: number of bins must be at least 1")
+	}
+	sorted := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			sorted = append(sorted, v)
+		}
+	}
+	if len(sorted) == 0 || sorted[0] == sorted[len(sorted)-1] {
+		min, max := valueRange(sorted)
+		return []float64{min, max}
+	}
+	sort.Float64s(sorted)
+
+	edges := []float64{sorted[0]}
+	for i := 1; i < bins; i++ {
+		if e := sorted[i*len(sorted)/bins]; e > edges[len(edges)-1] {
+			edges = append(edges, e)
+		}
+	}
+	if max := sorted[len(sorted)-1]; max > edges[len(edges)-1] {
+		edges = append(edges, max)
+	}
+	return edges
+}
+
+// Digitise returns the index of the bin, defined by edges in ascending order, into which v falls.  Bin i covers
+// the range [edges[i], edges[i+1]) except for the final bin which also includes its upper edge.  Values outside
+// the range of the edges fall into the first or last bin as appropriate.  If v is NaN, -1 is returned.
+func Digitise(v float64, edges []float64) int {
+	if len(edges) < 2 {
+		panic("datautils: at least 2 bin edges must be specified")
+	}
+	if math.IsNaN(v) {
+		return -1
+	}
+	b := sort.Search(len(edges), func(i int) bool { return edges[i] > v }) - 1
+	if b < 0 {
+		return 0
+	}
+	if b > len(edges)-2 {
+		return len(edges) - 2
+	}
+	return b
+}
+
+// BinStrategy specifies how a Discretiser chooses the edges of its bins.
+type BinStrategy int
+
+const (
+	// EqualWidth bins span the range of the values with bins of equal width (see EqualWidthEdges)
+	EqualWidth BinStrategy = iota
+
+	// EqualFrequency bins contain (as near as possible) equal numbers of values (see EqualFrequencyEdges)
+	EqualFrequency
+)
+
+// Discretiser transforms continuous features into discrete features by replacing each value with the index of
+// the bin into which it falls (see Digitise).  The edges of the bins of each column (feature) are learned from a
+// matrix of training data with Fit and then applied to other matrices (e.g. a test set) with Transform so that
+// all data is binned consistently.  Alternatively, Edges may be set directly to bin with custom edges without
+// fitting.  Missing values, represented as NaN, are ignored when fitting and remain NaN when transformed.
+type Discretiser struct {
+	// Bins is the number of bins to fit for each column.  With the EqualFrequency strategy, columns with many
+	// repeated values may be fitted with fewer bins.
+	Bins int
+
+	// Strategy specifies how the edges of the bins are chosen when fitting
+	Strategy BinStrategy
+
+	// Edges contains the edges of the bins of each column in ascending order
+	Edges [][]float64
+}
+
+// Fit learns the edges of the bins of each column of m according to the Strategy.
+func (d *Discretiser) Fit(m mat.Matrix) {
+	cols := columns(m)
+	d.Edges = make([][]float64, len(cols))
+	for j, col := range cols {
+		switch d.Strategy {
+		case EqualWidth:
+			d.Edges[j] = EqualWidthEdges(col, d.Bins)
+		case EqualFrequency:
+			d.Edges[j] = EqualFrequencyEdges(col, d.Bins)
+		default:
+			panic("datautils: unknown bin strategy")
+		}
+	}
+}
+
+// Transform returns a new matrix containing the index of the bin into which each value of m falls.
+func (d *Discretiser) Transform(m mat.Matrix) *mat.Dense {
+	r, c := m.Dims()
+	if d.Edges == nil {
+		panic("datautils: discretiser has not been fitted")
+	}
+	if c != len(d.Edges) {
+		panic(mat.ErrShape)
+	}
+	t := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v := m.At(i, j)
+			if math.IsNaN(v) {
+				t.Set(i, j, math.NaN())
+				continue
+			}
+			t.Set(i, j, float64(Digitise(v, d.Edges[j])))
+		}
+	}
+	return t
+}
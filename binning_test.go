@@ -0,0 +1,116 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestEqualWidthEdges(t *testing.T) {
+	tests := []struct {
+		values []float64
+		bins   int
+		edges  []float64
+	}{
+		{values: []float64{0, 1, math.NaN(), 4}, bins: 4, edges: []float64{0, 1, 2, 3, 4}},
+		{values: []float64{2, 2}, bins: 2, edges: []float64{1.5, 2, 2.5}},
+		{values: []float64{}, bins: 1, edges: []float64{-0.5, 0.5}},
+	}
+
+	for i, test := range tests {
+		if edges := datautils.EqualWidthEdges(test.values, test.bins); !floats.Equal(test.edges, edges) {
+			t.Errorf("Test %d: Expected edges: %v but received %v", i+1, test.edges, edges)
+		}
+	}
+}
+
+func TestEqualFrequencyEdges(t *testing.T) {
+	tests := []struct {
+		values []float64
+		bins   int
+		edges  []float64
+	}{
+		{values: []float64{8, 1, 2, 3, 4, 5, 6, 7}, bins: 4, edges: []float64{1, 3, 5, 7, 8}},
+		{values: []float64{1, 1, 1, 1, 1, 1, 2, 3}, bins: 4, edges: []float64{1, 2, 3}},
+		{values: []float64{5, math.NaN(), 5}, bins: 3, edges: []float64{4.5, 5.5}},
+	}
+
+	for i, test := range tests {
+		if edges := datautils.EqualFrequencyEdges(test.values, test.bins); !floats.Equal(test.edges, edges) {
+			t.Errorf("Test %d: Expected edges: %v but received %v", i+1, test.edges, edges)
+		}
+	}
+}
+
+func TestDigitise(t *testing.T) {
+	edges := []float64{0, 1, 2, 3}
+
+	tests := []struct {
+		v   float64
+		bin int
+	}{
+		{v: -1, bin: 0},
+		{v: 0, bin: 0},
+		{v: 0.5, bin: 0},
+		{v: 1, bin: 1},
+		{v: 2.5, bin: 2},
+		{v: 3, bin: 2},
+		{v: 10, bin: 2},
+		{v: math.NaN(), bin: -1},
+	}
+
+	for i, test := range tests {
+		if bin := datautils.Digitise(test.v, edges); bin != test.bin {
+			t.Errorf("Test %d: Expected %v in bin %d but received %d", i+1, test.v, test.bin, bin)
+		}
+	}
+}
+
+func TestDiscretiser(t *testing.T) {
+	nan := math.NaN()
+	train := mat.NewDense(4, 2, []float64{
+		0, 1,
+		1, 2,
+		2, 3,
+		4, 10,
+	})
+	test := mat.NewDense(3, 2, []float64{
+		-1, 2.5,
+		3, nan,
+		5, 100,
+	})
+
+	tests := []struct {
+		d        datautils.Discretiser
+		expected []float64
+	}{
+		{
+			d:        datautils.Discretiser{Bins: 2, Strategy: datautils.EqualWidth},
+			expected: []float64{0, 0, 1, nan, 1, 1},
+		},
+		{
+			d:        datautils.Discretiser{Bins: 2, Strategy: datautils.EqualFrequency},
+			expected: []float64{0, 0, 1, nan, 1, 1},
+		},
+	}
+
+	for i, tc := range tests {
+		tc.d.Fit(train)
+		if len(tc.d.Edges) != 2 {
+			t.Errorf("Test %d: Expected edges for 2 columns but received %v", i+1, tc.d.Edges)
+		}
+		transformed := tc.d.Transform(test)
+		if !equalWithNaN(tc.expected, flatten(transformed)) {
+			t.Errorf("Test %d: Expected bins: %v but received %v", i+1, tc.expected, flatten(transformed))
+		}
+	}
+
+	custom := datautils.Discretiser{Edges: [][]float64{{0, 2, 4}, {0, 5, 50, 500}}}
+	expected := []float64{0, 0, 1, nan, 1, 2}
+	if transformed := custom.Transform(test); !equalWithNaN(expected, flatten(transformed)) {
+		t.Errorf("Expected custom bins: %v but received %v", expected, flatten(transformed))
+	}
+}
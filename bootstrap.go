@@ -0,0 +1,130 @@
+package datautils
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// MetricFunc is a function that calculates a single evaluation metric from a set of predictions and their
+// corresponding ground truth labels e.g. average precision, NDCG or F1 score at a fixed threshold.
+type MetricFunc func(predictions, labels []float64) float64
+
+// ConfidenceInterval represents an estimate of a metric along with the lower and upper bounds of its confidence
+// interval.
+type ConfidenceInterval struct {
+	// Estimate is the value of the metric calculated over the original (not resampled) data
+	Estimate float64
+
+	// Lower and Upper are the lower and upper bounds of the confidence interval
+	Lower, Upper float64
+
+	// Confidence is the confidence level of the interval e.g. 0.95 for a 95% confidence interval
+	Confidence float64
+}
+
+// Bootstrap estimates a confidence interval for the specified metric using the bootstrap percentile method.  The
+// (prediction, label) pairs are resampled with replacement n times and the metric calculated for each resample.  The
+// bounds of the interval are the (1-confidence)/2 and 1-(1-confidence)/2 quantiles of the resampled metric values
+// e.g. the 2.5th and 97.5th percentiles for a confidence of 0.95.  Resamples for which the metric evaluates to NaN
+// (e.g. AP for a resample containing no positive observations) are excluded.  The seed is used to initialise the
+// random number generator so that results are reproducible.  For highly imbalanced data, consider
+// StratifiedBootstrap.
+func Bootstrap(predictions, labels []float64, metric MetricFunc, n int, confidence float64, seed int64) ConfidenceInterval {
+	ci, _ := BootstrapContext(context.Background(), predictions, labels, metric, n, confidence, seed)
+	return ci
+}
+
+// BootstrapContext estimates a confidence interval for the specified metric as Bootstrap but stops resampling
+// promptly if ctx is cancelled (or its deadline is exceeded) returning an interval calculated from the resamples
+// completed so far along with the context's error.  If ctx is cancelled before any resamples are completed, the
+// bounds of the returned interval are NaN.
+func BootstrapContext(ctx context.Context, predictions, labels []float64, metric MetricFunc, n int, confidence float64, seed int64) (ConfidenceInterval, error) {
+	strata := [][]int{allIndices(len(predictions))}
+	return bootstrap(ctx, predictions, labels, metric, n, confidence, seed, strata)
+}
+
+// StratifiedBootstrap estimates a confidence interval for the specified metric using the bootstrap percentile method
+// as Bootstrap except that the positive and negative observations (where, as with NewPrecisionRecallCurve, any label
+// value greater than 0 is considered positive) are resampled separately so that every resample preserves the ratio
+// of positive to negative observations.  With ordinary resampling, small or highly imbalanced test sets can yield
+// resamples with few or no positive observations distorting estimates of metrics such as AP and ROC AUC.
+func StratifiedBootstrap(predictions, labels []float64, metric MetricFunc, n int, confidence float64, seed int64) ConfidenceInterval {
+	ci, _ := StratifiedBootstrapContext(context.Background(), predictions, labels, metric, n, confidence, seed)
+	return ci
+}
+
+// StratifiedBootstrapContext estimates a confidence interval for the specified metric as StratifiedBootstrap but
+// stops resampling promptly if ctx is cancelled, returning a partial result and the context's error as for
+// BootstrapContext.
+func StratifiedBootstrapContext(ctx context.Context, predictions, labels []float64, metric MetricFunc, n int, confidence float64, seed int64) (ConfidenceInterval, error) {
+	var pos, neg []int
+	for i, v := range labels {
+		if v > 0 {
+			pos = append(pos, i)
+		} else {
+			neg = append(neg, i)
+		}
+	}
+	return bootstrap(ctx, predictions, labels, metric, n, confidence, seed, [][]int{pos, neg})
+}
+
+// bootstrap implements the bootstrap percentile method resampling the indices of each of the strata with
+// replacement, independently of the other strata, to construct each resample.  Resampling stops early if ctx is
+// cancelled, in which case the interval is calculated from the completed resamples and the context's error returned.
+func bootstrap(ctx context.Context, predictions, labels []float64, metric MetricFunc, n int, confidence float64, seed int64, strata [][]int) (ConfidenceInterval, error) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+	if n < 1 {
+		panic("datautils: number of resamples must be at least 1")
+	}
+	if confidence <= 0 || confidence >= 1 {
+		panic("datautils: confidence must be between 0 and 1")
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+
+	preds := make([]float64, len(predictions))
+	labs := make([]float64, len(labels))
+	samples := make([]float64, 0, n)
+
+	var err error
+	for i := 0; i < n; i++ {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		var j int
+		for _, stratum := range strata {
+			for range stratum {
+				k := stratum[rnd.Intn(len(stratum))]
+				preds[j] = predictions[k]
+				labs[j] = labels[k]
+				j++
+			}
+		}
+		if v := metric(preds, labs); !math.IsNaN(v) {
+			samples = append(samples, v)
+		}
+	}
+
+	ci := ConfidenceInterval{
+		Estimate:   metric(predictions, labels),
+		Lower:      math.NaN(),
+		Upper:      math.NaN(),
+		Confidence: confidence,
+	}
+	if len(samples) == 0 {
+		return ci, err
+	}
+
+	sort.Float64s(samples)
+	alpha := (1 - confidence) / 2
+	ci.Lower = stat.Quantile(alpha, stat.Empirical, samples, nil)
+	ci.Upper = stat.Quantile(1-alpha, stat.Empirical, samples, nil)
+
+	return ci, err
+}
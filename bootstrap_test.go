@@ -0,0 +1,108 @@
+package datautils_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func averagePrecision(predictions, labels []float64) float64 {
+	return datautils.NewPrecisionRecallCurve(predictions, labels).AveragePrecision()
+}
+
+func TestBootstrap(t *testing.T) {
+	for i, d := range datasets[:3] {
+		ci := datautils.Bootstrap(d.probs, d.labels, averagePrecision, 200, 0.95, 42)
+
+		if ci.Estimate != averagePrecision(d.probs, d.labels) {
+			t.Errorf("Test %d: Expected estimate: %v but received %v", i+1, averagePrecision(d.probs, d.labels), ci.Estimate)
+		}
+		if ci.Lower > ci.Upper || ci.Lower < 0 || ci.Upper > 1 {
+			t.Errorf("Test %d: Expected valid interval within [0, 1] but received [%v, %v]", i+1, ci.Lower, ci.Upper)
+		}
+		if ci.Confidence != 0.95 {
+			t.Errorf("Test %d: Expected confidence: %v but received %v", i+1, 0.95, ci.Confidence)
+		}
+
+		again := datautils.Bootstrap(d.probs, d.labels, averagePrecision, 200, 0.95, 42)
+		if ci != again {
+			t.Errorf("Test %d: Expected identical intervals for identical seeds but received %+v and %+v", i+1, ci, again)
+		}
+	}
+}
+
+func TestBootstrapConstantMetric(t *testing.T) {
+	constant := func(predictions, labels []float64) float64 { return 0.5 }
+	ci := datautils.Bootstrap(datasets[0].probs, datasets[0].labels, constant, 50, 0.9, 1)
+
+	if ci.Estimate != 0.5 || ci.Lower != 0.5 || ci.Upper != 0.5 {
+		t.Errorf("Expected degenerate interval at 0.5 but received %+v", ci)
+	}
+}
+
+func TestBootstrapAllNaN(t *testing.T) {
+	nan := func(predictions, labels []float64) float64 { return math.NaN() }
+	ci := datautils.Bootstrap(datasets[0].probs, datasets[0].labels, nan, 10, 0.95, 1)
+
+	if !math.IsNaN(ci.Lower) || !math.IsNaN(ci.Upper) {
+		t.Errorf("Expected NaN bounds but received [%v, %v]", ci.Lower, ci.Upper)
+	}
+}
+
+func TestStratifiedBootstrap(t *testing.T) {
+	predictions := []float64{0.9, 0.1, 0.4, 0.3, 0.2, 0.35, 0.6, 0.05, 0.15, 0.5}
+	labels := []float64{1, 0, 0, 0, 0, 0, 1, 0, 0, 0}
+	positiveRate := func(predictions, labels []float64) float64 {
+		var pos float64
+		for _, v := range labels {
+			pos += v
+		}
+		return pos / float64(len(labels))
+	}
+
+	// every resample preserves the ratio of positive to negative observations
+	ci := datautils.StratifiedBootstrap(predictions, labels, positiveRate, 100, 0.95, 1)
+	if ci.Estimate != 0.2 || ci.Lower != 0.2 || ci.Upper != 0.2 {
+		t.Errorf("Expected degenerate interval at 0.2 but received %+v", ci)
+	}
+
+	ci = datautils.StratifiedBootstrap(predictions, labels, averagePrecision, 200, 0.95, 42)
+	if ci.Lower > ci.Upper || ci.Lower < 0 || ci.Upper > 1 {
+		t.Errorf("Expected valid interval within [0, 1] but received [%v, %v]", ci.Lower, ci.Upper)
+	}
+	if again := datautils.StratifiedBootstrap(predictions, labels, averagePrecision, 200, 0.95, 42); ci != again {
+		t.Errorf("Expected identical intervals for identical seeds but received %+v and %+v", ci, again)
+	}
+}
+
+func TestBootstrapContext(t *testing.T) {
+	d := datasets[0]
+
+	ci, err := datautils.BootstrapContext(context.Background(), d.probs, d.labels, averagePrecision, 100, 0.95, 42)
+	if expected := datautils.Bootstrap(d.probs, d.labels, averagePrecision, 100, 0.95, 42); err != nil || ci != expected {
+		t.Errorf("Expected %+v without error but received %+v and %v", expected, ci, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ci, err = datautils.StratifiedBootstrapContext(ctx, d.probs, d.labels, averagePrecision, 100, 0.95, 42)
+	if err != context.Canceled || !math.IsNaN(ci.Lower) || !math.IsNaN(ci.Upper) {
+		t.Errorf("Expected NaN bounds and %v but received [%v, %v] and %v", context.Canceled, ci.Lower, ci.Upper, err)
+	}
+
+	// cancelling part way through returns an interval from the completed resamples
+	ctx, cancel = context.WithCancel(context.Background())
+	var calls int
+	metric := func(predictions, labels []float64) float64 {
+		if calls++; calls == 10 {
+			cancel()
+		}
+		return float64(calls)
+	}
+	ci, err = datautils.BootstrapContext(ctx, d.probs, d.labels, metric, 100, 0.5, 1)
+	if err != context.Canceled || ci.Upper > 10 || ci.Estimate != 11 {
+		t.Errorf("Expected an interval of the first 10 resamples and %v but received %+v and %v", context.Canceled, ci, err)
+	}
+}
@@ -0,0 +1,80 @@
+package datautils
+
+// ConfusionMatrixBuilder incrementally builds a ConfusionMatrix from a stream of (prediction, label) pairs
+// so that predictions need not all be held in memory.  A ConfusionMatrixBuilder is not safe for concurrent use
+// so, to accumulate across goroutines or shards, use a separate builder for each and combine them using Merge.
+type ConfusionMatrixBuilder struct {
+	// Threshold is the decision threshold.  As with NewConfusionMatrix, predictions greater than or equal to the
+	// threshold are predicted positive
+	Threshold float64
+
+	matrix ConfusionMatrix
+}
+
+// NewConfusionMatrixBuilder creates a new, empty, ConfusionMatrixBuilder using the specified decision threshold.
+func NewConfusionMatrixBuilder(threshold float64) *ConfusionMatrixBuilder {
+	return &ConfusionMatrixBuilder{Threshold: threshold}
+}
+
+// Add adds a single observation with the specified prediction and ground truth label.  As with
+// NewConfusionMatrix, labels of 1 are considered positive and all other values negative.
+func (b *ConfusionMatrixBuilder) Add(prediction, label float64) {
+	b.matrix.add(prediction >= b.Threshold, label, 1)
+}
+
+// Merge adds all the observations accumulated by other into b.
+func (b *ConfusionMatrixBuilder) Merge(other *ConfusionMatrixBuilder) {
+	b.matrix = b.matrix.Merge(other.matrix)
+}
+
+// Matrix returns the ConfusionMatrix for all the observations added so far.
+func (b *ConfusionMatrixBuilder) Matrix() ConfusionMatrix {
+	return b.matrix
+}
+
+// Merge returns a new ConfusionMatrix combining the observations of c and other e.g. to combine the confusion
+// matrices of separate shards of a dataset.  If either matrix is weighted, the resulting matrix is weighted with
+// the observations of an unweighted matrix each contributing a weight of 1.
+func (c ConfusionMatrix) Merge(other ConfusionMatrix) ConfusionMatrix {
+	merged := ConfusionMatrix{
+		Observations: c.Observations + other.Observations,
+		Pos:          c.Pos + other.Pos,
+		Neg:          c.Neg + other.Neg,
+		TruePos:      c.TruePos + other.TruePos,
+		TrueNeg:      c.TrueNeg + other.TrueNeg,
+		FalsePos:     c.FalsePos + other.FalsePos,
+		FalseNeg:     c.FalseNeg + other.FalseNeg,
+		ZeroDivision: c.ZeroDivision,
+	}
+	if c.Weighted || other.Weighted {
+		merged.Weighted = true
+		a, b := c.weights(), other.weights()
+		merged.Weights = ConfusionWeights{
+			Observations: a.Observations + b.Observations,
+			Pos:          a.Pos + b.Pos,
+			Neg:          a.Neg + b.Neg,
+			TruePos:      a.TruePos + b.TruePos,
+			TrueNeg:      a.TrueNeg + b.TrueNeg,
+			FalsePos:     a.FalsePos + b.FalsePos,
+			FalseNeg:     a.FalseNeg + b.FalseNeg,
+		}
+	}
+	return merged
+}
+
+// weights returns the sums of the weights for each cell of the matrix.  For an unweighted matrix each
+// observation has a weight of 1 so the sums of the weights are the counts.
+func (c ConfusionMatrix) weights() ConfusionWeights {
+	if c.Weighted {
+		return c.Weights
+	}
+	return ConfusionWeights{
+		Observations: float64(c.Observations),
+		Pos:          float64(c.Pos),
+		Neg:          float64(c.Neg),
+		TruePos:      float64(c.TruePos),
+		TrueNeg:      float64(c.TrueNeg),
+		FalsePos:     float64(c.FalsePos),
+		FalseNeg:     float64(c.FalseNeg),
+	}
+}
@@ -0,0 +1,43 @@
+package datautils_test
+
+import (
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestConfusionMatrixBuilder(t *testing.T) {
+	for i, d := range datasets {
+		expected := datautils.NewConfusionMatrix(d.probs, d.labels, 0.38)
+
+		// split observations across two builders and merge
+		a := datautils.NewConfusionMatrixBuilder(0.38)
+		b := datautils.NewConfusionMatrixBuilder(0.38)
+		for j := range d.probs {
+			if j%2 == 0 {
+				a.Add(d.probs[j], d.labels[j])
+			} else {
+				b.Add(d.probs[j], d.labels[j])
+			}
+		}
+		a.Merge(b)
+
+		if matrix := a.Matrix(); matrix != expected {
+			t.Errorf("Test %d: Expected %+v but received %+v", i+1, expected, matrix)
+		}
+	}
+}
+
+func TestConfusionMatrixMergeWeighted(t *testing.T) {
+	unweighted := datautils.NewConfusionMatrix(datasets[0].probs, datasets[0].labels, 0.5)
+	weighted := datautils.NewWeightedConfusionMatrix(datasets[0].probs, datasets[0].labels, []float64{1, 1, 3, 1}, 0.5)
+
+	merged := unweighted.Merge(weighted)
+
+	if !merged.Weighted || merged.Observations != 8 || merged.Weights.Observations != 10 || merged.Weights.FalseNeg != 4 {
+		t.Errorf("Expected weighted merge of matrices but received %+v", merged)
+	}
+	if r := merged.Recall(); r != 2.0/6.0 {
+		t.Errorf("Expected recall: %v but received %v", 2.0/6.0, r)
+	}
+}
@@ -0,0 +1,172 @@
+package datautils
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// CalibrationRow contains the statistics for a single bin of predictions of a CalibrationTable.
+type CalibrationRow struct {
+	// Bin is the 1 based index of the bin with bin 1 containing the lowest predictions
+	Bin int `json:"bin"`
+
+	// MinPrediction and MaxPrediction are the lowest and highest predictions within the bin
+	MinPrediction float64 `json:"min_prediction"`
+	MaxPrediction float64 `json:"max_prediction"`
+
+	// Count is the number of observations within the bin
+	Count int `json:"count"`
+
+	// MeanPredicted is the mean prediction (predicted probability) of the observations within the bin
+	MeanPredicted float64 `json:"mean_predicted"`
+
+	// ObservedRate is the proportion of observations within the bin that are positive
+	ObservedRate float64 `json:"observed_rate"`
+
+	// Lift is the ObservedRate of the bin relative to the overall proportion of positive observations
+	Lift float64 `json:"lift"`
+}
+
+// CalibrationTable is a calibration (reliability) table comparing the probabilities predicted by a classifier with
+// the observed rates of positive observations across bins of predictions, as commonly required for model risk
+// documentation.  For a well calibrated model, the mean predicted probability of each bin matches its observed
+// rate.  Rows are ordered by ascending prediction.
+type CalibrationTable []CalibrationRow
+
+// NewCalibrationTable creates a new calibration table by dividing the predictions into (up to) the specified
+// number of bins according to the strategy (see BinStrategy).  EqualFrequency bins (e.g. 10 for deciles) contain
+// near equal numbers of observations while EqualWidth bins divide the range of predictions evenly.  Empty bins are
+// omitted and, as with EqualFrequencyEdges, tied predictions may produce fewer bins than requested.  As with
+// NewPrecisionRecallCurve, any label value greater than 0 is considered positive.
+func NewCalibrationTable(predictions, labels []float64, bins int, strategy BinStrategy) CalibrationTable {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+	if bins < 1 {
+		panic("datautils: number of bins must be at least 1")
+	}
+
+	var edges []float64
+	switch strategy {
+	case EqualWidth:
+		edges = EqualWidthEdges(predictions, bins)
+	case EqualFrequency:
+		edges = EqualFrequencyEdges(predictions, bins)
+	default:
+		panic("datautils: unknown bin strategy")
+	}
+
+	rows := make([]CalibrationRow, len(edges)-1)
+	positives := make([]int, len(rows))
+	var total int
+	for i, v := range predictions {
+		b := Digitise(v, edges)
+		if b < 0 {
+			continue
+		}
+		r := &rows[b]
+		if r.Count == 0 || v < r.MinPrediction {
+			r.MinPrediction = v
+		}
+		if r.Count == 0 || v > r.MaxPrediction {
+			r.MaxPrediction = v
+		}
+		r.Count++
+		r.MeanPredicted += v
+		if labels[i] > 0 {
+			positives[b]++
+			total++
+		}
+	}
+
+	var n int
+	for _, r := range rows {
+		n += r.Count
+	}
+	baseRate := float64(total) / float64(n)
+
+	var table CalibrationTable
+	for b, r := range rows {
+		if r.Count == 0 {
+			continue
+		}
+		r.Bin = len(table) + 1
+		r.MeanPredicted /= float64(r.Count)
+		r.ObservedRate = float64(positives[b]) / float64(r.Count)
+		r.Lift = r.ObservedRate / baseRate
+		table = append(table, r)
+	}
+	return table
+}
+
+// ExpectedCalibrationError calculates the expected calibration error (ECE) of the table, the mean of the absolute
+// differences between the mean predicted probability and the observed rate of each bin weighted by the number of
+// observations in the bin.  0 indicates perfect calibration.  If the table is empty, NaN is returned.
+func (t CalibrationTable) ExpectedCalibrationError() float64 {
+	var sum float64
+	var n int
+	for _, r := range t {
+		sum += float64(r.Count) * math.Abs(r.MeanPredicted-r.ObservedRate)
+		n += r.Count
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	return sum / float64(n)
+}
+
+// String formats the calibration table for printing.
+func (t CalibrationTable) String() string {
+	s := "Bin | Min Prediction | Max Prediction |  Count  | Mean Predicted | Observed Rate |   Lift\n"
+	s = s + "-----------------------------------------------------------------------------------------\n"
+	for _, r := range t {
+		s = fmt.Sprintf("%s%3d | %14.6g | %14.6g | %7d | %14.4f | %13.4f | %8.4f\n", s,
+			r.Bin, r.MinPrediction, r.MaxPrediction, r.Count, r.MeanPredicted, r.ObservedRate, r.Lift)
+	}
+	return s
+}
+
+// Plot renders the calibration table as a reliability diagram plotting the observed rate of each bin against its
+// mean predicted probability.  The dashed diagonal represents perfect calibration; points below the diagonal
+// indicate over-confident predictions and points above it under-confident predictions.
+func (t CalibrationTable) Plot() *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+
+	p.Title.Text = PlotTheme.title(fmt.Sprintf("Reliability Diagram, ECE=%f", t.ExpectedCalibrationError()))
+	p.X.Label.Text = "Mean Predicted Probability"
+	p.Y.Label.Text = "Observed Rate"
+	p.X.Min, p.X.Max = 0, 1
+	p.Y.Min, p.Y.Max = 0, 1
+
+	diagonal, err := plotter.NewLine(plotter.XYs{{X: 0, Y: 0}, {X: 1, Y: 1}})
+	if err != nil {
+		panic(err)
+	}
+	diagonal.Color = color.Gray{Y: 128}
+	diagonal.Dashes = []vg.Length{vg.Points(4), vg.Points(4)}
+	p.Add(diagonal)
+
+	if len(t) > 0 {
+		pts := make(plotter.XYs, len(t))
+		for i, r := range t {
+			pts[i].X, pts[i].Y = r.MeanPredicted, r.ObservedRate
+		}
+		line, points, err := plotter.NewLinePoints(pts)
+		if err != nil {
+			panic(err)
+		}
+		line.Color = PlotTheme.Primary
+		points.Color = PlotTheme.Primary
+		p.Add(line, points)
+	}
+
+	return p
+}
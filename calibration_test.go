@@ -0,0 +1,52 @@
+package datautils_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestNewCalibrationTable(t *testing.T) {
+	predictions := []float64{0.1, 0.2, 0.3, 0.4, 0.6, 0.7, 0.8, 0.9}
+	labels := []float64{0, 0, 1, 0, 1, 0, 1, 1}
+
+	table := datautils.NewCalibrationTable(predictions, labels, 2, datautils.EqualFrequency)
+	expected := datautils.CalibrationTable{
+		{Bin: 1, MinPrediction: 0.1, MaxPrediction: 0.4, Count: 4, MeanPredicted: 0.25, ObservedRate: 0.25, Lift: 0.5},
+		{Bin: 2, MinPrediction: 0.6, MaxPrediction: 0.9, Count: 4, MeanPredicted: 0.75, ObservedRate: 0.75, Lift: 1.5},
+	}
+	if len(table) != len(expected) {
+		t.Fatalf("Expected %d bins but received %d", len(expected), len(table))
+	}
+	for i, r := range table {
+		e := expected[i]
+		if r.Bin != e.Bin || r.Count != e.Count || r.MinPrediction != e.MinPrediction || r.MaxPrediction != e.MaxPrediction ||
+			math.Abs(r.MeanPredicted-e.MeanPredicted) > 1e-12 || r.ObservedRate != e.ObservedRate || r.Lift != e.Lift {
+			t.Errorf("Bin %d: Expected %+v but received %+v", i+1, e, r)
+		}
+	}
+	if ece := table.ExpectedCalibrationError(); math.Abs(ece) > 1e-12 {
+		t.Errorf("Expected ECE: 0 but received %v", ece)
+	}
+
+	// empty equal width bins are omitted
+	table = datautils.NewCalibrationTable([]float64{0, 0.1, 0.9, 1}, []float64{0, 0, 1, 1}, 4, datautils.EqualWidth)
+	if len(table) != 2 || table[1].Bin != 2 || table[1].Count != 2 {
+		t.Errorf("Expected 2 non-empty bins but received %v", table)
+	}
+	if ece := table.ExpectedCalibrationError(); math.Abs(ece-0.05) > 1e-12 {
+		t.Errorf("Expected ECE: 0.05 but received %v", ece)
+	}
+
+	if s := table.String(); strings.Count(s, "\n") != 4 || !strings.Contains(s, "Observed Rate") {
+		t.Errorf("Unexpected calibration table:\n%s", s)
+	}
+	if p := table.Plot(); p == nil {
+		t.Errorf("Expected reliability diagram but received nil")
+	}
+	if ece := (datautils.CalibrationTable{}).ExpectedCalibrationError(); !math.IsNaN(ece) {
+		t.Errorf("Expected NaN ECE for empty table but received %v", ece)
+	}
+}
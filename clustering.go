@@ -0,0 +1,84 @@
+package datautils
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SilhouetteSamples calculates the silhouette coefficient (Rousseeuw, 1987) of each observation of a clustering
+// from the matrix of pairwise distances between the observations (e.g. as calculated by distances.Pairwise) and
+// the cluster to which each observation is assigned.  The silhouette coefficient of observation i is
+//
+//	s(i) = (b(i) - a(i)) / max(a(i), b(i))
+//
+// where a(i) is the mean distance from i to the other observations of its cluster and b(i) the smallest mean
+// distance from i to the observations of any other cluster.  Coefficients range from -1 to 1 where values near 1
+// indicate the observation is well matched to its cluster and values below 0 that it would be better assigned to
+// the neighbouring cluster.  Observations that are the only member of their cluster have a coefficient of 0.
+// SilhouetteSamples will panic if there are fewer than 2 clusters.
+func SilhouetteSamples(distances mat.Matrix, clusters []int) []float64 {
+	r, c := distances.Dims()
+	if r != c {
+		panic(mat.ErrShape)
+	}
+	if len(clusters) != r {
+		panic(ErrLengthMismatch)
+	}
+
+	// map cluster labels to consecutive indices so distance sums can be accumulated in a slice
+	index := make(map[int]int)
+	for _, k := range clusters {
+		if _, ok := index[k]; !ok {
+			index[k] = len(index)
+		}
+	}
+	if len(index) < 2 {
+		panic("datautils: silhouette requires at least 2 clusters")
+	}
+	sizes := make([]int, len(index))
+	for _, k := range clusters {
+		sizes[index[k]]++
+	}
+
+	samples := make([]float64, r)
+	sums := make([]float64, len(index))
+	for i := range samples {
+		own := index[clusters[i]]
+		if sizes[own] == 1 {
+			continue
+		}
+		for k := range sums {
+			sums[k] = 0
+		}
+		for j := 0; j < r; j++ {
+			if j != i {
+				sums[index[clusters[j]]] += distances.At(i, j)
+			}
+		}
+
+		a := sums[own] / float64(sizes[own]-1)
+		b := math.Inf(1)
+		for k, sum := range sums {
+			if k != own {
+				b = math.Min(b, sum/float64(sizes[k]))
+			}
+		}
+		if max := math.Max(a, b); max > 0 {
+			samples[i] = (b - a) / max
+		}
+	}
+	return samples
+}
+
+// Silhouette calculates the silhouette score of a clustering, the mean silhouette coefficient of all observations
+// (see SilhouetteSamples).  Higher scores indicate denser, better separated clusters and so the score may be used
+// to compare clusterings of the same data e.g. to select the number of clusters.
+func Silhouette(distances mat.Matrix, clusters []int) float64 {
+	samples := SilhouetteSamples(distances, clusters)
+	var sum float64
+	for _, s := range samples {
+		sum += s
+	}
+	return sum / float64(len(samples))
+}
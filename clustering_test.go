@@ -0,0 +1,80 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+// pointDistances returns the matrix of absolute differences between the points of a line.
+func pointDistances(points []float64) *mat.Dense {
+	d := mat.NewDense(len(points), len(points), nil)
+	for i := range points {
+		for j := range points {
+			d.Set(i, j, math.Abs(points[i]-points[j]))
+		}
+	}
+	return d
+}
+
+func TestSilhouette(t *testing.T) {
+	tests := []struct {
+		points   []float64
+		clusters []int
+		samples  []float64
+	}{
+		{
+			points:   []float64{0, 1, 10, 11},
+			clusters: []int{0, 0, 1, 1},
+			samples:  []float64{9.5 / 10.5, 8.5 / 9.5, 8.5 / 9.5, 9.5 / 10.5},
+		},
+		// a singleton cluster has a coefficient of 0
+		{
+			points:   []float64{0, 1, 10},
+			clusters: []int{3, 3, 7},
+			samples:  []float64{9.0 / 10, 8.0 / 9, 0},
+		},
+		// observations closer to the neighbouring cluster have negative coefficients
+		{
+			points:   []float64{0, 10, 1, 11},
+			clusters: []int{0, 0, 1, 1},
+			samples:  []float64{-0.4, -0.5, -0.5, -0.4},
+		},
+	}
+
+	for i, test := range tests {
+		d := pointDistances(test.points)
+		samples := datautils.SilhouetteSamples(d, test.clusters)
+		if !floats.EqualApprox(samples, test.samples, 1e-12) {
+			t.Errorf("Test %d: Expected silhouette coefficients: %v but received %v", i+1, test.samples, samples)
+		}
+		if s, expected := datautils.Silhouette(d, test.clusters), floats.Sum(test.samples)/float64(len(test.samples)); math.Abs(s-expected) > 1e-12 {
+			t.Errorf("Test %d: Expected silhouette score: %v but received %v", i+1, expected, s)
+		}
+	}
+}
+
+func TestSilhouettePanics(t *testing.T) {
+	tests := []struct {
+		d        mat.Matrix
+		clusters []int
+	}{
+		{d: pointDistances([]float64{0, 1}), clusters: []int{0, 0}},
+		{d: pointDistances([]float64{0, 1}), clusters: []int{0, 1, 1}},
+		{d: mat.NewDense(2, 3, nil), clusters: []int{0, 1}},
+	}
+
+	for i, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Test %d: Expected panic but received none", i+1)
+				}
+			}()
+			datautils.SilhouetteSamples(test.d, test.clusters)
+		}()
+	}
+}
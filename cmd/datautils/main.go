@@ -0,0 +1,312 @@
+// Command datautils evaluates model predictions from the command line using the metric implementations of the
+// datautils package so that they can be used from shell scripts and by non-Go users.
+//
+// Binary classification predictions and labels are read from CSV or JSON lines files:
+//
+//	datautils -format csv -input predictions.csv -metrics ap,auc,f1 -threshold 0.5
+//	datautils -format jsonl -input predictions.jsonl -output json -roc-plot roc.png
+//
+// Ranking runs are read from a TREC run file and its corresponding relevance judgements (qrels):
+//
+//	datautils -format trec -run run.txt -qrels qrels.txt -metrics mrr,ndcg@10,hr@5
+//
+// Binary classification metrics may be any metric in datautils.DefaultMetricRegistry e.g. ap (average
+// precision), auc (area under the ROC curve), precision, recall, f1, accuracy, specificity, mcc and kappa
+// (the last 7 at -threshold).  Supported ranking metrics are mrr, ndcg@k and hr@k (hit rate).
+//
+// NaN predictions and non-finite (e.g. missing) labels are rejected with an error unless -non-finite is drop
+// (to drop the affected observations) or lowest (to rank NaN predictions last).
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/james-bowman/datautils"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// options contains the parsed command line flags.
+type options struct {
+	format      string
+	input       string
+	runFile     string
+	qrelsFile   string
+	predColumn  string
+	labelColumn string
+	metrics     string
+	threshold   float64
+	nonFinite   string
+	output      string
+	prPlot      string
+	rocPlot     string
+}
+
+// result is the value of a single named metric.
+type result struct {
+	name  string
+	value float64
+}
+
+func run(args []string, stdout io.Writer) error {
+	var opts options
+	fs := flag.NewFlagSet("datautils", flag.ContinueOnError)
+	fs.StringVar(&opts.format, "format", "csv", "input format: csv, jsonl or trec")
+	fs.StringVar(&opts.input, "input", "", "path of the CSV or JSON lines file of predictions and labels (- for stdin)")
+	fs.StringVar(&opts.runFile, "run", "", "path of the TREC run file (trec format only)")
+	fs.StringVar(&opts.qrelsFile, "qrels", "", "path of the TREC qrels file (trec format only)")
+	fs.StringVar(&opts.predColumn, "prediction-column", "prediction", "name of the prediction column or field")
+	fs.StringVar(&opts.labelColumn, "label-column", "label", "name of the label column or field")
+	fs.StringVar(&opts.metrics, "metrics", "", "comma separated list of metrics (default ap,auc,f1 or mrr,ndcg@10 for trec)")
+	fs.Float64Var(&opts.threshold, "threshold", 0.5, "decision threshold for threshold based metrics")
+	fs.StringVar(&opts.nonFinite, "non-finite", "error", "handling of NaN predictions and non-finite labels: error (the default, rejecting inputs with e.g. missing labels), drop or lowest")
+	fs.StringVar(&opts.output, "output", "text", "report format: text or json")
+	fs.StringVar(&opts.prPlot, "pr-plot", "", "path to save a plot of the precision recall curve (e.g. pr.png)")
+	fs.StringVar(&opts.rocPlot, "roc-plot", "", "path to save a plot of the ROC curve (e.g. roc.png)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var results []result
+	var err error
+	switch opts.format {
+	case "csv", "jsonl":
+		results, err = evaluateBinary(opts)
+	case "trec":
+		results, err = evaluateRanking(opts)
+	default:
+		err = fmt.Errorf("unknown format %q", opts.format)
+	}
+	if err != nil {
+		return err
+	}
+
+	return report(stdout, opts.output, results)
+}
+
+// open opens the file at path for reading or returns stdin if path is "-".
+func open(path string) (io.ReadCloser, error) {
+	if path == "" {
+		return nil, errors.New("no input file specified")
+	}
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+func evaluateBinary(opts options) ([]result, error) {
+	r, err := open(opts.input)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var predictions, labels []float64
+	if opts.format == "csv" {
+		predictions, labels, err = readCSV(r, opts.predColumn, opts.labelColumn)
+	} else {
+		predictions, labels, err = readJSONL(r, opts.predColumn, opts.labelColumn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var policy datautils.NonFinitePolicy
+	if err := policy.UnmarshalText([]byte(opts.nonFinite)); err != nil {
+		return nil, err
+	}
+	if predictions, labels, _, err = datautils.HandleNonFinite(predictions, labels, policy); err != nil {
+		return nil, err
+	}
+
+	names := metricNames(opts.metrics, "ap,auc,f1")
+	results := make([]result, 0, len(names))
+	for _, name := range names {
+		m, err := datautils.LookupMetric(name)
+		if err != nil {
+			return nil, err
+		}
+		v := m.Compute(predictions, labels, datautils.MetricOptions{Threshold: opts.threshold})
+		results = append(results, result{name: name, value: v})
+	}
+
+	if opts.prPlot != "" {
+		if err := datautils.SavePlot(datautils.NewPrecisionRecallCurve(predictions, labels).Plot(), opts.prPlot, 15, 15); err != nil {
+			return nil, err
+		}
+	}
+	if opts.rocPlot != "" {
+		if err := datautils.SavePlot(datautils.NewROCCurve(predictions, labels).Plot(), opts.rocPlot, 15, 15); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+func evaluateRanking(opts options) ([]result, error) {
+	rf, err := open(opts.runFile)
+	if err != nil {
+		return nil, err
+	}
+	defer rf.Close()
+	r, err := datautils.ReadRun(rf)
+	if err != nil {
+		return nil, err
+	}
+
+	qf, err := open(opts.qrelsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer qf.Close()
+	qrels, err := datautils.ReadQrels(qf)
+	if err != nil {
+		return nil, err
+	}
+
+	set := datautils.NewTRECEvaluationSet(r, qrels)
+
+	names := metricNames(opts.metrics, "mrr,ndcg@10")
+	results := make([]result, 0, len(names))
+	for _, name := range names {
+		var v float64
+		switch {
+		case name == "mrr":
+			v = set.MeanReciprocalRank()
+		case strings.HasPrefix(name, "ndcg@"), strings.HasPrefix(name, "hr@"):
+			k, err := strconv.Atoi(name[strings.Index(name, "@")+1:])
+			if err != nil || k < 1 {
+				return nil, fmt.Errorf("invalid cut-off for metric %q", name)
+			}
+			if strings.HasPrefix(name, "hr@") {
+				v = set.HitRate(k)
+			} else {
+				v = set.MeanNormalisedDiscountedCumulativeGains([]int{k}, datautils.TraditionalRelevancy)[0]
+			}
+		default:
+			return nil, fmt.Errorf("unknown metric %q for trec format", name)
+		}
+		results = append(results, result{name: name, value: v})
+	}
+
+	return results, nil
+}
+
+// metricNames splits the comma separated list of metrics, using defaults if the list is empty.
+func metricNames(list, defaults string) []string {
+	if list == "" {
+		list = defaults
+	}
+	names := strings.Split(list, ",")
+	for i := range names {
+		names[i] = strings.ToLower(strings.TrimSpace(names[i]))
+	}
+	return names
+}
+
+// readCSV reads the prediction and label columns, identified by the header, from CSV data.  Other columns
+// (e.g. IDs) are ignored so need not be numeric.  Missing values (see datautils.DefaultMissingValues) are
+// read as NaN.
+func readCSV(r io.Reader, predColumn, labelColumn string) (predictions, labels []float64, err error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	predIndex, labelIndex := -1, -1
+	for j, name := range header {
+		switch strings.TrimSpace(name) {
+		case predColumn:
+			predIndex = j
+		case labelColumn:
+			labelIndex = j
+		}
+	}
+	if predIndex < 0 {
+		return nil, nil, fmt.Errorf("prediction column %q not found", predColumn)
+	}
+	if labelIndex < 0 {
+		return nil, nil, fmt.Errorf("label column %q not found", labelColumn)
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return predictions, labels, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		p, err := parseCSVValue(record[predIndex])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d, column %q: %w", line, predColumn, err)
+		}
+		l, err := parseCSVValue(record[labelIndex])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d, column %q: %w", line, labelColumn, err)
+		}
+		predictions = append(predictions, p)
+		labels = append(labels, l)
+	}
+}
+
+// parseCSVValue parses a numeric CSV field, reading missing values (see datautils.DefaultMissingValues)
+// as NaN.
+func parseCSVValue(field string) (float64, error) {
+	field = strings.TrimSpace(field)
+	for _, token := range datautils.DefaultMissingValues {
+		if field == token {
+			return math.NaN(), nil
+		}
+	}
+	return strconv.ParseFloat(field, 64)
+}
+
+// readJSONL reads the prediction and label fields from JSON lines data (see datautils.ReadPredictionLog).
+// Other fields (e.g. query IDs) are ignored so may be of any type and missing or null labels are read as NaN.
+func readJSONL(r io.Reader, predField, labelField string) (predictions, labels []float64, err error) {
+	l, err := datautils.ReadPredictionLog(r, datautils.PredictionLogFields{Score: predField, Label: labelField})
+	if err != nil {
+		return nil, nil, err
+	}
+	return l.Predictions, l.Labels, nil
+}
+
+// report writes the results to w in the specified format.  JSON reports are a single object mapping each
+// metric name to its value with undefined (NaN) and infinite values written as strings (see
+// datautils.JSONFloat).
+func report(w io.Writer, format string, results []result) error {
+	switch format {
+	case "text":
+		for _, r := range results {
+			if _, err := fmt.Fprintf(w, "%-12s %f\n", r.name, r.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		values := make(map[string]datautils.JSONFloat, len(results))
+		for _, r := range results {
+			values[r.name] = datautils.JSONFloat(r.value)
+		}
+		return json.NewEncoder(w).Encode(values)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	csv := writeFile(t, dir, "predictions.csv", "prediction,label\n0.1,0\n0.4,0\n0.35,1\n0.8,1\n")
+	jsonl := writeFile(t, dir, "predictions.jsonl", "{\"score\": 0.1, \"y\": 0, \"query\": \"a\"}\n{\"score\": 0.4, \"y\": 0, \"cached\": true}\n\n{\"score\": 0.35, \"y\": 1}\n{\"score\": 0.8, \"y\": 1}\n")
+	withIDs := writeFile(t, dir, "ids.csv", "id,prediction,label\na1,0.1,0\na2,0.4,0\na3,0.35,1\na4,0.8,1\n")
+	runFile := writeFile(t, dir, "run.txt", "q1 Q0 d1 1 0.9 test\nq1 Q0 d2 2 0.8 test\nq2 Q0 d3 1 0.7 test\nq2 Q0 d4 2 0.6 test\n")
+	qrels := writeFile(t, dir, "qrels.txt", "q1 0 d1 1\nq2 0 d4 1\n")
+	nonFinite := writeFile(t, dir, "nonfinite.csv", "prediction,label\n0.1,0\nNaN,1\n0.4,0\n0.35,1\n0.8,1\n0.9,NaN\n")
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{
+			args:     []string{"-input", csv, "-metrics", "ap,auc,accuracy", "-threshold", "0.3"},
+			expected: "ap           0.833333\nauc          0.750000\naccuracy     0.750000\n",
+		},
+		{
+			args:     []string{"-format", "jsonl", "-input", jsonl, "-prediction-column", "score", "-label-column", "y", "-metrics", "auc,recall", "-output", "json"},
+			expected: "{\"auc\":0.75,\"recall\":0.5}\n",
+		},
+		{
+			args:     []string{"-input", withIDs, "-metrics", "ap,auc,accuracy", "-threshold", "0.3"},
+			expected: "ap           0.833333\nauc          0.750000\naccuracy     0.750000\n",
+		},
+		{
+			args:     []string{"-input", nonFinite, "-metrics", "precision", "-threshold", "0.95", "-non-finite", "drop", "-output", "json"},
+			expected: "{\"precision\":\"NaN\"}\n",
+		},
+		{
+			args:     []string{"-input", nonFinite, "-metrics", "ap,auc", "-non-finite", "drop"},
+			expected: "ap           0.833333\nauc          0.750000\n",
+		},
+		{
+			args:     []string{"-input", nonFinite, "-metrics", "auc", "-non-finite", "lowest"},
+			expected: "auc          0.500000\n",
+		},
+		{
+			args:     []string{"-format", "trec", "-run", runFile, "-qrels", qrels, "-metrics", "mrr,hr@1", "-output", "json"},
+			expected: "{\"hr@1\":0.5,\"mrr\":0.75}\n",
+		},
+	}
+
+	for i, test := range tests {
+		var out bytes.Buffer
+		if err := run(test.args, &out); err != nil {
+			t.Errorf("Test %d: Unexpected error: %v", i+1, err)
+			continue
+		}
+		if out.String() != test.expected {
+			t.Errorf("Test %d: Expected output:\n%s\nbut received:\n%s", i+1, test.expected, out.String())
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	dir := t.TempDir()
+	csv := writeFile(t, dir, "predictions.csv", "prediction,label\n0.1,0\n0.8,1\n")
+	nonFinite := writeFile(t, dir, "nonfinite.csv", "prediction,label\n0.1,0\nNaN,1\n")
+
+	tests := [][]string{
+		{"-input", csv, "-metrics", "unknown"},
+		{"-input", csv, "-prediction-column", "missing"},
+		{"-format", "xml", "-input", csv},
+		{"-input", csv, "-output", "yaml"},
+		{"-format", "trec", "-run", csv},
+		{"-input", nonFinite},
+		{"-input", csv, "-non-finite", "ignore"},
+	}
+
+	for i, args := range tests {
+		var out bytes.Buffer
+		if err := run(args, &out); err == nil {
+			t.Errorf("Test %d: Expected error for args %v but received nil", i+1, args)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package datautils
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// QueryDelta contains the value of a metric for a single query under each of two runs being compared.
+type QueryDelta struct {
+	// Query is the query ID
+	Query string
+
+	// A and B are the values of the metric for the query under runs A and B respectively
+	A, B float64
+
+	// Delta is the change in the metric from run A to run B (B - A) so that positive values are improvements
+	Delta float64
+}
+
+// PairedComparison compares two runs (e.g. a baseline and a candidate ranker) over the same set of queries query by
+// query, as in the standard IR "run diff" workflow.  Run B is compared against run A so that wins are queries for
+// which B scores higher than A.
+type PairedComparison struct {
+	// Metric is the name of the compared metric
+	Metric string
+
+	// Queries contains the per query metric values of the queries common to both runs ordered by query ID
+	Queries []QueryDelta
+
+	// Wins, Losses and Ties are the numbers of queries for which run B scores higher than, lower than or the same
+	// as run A respectively.  Queries for which the metric is undefined (NaN) under either run count as ties
+	Wins, Losses, Ties int
+
+	// MeanA and MeanB are the means of the per query metric values of runs A and B respectively
+	MeanA, MeanB float64
+
+	// PValue is the two-sided p-value of a paired permutation test (see PairedPermutationTest) of the difference
+	// between the means
+	PValue float64
+}
+
+// NewPairedComparison compares the per query values of metric for the evaluation sets of two runs, a and b.  Each
+// query's ranking is evaluated separately (opts.Queries is ignored) and only queries present in both sets are
+// compared.  The significance of the difference between the runs is tested with a paired permutation test using n
+// permutations with the specified seed so that results are reproducible.
+func NewPairedComparison(a, b EvaluationSet, metric Metric, opts MetricOptions, n int, seed int64) PairedComparison {
+	opts.Queries = nil
+	c := PairedComparison{Metric: metric.Name()}
+
+	scoresA := make([]float64, 0, len(a))
+	scoresB := make([]float64, 0, len(a))
+	for _, q := range a.Queries() {
+		rb, ok := b[q]
+		if !ok {
+			continue
+		}
+		ra := a[q]
+		d := QueryDelta{
+			Query: q,
+			A:     metric.Compute(ra.Predictions, ra.Relevancies, opts),
+			B:     metric.Compute(rb.Predictions, rb.Relevancies, opts),
+		}
+		d.Delta = d.B - d.A
+		switch {
+		case d.Delta > 0:
+			c.Wins++
+		case d.Delta < 0:
+			c.Losses++
+		default:
+			c.Ties++
+		}
+		c.Queries = append(c.Queries, d)
+		scoresA = append(scoresA, d.A)
+		scoresB = append(scoresB, d.B)
+		c.MeanA += d.A
+		c.MeanB += d.B
+	}
+	if len(c.Queries) > 0 {
+		c.MeanA /= float64(len(c.Queries))
+		c.MeanB /= float64(len(c.Queries))
+	}
+	c.PValue = PairedPermutationTest(scoresA, scoresB, n, seed)
+	return c
+}
+
+// Regressions returns up to k of the queries for which run B scores lower than run A ordered by decreasing size of
+// regression (most negative delta first).  Equal regressions are ordered by query ID.
+func (c PairedComparison) Regressions(k int) []QueryDelta {
+	var regressions []QueryDelta
+	for _, d := range c.Queries {
+		if d.Delta < 0 {
+			regressions = append(regressions, d)
+		}
+	}
+	sort.SliceStable(regressions, func(i, j int) bool { return regressions[i].Delta < regressions[j].Delta })
+	if k < len(regressions) {
+		regressions = regressions[:k]
+	}
+	return regressions
+}
+
+// String formats the comparison for printing as a summary of the means, win/loss/tie counts and significance
+// followed by the (up to) 10 largest regressions.
+func (c PairedComparison) String() string {
+	s := fmt.Sprintf("Metric = %s, Queries = %d\n", c.Metric, len(c.Queries))
+	s = fmt.Sprintf("%sMean A = %f, Mean B = %f, Delta = %f, p = %f\n", s, c.MeanA, c.MeanB, c.MeanB-c.MeanA, c.PValue)
+	s = fmt.Sprintf("%sWins = %d, Losses = %d, Ties = %d\n", s, c.Wins, c.Losses, c.Ties)
+	s = s + "Query                |     A      |     B      |   Delta\n"
+	s = s + "------------------------------------------------------------\n"
+	for _, d := range c.Regressions(10) {
+		s = fmt.Sprintf("%s%-20s | %10.4f | %10.4f | %10.4f\n", s, d.Query, d.A, d.B, d.Delta)
+	}
+	return s
+}
+
+// WriteCSV writes the per query metric values to w as CSV with a header followed by a record per query ordered by
+// query ID.  The columns are query, a, b and delta.  comma is the field delimiter e.g. '\t' for TSV (or ',' if
+// zero).
+func (c PairedComparison) WriteCSV(w io.Writer, comma rune) error {
+	records := [][]string{{"query", "a", "b", "delta"}}
+	for _, d := range c.Queries {
+		records = append(records, []string{d.Query, formatFloat(d.A), formatFloat(d.B), formatFloat(d.Delta)})
+	}
+	return writeCSV(w, comma, records)
+}
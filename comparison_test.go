@@ -0,0 +1,80 @@
+package datautils_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestPairedComparison(t *testing.T) {
+	a := datautils.EvaluationSet{
+		"q1": datautils.NewRankingEvaluation([]float64{0.9, 0.5, 0.1}, []float64{1, 0, 0}),
+		"q2": datautils.NewRankingEvaluation([]float64{0.9, 0.5, 0.1}, []float64{0, 1, 0}),
+		"q3": datautils.NewRankingEvaluation([]float64{0.9, 0.5, 0.1}, []float64{0, 0, 1}),
+		"q4": datautils.NewRankingEvaluation([]float64{0.9, 0.5}, []float64{1, 0}),
+		"q5": datautils.NewRankingEvaluation([]float64{0.9}, []float64{1}),
+	}
+	b := datautils.EvaluationSet{
+		"q1": datautils.NewRankingEvaluation([]float64{0.1, 0.5, 0.9}, []float64{1, 0, 0}),
+		"q2": datautils.NewRankingEvaluation([]float64{0.5, 0.9, 0.1}, []float64{0, 1, 0}),
+		"q3": datautils.NewRankingEvaluation([]float64{0.5, 0.1, 0.9}, []float64{0, 0, 1}),
+		"q4": datautils.NewRankingEvaluation([]float64{0.5, 0.9}, []float64{1, 0}),
+		"q6": datautils.NewRankingEvaluation([]float64{0.9}, []float64{1}),
+	}
+	mrr, err := datautils.LookupMetric("mrr")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := datautils.NewPairedComparison(a, b, mrr, datautils.MetricOptions{Queries: []string{"ignored"}}, 1000, 42)
+
+	expected := []datautils.QueryDelta{
+		{Query: "q1", A: 1, B: 1.0 / 3},
+		{Query: "q2", A: 0.5, B: 1},
+		{Query: "q3", A: 1.0 / 3, B: 1},
+		{Query: "q4", A: 1, B: 0.5},
+	}
+	for i := range expected {
+		expected[i].Delta = expected[i].B - expected[i].A
+	}
+	if len(c.Queries) != len(expected) {
+		t.Fatalf("Expected %d queries but received %d", len(expected), len(c.Queries))
+	}
+	for i, d := range c.Queries {
+		if d != expected[i] {
+			t.Errorf("Expected %+v but received %+v", expected[i], d)
+		}
+	}
+	if c.Metric != "mrr" || c.Wins != 2 || c.Losses != 2 || c.Ties != 0 {
+		t.Errorf("Unexpected comparison: %+v", c)
+	}
+	if math.Abs(c.MeanA-17.0/24) > 1e-12 || math.Abs(c.MeanB-17.0/24) > 1e-12 {
+		t.Errorf("Unexpected means: A=%v, B=%v", c.MeanA, c.MeanB)
+	}
+	scoresA, scoresB := []float64{1, 0.5, 1.0 / 3, 1}, []float64{1.0 / 3, 1, 1, 0.5}
+	if p := datautils.PairedPermutationTest(scoresA, scoresB, 1000, 42); c.PValue != p {
+		t.Errorf("Expected p-value: %v but received %v", p, c.PValue)
+	}
+
+	regressions := c.Regressions(1)
+	if len(regressions) != 1 || regressions[0].Query != "q1" {
+		t.Errorf("Expected largest regression q1 but received %+v", regressions)
+	}
+	if regressions := c.Regressions(10); len(regressions) != 2 || regressions[1].Query != "q4" {
+		t.Errorf("Expected regressions q1 and q4 but received %+v", regressions)
+	}
+	if s := c.String(); !strings.Contains(s, "Wins = 2, Losses = 2, Ties = 0") {
+		t.Errorf("Unexpected summary:\n%s", s)
+	}
+
+	var buf bytes.Buffer
+	if err := c.WriteCSV(&buf, '\t'); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 5 || lines[0] != "query\ta\tb\tdelta" || lines[4] != "q4\t1\t0.5\t-0.5" {
+		t.Errorf("Unexpected CSV:\n%s", buf.String())
+	}
+}
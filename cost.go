@@ -0,0 +1,167 @@
+package datautils
+
+import (
+	"fmt"
+	"image/color"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// CostCurve represents a cost curve (Drummond & Holte, 2006) for evaluating a binary classifier in terms of
+// expected misclassification cost rather than accuracy or F1.  The x axis is the operating point expressed as the
+// probability cost of the positive class, PC(+) = p(+)C(FN) / (p(+)C(FN) + p(-)C(FP)), which combines the class
+// priors and misclassification costs into a single value between 0 and 1.  Each threshold (point of the ROC curve)
+// is a straight line giving its normalised expected cost, FNR * PC(+) + FPR * (1 - PC(+)), and the cost curve is
+// the lower envelope of these lines i.e. the lowest normalised expected cost achievable at each operating point by
+// choosing the best threshold.  The costs and prior the curve is constructed with define the operating point of
+// interest.
+type CostCurve struct {
+	// ProbabilityCost contains the operating points, in ascending order, at the vertices of the lower envelope
+	ProbabilityCost []float64
+
+	// NormalisedExpectedCost contains the normalised expected cost of the lower envelope at each operating point
+	NormalisedExpectedCost []float64
+
+	// FPR, FNR and Thresholds contain the false positive rate, false negative rate and threshold of each point of
+	// the ROC curve for the predictions, ordered by descending threshold (see ROCCurve)
+	FPR, FNR, Thresholds []float64
+
+	// CostFN and CostFP are the costs of a false negative and a false positive respectively
+	CostFN, CostFP float64
+
+	// Prior is the prior probability of the positive class in deployment
+	Prior float64
+}
+
+// NewCostCurve creates a new cost curve from the supplied predictions and ground truth labels (see NewROCCurve).
+// costFN and costFP are the costs of a false negative and a false positive respectively and prior is the expected
+// probability of the positive class in deployment, which may differ from the proportion of positives within the
+// labels.
+func NewCostCurve[P, L Float](predictions []P, labels []L, costFN, costFP, prior float64) CostCurve {
+	validateCosts(prior, costFN, costFP)
+
+	roc := NewROCCurve(predictions, labels)
+	fnr := make([]float64, len(roc.TPR))
+	for i, tpr := range roc.TPR {
+		fnr[i] = 1 - tpr
+	}
+
+	c := CostCurve{
+		FPR:        roc.FPR,
+		FNR:        fnr,
+		Thresholds: roc.Thresholds,
+		CostFN:     costFN,
+		CostFP:     costFP,
+		Prior:      prior,
+	}
+	c.ProbabilityCost, c.NormalisedExpectedCost = c.envelope()
+
+	return c
+}
+
+// envelope calculates the vertices of the lower envelope of the cost lines of the ROC points.  As the ROC points
+// are ordered by ascending FPR and descending FNR, the slopes of their cost lines (FNR - FPR) are strictly
+// decreasing allowing the envelope to be found in a single pass (the convex hull trick).
+func (c CostCurve) envelope() (x, y []float64) {
+	slope := func(i int) float64 { return c.FNR[i] - c.FPR[i] }
+	cost := func(i int, pc float64) float64 { return c.FPR[i] + slope(i)*pc }
+	// intersect returns the operating point at which the cost lines of i and j intersect
+	intersect := func(i, j int) float64 { return (c.FPR[j] - c.FPR[i]) / (slope(i) - slope(j)) }
+
+	var hull []int
+	for i := range c.FPR {
+		for len(hull) >= 2 && intersect(hull[len(hull)-2], i) <= intersect(hull[len(hull)-2], hull[len(hull)-1]) {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, i)
+	}
+
+	// find the line of the hull that is lowest at operating point 0 then walk the intersections up to 1
+	var h int
+	for h < len(hull)-1 && intersect(hull[h], hull[h+1]) <= 0 {
+		h++
+	}
+	x = append(x, 0)
+	y = append(y, cost(hull[h], 0))
+	for ; h < len(hull)-1; h++ {
+		pc := intersect(hull[h], hull[h+1])
+		if pc >= 1 {
+			break
+		}
+		x = append(x, pc)
+		y = append(y, cost(hull[h], pc))
+	}
+	x = append(x, 1)
+	y = append(y, cost(hull[h], 1))
+
+	return x, y
+}
+
+// OperatingPoint returns the probability cost of the positive class, PC(+), for the costs and prior the curve was
+// constructed with.
+func (c CostCurve) OperatingPoint() float64 {
+	return c.Prior * c.CostFN / (c.Prior*c.CostFN + (1-c.Prior)*c.CostFP)
+}
+
+// MinimumExpectedCost returns the lowest expected cost per observation, p(+)C(FN)FNR + p(-)C(FP)FPR, achievable
+// for the costs and prior the curve was constructed with along with the threshold that achieves it.  Predictions
+// greater than or equal to the threshold should be classified as positive.  Where several thresholds achieve the
+// same cost, the highest is returned.
+func (c CostCurve) MinimumExpectedCost() (cost, threshold float64) {
+	for i := range c.FPR {
+		v := c.Prior*c.CostFN*c.FNR[i] + (1-c.Prior)*c.CostFP*c.FPR[i]
+		if i == 0 || v < cost {
+			cost, threshold = v, c.Thresholds[i]
+		}
+	}
+	return cost, threshold
+}
+
+// Plot renders the cost curve as a plot for visualisation.  The lower envelope of the trivial classifiers, which
+// always predict negative or always predict positive, is shown dashed for reference and the operating point for
+// the costs and prior the curve was constructed with is marked with a vertical line.
+func (c CostCurve) Plot() *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+
+	cost, _ := c.MinimumExpectedCost()
+	p.Title.Text = PlotTheme.title(fmt.Sprintf("Cost Curve, Minimum Expected Cost=%f", cost))
+	p.X.Label.Text = "Probability Cost PC(+)"
+	p.Y.Label.Text = "Normalised Expected Cost"
+	p.X.Min, p.X.Max = 0, 1
+	p.Y.Min, p.Y.Max = 0, 1
+
+	trivial, err := plotter.NewLine(plotter.XYs{{X: 0, Y: 0}, {X: 0.5, Y: 0.5}, {X: 1, Y: 0}})
+	if err != nil {
+		panic(err)
+	}
+	trivial.Color = color.Gray{Y: 128}
+	trivial.Dashes = []vg.Length{vg.Points(4), vg.Points(4)}
+	p.Add(trivial)
+
+	pts := make(plotter.XYs, len(c.ProbabilityCost))
+	for i := range pts {
+		pts[i].X = c.ProbabilityCost[i]
+		pts[i].Y = c.NormalisedExpectedCost[i]
+	}
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		panic(err)
+	}
+	line.Color = PlotTheme.Primary
+	p.Add(line)
+
+	op := c.OperatingPoint()
+	marker, err := plotter.NewLine(plotter.XYs{{X: op, Y: 0}, {X: op, Y: 1}})
+	if err != nil {
+		panic(err)
+	}
+	marker.Color = color.Gray{Y: 64}
+	p.Add(marker)
+
+	return p
+}
@@ -0,0 +1,45 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestCostCurve(t *testing.T) {
+	predictions := []float64{0.1, 0.4, 0.35, 0.8}
+	labels := []float64{0, 0, 1, 1}
+
+	tests := []struct {
+		costFN, costFP, prior float64
+		operatingPoint        float64
+		cost                  float64
+		threshold             float64
+	}{
+		{costFN: 1, costFP: 1, prior: 0.5, operatingPoint: 0.5, cost: 0.25, threshold: 0.8},
+		{costFN: 4, costFP: 1, prior: 0.5, operatingPoint: 0.8, cost: 0.25, threshold: 0.35},
+		{costFN: 1, costFP: 1, prior: 0, operatingPoint: 0, cost: 0, threshold: math.Inf(1)},
+	}
+
+	for i, test := range tests {
+		curve := datautils.NewCostCurve(predictions, labels, test.costFN, test.costFP, test.prior)
+
+		// the envelope is independent of the costs and prior
+		if x := []float64{0, 0.5, 1}; !floats.Equal(curve.ProbabilityCost, x) {
+			t.Errorf("Test %d: Expected probability costs: %v but received %v", i+1, x, curve.ProbabilityCost)
+		}
+		if y := []float64{0, 0.25, 0}; !floats.Equal(curve.NormalisedExpectedCost, y) {
+			t.Errorf("Test %d: Expected normalised expected costs: %v but received %v", i+1, y, curve.NormalisedExpectedCost)
+		}
+
+		if op := curve.OperatingPoint(); op != test.operatingPoint {
+			t.Errorf("Test %d: Expected operating point: %v but received %v", i+1, test.operatingPoint, op)
+		}
+		cost, threshold := curve.MinimumExpectedCost()
+		if cost != test.cost || threshold != test.threshold {
+			t.Errorf("Test %d: Expected minimum cost: %v at threshold %v but received %v at %v", i+1, test.cost, test.threshold, cost, threshold)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package datautils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// HeaderMode specifies whether the first record of a CSV file contains column names.
+type HeaderMode int
+
+const (
+	// HeaderAuto detects a header by checking whether any field of the first record is non-numeric
+	HeaderAuto HeaderMode = iota
+
+	// HeaderPresent indicates that the first record contains column names
+	HeaderPresent
+
+	// HeaderAbsent indicates that there is no header and all records contain data.  Columns are named
+	// by their (zero based) index
+	HeaderAbsent
+)
+
+// DefaultMissingValues are the tokens treated as missing values when reading CSV data if none are specified.
+var DefaultMissingValues = []string{"", "NA", "N/A", "NaN", "nan", "null", "NULL"}
+
+// CSVOptions configures how CSV data is read by ReadCSV.
+type CSVOptions struct {
+	// Comma is the field delimiter.  If zero, ',' is used
+	Comma rune
+
+	// Comment, if not zero, is the comment character.  Lines beginning with the comment character are ignored
+	Comment rune
+
+	// LazyQuotes relaxes the quoting rules so that quotes may appear in unquoted fields and non-doubled quotes
+	// may appear in quoted fields
+	LazyQuotes bool
+
+	// Header specifies whether the first record contains column names
+	Header HeaderMode
+
+	// LabelColumn is the name of the column containing the labels.  If empty, the data is assumed to be
+	// unlabelled and all columns are read as features
+	LabelColumn string
+
+	// MissingValues are the tokens representing missing values which are read as NaN.  If nil,
+	// DefaultMissingValues is used
+	MissingValues []string
+
+	// CategoricalColumns are the names of the columns containing categorical (string) values which are read into
+	// Dataset.Categorical rather than Features.  Fields are read verbatim (after trimming surrounding space) and
+	// missing value tokens are not replaced.  As categorical columns are identified by name, the first record is
+	// always treated as a header unless Header is HeaderAbsent
+	CategoricalColumns []string
+}
+
+// ReadCSV reads CSV data into a Dataset.  All fields, apart from those within the header and categorical columns,
+// must either be numeric or one of the configured missing value tokens (represented as NaN within the Dataset).
+func ReadCSV(r io.Reader, opts CSVOptions) (Dataset, error) {
+	reader := csv.NewReader(r)
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+	reader.Comment = opts.Comment
+	reader.LazyQuotes = opts.LazyQuotes
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return Dataset{}, fmt.Errorf("datautils: failed to read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return Dataset{}, fmt.Errorf("datautils: no CSV records found")
+	}
+
+	missing := opts.MissingValues
+	if missing == nil {
+		missing = DefaultMissingValues
+	}
+	isMissing := make(map[string]bool, len(missing))
+	for _, v := range missing {
+		isMissing[v] = true
+	}
+
+	var header []string
+	switch {
+	case opts.Header == HeaderPresent, opts.Header == HeaderAuto && opts.CategoricalColumns != nil:
+		header, records = records[0], records[1:]
+	case opts.Header == HeaderAuto:
+		for _, field := range records[0] {
+			if _, err := parseField(field, isMissing); err != nil {
+				header, records = records[0], records[1:]
+				break
+			}
+		}
+	}
+	if header == nil {
+		header = make([]string, len(records[0]))
+		for i := range header {
+			header[i] = strconv.Itoa(i)
+		}
+	}
+	if len(records) == 0 {
+		return Dataset{}, fmt.Errorf("datautils: no CSV data records found")
+	}
+
+	labelCol := -1
+	if opts.LabelColumn != "" {
+		if labelCol = indexOf(header, opts.LabelColumn); labelCol == -1 {
+			return Dataset{}, fmt.Errorf("datautils: label column %q not found", opts.LabelColumn)
+		}
+	}
+
+	categoricalCols := make(map[int]bool, len(opts.CategoricalColumns))
+	for _, name := range opts.CategoricalColumns {
+		i := indexOf(header, name)
+		if i == -1 {
+			return Dataset{}, fmt.Errorf("datautils: categorical column %q not found", name)
+		}
+		categoricalCols[i] = true
+	}
+
+	var columns []string
+	for i, name := range header {
+		if i != labelCol && !categoricalCols[i] {
+			columns = append(columns, name)
+		}
+	}
+	if len(columns) == 0 {
+		return Dataset{}, fmt.Errorf("datautils: no feature columns found")
+	}
+
+	features := mat.NewDense(len(records), len(columns), nil)
+	var labels []float64
+	if labelCol != -1 {
+		labels = make([]float64, len(records))
+	}
+	var categorical map[string][]string
+	if len(categoricalCols) > 0 {
+		categorical = make(map[string][]string, len(categoricalCols))
+		for j := range categoricalCols {
+			categorical[header[j]] = make([]string, len(records))
+		}
+	}
+
+	for i, record := range records {
+		var col int
+		for j, field := range record {
+			if categoricalCols[j] {
+				categorical[header[j]][i] = strings.TrimSpace(field)
+				continue
+			}
+			v, err := parseField(field, isMissing)
+			if err != nil {
+				return Dataset{}, fmt.Errorf("datautils: record %d, column %q: %w", i+1, header[j], err)
+			}
+			if j == labelCol {
+				labels[i] = v
+				continue
+			}
+			features.Set(i, col, v)
+			col++
+		}
+	}
+
+	return Dataset{Features: features, Labels: labels, Columns: columns, Categorical: categorical}, nil
+}
+
+// indexOf returns the index of the first occurrence of name within names or -1 if not present.
+func indexOf(names []string, name string) int {
+	for i, v := range names {
+		if v == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseField parses a single CSV field into a float64 returning NaN for missing values.
+func parseField(field string, isMissing map[string]bool) (float64, error) {
+	field = strings.TrimSpace(field)
+	if isMissing[field] {
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(field, 64)
+}
+
+// writeCSV writes the records to w as CSV using comma as the field delimiter (or ',' if comma is zero).
+func writeCSV(w io.Writer, comma rune, records [][]string) error {
+	writer := csv.NewWriter(w)
+	if comma != 0 {
+		writer.Comma = comma
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("datautils: failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the precision recall curve to w as CSV with a header followed by a record per rank, k, in
+// ascending order of k (descending threshold).  The columns are k, threshold, precision and recall.  The first
+// record is the point @ 0 which has no threshold.  comma is the field delimiter e.g. '\t' for TSV (or ',' if
+// zero).
+func (c PrecisionRecallCurve) WriteCSV(w io.Writer, comma rune) error {
+	records := [][]string{{"k", "threshold", "precision", "recall"}}
+	for k := 0; k < len(c.Precision); k++ {
+		i := len(c.Precision) - 1 - k
+		var threshold string
+		if k > 0 {
+			threshold = formatFloat(c.Thresholds[i])
+		}
+		records = append(records, []string{strconv.Itoa(k), threshold, formatFloat(c.Precision[i]), formatFloat(c.Recall[i])})
+	}
+	return writeCSV(w, comma, records)
+}
+
+// WriteCSV writes the ROC curve to w as CSV with a header followed by a record per point in order of descending
+// threshold.  The columns are threshold, fpr and tpr.  comma is the field delimiter e.g. '\t' for TSV (or ',' if
+// zero).
+func (c ROCCurve) WriteCSV(w io.Writer, comma rune) error {
+	records := [][]string{{"threshold", "fpr", "tpr"}}
+	for i := range c.FPR {
+		var threshold string
+		if i < len(c.Thresholds) {
+			threshold = formatFloat(c.Thresholds[i])
+		}
+		records = append(records, []string{threshold, formatFloat(c.FPR[i]), formatFloat(c.TPR[i])})
+	}
+	return writeCSV(w, comma, records)
+}
+
+// WriteCSV writes the confusion matrix to w as CSV laid out as a 2 x 2 table with a header record of predicted
+// classes and a record per actual class.  Weighted matrices are written with the sums of weights rather than
+// counts.  comma is the field delimiter e.g. '\t' for TSV (or ',' if zero).
+func (c ConfusionMatrix) WriteCSV(w io.Writer, comma rune) error {
+	records := [][]string{
+		{"", "predicted_no", "predicted_yes"},
+		{"actual_no", c.cell(c.TrueNeg, c.Weights.TrueNeg), c.cell(c.FalsePos, c.Weights.FalsePos)},
+		{"actual_yes", c.cell(c.FalseNeg, c.Weights.FalseNeg), c.cell(c.TruePos, c.Weights.TruePos)},
+	}
+	return writeCSV(w, comma, records)
+}
+
+// WriteCSV writes the calibration table to w as CSV with a header followed by a record per bin in ascending order
+// of prediction.  The columns are bin, min_prediction, max_prediction, count, mean_predicted, observed_rate and
+// lift.  comma is the field delimiter e.g. '\t' for TSV (or ',' if zero).
+func (t CalibrationTable) WriteCSV(w io.Writer, comma rune) error {
+	records := [][]string{{"bin", "min_prediction", "max_prediction", "count", "mean_predicted", "observed_rate", "lift"}}
+	for _, r := range t {
+		records = append(records, []string{
+			strconv.Itoa(r.Bin), formatFloat(r.MinPrediction), formatFloat(r.MaxPrediction), strconv.Itoa(r.Count),
+			formatFloat(r.MeanPredicted), formatFloat(r.ObservedRate), formatFloat(r.Lift),
+		})
+	}
+	return writeCSV(w, comma, records)
+}
+
+// WriteMatrixCSV writes the matrix m (e.g. a correlation matrix) to w as CSV with a header record of column labels
+// followed by a record per row beginning with the row label.  If xlabels or ylabels are nil, the column or row
+// indices are used as labels.  comma is the field delimiter e.g. '\t' for TSV (or ',' if zero).
+func WriteMatrixCSV(w io.Writer, m mat.Matrix, xlabels, ylabels []string, comma rune) error {
+	r, c := m.Dims()
+	if xlabels != nil && len(xlabels) != c {
+		return fmt.Errorf("datautils: %d x labels specified for matrix with %d columns", len(xlabels), c)
+	}
+	if ylabels != nil && len(ylabels) != r {
+		return fmt.Errorf("datautils: %d y labels specified for matrix with %d rows", len(ylabels), r)
+	}
+	label := func(labels []string, i int) string {
+		if labels == nil {
+			return strconv.Itoa(i)
+		}
+		return labels[i]
+	}
+
+	header := make([]string, c+1)
+	for j := 0; j < c; j++ {
+		header[j+1] = label(xlabels, j)
+	}
+	records := [][]string{header}
+	for i := 0; i < r; i++ {
+		record := make([]string, c+1)
+		record[0] = label(ylabels, i)
+		for j := 0; j < c; j++ {
+			record[j+1] = formatFloat(m.At(i, j))
+		}
+		records = append(records, record)
+	}
+	return writeCSV(w, comma, records)
+}
@@ -0,0 +1,184 @@
+package datautils_test
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		data     string
+		opts     datautils.CSVOptions
+		columns  []string
+		features [][]float64
+		labels   []float64
+	}{
+		{
+			data:     "a,b,label\n1,2,0\n3,NA,1\n",
+			opts:     datautils.CSVOptions{LabelColumn: "label"},
+			columns:  []string{"a", "b"},
+			features: [][]float64{{1, 2}, {3, math.NaN()}},
+			labels:   []float64{0, 1},
+		},
+		{
+			data:     "1;2.5\n\"3\";?\n",
+			opts:     datautils.CSVOptions{Comma: ';', MissingValues: []string{"?"}},
+			columns:  []string{"0", "1"},
+			features: [][]float64{{1, 2.5}, {3, math.NaN()}},
+		},
+		{
+			data:     "x,y\n1,0\n",
+			opts:     datautils.CSVOptions{Header: datautils.HeaderPresent, LabelColumn: "x"},
+			columns:  []string{"y"},
+			features: [][]float64{{0}},
+			labels:   []float64{1},
+		},
+		{
+			data:     "1,0\n2,1\n",
+			opts:     datautils.CSVOptions{Header: datautils.HeaderAbsent, LabelColumn: "1"},
+			columns:  []string{"0"},
+			features: [][]float64{{1}, {2}},
+			labels:   []float64{0, 1},
+		},
+	}
+
+	for i, test := range tests {
+		dataset, err := datautils.ReadCSV(strings.NewReader(test.data), test.opts)
+		if err != nil {
+			t.Errorf("Test %d: Unexpected error: %v", i+1, err)
+			continue
+		}
+		if strings.Join(dataset.Columns, ",") != strings.Join(test.columns, ",") {
+			t.Errorf("Test %d: Expected columns: %v but received %v", i+1, test.columns, dataset.Columns)
+		}
+		r, c := dataset.Features.Dims()
+		if r != len(test.features) || c != len(test.features[0]) {
+			t.Errorf("Test %d: Expected %dx%d features but received %dx%d", i+1, len(test.features), len(test.features[0]), r, c)
+			continue
+		}
+		for j, row := range test.features {
+			for k, v := range row {
+				got := dataset.Features.At(j, k)
+				if got != v && !(math.IsNaN(v) && math.IsNaN(got)) {
+					t.Errorf("Test %d: Expected feature (%d, %d): %v but received %v", i+1, j, k, v, got)
+				}
+			}
+		}
+		if len(dataset.Labels) != len(test.labels) {
+			t.Errorf("Test %d: Expected labels: %v but received %v", i+1, test.labels, dataset.Labels)
+		}
+		for j, v := range test.labels {
+			if dataset.Labels[j] != v {
+				t.Errorf("Test %d: Expected labels: %v but received %v", i+1, test.labels, dataset.Labels)
+			}
+		}
+	}
+}
+
+func TestReadCSVCategorical(t *testing.T) {
+	data := "colour,size,label\nred,1,0\nblue, NA,1\n,3,1\n"
+
+	dataset, err := datautils.ReadCSV(strings.NewReader(data), datautils.CSVOptions{LabelColumn: "label", CategoricalColumns: []string{"colour"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(dataset.Columns, ",") != "size" {
+		t.Errorf("Expected columns: [size] but received %v", dataset.Columns)
+	}
+	if r, c := dataset.Features.Dims(); r != 3 || c != 1 || dataset.Features.At(2, 0) != 3 || !math.IsNaN(dataset.Features.At(1, 0)) {
+		t.Errorf("Expected 3x1 features but received %v", dataset.Features)
+	}
+	if colours := dataset.Categorical["colour"]; strings.Join(colours, ",") != "red,blue," || len(dataset.Categorical) != 1 {
+		t.Errorf("Expected categorical colours [red blue ] but received %v", dataset.Categorical)
+	}
+
+	// categorical values in the first record must not be mistaken for a header
+	dataset, err = datautils.ReadCSV(strings.NewReader("red,1\n"), datautils.CSVOptions{Header: datautils.HeaderAbsent, CategoricalColumns: []string{"0"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if colours := dataset.Categorical["0"]; len(colours) != 1 || colours[0] != "red" {
+		t.Errorf("Expected categorical values [red] but received %v", dataset.Categorical)
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		opts datautils.CSVOptions
+	}{
+		{data: "", opts: datautils.CSVOptions{}},
+		{data: "a,b\n", opts: datautils.CSVOptions{}},
+		{data: "a,b\n1,2\n", opts: datautils.CSVOptions{LabelColumn: "c"}},
+		{data: "a,b\n1,x\n", opts: datautils.CSVOptions{}},
+		{data: "a,b\n1,2,3\n", opts: datautils.CSVOptions{}},
+		{data: "a,b\n1,2\n", opts: datautils.CSVOptions{CategoricalColumns: []string{"c"}}},
+	}
+
+	for i, test := range tests {
+		if _, err := datautils.ReadCSV(strings.NewReader(test.data), test.opts); err == nil {
+			t.Errorf("Test %d: Expected error but received none", i+1)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	predictions := []float64{0.1, 0.4, 0.35, 0.8}
+	labels := []float64{0, 0, 1, 1}
+
+	tests := []struct {
+		name     string
+		write    func(w io.Writer) error
+		expected string
+	}{
+		{
+			name:     "PrecisionRecallCurve",
+			write:    func(w io.Writer) error { return datautils.NewPrecisionRecallCurve(predictions, labels).WriteCSV(w, 0) },
+			expected: "k,threshold,precision,recall\n0,,1,0\n1,0.8,1,0.5\n2,0.4,0.5,0.5\n3,0.35,0.6666666666666666,1\n",
+		},
+		{
+			name:     "ROCCurve",
+			write:    func(w io.Writer) error { return datautils.NewROCCurve(predictions, labels).WriteCSV(w, '\t') },
+			expected: "threshold\tfpr\ttpr\n+Inf\t0\t0\n0.8\t0\t0.5\n0.4\t0.5\t0.5\n0.35\t0.5\t1\n0.1\t1\t1\n",
+		},
+		{
+			name:     "ConfusionMatrix",
+			write:    func(w io.Writer) error { return datautils.NewConfusionMatrix(predictions, labels, 0.3).WriteCSV(w, 0) },
+			expected: ",predicted_no,predicted_yes\nactual_no,1,1\nactual_yes,0,2\n",
+		},
+		{
+			name: "CalibrationTable",
+			write: func(w io.Writer) error {
+				return datautils.NewCalibrationTable([]float64{0, 0.1, 0.9, 1}, labels, 4, datautils.EqualWidth).WriteCSV(w, 0)
+			},
+			expected: "bin,min_prediction,max_prediction,count,mean_predicted,observed_rate,lift\n1,0,0.1,2,0.05,0,0\n2,0.9,1,2,0.95,1,2\n",
+		},
+		{
+			name: "Matrix",
+			write: func(w io.Writer) error {
+				return datautils.WriteMatrixCSV(w, mat.NewDense(2, 2, []float64{1, 0.5, 0.5, 1}), []string{"a", "b"}, nil, 0)
+			},
+			expected: ",a,b\n0,1,0.5\n1,0.5,1\n",
+		},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := test.write(&buf); err != nil {
+			t.Errorf("%s: Unexpected error: %v", test.name, err)
+		}
+		if buf.String() != test.expected {
+			t.Errorf("%s: Expected:\n%s\nbut received:\n%s", test.name, test.expected, buf.String())
+		}
+	}
+
+	if err := datautils.WriteMatrixCSV(io.Discard, mat.NewDense(2, 2, nil), []string{"a"}, nil, 0); err == nil {
+		t.Errorf("Expected error for mismatched labels but received nil")
+	}
+}
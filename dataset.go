@@ -0,0 +1,21 @@
+package datautils
+
+import "gonum.org/v1/gonum/mat"
+
+// Dataset represents a set of observations as a matrix of features (one row per observation and one
+// column per feature) along with their corresponding labels and the names of the feature columns.
+type Dataset struct {
+	// Features is the matrix of feature values with missing values represented as NaN
+	Features *mat.Dense
+
+	// Labels contains the label for each observation (row of Features) or nil if the dataset is unlabelled
+	Labels []float64
+
+	// Columns contains the name of each column of Features
+	Columns []string
+
+	// Categorical contains the values of each categorical (string valued) column, keyed by column name, or nil
+	// if the dataset has no categorical columns.  Categorical columns are not included within Features but may be
+	// encoded as features with e.g. a TargetEncoder or FrequencyEncoder.
+	Categorical map[string][]string
+}
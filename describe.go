@@ -0,0 +1,108 @@
+package datautils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// ColumnSummary contains summary statistics of the values of a single column of a dataset.  Missing values
+// (NaN) are excluded from all the statistics other than Missing.  If a column has no values other than missing
+// values, all the floating point statistics are NaN.
+type ColumnSummary struct {
+	// Column is the name of the column
+	Column string
+
+	// Count is the number of non-missing values and Missing the number of missing values
+	Count, Missing int
+
+	// Cardinality is the number of distinct non-missing values
+	Cardinality int
+
+	// Mean and StdDev are the mean and (sample) standard deviation of the values
+	Mean, StdDev float64
+
+	// Min, Q1, Median, Q3 and Max are the minimum, quartiles and maximum of the values
+	Min, Q1, Median, Q3, Max float64
+}
+
+// Summary contains summary statistics for each column of a dataset.
+type Summary []ColumnSummary
+
+// Describe calculates summary statistics for each column of m, typically the features of a Dataset (see
+// ReadCSV), as a first step in exploring a dataset.  cols contains the names of the columns or may be nil in which
+// case columns are named by their index.  Quartiles are calculated with stat.Empirical i.e. they are always one of
+// the column's values rather than interpolated between values.
+func Describe(m mat.Matrix, cols []string) Summary {
+	r, c := m.Dims()
+	if cols != nil && len(cols) != c {
+		panic(ErrLengthMismatch)
+	}
+
+	summary := make(Summary, c)
+	values := make([]float64, 0, r)
+	for j := range summary {
+		values = values[:0]
+		for i := 0; i < r; i++ {
+			if v := m.At(i, j); !math.IsNaN(v) {
+				values = append(values, v)
+			}
+		}
+
+		s := ColumnSummary{
+			Column:  featureName(cols, j),
+			Count:   len(values),
+			Missing: r - len(values),
+			Mean:    math.NaN(),
+			StdDev:  math.NaN(),
+			Min:     math.NaN(),
+			Q1:      math.NaN(),
+			Median:  math.NaN(),
+			Q3:      math.NaN(),
+			Max:     math.NaN(),
+		}
+		if len(values) > 0 {
+			sort.Float64s(values)
+			s.Cardinality = 1
+			for i := 1; i < len(values); i++ {
+				if values[i] != values[i-1] {
+					s.Cardinality++
+				}
+			}
+			s.Mean, s.StdDev = stat.MeanStdDev(values, nil)
+			s.Min, s.Max = values[0], values[len(values)-1]
+			s.Q1 = stat.Quantile(0.25, stat.Empirical, values, nil)
+			s.Median = stat.Quantile(0.5, stat.Empirical, values, nil)
+			s.Q3 = stat.Quantile(0.75, stat.Empirical, values, nil)
+		}
+		summary[j] = s
+	}
+	return summary
+}
+
+// String formats the summary for printing as a table with one row per column.
+func (s Summary) String() string {
+	t := "Column               |  Count | Missing | Distinct |       Mean |        Std |        Min |         Q1 |     Median |         Q3 |        Max\n"
+	t = t + "------------------------------------------------------------------------------------------------------------------------------------------------\n"
+	for _, c := range s {
+		t = fmt.Sprintf("%s%-20s | %6d | %7d | %8d | %10.4g | %10.4g | %10.4g | %10.4g | %10.4g | %10.4g | %10.4g\n", t,
+			c.Column, c.Count, c.Missing, c.Cardinality, c.Mean, c.StdDev, c.Min, c.Q1, c.Median, c.Q3, c.Max)
+	}
+	return t
+}
+
+// Markdown formats the summary as a Markdown table with one row per column suitable for inclusion in reports and
+// notebooks.
+func (s Summary) Markdown() string {
+	t := "| Column | Count | Missing | Distinct | Mean | Std | Min | Q1 | Median | Q3 | Max |\n"
+	t = t + "|:--|--:|--:|--:|--:|--:|--:|--:|--:|--:|--:|\n"
+	for _, c := range s {
+		t = fmt.Sprintf("%s| %s | %d | %d | %d | %g | %g | %g | %g | %g | %g | %g |\n", t,
+			strings.ReplaceAll(c.Column, "|", "\\|"), c.Count, c.Missing, c.Cardinality, c.Mean, c.StdDev, c.Min, c.Q1, c.Median, c.Q3, c.Max)
+	}
+	return t
+}
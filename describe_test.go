@@ -0,0 +1,56 @@
+package datautils_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDescribe(t *testing.T) {
+	nan := math.NaN()
+	m := mat.NewDense(5, 3, []float64{
+		1, nan, 2,
+		2, nan, 2,
+		3, nan, nan,
+		4, nan, 2,
+		5, nan, 2,
+	})
+
+	summary := datautils.Describe(m, []string{"a", "b", "c|d"})
+
+	expected := datautils.Summary{
+		{Column: "a", Count: 5, Missing: 0, Cardinality: 5, Mean: 3, StdDev: math.Sqrt(2.5), Min: 1, Q1: 2, Median: 3, Q3: 4, Max: 5},
+		{Column: "b", Count: 0, Missing: 5, Cardinality: 0, Mean: nan, StdDev: nan, Min: nan, Q1: nan, Median: nan, Q3: nan, Max: nan},
+		{Column: "c|d", Count: 4, Missing: 1, Cardinality: 1, Mean: 2, StdDev: 0, Min: 2, Q1: 2, Median: 2, Q3: 2, Max: 2},
+	}
+
+	if len(summary) != len(expected) {
+		t.Fatalf("Expected %d column summaries but received %d", len(expected), len(summary))
+	}
+	for i, e := range expected {
+		s := summary[i]
+		if s.Column != e.Column || s.Count != e.Count || s.Missing != e.Missing || s.Cardinality != e.Cardinality {
+			t.Errorf("Column %d: Expected %+v but received %+v", i, e, s)
+		}
+		if !equalWithNaN([]float64{s.Mean, s.StdDev, s.Min, s.Q1, s.Median, s.Q3, s.Max}, []float64{e.Mean, e.StdDev, e.Min, e.Q1, e.Median, e.Q3, e.Max}) {
+			t.Errorf("Column %d: Expected %+v but received %+v", i, e, s)
+		}
+	}
+
+	if s := datautils.Describe(m, nil); s[2].Column != "2" {
+		t.Errorf("Expected unnamed column to be named by index but received %q", s[2].Column)
+	}
+
+	text := summary.String()
+	if lines := strings.Split(strings.TrimSpace(text), "\n"); len(lines) != 5 || !strings.HasPrefix(lines[2], "a ") {
+		t.Errorf("Expected text table with header and 3 rows but received:\n%s", text)
+	}
+
+	md := summary.Markdown()
+	if !strings.Contains(md, "| a | 5 | 0 | 5 | 3 |") || !strings.Contains(md, `| c\|d | 4 | 1 | 1 | 2 | 0 |`) {
+		t.Errorf("Unexpected Markdown table:\n%s", md)
+	}
+}
@@ -0,0 +1,102 @@
+package datautils
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+// DETCurve represents a Detection Error Tradeoff (DET) curve as commonly used for evaluating speaker and
+// biometric verification systems.  The curve plots the false negative (miss) rate against the false positive
+// (false alarm) rate as the decision threshold is lowered.  When plotted on normal deviate scales, as with Plot,
+// the curve of a system with normally distributed scores is a straight line making it easier to compare systems
+// operating at low error rates than with a ROC curve.  The points are ordered by descending threshold.
+type DETCurve struct {
+	// FPR is a slice containing the false positive rate at each threshold
+	FPR []float64
+
+	// FNR is a slice containing the false negative rate at each threshold
+	FNR []float64
+
+	// Thresholds is a slice containing the distinct predictions in descending order, preceded by +Inf (see
+	// ROCCurve.Thresholds)
+	Thresholds []float64
+}
+
+// NewDETCurve creates a new DET curve from the supplied predictions and ground truth labels.  As with
+// NewROCCurve, any label value greater than 0 is assumed to represent a positive observation.
+func NewDETCurve[P, L Float](predictions []P, labels []L) DETCurve {
+	roc := NewROCCurve(predictions, labels)
+
+	fnr := make([]float64, len(roc.TPR))
+	for i, tpr := range roc.TPR {
+		fnr[i] = 1 - tpr
+	}
+
+	return DETCurve{FPR: roc.FPR, FNR: fnr, Thresholds: roc.Thresholds}
+}
+
+// EER calculates the Equal Error Rate.  This is the error rate at the operating point where the false positive
+// rate and false negative rate are equal.  Where this point lies between two points of the curve, the rates are
+// linearly interpolated between them.
+func (c DETCurve) EER() float64 {
+	for i := 1; i < len(c.FPR); i++ {
+		if c.FPR[i] < c.FNR[i] {
+			continue
+		}
+		d0 := c.FPR[i-1] - c.FNR[i-1]
+		d1 := c.FPR[i] - c.FNR[i]
+		t := -d0 / (d1 - d0)
+		return c.FPR[i-1] + t*(c.FPR[i]-c.FPR[i-1])
+	}
+	return math.NaN()
+}
+
+// detTicks are the error rates labelled on the normal deviate scaled axes of a DET plot.
+var detTicks = []float64{0.001, 0.002, 0.005, 0.01, 0.02, 0.05, 0.1, 0.2, 0.4, 0.6, 0.8, 0.9, 0.95, 0.98, 0.99, 0.995, 0.998, 0.999}
+
+// probit returns the normal deviate (quantile of the standard normal distribution) of the probability p.  p is
+// clamped to the range of detTicks so that error rates of 0 and 1 may be plotted at the edges of the axes.
+func probit(p float64) float64 {
+	p = math.Max(detTicks[0], math.Min(detTicks[len(detTicks)-1], p))
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// Plot renders the DET curve as a plot for visualisation.  Both axes use normal deviate scales labelled with
+// error rates as percentages.
+func (c DETCurve) Plot() *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+
+	p.Title.Text = PlotTheme.title(fmt.Sprintf("DET Curve, EER=%f", c.EER()))
+	p.X.Label.Text = "False Positive Rate (%)"
+	p.Y.Label.Text = "False Negative Rate (%)"
+
+	ticks := make(plot.ConstantTicks, len(detTicks))
+	for i, v := range detTicks {
+		ticks[i] = plot.Tick{Value: probit(v), Label: fmt.Sprintf("%g", v*100)}
+	}
+	for _, axis := range []*plot.Axis{&p.X, &p.Y} {
+		axis.Tick.Marker = ticks
+		axis.Min = probit(detTicks[0])
+		axis.Max = probit(detTicks[len(detTicks)-1])
+	}
+
+	pts := make(plotter.XYs, len(c.FPR))
+	for i := range pts {
+		pts[i].X = probit(c.FPR[i])
+		pts[i].Y = probit(c.FNR[i])
+	}
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		panic(err)
+	}
+	line.Color = PlotTheme.Primary
+	p.Add(line)
+
+	return p
+}
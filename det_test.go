@@ -0,0 +1,56 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestDETCurve(t *testing.T) {
+	tests := []struct {
+		predictions []float64
+		labels      []float64
+		fpr         []float64
+		fnr         []float64
+		eer         float64
+	}{
+		{
+			predictions: []float64{0.1, 0.4, 0.35, 0.8},
+			labels:      []float64{0, 0, 1, 1},
+			fpr:         []float64{0, 0, 0.5, 0.5, 1},
+			fnr:         []float64{1, 0.5, 0.5, 0, 0},
+			eer:         0.5,
+		},
+		{
+			predictions: []float64{0.9, 0.8, 0.3, 0.2},
+			labels:      []float64{1, 1, 0, 0},
+			fpr:         []float64{0, 0, 0, 0.5, 1},
+			fnr:         []float64{1, 0.5, 0, 0, 0},
+			eer:         0,
+		},
+		{
+			predictions: []float64{0.9, 0.8, 0.7, 0.6, 0.5},
+			labels:      []float64{1, 0, 1, 0, 0},
+			fpr:         []float64{0, 0, 1.0 / 3, 1.0 / 3, 2.0 / 3, 1},
+			fnr:         []float64{1, 0.5, 0.5, 0, 0, 0},
+			// FPR and FNR cross on the vertical segment from (1/3, 0.5) to (1/3, 0)
+			eer: 1.0 / 3,
+		},
+	}
+
+	for i, test := range tests {
+		curve := datautils.NewDETCurve(test.predictions, test.labels)
+
+		if !floats.EqualApprox(curve.FPR, test.fpr, 1e-12) {
+			t.Errorf("Test %d: Expected FPR: %v but received %v", i+1, test.fpr, curve.FPR)
+		}
+		if !floats.EqualApprox(curve.FNR, test.fnr, 1e-12) {
+			t.Errorf("Test %d: Expected FNR: %v but received %v", i+1, test.fnr, curve.FNR)
+		}
+		if eer := curve.EER(); math.Abs(eer-test.eer) > 1e-12 {
+			t.Errorf("Test %d: Expected EER: %v but received %v", i+1, test.eer, eer)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package datautils
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// Box is an axis aligned bounding box within an image.
+type Box struct {
+	// XMin, YMin, XMax and YMax are the coordinates of the top left and bottom right corners of the box
+	XMin, YMin, XMax, YMax float64
+}
+
+// Area returns the area of the box or 0 if the box is empty.
+func (b Box) Area() float64 {
+	return math.Max(b.XMax-b.XMin, 0) * math.Max(b.YMax-b.YMin, 0)
+}
+
+// IoU returns the intersection over union (Jaccard index) of the two boxes i.e. the area of their overlap divided
+// by the area they cover in total.  IoU ranges from 0 for boxes that do not overlap to 1 for identical boxes.
+func IoU(a, b Box) float64 {
+	intersection := Box{
+		XMin: math.Max(a.XMin, b.XMin),
+		YMin: math.Max(a.YMin, b.YMin),
+		XMax: math.Min(a.XMax, b.XMax),
+		YMax: math.Min(a.YMax, b.YMax),
+	}.Area()
+	union := a.Area() + b.Area() - intersection
+	if union == 0 {
+		return 0
+	}
+	return intersection / union
+}
+
+// Detection is an object predicted by an object detection model.
+type Detection struct {
+	// Image identifies the image within which the object was detected
+	Image string
+
+	// Class is the predicted class of the object
+	Class string
+
+	// Box is the predicted bounding box of the object
+	Box Box
+
+	// Score is the model's confidence in the detection
+	Score float64
+}
+
+// GroundTruth is an object annotated within an image against which detections are evaluated.
+type GroundTruth struct {
+	// Image identifies the image containing the object
+	Image string
+
+	// Class is the class of the object
+	Class string
+
+	// Box is the bounding box of the object
+	Box Box
+}
+
+// DetectionEvaluation evaluates the detections of an object detection model against the ground truth objects
+// annotated within a set of images.
+type DetectionEvaluation struct {
+	detections map[string][]Detection
+	truths     map[string][]GroundTruth
+}
+
+// NewDetectionEvaluation creates a new DetectionEvaluation for the supplied detections and ground truth objects.
+func NewDetectionEvaluation(detections []Detection, truths []GroundTruth) DetectionEvaluation {
+	e := DetectionEvaluation{
+		detections: make(map[string][]Detection),
+		truths:     make(map[string][]GroundTruth),
+	}
+	for _, d := range detections {
+		e.detections[d.Class] = append(e.detections[d.Class], d)
+	}
+	for _, t := range truths {
+		e.truths[t.Class] = append(e.truths[t.Class], t)
+	}
+	return e
+}
+
+// Classes returns the classes of the ground truth objects in sorted order.
+func (e DetectionEvaluation) Classes() []string {
+	classes := make([]string, 0, len(e.truths))
+	for c := range e.truths {
+		classes = append(classes, c)
+	}
+	sort.Strings(classes)
+	return classes
+}
+
+// PrecisionRecallCurve returns the precision recall curve of the detections of the specified class.  Detections
+// are ranked by descending score (ties retaining their original order) and each in turn is matched to the
+// unmatched ground truth object of the same class and image with which it has the highest IoU, provided the IoU
+// is at least iou.  Matched detections are true positives while unmatched detections, including duplicate
+// detections of an object that has already been matched, are false positives.  Recall is relative to the total
+// number of ground truth objects of the class, including those never detected, so may not reach 1.
+func (e DetectionEvaluation) PrecisionRecallCurve(class string, iou float64) PrecisionRecallCurve {
+	detections := make([]Detection, len(e.detections[class]))
+	copy(detections, e.detections[class])
+	sort.SliceStable(detections, func(i, j int) bool { return detections[i].Score > detections[j].Score })
+
+	truths := make(map[string][]GroundTruth)
+	for _, t := range e.truths[class] {
+		truths[t.Image] = append(truths[t.Image], t)
+	}
+	matched := make(map[string][]bool, len(truths))
+	for image, t := range truths {
+		matched[image] = make([]bool, len(t))
+	}
+
+	positives := len(e.truths[class])
+	if positives == 0 {
+		return PrecisionRecallCurve{Precision: []float64{1}, Recall: []float64{0}, Thresholds: []float64{}}
+	}
+
+	var precision, recall, thresholds []float64
+	var hits int
+	for k, d := range detections {
+		best, bestIoU := -1, iou
+		for i, t := range truths[d.Image] {
+			if v := IoU(d.Box, t.Box); !matched[d.Image][i] && v >= bestIoU {
+				best, bestIoU = i, v
+			}
+		}
+		if best >= 0 {
+			matched[d.Image][best] = true
+			hits++
+		}
+		precision = append(precision, float64(hits)/float64(k+1))
+		recall = append(recall, float64(hits)/float64(positives))
+		thresholds = append(thresholds, d.Score)
+		if hits == positives {
+			break
+		}
+	}
+
+	// order as for NewPrecisionRecallCurve with the highest scoring detection last followed by the point @ 0
+	floats.Reverse(precision)
+	floats.Reverse(recall)
+	floats.Reverse(thresholds)
+	return PrecisionRecallCurve{
+		Precision:  append(precision, 1),
+		Recall:     append(recall, 0),
+		Thresholds: thresholds,
+		positives:  positives,
+	}
+}
+
+// AveragePrecision calculates the average precision of the detections of the specified class at the specified IoU
+// threshold (see PrecisionRecallCurve) using 101 point interpolation as defined by COCO
+//
+//	AP = 1/101 * Σ max(P_k for all k where R_k >= r) for r in {0.00, 0.01, 0.02, ... 1.00}
+//
+// where the interpolated precision is 0 for recalls that are never reached.  If there are no ground truth objects
+// of the class, NaN is returned.
+func (e DetectionEvaluation) AveragePrecision(class string, iou float64) float64 {
+	if len(e.truths[class]) == 0 {
+		return math.NaN()
+	}
+	c := e.PrecisionRecallCurve(class, iou)
+
+	var sum float64
+	for i := 0; i <= 100; i++ {
+		r := float64(i) / 100
+		var max float64
+		// exclude the point @ 0 which is not a detection
+		for k := 0; k < len(c.Recall)-1; k++ {
+			if c.Recall[k] >= r && c.Precision[k] > max {
+				max = c.Precision[k]
+			}
+		}
+		sum += max
+	}
+	return sum / 101
+}
+
+// MeanAveragePrecision calculates the mean of the average precision (see AveragePrecision) of every class of the
+// ground truth objects at the specified IoU threshold e.g. mAP@0.5 as reported for PASCAL VOC.  Classes that are
+// detected but have no ground truth objects are excluded.  If there are no ground truth objects, NaN is returned.
+func (e DetectionEvaluation) MeanAveragePrecision(iou float64) float64 {
+	classes := e.Classes()
+	if len(classes) == 0 {
+		return math.NaN()
+	}
+	var sum float64
+	for _, c := range classes {
+		sum += e.AveragePrecision(c, iou)
+	}
+	return sum / float64(len(classes))
+}
+
+// COCOMeanAveragePrecision calculates the primary COCO detection metric, mAP@[.5:.95], the mean of the mean
+// average precision (see MeanAveragePrecision) at each of the 10 IoU thresholds 0.50, 0.55, ... 0.95.  Averaging
+// over IoU thresholds rewards detectors that localise objects more accurately.
+func (e DetectionEvaluation) COCOMeanAveragePrecision() float64 {
+	var sum float64
+	for i := 0; i < 10; i++ {
+		sum += e.MeanAveragePrecision(float64(50+5*i) / 100)
+	}
+	return sum / 10
+}
@@ -0,0 +1,116 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestIoU(t *testing.T) {
+	tests := []struct {
+		a, b datautils.Box
+		iou  float64
+	}{
+		{a: datautils.Box{XMin: 0, YMin: 0, XMax: 2, YMax: 2}, b: datautils.Box{XMin: 0, YMin: 0, XMax: 2, YMax: 2}, iou: 1},
+		{a: datautils.Box{XMin: 0, YMin: 0, XMax: 2, YMax: 2}, b: datautils.Box{XMin: 1, YMin: 0, XMax: 3, YMax: 2}, iou: 2.0 / 6.0},
+		{a: datautils.Box{XMin: 0, YMin: 0, XMax: 1, YMax: 1}, b: datautils.Box{XMin: 2, YMin: 2, XMax: 3, YMax: 3}, iou: 0},
+		{a: datautils.Box{}, b: datautils.Box{}, iou: 0},
+	}
+
+	for i, test := range tests {
+		if iou := datautils.IoU(test.a, test.b); math.Abs(iou-test.iou) > 1e-12 {
+			t.Errorf("Test %d: Expected IoU: %v but received %v", i+1, test.iou, iou)
+		}
+	}
+}
+
+func detectionEvaluation() datautils.DetectionEvaluation {
+	unit := func(x float64) datautils.Box { return datautils.Box{XMin: x, YMin: 0, XMax: x + 10, YMax: 10} }
+	truths := []datautils.GroundTruth{
+		{Image: "a", Class: "cat", Box: unit(0)},
+		{Image: "a", Class: "cat", Box: unit(20)},
+		{Image: "b", Class: "cat", Box: unit(0)},
+		{Image: "b", Class: "dog", Box: unit(0)},
+	}
+	detections := []datautils.Detection{
+		// exact match
+		{Image: "a", Class: "cat", Box: unit(0), Score: 0.9},
+		// duplicate of an already matched object
+		{Image: "a", Class: "cat", Box: unit(1), Score: 0.8},
+		// IoU of 8/12 with the object in image b
+		{Image: "b", Class: "cat", Box: unit(2), Score: 0.7},
+		// no overlapping object
+		{Image: "b", Class: "cat", Box: unit(50), Score: 0.6},
+		// wrong image
+		{Image: "a", Class: "dog", Box: unit(0), Score: 0.5},
+		// no ground truth objects of the class
+		{Image: "a", Class: "bird", Box: unit(0), Score: 0.5},
+	}
+	return datautils.NewDetectionEvaluation(detections, truths)
+}
+
+func TestDetectionPrecisionRecallCurve(t *testing.T) {
+	e := detectionEvaluation()
+
+	tests := []struct {
+		class     string
+		iou       float64
+		precision []float64
+		recall    []float64
+	}{
+		{class: "cat", iou: 0.5, precision: []float64{2.0 / 4.0, 2.0 / 3.0, 1.0 / 2.0, 1, 1}, recall: []float64{2.0 / 3.0, 2.0 / 3.0, 1.0 / 3.0, 1.0 / 3.0, 0}},
+		{class: "cat", iou: 0.7, precision: []float64{1.0 / 4.0, 1.0 / 3.0, 1.0 / 2.0, 1, 1}, recall: []float64{1.0 / 3.0, 1.0 / 3.0, 1.0 / 3.0, 1.0 / 3.0, 0}},
+		{class: "dog", iou: 0.5, precision: []float64{0, 1}, recall: []float64{0, 0}},
+		{class: "bird", iou: 0.5, precision: []float64{1}, recall: []float64{0}},
+	}
+
+	for i, test := range tests {
+		c := e.PrecisionRecallCurve(test.class, test.iou)
+		if !floats.EqualApprox(test.precision, c.Precision, 1e-12) || !floats.EqualApprox(test.recall, c.Recall, 1e-12) {
+			t.Errorf("Test %d: Expected precision %v and recall %v but received %v and %v", i+1, test.precision, test.recall, c.Precision, c.Recall)
+		}
+	}
+}
+
+func TestDetectionAveragePrecision(t *testing.T) {
+	e := detectionEvaluation()
+
+	// recalls 0.00 to 0.33 are interpolated at precision 1 and 0.34 to 0.66 at precision 2/3
+	cat50 := (34*1 + 33*2.0/3.0) / 101
+	cat70 := 34.0 / 101
+
+	tests := []struct {
+		class string
+		iou   float64
+		ap    float64
+	}{
+		{class: "cat", iou: 0.5, ap: cat50},
+		{class: "cat", iou: 0.7, ap: cat70},
+		{class: "dog", iou: 0.5, ap: 0},
+		{class: "bird", iou: 0.5, ap: math.NaN()},
+	}
+
+	for i, test := range tests {
+		if ap := e.AveragePrecision(test.class, test.iou); !equalWithNaN([]float64{ap}, []float64{test.ap}) {
+			t.Errorf("Test %d: Expected AP: %v but received %v", i+1, test.ap, ap)
+		}
+	}
+
+	if classes := e.Classes(); len(classes) != 2 || classes[0] != "cat" || classes[1] != "dog" {
+		t.Errorf("Expected classes [cat dog] but received %v", classes)
+	}
+	if m := e.MeanAveragePrecision(0.5); math.Abs(m-cat50/2) > 1e-12 {
+		t.Errorf("Expected mAP@0.5: %v but received %v", cat50/2, m)
+	}
+
+	// the match at IoU 8/12 counts for thresholds 0.50 to 0.65
+	expected := (4*cat50 + 6*cat70) / 2 / 10
+	if m := e.COCOMeanAveragePrecision(); math.Abs(m-expected) > 1e-12 {
+		t.Errorf("Expected mAP@[.5:.95]: %v but received %v", expected, m)
+	}
+	if m := datautils.NewDetectionEvaluation(nil, nil).MeanAveragePrecision(0.5); !math.IsNaN(m) {
+		t.Errorf("Expected NaN mAP without ground truth objects but received %v", m)
+	}
+}
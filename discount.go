@@ -0,0 +1,143 @@
+package datautils
+
+import "math"
+
+// DiscountFunction supports specification of the rank discount used for calculating discounted cumulative gain.
+// It returns the weight applied to the gain of the item at the specified rank where the top ranked item has
+// rank 1.  See LogarithmicDiscount, ReciprocalDiscount, ExposureDiscount and PropensityDiscount.
+type DiscountFunction func(rank int) float64
+
+// LogarithmicDiscount is the traditional discount for calculating discounted cumulative gain, 1/log2(rank+1), as
+// used by DiscountedCumulativeGain.
+func LogarithmicDiscount(rank int) float64 {
+	return 1 / math.Log2(float64(rank+1))
+}
+
+// ReciprocalDiscount is a steeper alternative to LogarithmicDiscount, 1/rank, concentrating the gain more
+// heavily on the top few ranked items.
+func ReciprocalDiscount(rank int) float64 {
+	return 1 / float64(rank)
+}
+
+// ExposureDiscount returns a DiscountFunction for the position based examination model of user behaviour where
+// the probability of a user examining (being exposed to) the item at a rank is (1/rank)^eta.  The discount
+// applied to each rank is its probability of examination so the resulting discounted cumulative gain is the
+// expected gain of a user browsing the ranking.  eta controls the severity of the position bias with larger
+// values corresponding to users examining fewer items.
+func ExposureDiscount(eta float64) DiscountFunction {
+	if eta < 0 {
+		panic("datautils: eta must be non-negative")
+	}
+	return func(rank int) float64 {
+		return math.Pow(1/float64(rank), eta)
+	}
+}
+
+// PropensityDiscount returns a DiscountFunction using the supplied, empirically estimated, examination
+// propensities where propensities[i] is the probability of a user examining the item at rank i+1 (e.g. estimated
+// from result randomisation experiments).  Items ranked beyond the end of propensities are assumed not to be
+// examined and receive a discount of 0.
+func PropensityDiscount(propensities []float64) DiscountFunction {
+	return func(rank int) float64 {
+		if rank > len(propensities) {
+			return 0
+		}
+		return propensities[rank-1]
+	}
+}
+
+// InversePropensityLabels returns relevance labels, for use with NewRankingEvaluation, derived from click logs
+// using inverse propensity weighting to correct for position bias.  clicks contains the (binary or graded) click
+// feedback for each item and positions the rank (starting at 1) at which each item was displayed to the user
+// when the clicks were logged.  Each click is weighted by the inverse of the examination propensity of the
+// position at which it was displayed (see ExposureDiscount and PropensityDiscount) so that clicks on items
+// displayed at rarely examined positions count for more.  This gives an unbiased estimate of the relevance
+// labels, in expectation, for evaluating a new ranking of the same items.  Items displayed at positions with a
+// propensity of 0 can not have been clicked and receive a label of 0.
+func InversePropensityLabels(clicks []float64, positions []int, propensity DiscountFunction) []float64 {
+	if len(clicks) != len(positions) {
+		panic(ErrLengthMismatch)
+	}
+	labels := make([]float64, len(clicks))
+	for i, c := range clicks {
+		if positions[i] < 1 {
+			panic(ErrOutOfBounds)
+		}
+		if p := propensity(positions[i]); p > 0 {
+			labels[i] = c / p
+		}
+	}
+	return labels
+}
+
+func (r RankingEvaluation) discountedCumulativeGainWith(k int, rankings []int, rel RelevancyFunction, discount DiscountFunction) float64 {
+	var sum float64
+	for i, v := range rankings[:k] {
+		sum += rel(r.Relevancies[v]) * discount(i+1)
+	}
+	return sum
+}
+
+// DiscountedCumulativeGainWith calculates the discounted cumulative gain for the ranking in the same way as
+// DiscountedCumulativeGain but using the specified discount function in place of the traditional logarithmic
+// discount.  Where k is the cut-off (specify len(Relevancies) for ALL items/no cut-off), rel is the relevancy
+// function and discount the discount function to use.
+func (r RankingEvaluation) DiscountedCumulativeGainWith(k int, rel RelevancyFunction, discount DiscountFunction) float64 {
+	return must(r.DiscountedCumulativeGainWithE(k, rel, discount))
+}
+
+// DiscountedCumulativeGainWithE calculates the discounted cumulative gain in the same way as
+// DiscountedCumulativeGainWith but returns ErrOutOfBounds, rather than panicking, if k is not a valid cut-off.
+func (r RankingEvaluation) DiscountedCumulativeGainWithE(k int, rel RelevancyFunction, discount DiscountFunction) (float64, error) {
+	if err := r.ValidateCutoff(k); err != nil {
+		return 0, err
+	}
+	return r.discountedCumulativeGainWith(k, r.PredictedRankInd, rel, discount), nil
+}
+
+// NormalisedDiscountedCumulativeGainWith calculates the normalised discounted cumulative gain for the ranking
+// in the same way as NormalisedDiscountedCumulativeGain but using the specified discount function in place of
+// the traditional logarithmic discount (see DiscountedCumulativeGainWith).  Combined with relevance labels
+// derived from click logs using InversePropensityLabels, this supports unbiased offline evaluation of rankings
+// for learning to rank.
+func (r RankingEvaluation) NormalisedDiscountedCumulativeGainWith(k int, rel RelevancyFunction, discount DiscountFunction) float64 {
+	return must(r.NormalisedDiscountedCumulativeGainWithE(k, rel, discount))
+}
+
+// NormalisedDiscountedCumulativeGainWithE calculates the normalised discounted cumulative gain in the same way
+// as NormalisedDiscountedCumulativeGainWith but returns ErrOutOfBounds, rather than panicking, if k is not a
+// valid cut-off.
+func (r RankingEvaluation) NormalisedDiscountedCumulativeGainWithE(k int, rel RelevancyFunction, discount DiscountFunction) (float64, error) {
+	if err := r.ValidateCutoff(k); err != nil {
+		return 0, err
+	}
+	if r.noRelevant() {
+		// no relevant items so the DCG of any ranking will match a perfect ordering
+		return 1.0, nil
+	}
+	var ideal float64
+	for i, v := range r.idealRelevancies(k) {
+		ideal += rel(v) * discount(i+1)
+	}
+	return r.discountedCumulativeGainWith(k, r.PredictedRankInd, rel, discount) / ideal, nil
+}
+
+// MeanNormalisedDiscountedCumulativeGainWith calculates the mean normalised discounted cumulative gain at
+// cut-off k across the set of queries using the specified relevancy and discount functions (see
+// RankingEvaluation.NormalisedDiscountedCumulativeGainWith).  For queries with fewer than k ranked items, all the
+// ranked items are considered.  Queries with no relevant items, ranked or unretrieved, score 1 while queries with
+// no ranked items whose relevant items were all unretrieved score 0.
+func (s EvaluationSet) MeanNormalisedDiscountedCumulativeGainWith(k int, rel RelevancyFunction, discount DiscountFunction) float64 {
+	return s.mean(func(r RankingEvaluation) float64 {
+		if r.noRelevant() {
+			return 1
+		}
+		if len(r.Relevancies) == 0 {
+			return 0
+		}
+		if k > len(r.Relevancies) {
+			return r.NormalisedDiscountedCumulativeGainWith(len(r.Relevancies), rel, discount)
+		}
+		return r.NormalisedDiscountedCumulativeGainWith(k, rel, discount)
+	})
+}
@@ -0,0 +1,78 @@
+package datautils_test
+
+import (
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestDiscountedCumulativeGainWith(t *testing.T) {
+	r := datautils.NewRankingEvaluation([]float64{0.9, 0.8, 0.7}, []float64{0, 1, 1})
+
+	tests := []struct {
+		discount datautils.DiscountFunction
+		dcg      float64
+		ndcg     float64
+	}{
+		{discount: datautils.ReciprocalDiscount, dcg: 5.0 / 6, ndcg: 5.0 / 9},
+		{discount: datautils.ExposureDiscount(1), dcg: 5.0 / 6, ndcg: 5.0 / 9},
+		{discount: datautils.ExposureDiscount(0), dcg: 2, ndcg: 1},
+		{discount: datautils.PropensityDiscount([]float64{1, 0.5}), dcg: 0.5, ndcg: 1.0 / 3},
+	}
+
+	for ti, test := range tests {
+		dcg := r.DiscountedCumulativeGainWith(3, datautils.TraditionalRelevancy, test.discount)
+		if !floats.EqualWithinAbs(test.dcg, dcg, 1e-12) {
+			t.Errorf("Test %d: Expected DCG: %v but received %v", ti+1, test.dcg, dcg)
+		}
+		ndcg := r.NormalisedDiscountedCumulativeGainWith(3, datautils.TraditionalRelevancy, test.discount)
+		if !floats.EqualWithinAbs(test.ndcg, ndcg, 1e-12) {
+			t.Errorf("Test %d: Expected NDCG: %v but received %v", ti+1, test.ndcg, ndcg)
+		}
+	}
+
+	// the logarithmic discount should match the traditional formulation
+	for k := 1; k <= 3; k++ {
+		expected := r.NormalisedDiscountedCumulativeGain(k, datautils.EmphasisedRelevancy)
+		ndcg := r.NormalisedDiscountedCumulativeGainWith(k, datautils.EmphasisedRelevancy, datautils.LogarithmicDiscount)
+		if !floats.EqualWithinAbs(expected, ndcg, 1e-12) {
+			t.Errorf("Expected NDCG@%d: %v but received %v", k, expected, ndcg)
+		}
+	}
+
+	set := datautils.EvaluationSet{"q1": r, "q2": datautils.NewRankingEvaluation([]float64{0.5}, []float64{1})}
+	expected := (5.0/9 + 1) / 2
+	if ndcg := set.MeanNormalisedDiscountedCumulativeGainWith(3, datautils.TraditionalRelevancy, datautils.ReciprocalDiscount); !floats.EqualWithinAbs(expected, ndcg, 1e-12) {
+		t.Errorf("Expected mean NDCG: %v but received %v", expected, ndcg)
+	}
+}
+
+func TestInversePropensityLabels(t *testing.T) {
+	tests := []struct {
+		clicks     []float64
+		positions  []int
+		propensity datautils.DiscountFunction
+		expected   []float64
+	}{
+		{
+			clicks:     []float64{1, 0, 1},
+			positions:  []int{1, 2, 3},
+			propensity: datautils.ExposureDiscount(1),
+			expected:   []float64{1, 0, 3},
+		},
+		{
+			clicks:     []float64{1, 1, 1},
+			positions:  []int{2, 1, 3},
+			propensity: datautils.PropensityDiscount([]float64{1, 0.25}),
+			expected:   []float64{4, 1, 0},
+		},
+	}
+
+	for ti, test := range tests {
+		labels := datautils.InversePropensityLabels(test.clicks, test.positions, test.propensity)
+		if !floats.Equal(test.expected, labels) {
+			t.Errorf("Test %d: Expected labels: %v but received %v", ti+1, test.expected, labels)
+		}
+	}
+}
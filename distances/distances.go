@@ -0,0 +1,147 @@
+// Package distances provides distance functions for comparing vectors, such as the rows of a feature or embedding
+// matrix, and for computing the matrix of pairwise distances between the rows of a matrix.  Pairwise distance
+// matrices may be plotted with datautils.PlotHeatmap or used to evaluate clusterings with datautils.Silhouette
+// e.g.
+//
+//	d := distances.Pairwise(embeddings, distances.Cosine)
+//	score := datautils.Silhouette(d, clusters)
+//	p, err := datautils.PlotHeatmap(d, nil, nil)
+package distances
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Metric returns the distance between the vectors a and b where 0 indicates identical vectors and larger values
+// indicate greater dissimilarity.  a and b must be of equal length.
+type Metric func(a, b []float64) float64
+
+// Euclidean returns the Euclidean (L2) distance between a and b.
+func Euclidean(a, b []float64) float64 {
+	checkLengths(a, b)
+	return floats.Distance(a, b, 2)
+}
+
+// Manhattan returns the Manhattan (L1 or city block) distance between a and b i.e. the sum of the absolute
+// differences of their elements.
+func Manhattan(a, b []float64) float64 {
+	checkLengths(a, b)
+	return floats.Distance(a, b, 1)
+}
+
+// Cosine returns the cosine distance between a and b i.e. 1 minus the cosine of the angle between them, ranging
+// from 0 for vectors pointing in the same direction to 2 for vectors pointing in opposite directions.  If either
+// vector has zero length, the distance is 1.
+func Cosine(a, b []float64) float64 {
+	checkLengths(a, b)
+	norms := floats.Norm(a, 2) * floats.Norm(b, 2)
+	if norms == 0 {
+		return 1
+	}
+	return 1 - floats.Dot(a, b)/norms
+}
+
+// Jaccard returns the Jaccard distance between a and b treated as sets, where non-zero elements are members of
+// the set, i.e. 1 minus the size of their intersection divided by the size of their union.  If both sets are
+// empty, the distance is 0.
+func Jaccard(a, b []float64) float64 {
+	checkLengths(a, b)
+	var intersection, union int
+	for i := range a {
+		x, y := a[i] != 0, b[i] != 0
+		if x && y {
+			intersection++
+		}
+		if x || y {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return 1 - float64(intersection)/float64(union)
+}
+
+// Hamming returns the normalised Hamming distance between a and b i.e. the proportion of elements that differ.
+// If the vectors are empty, the distance is 0.
+func Hamming(a, b []float64) float64 {
+	checkLengths(a, b)
+	if len(a) == 0 {
+		return 0
+	}
+	var differ int
+	for i := range a {
+		if a[i] != b[i] {
+			differ++
+		}
+	}
+	return float64(differ) / float64(len(a))
+}
+
+// NewMahalanobis returns a Metric calculating the Mahalanobis distance between vectors with the specified
+// covariance matrix (e.g. the covariance of a dataset's features as estimated by stat.CovarianceMatrix) i.e.
+//
+//	d(a, b) = sqrt((a - b)ᵀ S⁻¹ (a - b))
+//
+// where S is the covariance matrix.  Unlike Euclidean distance, Mahalanobis distance accounts for the scale of,
+// and correlation between, the features.  An error is returned if the covariance matrix is not square or cannot be
+// inverted.
+func NewMahalanobis(cov mat.Matrix) (Metric, error) {
+	r, c := cov.Dims()
+	if r != c {
+		return nil, fmt.Errorf("distances: covariance matrix must be square but is %d x %d", r, c)
+	}
+	var inv mat.Dense
+	if err := inv.Inverse(cov); err != nil {
+		var cond mat.Condition
+		if !errors.As(err, &cond) || math.IsInf(float64(cond), 1) {
+			return nil, fmt.Errorf("distances: inverting covariance matrix: %w", err)
+		}
+		// an ill-conditioned inverse is still returned, albeit with reduced accuracy
+	}
+
+	return func(a, b []float64) float64 {
+		checkLengths(a, b)
+		if len(a) != r {
+			panic(mat.ErrShape)
+		}
+		diff := make([]float64, len(a))
+		floats.SubTo(diff, a, b)
+		var sum float64
+		for i := range diff {
+			sum += diff[i] * floats.Dot(inv.RawRowView(i), diff)
+		}
+		// guard against small negative values arising from rounding error
+		return math.Sqrt(math.Max(sum, 0))
+	}, nil
+}
+
+// checkLengths panics if the vectors a and b differ in length.
+func checkLengths(a, b []float64) {
+	if len(a) != len(b) {
+		panic("distances: vectors differ in length")
+	}
+}
+
+// Vectors returns the distance between the vectors a and b using the specified metric.
+func Vectors(a, b mat.Vector, metric Metric) float64 {
+	return metric(mat.Col(nil, 0, a), mat.Col(nil, 0, b))
+}
+
+// Rows returns the distance between rows i and j of m using the specified metric.
+func Rows(m mat.Matrix, i, j int, metric Metric) float64 {
+	return metric(mat.Row(nil, i, m), mat.Row(nil, j, m))
+}
+
+// Pairwise returns the symmetric matrix of the distances between every pair of rows of m using the specified
+// metric such that element i, j is the distance between rows i and j.  The distance of each row from itself is
+// taken to be 0 without being calculated.  Distances are calculated sequentially; use PairwiseDistances to
+// calculate them in parallel.
+func Pairwise(m mat.Matrix, metric Metric) *mat.SymDense {
+	return PairwiseDistances(m, metric, 1)
+}
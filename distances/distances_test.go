@@ -0,0 +1,101 @@
+package distances_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"github.com/james-bowman/datautils/distances"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		metric   distances.Metric
+		a, b     []float64
+		expected float64
+	}{
+		{name: "Euclidean", metric: distances.Euclidean, a: []float64{0, 0}, b: []float64{3, 4}, expected: 5},
+		{name: "Manhattan", metric: distances.Manhattan, a: []float64{0, 0}, b: []float64{3, -4}, expected: 7},
+		{name: "Cosine", metric: distances.Cosine, a: []float64{1, 0}, b: []float64{0, 2}, expected: 1},
+		{name: "Cosine", metric: distances.Cosine, a: []float64{1, 1}, b: []float64{2, 2}, expected: 0},
+		{name: "Cosine", metric: distances.Cosine, a: []float64{1, 0}, b: []float64{-1, 0}, expected: 2},
+		{name: "Cosine", metric: distances.Cosine, a: []float64{1, 0}, b: []float64{0, 0}, expected: 1},
+		{name: "Jaccard", metric: distances.Jaccard, a: []float64{1, 1, 0, 0}, b: []float64{0, 1, 1, 0}, expected: 2.0 / 3},
+		{name: "Jaccard", metric: distances.Jaccard, a: []float64{0, 0}, b: []float64{0, 0}, expected: 0},
+		{name: "Hamming", metric: distances.Hamming, a: []float64{1, 2, 3, 4}, b: []float64{1, 0, 3, 0}, expected: 0.5},
+		{name: "Hamming", metric: distances.Hamming, a: []float64{}, b: []float64{}, expected: 0},
+	}
+
+	for i, test := range tests {
+		if d := test.metric(test.a, test.b); math.Abs(d-test.expected) > 1e-12 {
+			t.Errorf("Test %d: Expected %s distance: %v but received %v", i+1, test.name, test.expected, d)
+		}
+		if d := test.metric(test.a, test.a); math.Abs(d) > 1e-12 {
+			t.Errorf("Test %d: Expected %s distance of vector from itself: 0 but received %v", i+1, test.name, d)
+		}
+	}
+}
+
+func TestMahalanobis(t *testing.T) {
+	metric, err := distances.NewMahalanobis(mat.NewDense(2, 2, []float64{4, 0, 0, 1}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := metric([]float64{0, 0}, []float64{2, 1}); math.Abs(d-math.Sqrt2) > 1e-12 {
+		t.Errorf("Expected Mahalanobis distance: %v but received %v", math.Sqrt2, d)
+	}
+
+	// with an identity covariance matrix Mahalanobis distance is Euclidean distance
+	metric, _ = distances.NewMahalanobis(mat.NewDense(2, 2, []float64{1, 0, 0, 1}))
+	a, b := []float64{1, 2}, []float64{4, 6}
+	if d := metric(a, b); math.Abs(d-distances.Euclidean(a, b)) > 1e-12 {
+		t.Errorf("Expected Mahalanobis distance: %v but received %v", distances.Euclidean(a, b), d)
+	}
+
+	if _, err := distances.NewMahalanobis(mat.NewDense(2, 2, []float64{1, 1, 1, 1})); err == nil {
+		t.Errorf("Expected error for singular covariance matrix but received none")
+	}
+	if _, err := distances.NewMahalanobis(mat.NewDense(2, 3, nil)); err == nil {
+		t.Errorf("Expected error for non-square covariance matrix but received none")
+	}
+}
+
+func TestVectorsAndRows(t *testing.T) {
+	m := mat.NewDense(3, 2, []float64{
+		0, 0,
+		3, 4,
+		6, 8,
+	})
+
+	if d := distances.Rows(m, 0, 2, distances.Euclidean); d != 10 {
+		t.Errorf("Expected distance between rows: 10 but received %v", d)
+	}
+	if d := distances.Vectors(m.ColView(0), m.ColView(1), distances.Manhattan); d != 3 {
+		t.Errorf("Expected distance between vectors: 3 but received %v", d)
+	}
+}
+
+func TestPairwise(t *testing.T) {
+	m := mat.NewDense(4, 1, []float64{0, 1, 10, 11})
+
+	d := distances.Pairwise(m, distances.Euclidean)
+	expected := mat.NewDense(4, 4, []float64{
+		0, 1, 10, 11,
+		1, 0, 9, 10,
+		10, 9, 0, 1,
+		11, 10, 1, 0,
+	})
+	if !mat.Equal(d, expected) {
+		t.Errorf("Expected pairwise distances: %v but received %v", expected, d)
+	}
+
+	// the distance matrix may be used directly with the clustering metrics and heatmaps
+	if s := datautils.Silhouette(d, []int{0, 0, 1, 1}); s < 0.85 {
+		t.Errorf("Expected silhouette score for well separated clusters of at least 0.85 but received %v", s)
+	}
+	if _, err := datautils.PlotHeatmap(d, nil, nil); err != nil {
+		t.Errorf("Unexpected error plotting pairwise distances: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package distances
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// blockSize is the number of rows in each side of the square blocks of the distance matrix calculated by a worker
+// at a time.  Calculating distances a block at a time reuses the rows of the block while they remain in cache
+// rather than streaming every other row through the cache for each row in turn.
+const blockSize = 64
+
+// PairwiseDistances returns the symmetric matrix of the distances between every pair of rows of m, as for
+// Pairwise, calculating the distances in parallel using numWorkers goroutines.  If numWorkers is less than 1, a
+// goroutine is used for each available CPU as reported by runtime.GOMAXPROCS.  The metric must be safe for
+// concurrent use, as are all the metrics of this package.  The distance matrix requires memory proportional to
+// the square of the number of rows; CondensedDistances requires half as much.
+func PairwiseDistances(m mat.Matrix, metric Metric, numWorkers int) *mat.SymDense {
+	d, _ := PairwiseDistancesContext(context.Background(), m, metric, numWorkers)
+	return d
+}
+
+// PairwiseDistancesContext returns the symmetric matrix of the distances between every pair of rows of m as for
+// PairwiseDistances but abandons the calculation promptly if ctx is cancelled (or its deadline is exceeded),
+// returning nil and the context's error.  Cancellation is checked before each block of distances is calculated.
+func PairwiseDistancesContext(ctx context.Context, m mat.Matrix, metric Metric, numWorkers int) (*mat.SymDense, error) {
+	n, _ := m.Dims()
+	d := mat.NewSymDense(n, nil)
+	if err := pairwise(ctx, m, metric, numWorkers, d.SetSym); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// CondensedDistances returns the distances between every pair of rows of m, calculated in parallel as for
+// PairwiseDistances, in condensed form i.e. the upper triangle of the distance matrix, excluding the diagonal,
+// flattened row by row into a slice of n(n-1)/2 elements for a matrix of n rows.  This is the layout used by
+// SciPy's pdist.  The distance between rows i and j is located at index CondensedIndex(n, i, j).
+func CondensedDistances(m mat.Matrix, metric Metric, numWorkers int) []float64 {
+	d, _ := CondensedDistancesContext(context.Background(), m, metric, numWorkers)
+	return d
+}
+
+// CondensedDistancesContext returns the condensed distances between every pair of rows of m as for
+// CondensedDistances but abandons the calculation promptly if ctx is cancelled, returning nil and the context's
+// error as for PairwiseDistancesContext.
+func CondensedDistancesContext(ctx context.Context, m mat.Matrix, metric Metric, numWorkers int) ([]float64, error) {
+	n, _ := m.Dims()
+	d := make([]float64, n*(n-1)/2)
+	err := pairwise(ctx, m, metric, numWorkers, func(i, j int, v float64) {
+		d[CondensedIndex(n, i, j)] = v
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// CondensedIndex returns the index within condensed distances (see CondensedDistances) for n rows of the distance
+// between the distinct rows i and j.  CondensedIndex will panic if i == j or either is outside the range 0 to n-1.
+func CondensedIndex(n, i, j int) int {
+	if i == j || i < 0 || j < 0 || i >= n || j >= n {
+		panic("distances: invalid row indices for condensed distances")
+	}
+	if i > j {
+		i, j = j, i
+	}
+	return n*i - i*(i+1)/2 + j - i - 1
+}
+
+// pairwise calculates the distance between every pair of distinct rows i < j of m using numWorkers goroutines,
+// passing each to set.  The upper triangle of the distance matrix is divided into square blocks which are
+// distributed between the workers.  set must be safe for concurrent use with distinct pairs of rows.  If ctx is
+// cancelled, no further blocks are distributed or calculated and the context's error is returned once the workers
+// have stopped.
+func pairwise(ctx context.Context, m mat.Matrix, metric Metric, numWorkers int, set func(i, j int, v float64)) error {
+	r := rowSlices(m)
+	if numWorkers < 1 {
+		numWorkers = runtime.GOMAXPROCS(0)
+	}
+
+	blocks := make(chan [2]int)
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			defer wg.Done()
+			for b := range blocks {
+				if ctx.Err() != nil {
+					continue
+				}
+				iEnd, jEnd := minInt(b[0]+blockSize, len(r)), minInt(b[1]+blockSize, len(r))
+				for i := b[0]; i < iEnd; i++ {
+					for j := maxInt(b[1], i+1); j < jEnd; j++ {
+						set(i, j, metric(r[i], r[j]))
+					}
+				}
+			}
+		}()
+	}
+distribute:
+	for i := 0; i < len(r); i += blockSize {
+		for j := i; j < len(r); j += blockSize {
+			select {
+			case blocks <- [2]int{i, j}:
+			case <-ctx.Done():
+				break distribute
+			}
+		}
+	}
+	close(blocks)
+	wg.Wait()
+	return ctx.Err()
+}
+
+// rowSlices returns the rows of m as slices.  The rows of a *mat.Dense are returned directly without copying,
+// otherwise each row is copied into a new slice.
+func rowSlices(m mat.Matrix) [][]float64 {
+	n, c := m.Dims()
+	rows := make([][]float64, n)
+	if d, ok := m.(*mat.Dense); ok {
+		for i := range rows {
+			rows[i] = d.RawRowView(i)
+		}
+		return rows
+	}
+	for i := range rows {
+		rows[i] = mat.Row(make([]float64, c), i, m)
+	}
+	return rows
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
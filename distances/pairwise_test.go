@@ -0,0 +1,118 @@
+package distances_test
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+
+	"github.com/james-bowman/datautils/distances"
+	"gonum.org/v1/gonum/mat"
+)
+
+// randomMatrix returns an r x c matrix of random values large enough to span several blocks of the distance matrix.
+func randomMatrix(r, c int) *mat.Dense {
+	rnd := rand.New(rand.NewSource(1))
+	data := make([]float64, r*c)
+	for i := range data {
+		data[i] = rnd.NormFloat64()
+	}
+	return mat.NewDense(r, c, data)
+}
+
+func TestPairwiseDistances(t *testing.T) {
+	m := randomMatrix(150, 3)
+
+	for _, workers := range []int{0, 1, 3} {
+		for _, input := range []mat.Matrix{m, mat.DenseCopyOf(m.T()).T()} {
+			d := distances.PairwiseDistances(input, distances.Euclidean, workers)
+			if r, c := d.Dims(); r != 150 || c != 150 {
+				t.Fatalf("Workers %d: Expected 150 x 150 distance matrix but received %d x %d", workers, r, c)
+			}
+			for i := 0; i < 150; i++ {
+				for j := 0; j < 150; j++ {
+					expected := 0.0
+					if i != j {
+						expected = distances.Rows(m, i, j, distances.Euclidean)
+					}
+					if d.At(i, j) != expected {
+						t.Fatalf("Workers %d: Expected distance between rows %d and %d: %v but received %v", workers, i, j, expected, d.At(i, j))
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCondensedDistances(t *testing.T) {
+	m := randomMatrix(130, 4)
+	full := distances.PairwiseDistances(m, distances.Manhattan, 1)
+
+	for _, workers := range []int{0, 1, 4} {
+		d := distances.CondensedDistances(m, distances.Manhattan, workers)
+		if len(d) != 130*129/2 {
+			t.Fatalf("Workers %d: Expected %d condensed distances but received %d", workers, 130*129/2, len(d))
+		}
+		for i := 0; i < 130; i++ {
+			for j := 0; j < 130; j++ {
+				if i != j && d[distances.CondensedIndex(130, i, j)] != full.At(i, j) {
+					t.Fatalf("Workers %d: Expected distance between rows %d and %d: %v but received %v", workers, i, j, full.At(i, j), d[distances.CondensedIndex(130, i, j)])
+				}
+			}
+		}
+	}
+}
+
+func TestPairwiseDistancesContext(t *testing.T) {
+	m := randomMatrix(150, 4)
+
+	d, err := distances.PairwiseDistancesContext(context.Background(), m, distances.Euclidean, 3)
+	if err != nil || !mat.Equal(d, distances.Pairwise(m, distances.Euclidean)) {
+		t.Errorf("Expected distances matching Pairwise without error but received error %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if d, err := distances.PairwiseDistancesContext(ctx, m, distances.Euclidean, 3); d != nil || err != context.Canceled {
+		t.Errorf("Expected nil distances and %v but received %v", context.Canceled, err)
+	}
+	if d, err := distances.CondensedDistancesContext(ctx, m, distances.Euclidean, 3); d != nil || err != context.Canceled {
+		t.Errorf("Expected nil condensed distances and %v but received %v", context.Canceled, err)
+	}
+
+	// cancelling part way through stops the remaining blocks being calculated
+	ctx, cancel = context.WithCancel(context.Background())
+	var calls int32
+	metric := func(a, b []float64) float64 {
+		if atomic.AddInt32(&calls, 1) == 10 {
+			cancel()
+		}
+		return distances.Euclidean(a, b)
+	}
+	if _, err := distances.PairwiseDistancesContext(ctx, m, metric, 1); err != context.Canceled {
+		t.Errorf("Expected %v but received %v", context.Canceled, err)
+	}
+	if n := atomic.LoadInt32(&calls); n >= 150*149/2 {
+		t.Errorf("Expected calculation to stop early but metric was called %d times", n)
+	}
+}
+
+func TestCondensedIndex(t *testing.T) {
+	expected := [][3]int{{0, 1, 0}, {0, 2, 1}, {0, 3, 2}, {1, 2, 3}, {1, 3, 4}, {2, 3, 5}, {3, 1, 4}}
+	for _, e := range expected {
+		if k := distances.CondensedIndex(4, e[0], e[1]); k != e[2] {
+			t.Errorf("Expected condensed index of %d, %d: %d but received %d", e[0], e[1], e[2], k)
+		}
+	}
+
+	for _, ij := range [][2]int{{1, 1}, {-1, 2}, {0, 4}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for indices %v but received none", ij)
+				}
+			}()
+			distances.CondensedIndex(4, ij[0], ij[1])
+		}()
+	}
+}
@@ -0,0 +1,145 @@
+package datautils
+
+import (
+	"image/color"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// scoreClass holds the predictions of the observations of a single class for plotting.
+type scoreClass struct {
+	name   string
+	values []float64
+	color  color.Color
+}
+
+// splitByClass splits the predictions into those of the negative and positive observations.  As with
+// NewPrecisionRecallCurve, any label value greater than 0 is considered positive.  Classes without any
+// observations are omitted.
+func splitByClass(predictions, labels []float64) []scoreClass {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+	var positives, negatives []float64
+	for i, v := range labels {
+		if v > 0 {
+			positives = append(positives, predictions[i])
+		} else {
+			negatives = append(negatives, predictions[i])
+		}
+	}
+
+	var classes []scoreClass
+	if len(negatives) > 0 {
+		classes = append(classes, scoreClass{name: "Negative", values: negatives, color: PlotTheme.Secondary})
+	}
+	if len(positives) > 0 {
+		classes = append(classes, scoreClass{name: "Positive", values: positives, color: PlotTheme.Primary})
+	}
+	return classes
+}
+
+// PlotScoreHistograms renders overlapping histograms of the predictions of the positive and negative observations
+// to visualise how well the predictions separate the classes.  Both histograms share the same bins, the specified
+// number of equal width bins spanning the range of the predictions, and show the probability density of each bin
+// (see Histogram.Density) so that the classes may be compared regardless of imbalance.  As with
+// NewPrecisionRecallCurve, any label value greater than 0 is considered positive.  The decision threshold is
+// marked with a vertical line unless it is NaN.
+func PlotScoreHistograms(predictions, labels []float64, bins int, threshold float64) *plot.Plot {
+	if bins < 1 {
+		panic("datautils: number of bins must be at least 1")
+	}
+	classes := splitByClass(predictions, labels)
+
+	p := newScorePlot("Score Histograms")
+	if len(predictions) == 0 {
+		return p
+	}
+	// a histogram of all the predictions determines bins common to both classes
+	edges := NewHistogram(predictions, bins).Edges
+
+	var top float64
+	for _, class := range classes {
+		h := NewHistogramRange(class.values, bins, edges[0], edges[bins])
+		top = math.Max(top, floats.Max(h.Density()))
+		hist := h.histogram(true, class.color)
+		p.Add(hist)
+		p.Legend.Add(class.name, hist)
+	}
+
+	addThreshold(p, threshold, top)
+	return p
+}
+
+// PlotScoreDensities renders kernel density estimates (see KDE) of the distributions of the predictions of the
+// positive and negative observations to visualise how well the predictions separate the classes.  The densities
+// are estimated using a Gaussian kernel with the specified bandwidth or, if bandwidth is 0, a bandwidth chosen
+// separately for each class using Silverman's rule of thumb.  As with NewPrecisionRecallCurve, any label value
+// greater than 0 is considered positive.  The decision threshold is marked with a vertical line unless it is NaN.
+func PlotScoreDensities(predictions, labels []float64, bandwidth float64, threshold float64) *plot.Plot {
+	if bandwidth < 0 {
+		panic("datautils: bandwidth must be non-negative")
+	}
+	classes := splitByClass(predictions, labels)
+
+	kdes := make([]KDE, len(classes))
+	min, max := math.Inf(1), math.Inf(-1)
+	for i, class := range classes {
+		kdes[i] = NewKDE(class.values, bandwidth)
+		lo, hi := kdes[i].extent()
+		min, max = math.Min(min, lo), math.Max(max, hi)
+	}
+
+	p := newScorePlot("Score Densities")
+	var top float64
+	for i, class := range classes {
+		pts := kdes[i].points(min, max)
+		for _, pt := range pts {
+			top = math.Max(top, pt.Y)
+		}
+		line, err := plotter.NewLine(pts)
+		if err != nil {
+			panic(err)
+		}
+		line.Color = class.color
+		line.Width = vg.Points(1.5)
+		p.Add(line)
+		p.Legend.Add(class.name, line)
+	}
+
+	if len(classes) > 0 {
+		addThreshold(p, threshold, top)
+	}
+	return p
+}
+
+func newScorePlot(title string) *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+	p.Title.Text = PlotTheme.title(title)
+	p.X.Label.Text = "Prediction"
+	p.Y.Label.Text = "Density"
+	return p
+}
+
+// addThreshold marks the threshold on the plot with a dashed vertical line from 0 to top unless the threshold is
+// NaN.
+func addThreshold(p *plot.Plot, threshold, top float64) {
+	if math.IsNaN(threshold) {
+		return
+	}
+	line, err := plotter.NewLine(plotter.XYs{{X: threshold, Y: 0}, {X: threshold, Y: top}})
+	if err != nil {
+		panic(err)
+	}
+	line.Color = color.Gray{Y: 64}
+	line.Dashes = []vg.Length{vg.Points(4), vg.Points(4)}
+	p.Add(line)
+	p.Legend.Add("Threshold", line)
+}
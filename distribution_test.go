@@ -0,0 +1,54 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestPlotScoreDistributions(t *testing.T) {
+	tests := []struct {
+		predictions []float64
+		labels      []float64
+		threshold   float64
+	}{
+		{
+			predictions: []float64{0.1, 0.4, 0.35, 0.8, 0.65, 0.2},
+			labels:      []float64{0, 0, 1, 1, 1, 0},
+			threshold:   0.5,
+		},
+		{
+			// single class with no spread
+			predictions: []float64{0.5, 0.5, 0.5},
+			labels:      []float64{1, 1, 1},
+			threshold:   math.NaN(),
+		},
+		{
+			predictions: []float64{},
+			labels:      []float64{},
+			threshold:   0.5,
+		},
+	}
+
+	for i, test := range tests {
+		if p := datautils.PlotScoreHistograms(test.predictions, test.labels, 10, test.threshold); p == nil {
+			t.Errorf("Test %d: Expected histogram plot but received nil", i+1)
+		}
+		if p := datautils.PlotScoreDensities(test.predictions, test.labels, 0, test.threshold); p == nil {
+			t.Errorf("Test %d: Expected density plot but received nil", i+1)
+		}
+		if p := datautils.PlotScoreDensities(test.predictions, test.labels, 0.1, test.threshold); p == nil {
+			t.Errorf("Test %d: Expected density plot but received nil", i+1)
+		}
+	}
+}
+
+func TestPlotScoreDistributionsLengthMismatch(t *testing.T) {
+	defer func() {
+		if r := recover(); r != datautils.ErrLengthMismatch {
+			t.Errorf("Expected panic: %v but received %v", datautils.ErrLengthMismatch, r)
+		}
+	}()
+	datautils.PlotScoreHistograms([]float64{0.1}, []float64{0, 1}, 10, 0.5)
+}
@@ -0,0 +1,94 @@
+package datautils
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// DriftEvent records a window in which a metric has degraded significantly relative to a baseline period.
+type DriftEvent struct {
+	// Index is the index of the window within the series
+	Index int `json:"index"`
+
+	// Window is the window in which the drift was detected
+	Window MetricWindow `json:"window"`
+
+	// Baseline is the mean value of the metric over the baseline period
+	Baseline float64 `json:"baseline"`
+
+	// Statistic is the value of the test statistic that triggered the event.  For DetectDrift this is the number
+	// of baseline standard deviations the window lies below the baseline mean (a negative z-score) and for
+	// PageHinkley it is the Page-Hinkley statistic.
+	Statistic float64 `json:"statistic"`
+}
+
+// baseline returns the mean and standard deviation of the non-empty windows within the first n windows of series.
+func baseline(series []MetricWindow, n int) (mean, std float64) {
+	if n < 1 || n >= len(series) {
+		panic(ErrOutOfBounds)
+	}
+	var values []float64
+	for _, w := range series[:n] {
+		if !math.IsNaN(w.Value) {
+			values = append(values, w.Value)
+		}
+	}
+	if len(values) == 0 {
+		panic("datautils: baseline period contains no non-empty windows")
+	}
+	if len(values) == 1 {
+		return values[0], 0
+	}
+	return stat.MeanStdDev(values, nil)
+}
+
+// DetectDrift flags windows of a metric time series (see WindowedMetric) in which the metric falls more than k
+// standard deviations below its mean over a baseline period.  The first n windows of the series form the baseline
+// and each subsequent window is tested independently.  Metrics are assumed to be "higher is better" (e.g. AP, AUC
+// or accuracy) so only degradation is flagged; negate the values of "lower is better" metrics such as log loss
+// before detection.  Empty windows (with a Value of NaN) are ignored.
+func DetectDrift(series []MetricWindow, n int, k float64) []DriftEvent {
+	mean, std := baseline(series, n)
+
+	var events []DriftEvent
+	for i := n; i < len(series); i++ {
+		v := series[i].Value
+		if math.IsNaN(v) || v >= mean-k*std {
+			continue
+		}
+		events = append(events, DriftEvent{Index: i, Window: series[i], Baseline: mean, Statistic: (v - mean) / std})
+	}
+	return events
+}
+
+// PageHinkley detects sustained degradation of a metric time series (see WindowedMetric) relative to a baseline
+// period using the Page-Hinkley test.  The first n windows of the series form the baseline and, for each
+// subsequent window, the cumulative deviation below the baseline mean, m = Σ (baseline - value - delta), is
+// accumulated.  An event is raised when m exceeds its minimum to date by more than lambda, after which the test is
+// reset to detect further drift.  delta is the magnitude of change tolerated without accumulating evidence of drift
+// and lambda controls the sensitivity of detection (higher values detect fewer, larger changes).  Unlike
+// DetectDrift, the Page-Hinkley test accumulates evidence across windows and so can detect gradual drift where no
+// single window is significantly degraded.  As with DetectDrift, metrics are assumed to be "higher is better" and
+// empty windows are ignored.
+func PageHinkley(series []MetricWindow, n int, delta, lambda float64) []DriftEvent {
+	mean, _ := baseline(series, n)
+
+	var events []DriftEvent
+	var m, min float64
+	for i := n; i < len(series); i++ {
+		v := series[i].Value
+		if math.IsNaN(v) {
+			continue
+		}
+		m += mean - v - delta
+		if m < min {
+			min = m
+		}
+		if ph := m - min; ph > lambda {
+			events = append(events, DriftEvent{Index: i, Window: series[i], Baseline: mean, Statistic: ph})
+			m, min = 0, 0
+		}
+	}
+	return events
+}
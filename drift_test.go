@@ -0,0 +1,54 @@
+package datautils_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func metricSeries(values ...float64) []datautils.MetricWindow {
+	series := make([]datautils.MetricWindow, len(values))
+	for i, v := range values {
+		series[i].Value = v
+	}
+	return series
+}
+
+func eventIndices(events []datautils.DriftEvent) []int {
+	indices := make([]int, len(events))
+	for i, e := range events {
+		indices[i] = e.Index
+	}
+	return indices
+}
+
+func TestDetectDrift(t *testing.T) {
+	// baseline mean 0.8 and sample standard deviation 0.1
+	series := metricSeries(0.7, 0.8, 0.9, 0.75, 0.55, math.NaN(), 0.85, 0.5)
+
+	events := datautils.DetectDrift(series, 3, 2)
+
+	if expected := []int{4, 7}; !reflect.DeepEqual(eventIndices(events), expected) {
+		t.Fatalf("Expected drift at windows: %v but received %v", expected, eventIndices(events))
+	}
+	if e := events[0]; math.Abs(e.Baseline-0.8) > 1e-12 || math.Abs(e.Statistic+2.5) > 1e-12 {
+		t.Errorf("Expected baseline 0.8 and statistic -2.5 but received %v and %v", e.Baseline, e.Statistic)
+	}
+}
+
+func TestPageHinkley(t *testing.T) {
+	// a gradual decline where no single window falls far below the baseline
+	series := metricSeries(0.8, 0.8, 0.8, 0.78, 0.76, 0.74, 0.72, 0.8, 0.8)
+
+	events := datautils.PageHinkley(series, 3, 0.01, 0.1)
+
+	// cumulative deviations: 0.01, 0.04, 0.09, 0.16 (> 0.1 so event and reset), -0.01, -0.02
+	if expected := []int{6}; !reflect.DeepEqual(eventIndices(events), expected) {
+		t.Fatalf("Expected drift at windows: %v but received %v", expected, eventIndices(events))
+	}
+	if s := events[0].Statistic; math.Abs(s-0.16) > 1e-12 {
+		t.Errorf("Expected statistic: %v but received %v", 0.16, s)
+	}
+}
@@ -0,0 +1,314 @@
+package datautils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// LabelEncoder maps class labels to contiguous class indices (0 to len(Classes)-1) and back.  Class indices are
+// assigned in sorted order of the distinct labels used to construct the encoder so that encodings are
+// deterministic.
+type LabelEncoder struct {
+	// Classes contains the distinct class labels, the index of each label being its class index
+	Classes []string
+
+	index map[string]int
+}
+
+// NewLabelEncoder creates a new LabelEncoder for the distinct labels within the supplied slice of labels.
+// Class indices are assigned in lexical order of the labels.
+func NewLabelEncoder(labels []string) *LabelEncoder {
+	seen := make(map[string]bool)
+	var classes []string
+	for _, v := range labels {
+		if !seen[v] {
+			seen[v] = true
+			classes = append(classes, v)
+		}
+	}
+	sort.Strings(classes)
+	return newLabelEncoder(classes)
+}
+
+// NewNumericLabelEncoder creates a new LabelEncoder for the distinct numeric labels (e.g. integer class labels
+// stored as float64 values) within the supplied slice of labels.  Class indices are assigned in numerical order
+// of the labels and the labels are held in Classes formatted as strings (see strconv.FormatFloat).  Numeric
+// labels should be encoded with EncodeNumeric.
+func NewNumericLabelEncoder(labels []float64) *LabelEncoder {
+	seen := make(map[float64]bool)
+	var values []float64
+	for _, v := range labels {
+		if !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	sort.Float64s(values)
+
+	classes := make([]string, len(values))
+	for i, v := range values {
+		classes[i] = formatFloat(v)
+	}
+	return newLabelEncoder(classes)
+}
+
+func newLabelEncoder(classes []string) *LabelEncoder {
+	index := make(map[string]int, len(classes))
+	for i, v := range classes {
+		index[v] = i
+	}
+	return &LabelEncoder{Classes: classes, index: index}
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Encode returns the class index of each of the supplied labels.  An error is returned if any of the labels
+// were not present when the encoder was constructed.
+func (e *LabelEncoder) Encode(labels []string) ([]int, error) {
+	classes := make([]int, len(labels))
+	for i, v := range labels {
+		class, ok := e.index[v]
+		if !ok {
+			return nil, fmt.Errorf("datautils: unknown class label %q", v)
+		}
+		classes[i] = class
+	}
+	return classes, nil
+}
+
+// EncodeNumeric returns the class index of each of the supplied numeric labels.  An error is returned if any
+// of the labels were not present when the encoder was constructed.
+func (e *LabelEncoder) EncodeNumeric(labels []float64) ([]int, error) {
+	formatted := make([]string, len(labels))
+	for i, v := range labels {
+		formatted[i] = formatFloat(v)
+	}
+	return e.Encode(formatted)
+}
+
+// Decode returns the class label corresponding to each of the supplied class indices.  Decode will panic with
+// ErrOutOfBounds if any of the class indices lie outside the range 0 to len(Classes)-1.
+func (e *LabelEncoder) Decode(classes []int) []string {
+	labels := make([]string, len(classes))
+	for i, class := range classes {
+		if class < 0 || class >= len(e.Classes) {
+			panic(ErrOutOfBounds)
+		}
+		labels[i] = e.Classes[class]
+	}
+	return labels
+}
+
+// OneHotEncoder maps class labels to binarised label matrices, with one row per sample and one column per
+// class, and back.  Each row contains 1 in the column of the sample's class and 0 elsewhere.  Each column is
+// therefore the binary label vector for evaluating the corresponding class one-vs-rest.
+type OneHotEncoder struct {
+	*LabelEncoder
+}
+
+// NewOneHotEncoder creates a new OneHotEncoder for the distinct labels within the supplied slice of labels.
+func NewOneHotEncoder(labels []string) *OneHotEncoder {
+	return &OneHotEncoder{LabelEncoder: NewLabelEncoder(labels)}
+}
+
+// Transform returns the binarised label matrix for the supplied labels.  An error is returned if any of the
+// labels were not present when the encoder was constructed.
+func (e *OneHotEncoder) Transform(labels []string) (*mat.Dense, error) {
+	classes, err := e.Encode(labels)
+	if err != nil {
+		return nil, err
+	}
+	return OneHot(classes, len(e.Classes)), nil
+}
+
+// InverseTransform returns the class labels for the rows of the supplied matrix.  The label for each row is
+// that of the column with the largest value so InverseTransform may also be used to decode a matrix of
+// predicted class scores (with ties resolved in favour of the lowest class index).
+func (e *OneHotEncoder) InverseTransform(m mat.Matrix) []string {
+	if _, c := m.Dims(); c != len(e.Classes) {
+		panic(mat.ErrShape)
+	}
+	return e.Decode(ArgMax(m))
+}
+
+// OneHot returns a binarised label matrix with a row for each of the supplied class indices and n columns.
+// Each row contains 1 in the column corresponding to its class index and 0 elsewhere.  OneHot will panic with
+// ErrOutOfBounds if any of the class indices lie outside the range 0 to n-1.
+func OneHot(classes []int, n int) *mat.Dense {
+	m := mat.NewDense(len(classes), n, nil)
+	for i, class := range classes {
+		if class < 0 || class >= n {
+			panic(ErrOutOfBounds)
+		}
+		m.Set(i, class, 1)
+	}
+	return m
+}
+
+// Binarize returns a binary label vector for evaluating the specified class one-vs-rest.  The returned slice
+// contains 1 for samples of the specified class and 0 for all other samples and so is suitable for use as
+// labels with NewPrecisionRecallCurve, NewConfusionMatrix, etc.
+func Binarize(classes []int, class int) []float64 {
+	labels := make([]float64, len(classes))
+	for i, v := range classes {
+		if v == class {
+			labels[i] = 1
+		}
+	}
+	return labels
+}
+
+// ArgMax returns the column index of the largest value within each row of the supplied matrix e.g. the
+// predicted class for each row of a matrix of class scores.  Ties are resolved in favour of the lowest index.
+func ArgMax(m mat.Matrix) []int {
+	r, c := m.Dims()
+	indices := make([]int, r)
+	for i := 0; i < r; i++ {
+		for j := 1; j < c; j++ {
+			if m.At(i, j) > m.At(i, indices[i]) {
+				indices[i] = j
+			}
+		}
+	}
+	return indices
+}
+
+// FrequencyEncoder encodes the values of a categorical feature as the relative frequency with which each value
+// (category) occurs within the data used to construct the encoder.  Frequency encoding produces a single numeric
+// feature regardless of the number of categories and so suits categorical features of high cardinality.
+type FrequencyEncoder struct {
+	// Frequencies contains the relative frequency of each category
+	Frequencies map[string]float64
+}
+
+// NewFrequencyEncoder creates a new FrequencyEncoder for the supplied categorical values e.g. a column of
+// Dataset.Categorical.
+func NewFrequencyEncoder(values []string) *FrequencyEncoder {
+	freq := make(map[string]float64)
+	for _, v := range values {
+		freq[v]++
+	}
+	for k := range freq {
+		freq[k] /= float64(len(values))
+	}
+	return &FrequencyEncoder{Frequencies: freq}
+}
+
+// Transform returns the encoded value of each of the supplied categorical values.  Categories that were not
+// present when the encoder was constructed are encoded as 0.
+func (e *FrequencyEncoder) Transform(values []string) []float64 {
+	encoded := make([]float64, len(values))
+	for i, v := range values {
+		encoded[i] = e.Frequencies[v]
+	}
+	return encoded
+}
+
+// TargetEncoder encodes the values of a categorical feature as the mean label of the observations of each value
+// (category) within the data used to construct the encoder e.g. the proportion of positive observations for
+// binary labels.  To reduce the variance of the encoding of rare categories, each mean is smoothed towards the
+// mean label across all observations (the prior) i.e. (sum + Smoothing * Prior) / (count + Smoothing).
+//
+// As the encoding is derived from the labels, encoding the same observations used to construct the encoder leaks
+// the labels into the features, leading to overly optimistic evaluations.  Observations used for training should
+// instead be encoded out of fold with TargetEncodeFolds.
+type TargetEncoder struct {
+	// Means contains the smoothed mean label of each category
+	Means map[string]float64
+
+	// Prior is the mean label across all observations used to construct the encoder
+	Prior float64
+
+	// Smoothing is the weight of the prior relative to a single observation when smoothing the means
+	Smoothing float64
+}
+
+// NewTargetEncoder creates a new TargetEncoder for the supplied categorical values and their corresponding
+// labels using the specified amount of smoothing (0 for none).  Observations with NaN labels are ignored.
+func NewTargetEncoder(values []string, labels []float64, smoothing float64) *TargetEncoder {
+	if len(values) != len(labels) {
+		panic(ErrLengthMismatch)
+	}
+	if smoothing < 0 {
+		panic("datautils: smoothing must not be negative")
+	}
+
+	sums := make(map[string]float64)
+	counts := make(map[string]float64)
+	var sum, n float64
+	for i, v := range values {
+		if math.IsNaN(labels[i]) {
+			continue
+		}
+		sums[v] += labels[i]
+		counts[v]++
+		sum += labels[i]
+		n++
+	}
+
+	e := &TargetEncoder{Means: make(map[string]float64, len(sums)), Smoothing: smoothing}
+	if n > 0 {
+		e.Prior = sum / n
+	}
+	for k, s := range sums {
+		e.Means[k] = (s + smoothing*e.Prior) / (counts[k] + smoothing)
+	}
+	return e
+}
+
+// Transform returns the encoded value of each of the supplied categorical values.  Categories that were not
+// present when the encoder was constructed are encoded as the Prior.
+func (e *TargetEncoder) Transform(values []string) []float64 {
+	encoded := make([]float64, len(values))
+	for i, v := range values {
+		encoded[i] = e.encode(v)
+	}
+	return encoded
+}
+
+func (e *TargetEncoder) encode(v string) float64 {
+	if mean, ok := e.Means[v]; ok {
+		return mean
+	}
+	return e.Prior
+}
+
+// TargetEncodeFolds returns the out of fold target encoding of the supplied categorical values for training.
+// For each of the folds (see KFold), the observations of the fold's test set are encoded by a TargetEncoder
+// constructed from the observations of the fold's training set so that no observation's label contributes to its
+// own encoding.  The folds should partition the observations as for cross-validation so that every observation is
+// encoded exactly once; observations not within the test set of any fold are encoded as NaN.  Data not used for
+// training (e.g. a held out test set) should be encoded using a TargetEncoder constructed from all the training
+// observations.
+func TargetEncodeFolds(values []string, labels []float64, folds []Fold, smoothing float64) []float64 {
+	if len(values) != len(labels) {
+		panic(ErrLengthMismatch)
+	}
+
+	encoded := make([]float64, len(values))
+	for i := range encoded {
+		encoded[i] = math.NaN()
+	}
+	for _, fold := range folds {
+		e := NewTargetEncoder(selectStrings(values, fold.Train), selectValues(labels, fold.Train), smoothing)
+		for _, i := range fold.Test {
+			encoded[i] = e.encode(values[i])
+		}
+	}
+	return encoded
+}
+
+// selectStrings returns the elements of s at the specified indices.
+func selectStrings(s []string, indices []int) []string {
+	selected := make([]string, len(indices))
+	for i, v := range indices {
+		selected[i] = s[v]
+	}
+	return selected
+}
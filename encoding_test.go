@@ -0,0 +1,135 @@
+package datautils_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLabelEncoder(t *testing.T) {
+	encoder := datautils.NewLabelEncoder([]string{"dog", "cat", "bird", "cat"})
+
+	if expected := []string{"bird", "cat", "dog"}; !reflect.DeepEqual(encoder.Classes, expected) {
+		t.Errorf("Expected classes: %v but received %v", expected, encoder.Classes)
+	}
+
+	labels := []string{"cat", "dog", "bird", "dog"}
+	classes, err := encoder.Encode(labels)
+	if err != nil {
+		t.Fatalf("Unexpected error encoding labels: %v", err)
+	}
+	if expected := []int{1, 2, 0, 2}; !reflect.DeepEqual(classes, expected) {
+		t.Errorf("Expected class indices: %v but received %v", expected, classes)
+	}
+	if decoded := encoder.Decode(classes); !reflect.DeepEqual(decoded, labels) {
+		t.Errorf("Expected decoded labels: %v but received %v", labels, decoded)
+	}
+
+	if _, err := encoder.Encode([]string{"fish"}); err == nil {
+		t.Errorf("Expected error encoding unknown label but received nil")
+	}
+}
+
+func TestNumericLabelEncoder(t *testing.T) {
+	encoder := datautils.NewNumericLabelEncoder([]float64{10, 2, -1, 2})
+
+	if expected := []string{"-1", "2", "10"}; !reflect.DeepEqual(encoder.Classes, expected) {
+		t.Errorf("Expected classes: %v but received %v", expected, encoder.Classes)
+	}
+
+	classes, err := encoder.EncodeNumeric([]float64{2, 10, -1})
+	if err != nil {
+		t.Fatalf("Unexpected error encoding labels: %v", err)
+	}
+	if expected := []int{1, 2, 0}; !reflect.DeepEqual(classes, expected) {
+		t.Errorf("Expected class indices: %v but received %v", expected, classes)
+	}
+}
+
+func TestOneHotEncoder(t *testing.T) {
+	encoder := datautils.NewOneHotEncoder([]string{"a", "b", "c"})
+
+	labels := []string{"b", "a", "c", "b"}
+	m, err := encoder.Transform(labels)
+	if err != nil {
+		t.Fatalf("Unexpected error transforming labels: %v", err)
+	}
+	expected := mat.NewDense(4, 3, []float64{
+		0, 1, 0,
+		1, 0, 0,
+		0, 0, 1,
+		0, 1, 0,
+	})
+	if !mat.Equal(m, expected) {
+		t.Errorf("Expected one-hot matrix: %v but received %v", expected, m)
+	}
+
+	if decoded := encoder.InverseTransform(m); !reflect.DeepEqual(decoded, labels) {
+		t.Errorf("Expected decoded labels: %v but received %v", labels, decoded)
+	}
+}
+
+func TestBinarize(t *testing.T) {
+	classes := []int{0, 2, 1, 2}
+	expected := []float64{0, 1, 0, 1}
+
+	if labels := datautils.Binarize(classes, 2); !reflect.DeepEqual(labels, expected) {
+		t.Errorf("Expected binary labels: %v but received %v", expected, labels)
+	}
+}
+
+func TestFrequencyEncoder(t *testing.T) {
+	e := datautils.NewFrequencyEncoder([]string{"a", "b", "a", "c"})
+
+	expected := []float64{0.5, 0.25, 0.25, 0}
+	if encoded := e.Transform([]string{"a", "b", "c", "d"}); !floats.Equal(expected, encoded) {
+		t.Errorf("Expected encoding: %v but received %v", expected, encoded)
+	}
+}
+
+func TestTargetEncoder(t *testing.T) {
+	values := []string{"a", "a", "b", "b", "b", "c"}
+	labels := []float64{1, 1, 0, 1, math.NaN(), 0}
+
+	tests := []struct {
+		smoothing float64
+		expected  []float64
+	}{
+		{smoothing: 0, expected: []float64{1, 0.5, 0, 0.6}},
+		{smoothing: 2, expected: []float64{(2 + 1.2) / 4, (1 + 1.2) / 4, 1.2 / 3, 0.6}},
+	}
+
+	for i, test := range tests {
+		e := datautils.NewTargetEncoder(values, labels, test.smoothing)
+		if e.Prior != 0.6 {
+			t.Errorf("Test %d: Expected prior 0.6 but received %v", i+1, e.Prior)
+		}
+		if encoded := e.Transform([]string{"a", "b", "c", "d"}); !floats.EqualApprox(test.expected, encoded, 1e-12) {
+			t.Errorf("Test %d: Expected encoding: %v but received %v", i+1, test.expected, encoded)
+		}
+	}
+}
+
+func TestTargetEncodeFolds(t *testing.T) {
+	values := []string{"a", "a", "b", "b"}
+	labels := []float64{1, 0, 1, 1}
+	folds := []datautils.Fold{
+		{Train: []int{1, 3}, Test: []int{0, 2}},
+		{Train: []int{0, 2}, Test: []int{1, 3}},
+	}
+
+	// each observation is encoded from the other fold only so never sees its own label
+	expected := []float64{0, 1, 1, 1}
+	if encoded := datautils.TargetEncodeFolds(values, labels, folds, 0); !floats.Equal(expected, encoded) {
+		t.Errorf("Expected encoding: %v but received %v", expected, encoded)
+	}
+
+	partial := datautils.TargetEncodeFolds(values, labels, folds[:1], 0)
+	if !math.IsNaN(partial[1]) || partial[0] != 0 {
+		t.Errorf("Expected observations outside the test folds to be NaN but received %v", partial)
+	}
+}
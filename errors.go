@@ -0,0 +1,87 @@
+package datautils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	// ErrLengthMismatch is returned when the lengths of the supplied predictions and labels do not match.
+	ErrLengthMismatch = errors.New("datautils: prediction/label length mismatch")
+
+	// ErrOutOfBounds is returned when a cut-off, k, lies outside the range of ranked items.
+	ErrOutOfBounds = errors.New("datautils: index k is out of bounds")
+
+	// ErrNonFinite is returned when predictions or labels contain values that cannot be evaluated (see
+	// HandleNonFinite).
+	ErrNonFinite = errors.New("datautils: non-finite predictions or labels")
+
+	// ErrZeroDivision is returned when a ConfusionMatrix metric is undefined because its denominator is zero.
+	ErrZeroDivision = errors.New("datautils: zero denominator")
+)
+
+// must returns v, panicking if err is not nil.  It implements the panicking constructors and metrics as thin
+// wrappers over their error returning variants which share the same name suffixed with E (e.g.
+// NewRankingEvaluationE and CumulativeGainE).  The error returning variants should be used for input from
+// untrusted sources (e.g. user supplied data in a server) so that malformed input may be handled without
+// recovering from panics.
+func must[T interface{}](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// ValidateLengths checks that the supplied predictions and labels are of matching lengths as required
+// by NewRankingEvaluation, NewPrecisionRecallCurve and NewConfusionMatrix, returning ErrLengthMismatch if
+// they are not.  The constructors panic when supplied mismatched input so either ValidateLengths should be used
+// to check input from untrusted sources (e.g. user supplied data in a server) before construction or the error
+// returning variants of the constructors (e.g. NewRankingEvaluationE) used instead.  The predictions and labels
+// may be of any Float type.
+func ValidateLengths[P, L Float](predictions []P, labels []L) error {
+	if len(predictions) != len(labels) {
+		return ErrLengthMismatch
+	}
+	return nil
+}
+
+// ValidateCutoff checks that the cut-off k is valid for use with CumulativeGain, DiscountedCumulativeGain and
+// NormalisedDiscountedCumulativeGain, returning ErrOutOfBounds if it is not.  Valid cut-offs lie within the
+// range 1 to len(Relevancies) inclusive.
+func (r RankingEvaluation) ValidateCutoff(k int) error {
+	if k < 1 || k > len(r.Relevancies) {
+		return ErrOutOfBounds
+	}
+	return nil
+}
+
+// ValidateCutoff checks that the cut-off k is valid for use with PrecisionAt, RecallAt, F1At and MetricsAt,
+// returning ErrOutOfBounds if it is not.  As the curve is truncated once all positive/relevant items have been
+// found (recall==1), valid cut-offs lie within the range 0 to len(Precision)-1 inclusive.
+func (c PrecisionRecallCurve) ValidateCutoff(k int) error {
+	if k < 0 || k > len(c.Precision)-1 {
+		return ErrOutOfBounds
+	}
+	return nil
+}
+
+// ValidateZeroDivision checks that none of the metrics of the matrix (Precision, Recall, Accuracy, F1, MCC, Kappa
+// and Specificity) have zero denominators, returning an error wrapping ErrZeroDivision and naming the undefined
+// metrics if any do.  With the ZeroDivisionPanic policy the metrics panic when undefined so ValidateZeroDivision
+// (or the error returning accessors e.g. PrecisionE) should be used for matrices of untrusted or sparse data (e.g.
+// small groups).
+func (c ConfusionMatrix) ValidateZeroDivision() error {
+	c.ZeroDivision = ZeroDivisionNaN
+	var undefined []string
+	for _, m := range confusionMetrics {
+		if math.IsNaN(m.metric(c)) {
+			undefined = append(undefined, m.name)
+		}
+	}
+	if len(undefined) > 0 {
+		return fmt.Errorf("%w: %s undefined", ErrZeroDivision, strings.Join(undefined, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package datautils_test
+
+import (
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestValidateLengths(t *testing.T) {
+	tests := []struct {
+		predictions []float64
+		labels      []float64
+		err         error
+	}{
+		{predictions: []float64{0.1, 0.2}, labels: []float64{0, 1}, err: nil},
+		{predictions: []float64{0.1, 0.2}, labels: []float64{0}, err: datautils.ErrLengthMismatch},
+		{predictions: []float64{}, labels: []float64{1}, err: datautils.ErrLengthMismatch},
+	}
+
+	for i, test := range tests {
+		if err := datautils.ValidateLengths(test.predictions, test.labels); err != test.err {
+			t.Errorf("Test %d: Expected error: %v but received %v", i+1, test.err, err)
+		}
+	}
+}
+
+func TestRankingEvaluationValidateCutoff(t *testing.T) {
+	evaluation := datautils.NewRankingEvaluation(datasets[0].probs, datasets[0].labels)
+	tests := []struct {
+		k   int
+		err error
+	}{
+		{k: 0, err: datautils.ErrOutOfBounds},
+		{k: 1, err: nil},
+		{k: 4, err: nil},
+		{k: 5, err: datautils.ErrOutOfBounds},
+	}
+
+	for i, test := range tests {
+		if err := evaluation.ValidateCutoff(test.k); err != test.err {
+			t.Errorf("Test %d: Expected error: %v but received %v", i+1, test.err, err)
+		}
+	}
+}
+
+func TestPrecisionRecallCurveValidateCutoff(t *testing.T) {
+	curve := datautils.NewPrecisionRecallCurve(datasets[0].probs, datasets[0].labels)
+	tests := []struct {
+		k   int
+		err error
+	}{
+		{k: -1, err: datautils.ErrOutOfBounds},
+		{k: 0, err: nil},
+		{k: 3, err: nil},
+		{k: 4, err: datautils.ErrOutOfBounds},
+	}
+
+	for i, test := range tests {
+		if err := curve.ValidateCutoff(test.k); err != test.err {
+			t.Errorf("Test %d: Expected error: %v but received %v", i+1, test.err, err)
+		}
+	}
+}
+
+func TestConstructorsPanicWithErrLengthMismatch(t *testing.T) {
+	constructors := map[string]func(){
+		"NewRankingEvaluation":    func() { datautils.NewRankingEvaluation([]float64{0.1}, []float64{0, 1}) },
+		"NewPrecisionRecallCurve": func() { datautils.NewPrecisionRecallCurve([]float64{0.1}, []float64{0, 1}) },
+		"NewConfusionMatrix":      func() { datautils.NewConfusionMatrix([]float64{0.1}, []float64{0, 1}, 0.5) },
+	}
+
+	for name, constructor := range constructors {
+		func() {
+			defer func() {
+				if r := recover(); r != datautils.ErrLengthMismatch {
+					t.Errorf("%s: Expected panic: %v but received %v", name, datautils.ErrLengthMismatch, r)
+				}
+			}()
+			constructor()
+		}()
+	}
+}
+
+func TestConstructorsReturnErrLengthMismatch(t *testing.T) {
+	predictions, labels := []float64{0.1}, []float64{0, 1}
+	constructors := map[string]func() error{
+		"NewRankingEvaluationE": func() error {
+			_, err := datautils.NewRankingEvaluationE(predictions, labels)
+			return err
+		},
+		"NewPrecisionRecallCurveE": func() error {
+			_, err := datautils.NewPrecisionRecallCurveE(predictions, labels)
+			return err
+		},
+		"NewROCCurveE": func() error {
+			_, err := datautils.NewROCCurveE(predictions, labels)
+			return err
+		},
+		"NewConfusionMatrixE": func() error {
+			_, err := datautils.NewConfusionMatrixE(predictions, labels, 0.5)
+			return err
+		},
+		"NewBinaryEvaluationE": func() error {
+			_, err := datautils.NewBinaryEvaluationE(predictions, labels)
+			return err
+		},
+		"NewQueryEvaluationSetE": func() error {
+			_, err := datautils.NewQueryEvaluationSetE(labels, labels, []string{"q"})
+			return err
+		},
+		"HandleNonFinite": func() error {
+			_, _, _, err := datautils.HandleNonFinite(predictions, labels, datautils.NonFiniteDrop)
+			return err
+		},
+	}
+
+	for name, constructor := range constructors {
+		if err := constructor(); err != datautils.ErrLengthMismatch {
+			t.Errorf("%s: Expected error: %v but received %v", name, datautils.ErrLengthMismatch, err)
+		}
+	}
+
+	if _, err := datautils.NewRankingEvaluationE(datasets[0].probs, datasets[0].labels); err != nil {
+		t.Errorf("Unexpected error for matching lengths: %v", err)
+	}
+}
+
+func TestCutoffMethodsReturnErrOutOfBounds(t *testing.T) {
+	evaluation := datautils.NewRankingEvaluation(datasets[0].probs, datasets[0].labels)
+	set := datautils.EvaluationSet{"q": evaluation}
+	rel := datautils.TraditionalRelevancy
+
+	for _, k := range []int{0, 5} {
+		methods := map[string]func() error{
+			"CumulativeGainE": func() error {
+				_, err := evaluation.CumulativeGainE(k)
+				return err
+			},
+			"NormalisedDiscountedCumulativeGainE": func() error {
+				_, err := evaluation.NormalisedDiscountedCumulativeGainE(k, rel)
+				return err
+			},
+			"NormalisedDiscountedCumulativeGainsE": func() error {
+				_, err := evaluation.NormalisedDiscountedCumulativeGainsE([]int{1, k}, rel)
+				return err
+			},
+			"NormalisedDiscountedCumulativeGainWithE": func() error {
+				_, err := evaluation.NormalisedDiscountedCumulativeGainWithE(k, rel, datautils.ReciprocalDiscount)
+				return err
+			},
+			"HitAtE": func() error {
+				_, err := evaluation.HitAtE(k)
+				return err
+			},
+			"AveragePrecisionAtE": func() error {
+				_, err := evaluation.AveragePrecisionAtE(k, 1)
+				return err
+			},
+		}
+		for name, method := range methods {
+			if err := method(); err != datautils.ErrOutOfBounds {
+				t.Errorf("%s(%d): Expected error: %v but received %v", name, k, datautils.ErrOutOfBounds, err)
+			}
+		}
+	}
+
+	if _, err := set.MeanAveragePrecisionAtE(0, 1); err != datautils.ErrOutOfBounds {
+		t.Errorf("MeanAveragePrecisionAtE: Expected error: %v but received %v", datautils.ErrOutOfBounds, err)
+	}
+	if _, err := set.HitRateE(0); err != datautils.ErrOutOfBounds {
+		t.Errorf("HitRateE: Expected error: %v but received %v", datautils.ErrOutOfBounds, err)
+	}
+	if _, err := set.MeanRecallAtE(0); err != datautils.ErrOutOfBounds {
+		t.Errorf("MeanRecallAtE: Expected error: %v but received %v", datautils.ErrOutOfBounds, err)
+	}
+
+	cg, err := evaluation.CumulativeGainE(2)
+	if err != nil || cg != evaluation.CumulativeGain(2) {
+		t.Errorf("Expected CG@2: %v but received %v (error %v)", evaluation.CumulativeGain(2), cg, err)
+	}
+}
@@ -0,0 +1,160 @@
+package datautils
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// EvaluationSet is a set of ranking evaluations, one per query, keyed by query ID.  It supports
+// aggregation of the per query ranking metrics across the whole set of queries e.g. Mean Reciprocal Rank.
+type EvaluationSet map[string]RankingEvaluation
+
+// Queries returns the query IDs of the evaluations within the set in sorted order.
+func (s EvaluationSet) Queries() []string {
+	queries := make([]string, 0, len(s))
+	for q := range s {
+		queries = append(queries, q)
+	}
+	sort.Strings(queries)
+	return queries
+}
+
+// mean calculates the arithmetic mean of the per query metric calculated by f across all queries in the set.
+// Queries are visited in sorted order so that results are deterministic.  If the set is empty, 0 is returned.
+func (s EvaluationSet) mean(f func(RankingEvaluation) float64) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, q := range s.Queries() {
+		sum += f(s[q])
+	}
+	return sum / float64(len(s))
+}
+
+// MeanReciprocalRank calculates the Mean Reciprocal Rank (MRR) for the set of queries.  This is the
+// mean of the reciprocal rank (see RankingEvaluation.ReciprocalRank) of each query in the set.
+func (s EvaluationSet) MeanReciprocalRank() float64 {
+	return s.mean(RankingEvaluation.ReciprocalRank)
+}
+
+// HitRate calculates the hit rate at cut-off k (HR@k) for the set of queries.  This is the proportion of
+// queries with at least one relevant item ranked within the top k (see RankingEvaluation.HitAt).  For
+// queries with fewer than k ranked items, all the ranked items are considered.
+func (s EvaluationSet) HitRate(k int) float64 {
+	return must(s.HitRateE(k))
+}
+
+// HitRateE calculates HR@k in the same way as HitRate but returns ErrOutOfBounds, rather than panicking, if k is
+// less than 1.
+func (s EvaluationSet) HitRateE(k int) (float64, error) {
+	if k < 1 {
+		return 0, ErrOutOfBounds
+	}
+	return s.mean(func(r RankingEvaluation) float64 {
+		if len(r.Relevancies) == 0 {
+			return 0
+		}
+		if k > len(r.Relevancies) {
+			return r.HitAt(len(r.Relevancies))
+		}
+		return r.HitAt(k)
+	}), nil
+}
+
+// MeanRecallAt calculates the mean recall at cut-off k (Recall@k) for the set of queries.  This is the mean of the
+// proportion of each query's relevant items ranked within the top k (see RankingEvaluation.RecallAt).  For queries
+// with fewer than k ranked items, all the ranked items are considered.
+func (s EvaluationSet) MeanRecallAt(k int) float64 {
+	return must(s.MeanRecallAtE(k))
+}
+
+// MeanRecallAtE calculates the mean Recall@k in the same way as MeanRecallAt but returns ErrOutOfBounds, rather
+// than panicking, if k is less than 1.
+func (s EvaluationSet) MeanRecallAtE(k int) (float64, error) {
+	if k < 1 {
+		return 0, ErrOutOfBounds
+	}
+	return s.mean(func(r RankingEvaluation) float64 {
+		if len(r.Relevancies) == 0 {
+			return 0
+		}
+		if k > len(r.Relevancies) {
+			return r.RecallAt(len(r.Relevancies))
+		}
+		return r.RecallAt(k)
+	}), nil
+}
+
+// MeanAveragePrecisionAt calculates the mean average precision at cut-off k (MAP@k) for the set of queries.  This
+// is the mean of the average precision at k (see RankingEvaluation.AveragePrecisionAt) of each query in the set,
+// with items whose relevancy value is greater than or equal to threshold considered relevant.  For queries with
+// fewer than k ranked items, all the ranked items are considered.  Queries with no ranked items score 0.
+func (s EvaluationSet) MeanAveragePrecisionAt(k int, threshold float64) float64 {
+	return must(s.MeanAveragePrecisionAtE(k, threshold))
+}
+
+// MeanAveragePrecisionAtE calculates MAP@k in the same way as MeanAveragePrecisionAt but returns ErrOutOfBounds,
+// rather than panicking, if k is less than 1.
+func (s EvaluationSet) MeanAveragePrecisionAtE(k int, threshold float64) (float64, error) {
+	if k < 1 {
+		return 0, ErrOutOfBounds
+	}
+	return s.mean(func(r RankingEvaluation) float64 {
+		if len(r.Relevancies) == 0 {
+			return 0
+		}
+		if k > len(r.Relevancies) {
+			return r.AveragePrecisionAt(len(r.Relevancies), threshold)
+		}
+		return r.AveragePrecisionAt(k, threshold)
+	}), nil
+}
+
+// MeanNormalisedDiscountedCumulativeGains calculates the mean normalised discounted cumulative gain across the
+// set of queries at each of the specified cut-offs, returning the values in the same order as cutoffs (see
+// RankingEvaluation.NormalisedDiscountedCumulativeGains).  For queries with fewer than k ranked items, all the
+// ranked items are considered.  Queries with no relevant items, ranked or unretrieved, score 1 in keeping with
+// NormalisedDiscountedCumulativeGain while queries with no ranked items whose relevant items were all unretrieved
+// score 0.  rel is the relevancy function to use.
+func (s EvaluationSet) MeanNormalisedDiscountedCumulativeGains(cutoffs []int, rel RelevancyFunction) []float64 {
+	return must(s.MeanNormalisedDiscountedCumulativeGainsE(cutoffs, rel))
+}
+
+// MeanNormalisedDiscountedCumulativeGainsE calculates the mean normalised discounted cumulative gains in the same
+// way as MeanNormalisedDiscountedCumulativeGains but returns ErrOutOfBounds, rather than panicking, if any of the
+// cut-offs are less than 1.
+func (s EvaluationSet) MeanNormalisedDiscountedCumulativeGainsE(cutoffs []int, rel RelevancyFunction) ([]float64, error) {
+	for _, k := range cutoffs {
+		if k < 1 {
+			return nil, ErrOutOfBounds
+		}
+	}
+
+	means := make([]float64, len(cutoffs))
+	if len(s) == 0 {
+		return means, nil
+	}
+
+	clamped := make([]int, len(cutoffs))
+	for _, q := range s.Queries() {
+		r := s[q]
+		if r.noRelevant() {
+			floats.AddConst(1, means)
+			continue
+		}
+		if len(r.Relevancies) == 0 {
+			continue
+		}
+		for i, k := range cutoffs {
+			clamped[i] = k
+			if k > len(r.Relevancies) {
+				clamped[i] = len(r.Relevancies)
+			}
+		}
+		floats.Add(means, r.NormalisedDiscountedCumulativeGains(clamped, rel))
+	}
+	floats.Scale(1/float64(len(s)), means)
+	return means, nil
+}
@@ -0,0 +1,142 @@
+package datautils_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func evaluationSet() datautils.EvaluationSet {
+	set := make(datautils.EvaluationSet)
+	for i, d := range datasets {
+		set[fmt.Sprintf("q%d", i+1)] = datautils.NewRankingEvaluation(d.probs, d.labels)
+	}
+	return set
+}
+
+func TestEvaluationSetQueries(t *testing.T) {
+	expected := []string{"q1", "q2", "q3", "q4", "q5"}
+	queries := evaluationSet().Queries()
+
+	if fmt.Sprint(expected) != fmt.Sprint(queries) {
+		t.Errorf("Expected queries: %v but received %v", expected, queries)
+	}
+}
+
+func TestMeanReciprocalRank(t *testing.T) {
+	tests := []struct {
+		set datautils.EvaluationSet
+		mrr float64
+	}{
+		{set: evaluationSet(), mrr: 0.4},
+		{set: datautils.EvaluationSet{}, mrr: 0},
+	}
+
+	for i, test := range tests {
+		if mrr := test.set.MeanReciprocalRank(); mrr != test.mrr {
+			t.Errorf("Test %d: Expected MRR: %v but received %v", i+1, test.mrr, mrr)
+		}
+	}
+}
+
+func TestHitRate(t *testing.T) {
+	tests := []struct {
+		k       int
+		hitRate float64
+	}{
+		{k: 1, hitRate: 0.2},
+		{k: 2, hitRate: 0.6},
+		{k: 10, hitRate: 0.6},
+	}
+
+	set := evaluationSet()
+	for i, test := range tests {
+		if hitRate := set.HitRate(test.k); hitRate != test.hitRate {
+			t.Errorf("Test %d: Expected HR@%d: %v but received %v", i+1, test.k, test.hitRate, hitRate)
+		}
+	}
+}
+
+func TestMeanRecallAt(t *testing.T) {
+	tests := []struct {
+		k      int
+		recall float64
+	}{
+		{k: 1, recall: 0.1},
+		{k: 2, recall: (0.5 + 0.5 + 1.0/3) / 5},
+		{k: 10, recall: 0.6},
+	}
+
+	set := evaluationSet()
+	for i, test := range tests {
+		if recall := set.MeanRecallAt(test.k); math.Abs(recall-test.recall) > 1e-12 {
+			t.Errorf("Test %d: Expected Recall@%d: %v but received %v", i+1, test.k, test.recall, recall)
+		}
+	}
+}
+
+func TestMeanNormalisedDiscountedCumulativeGains(t *testing.T) {
+	set := evaluationSet()
+	cutoffs := []int{1, 3, 10}
+
+	means := set.MeanNormalisedDiscountedCumulativeGains(cutoffs, datautils.TraditionalRelevancy)
+
+	for i, k := range cutoffs {
+		var expected float64
+		for _, q := range set.Queries() {
+			r := set[q]
+			c := k
+			if c > len(r.Relevancies) {
+				c = len(r.Relevancies)
+			}
+			expected += r.NormalisedDiscountedCumulativeGain(c, datautils.TraditionalRelevancy)
+		}
+		expected /= float64(len(set))
+
+		if math.Abs(means[i]-expected) > 1e-12 {
+			t.Errorf("Expected mean NDCG@%d: %v but received %v", k, expected, means[i])
+		}
+	}
+
+	if means := (datautils.EvaluationSet{}).MeanNormalisedDiscountedCumulativeGains(cutoffs, datautils.TraditionalRelevancy); len(means) != 3 || means[0] != 0 {
+		t.Errorf("Expected zero means for an empty set but received %v", means)
+	}
+}
+
+func TestMeanAveragePrecisionAt(t *testing.T) {
+	tests := []struct {
+		set       datautils.EvaluationSet
+		k         int
+		threshold float64
+		mapk      float64
+	}{
+		{
+			set: datautils.EvaluationSet{
+				"q1": datautils.NewRankingEvaluation([]float64{0.9, 0.8, 0.7, 0.6}, []float64{2, 0, 1, 3}),
+				"q2": datautils.NewRankingEvaluation([]float64{0.2, 0.8}, []float64{2, 1}),
+				"q3": datautils.NewRankingEvaluation([]float64{}, []float64{}),
+			},
+			k:         10,
+			threshold: 2,
+			mapk:      (0.75 + 0.5 + 0) / 3,
+		},
+		{
+			set: datautils.EvaluationSet{
+				"q1": datautils.NewRankingEvaluation([]float64{0.9, 0.8, 0.7, 0.6}, []float64{2, 0, 1, 3}),
+				"q2": datautils.NewRankingEvaluation([]float64{0.2, 0.8}, []float64{2, 1}),
+			},
+			k:         1,
+			threshold: 1,
+			mapk:      1,
+		},
+		{set: datautils.EvaluationSet{}, k: 5, threshold: 1, mapk: 0},
+	}
+
+	for i, test := range tests {
+		if mapk := test.set.MeanAveragePrecisionAt(test.k, test.threshold); math.Abs(mapk-test.mapk) > 1e-12 {
+			t.Errorf("Test %d: Expected MAP@%d: %v but received %v", i+1, test.k, test.mapk, mapk)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package datautils
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// DefaultRunSize is the default number of observations held in memory at once by NewExternalEvaluation.  Each
+// observation occupies 16 bytes so the default runs are 128MB.
+const DefaultRunSize = 8 << 20
+
+// ExternalEvaluation contains the exact AUC and average precision of a stream of (prediction, label) pairs
+// calculated by NewExternalEvaluation without holding the whole stream in memory.
+type ExternalEvaluation struct {
+	// Observations, Positives and Negatives are the total numbers of observations, positive observations (labels
+	// greater than 0) and negative observations respectively
+	Observations, Positives, Negatives int
+
+	// AUC is the area under the ROC curve, identical to ROCCurve.AUC
+	AUC float64
+
+	// AveragePrecision is the average precision, identical to PrecisionRecallCurve.AveragePrecision
+	AveragePrecision float64
+
+	// Runs is the number of sorted runs the stream was divided into
+	Runs int
+}
+
+// NewExternalEvaluation calculates the exact AUC and average precision of all the remaining observations of src
+// for datasets too large to hold in memory (for which a ScoreHistogram would only approximate the metrics).  The
+// stream is read in runs of up to runSize observations (or DefaultRunSize if runSize is 0) which are each sorted
+// in memory and spilled to a temporary file in dir (or the default directory for temporary files if dir is empty)
+// before the sorted runs are merged and the metrics calculated in a single pass.  Memory use is therefore
+// proportional to runSize rather than the length of the stream.  The temporary files are removed before
+// returning.  As with the in memory curves, any label greater than 0 is considered positive.  Tied predictions
+// count as half for AUC and, as with PrecisionRecallCurve, are ranked in no particular order for average
+// precision.
+func NewExternalEvaluation(src Source, runSize int, dir string) (ExternalEvaluation, error) {
+	if runSize < 0 {
+		panic("datautils: run size must not be negative")
+	}
+	if runSize == 0 {
+		runSize = DefaultRunSize
+	}
+
+	var e ExternalEvaluation
+	var runs []*scoreRun
+	defer func() {
+		for _, r := range runs {
+			r.close()
+		}
+	}()
+
+	var buf []scorePair
+	for {
+		p, l, ok := src.Next()
+		if ok {
+			buf = append(buf, scorePair{prediction: p, label: l})
+			if len(buf) < runSize {
+				continue
+			}
+		}
+		if len(buf) > 0 {
+			sort.Slice(buf, func(i, j int) bool { return buf[i].prediction > buf[j].prediction })
+			if !ok && len(runs) == 0 {
+				// the whole stream fits in a single run so there is no need to spill it to disk
+				runs = append(runs, &scoreRun{pairs: buf})
+				break
+			}
+			r, err := spillRun(buf, dir)
+			if err != nil {
+				return e, err
+			}
+			runs = append(runs, r)
+			buf = buf[:0]
+		}
+		if !ok {
+			break
+		}
+	}
+	e.Runs = len(runs)
+
+	// merge the runs in descending order of prediction
+	var h runHeap
+	for _, r := range runs {
+		more, err := r.next()
+		if err != nil {
+			return e, err
+		}
+		if more {
+			h = append(h, r)
+		}
+	}
+	heap.Init(&h)
+
+	var sumPrecision, posAbove, groupPos, groupNeg, auc float64
+	group := math.NaN()
+	for len(h) > 0 {
+		r := h[0]
+		pair := r.head
+		if pair.prediction != group {
+			auc += groupNeg * (posAbove + 0.5*groupPos)
+			posAbove += groupPos
+			group, groupPos, groupNeg = pair.prediction, 0, 0
+		}
+
+		e.Observations++
+		if pair.label > 0 {
+			e.Positives++
+			groupPos++
+			sumPrecision += float64(e.Positives) / float64(e.Observations)
+		} else {
+			e.Negatives++
+			groupNeg++
+		}
+
+		more, err := r.next()
+		if err != nil {
+			return e, err
+		}
+		if more {
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+	auc += groupNeg * (posAbove + 0.5*groupPos)
+
+	e.AUC = auc / (float64(e.Positives) * float64(e.Negatives))
+	if e.Positives > 0 {
+		e.AveragePrecision = sumPrecision / float64(e.Positives)
+	}
+	return e, nil
+}
+
+// scorePair is a single (prediction, label) observation.
+type scorePair struct {
+	prediction, label float64
+}
+
+// scoreRun is a run of observations sorted in descending order of prediction held either in memory (pairs) or
+// in a temporary file.  head is the current observation of the run during merging.
+type scoreRun struct {
+	pairs []scorePair
+	file  *os.File
+	r     *bufio.Reader
+	buf   [16]byte
+	head  scorePair
+}
+
+// spillRun writes the sorted pairs to a new temporary file in dir returning a run reading from the start of it.
+func spillRun(pairs []scorePair, dir string) (*scoreRun, error) {
+	f, err := os.CreateTemp(dir, "datautils-run-*")
+	if err != nil {
+		return nil, fmt.Errorf("datautils: creating run file: %w", err)
+	}
+	r := &scoreRun{file: f}
+
+	w := bufio.NewWriter(f)
+	for _, pair := range pairs {
+		binary.LittleEndian.PutUint64(r.buf[:8], math.Float64bits(pair.prediction))
+		binary.LittleEndian.PutUint64(r.buf[8:], math.Float64bits(pair.label))
+		if _, err := w.Write(r.buf[:]); err != nil {
+			r.close()
+			return nil, fmt.Errorf("datautils: writing run file: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		r.close()
+		return nil, fmt.Errorf("datautils: writing run file: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		r.close()
+		return nil, fmt.Errorf("datautils: reading run file: %w", err)
+	}
+	r.r = bufio.NewReader(f)
+	return r, nil
+}
+
+// next advances the run to its next observation returning false once the run is exhausted.
+func (r *scoreRun) next() (bool, error) {
+	if r.file == nil {
+		if len(r.pairs) == 0 {
+			return false, nil
+		}
+		r.head, r.pairs = r.pairs[0], r.pairs[1:]
+		return true, nil
+	}
+	if _, err := io.ReadFull(r.r, r.buf[:]); err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, fmt.Errorf("datautils: reading run file: %w", err)
+	}
+	r.head = scorePair{
+		prediction: math.Float64frombits(binary.LittleEndian.Uint64(r.buf[:8])),
+		label:      math.Float64frombits(binary.LittleEndian.Uint64(r.buf[8:])),
+	}
+	return true, nil
+}
+
+// close closes and removes the run's temporary file, if any.
+func (r *scoreRun) close() {
+	if r.file != nil {
+		r.file.Close()
+		os.Remove(r.file.Name())
+		r.file = nil
+	}
+}
+
+// runHeap is a heap of runs ordered by descending prediction of their current observations.
+type runHeap []*scoreRun
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return h[i].head.prediction > h[j].head.prediction }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x interface{}) {
+	*h = append(*h, x.(*scoreRun))
+}
+func (h *runHeap) Pop() interface{} {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
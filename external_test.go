@@ -0,0 +1,80 @@
+package datautils_test
+
+import (
+	"math"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestExternalEvaluation(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	predictions := make([]float64, 100)
+	labels := make([]float64, 100)
+	for i := range predictions {
+		predictions[i] = rnd.Float64()
+		if rnd.Float64() < predictions[i] {
+			labels[i] = 1
+		}
+	}
+	// tied predictions only affect AUC which counts ties as half regardless of their order
+	tied := make([]float64, len(predictions))
+	for i, v := range predictions {
+		tied[i] = math.Round(v*10) / 10
+	}
+
+	tests := []struct {
+		predictions []float64
+		runSize     int
+		runs        int
+		ap          bool
+	}{
+		{predictions: predictions, runSize: 7, runs: 15, ap: true},
+		{predictions: predictions, runSize: 100, runs: 1, ap: true},
+		{predictions: predictions, runSize: 0, runs: 1, ap: true},
+		{predictions: tied, runSize: 9, runs: 12},
+	}
+
+	for i, test := range tests {
+		dir := t.TempDir()
+		e, err := datautils.NewExternalEvaluation(datautils.NewSliceSource(test.predictions, labels), test.runSize, dir)
+		if err != nil {
+			t.Fatalf("Test %d: Unexpected error: %v", i+1, err)
+		}
+
+		if e.Runs != test.runs {
+			t.Errorf("Test %d: Expected %d runs but received %d", i+1, test.runs, e.Runs)
+		}
+		if e.Observations != len(labels) || e.Positives+e.Negatives != len(labels) {
+			t.Errorf("Test %d: Unexpected counts: %+v", i+1, e)
+		}
+		if auc := datautils.NewROCCurve(test.predictions, labels).AUC(); math.Abs(e.AUC-auc) > 1e-12 {
+			t.Errorf("Test %d: Expected AUC: %v but received %v", i+1, auc, e.AUC)
+		}
+		if ap := datautils.NewPrecisionRecallCurve(test.predictions, labels).AveragePrecision(); test.ap && math.Abs(e.AveragePrecision-ap) > 1e-12 {
+			t.Errorf("Test %d: Expected average precision: %v but received %v", i+1, ap, e.AveragePrecision)
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Errorf("Test %d: Expected temporary run files to be removed but found %d", i+1, len(files))
+		}
+	}
+}
+
+func TestExternalEvaluationEmpty(t *testing.T) {
+	e, err := datautils.NewExternalEvaluation(datautils.NewSliceSource([]float64{}, []float64{}), 10, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Observations != 0 || e.Runs != 0 || !math.IsNaN(e.AUC) || e.AveragePrecision != 0 {
+		t.Errorf("Unexpected evaluation of empty stream: %+v", e)
+	}
+}
+
+func TestExternalEvaluationError(t *testing.T) {
+	src := datautils.NewSliceSource([]float64{0.1, 0.2, 0.3}, []float64{0, 1, 0})
+	if _, err := datautils.NewExternalEvaluation(src, 1, "/nonexistent/directory"); err == nil {
+		t.Error("Expected error creating run files in a missing directory")
+	}
+}
@@ -0,0 +1,167 @@
+package datautils
+
+import (
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// DefaultFacetLegendWidth is the width in centimetres reserved for the common legend of a FacetGrid if none is
+// specified.
+const DefaultFacetLegendWidth = 4.0
+
+// FacetGrid lays out multiple plots of the same kind (facets) e.g. the PR curves of each class or the calibration
+// plots of each segment, into a single grid image for comparison.  Facets may share axis ranges so that they can
+// be compared directly and a common legend rather than repeating the same legend in every facet.
+type FacetGrid struct {
+	// Plots are the facets in row major order.  nil facets leave their cell empty
+	Plots []*plot.Plot
+
+	// Cols is the number of columns of the grid.  If 0, the grid is as near square as possible
+	Cols int
+
+	// ShareX and ShareY give all the facets the same x and y axis ranges respectively, spanning the ranges of
+	// all the facets, and label the shared axes and their ticks only on the outer facets (the x axis of the bottom
+	// facet of each column and the y axis of the facets in the first column)
+	ShareX, ShareY bool
+
+	// Legend draws the legend of the first facet once, to the right of the grid, in place of the legends of the
+	// individual facets.  The facets are assumed to plot the same series as is usual for faceted plots
+	Legend bool
+
+	// LegendWidth is the width in centimetres reserved for the common legend.  If 0, DefaultFacetLegendWidth is
+	// used
+	LegendWidth float64
+}
+
+// Save renders the grid as a single image and saves it to the file at path with the specified overall width and
+// height in centimetres.  As with SavePlot, the format of the file is determined by the file extension of path.
+// The facets are aligned so that the data areas of the facets in each row and column line up.  The supplied plots
+// are not modified so may still be saved individually.
+func (g FacetGrid) Save(path string, widthCm, heightCm float64) error {
+	cols := g.Cols
+	if cols <= 0 {
+		cols = int(math.Ceil(math.Sqrt(float64(len(g.Plots)))))
+	}
+	rows := (len(g.Plots) + cols - 1) / cols
+
+	facets := make([]*plot.Plot, len(g.Plots))
+	for i, p := range g.Plots {
+		if p != nil {
+			facets[i] = copyFacet(p)
+		}
+	}
+
+	if g.ShareX {
+		shareAxis(facets, func(p *plot.Plot) *plot.Axis { return &p.X })
+		for i, p := range facets {
+			// only the bottom facet of each column is labelled
+			if p != nil && i+cols < len(facets) && facets[i+cols] != nil {
+				p.X.Label.Text = ""
+				p.X.Tick.Marker = unlabelledTicks{p.X.Tick.Marker}
+			}
+		}
+	}
+	if g.ShareY {
+		shareAxis(facets, func(p *plot.Plot) *plot.Axis { return &p.Y })
+		for i, p := range facets {
+			if p != nil && i%cols != 0 {
+				p.Y.Label.Text = ""
+				p.Y.Tick.Marker = unlabelledTicks{p.Y.Tick.Marker}
+			}
+		}
+	}
+
+	var legend *plot.Legend
+	if g.Legend {
+		for _, p := range facets {
+			if p == nil {
+				continue
+			}
+			if legend == nil {
+				l := p.Legend
+				legend = &l
+			}
+			empty, err := plot.NewLegend()
+			if err != nil {
+				return err
+			}
+			p.Legend = empty
+		}
+	}
+
+	grid := make([][]*plot.Plot, rows)
+	for i := range grid {
+		grid[i] = make([]*plot.Plot, cols)
+		copy(grid[i], facets[i*cols:])
+	}
+
+	w, err := newGridCanvas(path, widthCm, heightCm)
+	if err != nil {
+		return err
+	}
+	c := draw.New(w)
+	if legend != nil {
+		legendWidth := g.LegendWidth
+		if legendWidth == 0 {
+			legendWidth = DefaultFacetLegendWidth
+		}
+		width := vg.Length(legendWidth) * vg.Centimeter
+		legend.Top, legend.Left = true, true
+		legend.Draw(draw.Crop(c, c.Max.X-c.Min.X-width, 0, 0, 0))
+		c = draw.Crop(c, 0, -width, 0, 0)
+	}
+	drawPlotGrid(grid, cols, c)
+	return writeFile(w, path)
+}
+
+// shareAxis sets the range of the axis of each of the plots to the range spanning the axes of all the plots.
+func shareAxis(plots []*plot.Plot, axis func(*plot.Plot) *plot.Axis) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, p := range plots {
+		if p != nil {
+			a := axis(p)
+			min, max = math.Min(min, a.Min), math.Max(max, a.Max)
+		}
+	}
+	for _, p := range plots {
+		if p != nil {
+			a := axis(p)
+			a.Min, a.Max = min, max
+		}
+	}
+}
+
+// copyFacet copies the plot p so that its axes and legend may be adjusted for rendering within a FacetGrid without
+// modifying the caller's plot.  The plotters are shared with p as they are drawn but never modified.
+func copyFacet(p *plot.Plot) *plot.Plot {
+	facet := *p
+	facet.X, facet.Y = copyAxis(p.X), copyAxis(p.Y)
+	return &facet
+}
+
+// copyAxis copies the axis a including the dash patterns of its lines which would otherwise share their backing
+// arrays with a.
+func copyAxis(a plot.Axis) plot.Axis {
+	a.LineStyle.Dashes = append([]vg.Length(nil), a.LineStyle.Dashes...)
+	a.Tick.LineStyle.Dashes = append([]vg.Length(nil), a.Tick.LineStyle.Dashes...)
+	return a
+}
+
+// unlabelledTicks wraps a Ticker removing the labels from its ticks so that the tick positions of the inner facets
+// of a FacetGrid with shared axes are marked without repeating the labels of the outer facets.  As with any
+// unlabelled ticks, the ticks are drawn as minor ticks.
+type unlabelledTicks struct {
+	plot.Ticker
+}
+
+func (t unlabelledTicks) Ticks(min, max float64) []plot.Tick {
+	// the ticks are copied as Tickers such as plot.ConstantTicks return their own backing array
+	ticks := append([]plot.Tick(nil), t.Ticker.Ticks(min, max)...)
+	for i := range ticks {
+		ticks[i].Label = ""
+	}
+	return ticks
+}
@@ -0,0 +1,56 @@
+package datautils_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/plot"
+)
+
+func TestFacetGrid(t *testing.T) {
+	labels := []float64{0, 0, 1, 1}
+	var facets []*plot.Plot
+	for _, predictions := range [][]float64{{0.1, 0.4, 0.35, 0.8}, {0.2, 0.3, 0.6, 0.9}, {0.5, 0.1, 0.4, 0.7}} {
+		p := datautils.NewPrecisionRecallCurve(predictions, labels).Plot()
+		p.X.Min, p.X.Max = predictions[0], predictions[3]
+		p.Y.Tick.Marker = plot.ConstantTicks{{Value: 0, Label: "0"}, {Value: 1, Label: "1"}}
+		facets = append(facets, p)
+	}
+	// a nil facet leaves its cell empty
+	facets = append(facets, nil, facets[0])
+
+	dir := t.TempDir()
+	grids := []datautils.FacetGrid{
+		{Plots: facets},
+		{Plots: facets, Cols: 2, ShareX: true, ShareY: true, Legend: true},
+		{Plots: facets, Cols: 4, ShareX: true, Legend: true, LegendWidth: 2},
+	}
+	for i, g := range grids {
+		path := filepath.Join(dir, "facets.png")
+		if err := g.Save(path, 30, 20); err != nil {
+			t.Errorf("Test %d: Unexpected error: %v", i+1, err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Test %d: Expected file %s to be created but received %v", i+1, path, err)
+		}
+		os.Remove(path)
+	}
+
+	// the supplied plots are left unmodified
+	if facets[1].X.Min != 0.2 || facets[1].X.Max != 0.9 || facets[1].X.Label.Text != "Recall" || facets[1].Y.Label.Text != "Precision" {
+		t.Errorf("Expected facet to be unmodified but received x axis [%v, %v] labelled %q and %q", facets[1].X.Min, facets[1].X.Max, facets[1].X.Label.Text, facets[1].Y.Label.Text)
+	}
+
+	for i, tick := range facets[1].Y.Tick.Marker.Ticks(0, 1) {
+		if tick.Label != strconv.Itoa(i) {
+			t.Errorf("Expected facet tick labels to be unmodified but received %q for tick %d", tick.Label, i)
+		}
+	}
+
+	if err := (datautils.FacetGrid{Plots: facets}).Save(filepath.Join(dir, "facets.unknown"), 30, 20); err == nil {
+		t.Errorf("Expected error for unsupported format but received nil")
+	}
+}
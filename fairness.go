@@ -0,0 +1,137 @@
+package datautils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// GroupMetrics contains the classification performance of a single group of observations sharing the same value
+// of a protected attribute (e.g. gender or age band).
+type GroupMetrics struct {
+	// Group is the value of the protected attribute shared by the observations in the group
+	Group string `json:"group"`
+
+	// Matrix is the confusion matrix for the observations in the group
+	Matrix ConfusionMatrix `json:"matrix"`
+
+	// SelectionRate is the proportion of observations in the group predicted positive
+	SelectionRate float64 `json:"selection_rate"`
+
+	// TPR is the true positive rate (recall) of the group
+	TPR float64 `json:"tpr"`
+
+	// FPR is the false positive rate (1 - specificity) of the group
+	FPR float64 `json:"fpr"`
+}
+
+// FairnessReport compares the classification performance of groups of observations defined by a protected
+// attribute to assess whether a model treats the groups fairly.  A perfectly fair model (by each definition) has
+// differences of 0 and a disparate impact ratio of 1.
+type FairnessReport struct {
+	// Groups contains the metrics for each group ordered by group
+	Groups []GroupMetrics `json:"groups"`
+
+	// DemographicParityDifference is the difference between the highest and lowest selection rates of the groups
+	DemographicParityDifference float64 `json:"demographic_parity_difference"`
+
+	// EqualisedOddsDifference is the larger of the differences between the highest and lowest true positive rates
+	// and the highest and lowest false positive rates of the groups
+	EqualisedOddsDifference float64 `json:"equalised_odds_difference"`
+
+	// DisparateImpactRatio is the ratio of the lowest to the highest selection rates of the groups.  A ratio below
+	// 0.8 is commonly taken as evidence of adverse impact (the "four-fifths rule").
+	DisparateImpactRatio float64 `json:"disparate_impact_ratio"`
+}
+
+// NewFairnessReport creates a new FairnessReport for the supplied predictions and labels grouped by the
+// corresponding values of the protected attribute in groups.  Observations are classified as positive where the
+// prediction is greater than or equal to the threshold and, as with NewConfusionMatrix, a label value of 1
+// represents a positive observation.  Groups with no positive (or negative) observations have an undefined TPR (or
+// FPR), represented as NaN, and are excluded from the corresponding difference.
+func NewFairnessReport(predictions, labels []float64, groups []string, threshold float64) FairnessReport {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+	if len(groups) != len(labels) {
+		panic(ErrLengthMismatch)
+	}
+
+	matrices := make(map[string]*ConfusionMatrix)
+	for i, g := range groups {
+		m, ok := matrices[g]
+		if !ok {
+			m = &ConfusionMatrix{}
+			matrices[g] = m
+		}
+		m.add(predictions[i] >= threshold, labels[i], 1)
+	}
+
+	names := make([]string, 0, len(matrices))
+	for g := range matrices {
+		names = append(names, g)
+	}
+	sort.Strings(names)
+
+	var report FairnessReport
+	selection := make([]float64, len(names))
+	tpr := make([]float64, len(names))
+	fpr := make([]float64, len(names))
+	for i, g := range names {
+		m := *matrices[g]
+		tp, tn, fp, fn := m.cells()
+		selection[i] = (tp + fp) / (tp + tn + fp + fn)
+		tpr[i] = m.Recall()
+		fpr[i] = fp / (fp + tn)
+		report.Groups = append(report.Groups, GroupMetrics{
+			Group:         g,
+			Matrix:        m,
+			SelectionRate: selection[i],
+			TPR:           tpr[i],
+			FPR:           fpr[i],
+		})
+	}
+
+	minSel, maxSel := extent(selection)
+	report.DemographicParityDifference = maxSel - minSel
+	report.DisparateImpactRatio = minSel / maxSel
+	minTPR, maxTPR := extent(tpr)
+	minFPR, maxFPR := extent(fpr)
+	report.EqualisedOddsDifference = math.Max(maxTPR-minTPR, maxFPR-minFPR)
+
+	return report
+}
+
+// extent returns the minimum and maximum values of s ignoring NaN values.  If s contains no values other than
+// NaN, both the minimum and maximum are 0.
+func extent(s []float64) (min, max float64) {
+	var found bool
+	for _, v := range s {
+		if math.IsNaN(v) {
+			continue
+		}
+		if !found || v < min {
+			min = v
+		}
+		if !found || v > max {
+			max = v
+		}
+		found = true
+	}
+	return min, max
+}
+
+// String formats the fairness report for printing as a comparison of the groups followed by the summary metrics.
+func (r FairnessReport) String() string {
+	s := "Group                | Observations | Selection Rate |    TPR    |    FPR    | Precision | Accuracy\n"
+	s = s + "--------------------------------------------------------------------------------------------------\n"
+	for _, g := range r.Groups {
+		s = fmt.Sprintf("%s%-20s | %12d | %14.4f | %9.4f | %9.4f | %9.4f | %8.4f\n", s,
+			g.Group, g.Matrix.Observations, g.SelectionRate, g.TPR, g.FPR, g.Matrix.Precision(), g.Matrix.Accuracy())
+	}
+	s = s + "--------------------------------------------------------------------------------------------------\n"
+	s = fmt.Sprintf("%sDemographic Parity Difference = %f\n", s, r.DemographicParityDifference)
+	s = fmt.Sprintf("%sEqualised Odds Difference = %f\n", s, r.EqualisedOddsDifference)
+	s = fmt.Sprintf("%sDisparate Impact Ratio = %f\n", s, r.DisparateImpactRatio)
+	return s
+}
@@ -0,0 +1,71 @@
+package datautils_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestFairnessReport(t *testing.T) {
+	predictions := []float64{0.9, 0.8, 0.3, 0.2, 0.7, 0.4, 0.3, 0.6}
+	labels := []float64{1, 0, 1, 0, 1, 1, 0, 0}
+	groups := []string{"a", "a", "a", "a", "b", "b", "b", "b"}
+
+	report := datautils.NewFairnessReport(predictions, labels, groups, 0.5)
+
+	expected := []struct {
+		group         string
+		selectionRate float64
+		tpr           float64
+		fpr           float64
+	}{
+		{group: "a", selectionRate: 0.5, tpr: 0.5, fpr: 0.5},
+		{group: "b", selectionRate: 0.5, tpr: 0.5, fpr: 0.5},
+	}
+	if len(report.Groups) != len(expected) {
+		t.Fatalf("Expected %d groups but received %d", len(expected), len(report.Groups))
+	}
+	for i, e := range expected {
+		g := report.Groups[i]
+		if g.Group != e.group || g.SelectionRate != e.selectionRate || g.TPR != e.tpr || g.FPR != e.fpr {
+			t.Errorf("Expected group %s: selection %v TPR %v FPR %v but received %+v", e.group, e.selectionRate, e.tpr, e.fpr, g)
+		}
+		if g.Matrix.Observations != 4 {
+			t.Errorf("Expected 4 observations for group %s but received %d", e.group, g.Matrix.Observations)
+		}
+	}
+	if report.DemographicParityDifference != 0 || report.EqualisedOddsDifference != 0 || report.DisparateImpactRatio != 1 {
+		t.Errorf("Expected a perfectly fair report but received %+v", report)
+	}
+
+	// lowering the threshold selects 3 of group a (FPR 0.5) but all of group b (FPR 1)
+	report = datautils.NewFairnessReport(predictions, labels, groups, 0.25)
+	if v := report.DemographicParityDifference; v != 0.25 {
+		t.Errorf("Expected demographic parity difference: %v but received %v", 0.25, v)
+	}
+	if v := report.DisparateImpactRatio; v != 0.75 {
+		t.Errorf("Expected disparate impact ratio: %v but received %v", 0.75, v)
+	}
+	if v := report.EqualisedOddsDifference; v != 0.5 {
+		t.Errorf("Expected equalised odds difference: %v but received %v", 0.5, v)
+	}
+
+	if s := report.String(); !strings.Contains(s, "Disparate Impact Ratio = 0.750000") {
+		t.Errorf("Expected report to contain the disparate impact ratio but received:\n%s", s)
+	}
+}
+
+func TestFairnessReportUndefinedRates(t *testing.T) {
+	// group b contains no positive observations so its TPR is undefined
+	report := datautils.NewFairnessReport([]float64{0.9, 0.1, 0.9}, []float64{1, 0, 0}, []string{"a", "a", "b"}, 0.5)
+
+	if tpr := report.Groups[1].TPR; !math.IsNaN(tpr) {
+		t.Errorf("Expected undefined TPR for group b but received %v", tpr)
+	}
+	// FPR of a is 0 and b is 1
+	if v := report.EqualisedOddsDifference; v != 1 {
+		t.Errorf("Expected equalised odds difference: %v but received %v", 1, v)
+	}
+}
@@ -0,0 +1,138 @@
+package datautils
+
+import (
+	"fmt"
+	"math"
+)
+
+// CaptureRate calculates the proportion of all positive observations captured within the top fraction (0 <
+// fraction <= 1) of observations ranked by descending prediction e.g. a fraction of 0.1 gives the capture rate in
+// the top decile.  The number of observations considered is rounded to the nearest observation.  As with
+// NewPrecisionRecallCurve, any label value greater than 0 is considered positive.  If there are no positive
+// observations, 0 is returned.
+func CaptureRate(predictions, labels []float64, fraction float64) float64 {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+	if fraction <= 0 || fraction > 1 {
+		panic("datautils: fraction must be greater than 0 and at most 1")
+	}
+
+	ind := rankDescending(predictions)
+	n := int(math.Round(fraction * float64(len(ind))))
+
+	var captured, positives int
+	for i, v := range ind {
+		if labels[v] > 0 {
+			positives++
+			if i < n {
+				captured++
+			}
+		}
+	}
+	if positives == 0 {
+		return 0
+	}
+	return float64(captured) / float64(positives)
+}
+
+// rankDescending returns the indices of the supplied predictions ordered by descending prediction.
+func rankDescending(predictions []float64) []int {
+	sorted := make([]float64, len(predictions))
+	ind := make([]int, len(predictions))
+	copy(sorted, predictions)
+	argsort(sorted, ind)
+	reverse(ind)
+	return ind
+}
+
+// GainsRow contains the statistics for a single bucket (e.g. decile) of a GainsTable.
+type GainsRow struct {
+	// Bucket is the 1 based rank of the bucket with bucket 1 containing the highest predictions
+	Bucket int `json:"bucket"`
+
+	// MinPrediction and MaxPrediction are the lowest and highest predictions within the bucket
+	MinPrediction float64 `json:"min_prediction"`
+	MaxPrediction float64 `json:"max_prediction"`
+
+	// Observations and Positives are the number of observations and positive observations within the bucket
+	Observations int `json:"observations"`
+	Positives    int `json:"positives"`
+
+	// ResponseRate is the proportion of observations within the bucket that are positive
+	ResponseRate float64 `json:"response_rate"`
+
+	// CumulativeCapture is the proportion of all positive observations captured within this and all higher buckets
+	CumulativeCapture float64 `json:"cumulative_capture"`
+
+	// Lift is the ResponseRate of the bucket relative to the overall proportion of positive observations
+	Lift float64 `json:"lift"`
+
+	// CumulativeLift is the response rate of this and all higher buckets relative to the overall proportion of
+	// positive observations
+	CumulativeLift float64 `json:"cumulative_lift"`
+}
+
+// GainsTable is a gains (or decile analysis) table summarising how positive observations are concentrated among
+// the highest ranked predictions, as commonly reported for credit risk and marketing response models.  Each row
+// represents a bucket of (near) equal numbers of observations ranked by descending prediction.
+type GainsTable []GainsRow
+
+// NewGainsTable creates a new gains table by ranking the observations by descending prediction and dividing them
+// into the specified number of buckets e.g. 10 for a decile analysis.  Where the number of observations does not
+// divide equally, bucket sizes differ by at most 1.  As with NewPrecisionRecallCurve, any label value greater than
+// 0 is considered positive.
+func NewGainsTable(predictions, labels []float64, buckets int) GainsTable {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+	if buckets < 1 || buckets > len(predictions) {
+		panic(ErrOutOfBounds)
+	}
+
+	ind := rankDescending(predictions)
+	var positives int
+	for _, v := range labels {
+		if v > 0 {
+			positives++
+		}
+	}
+	baseRate := float64(positives) / float64(len(labels))
+
+	table := make(GainsTable, buckets)
+	var start, cumPositives int
+	for b := range table {
+		end := (b + 1) * len(ind) / buckets
+		row := GainsRow{
+			Bucket:        b + 1,
+			MinPrediction: predictions[ind[end-1]],
+			MaxPrediction: predictions[ind[start]],
+			Observations:  end - start,
+		}
+		for _, v := range ind[start:end] {
+			if labels[v] > 0 {
+				row.Positives++
+			}
+		}
+		cumPositives += row.Positives
+		row.ResponseRate = float64(row.Positives) / float64(row.Observations)
+		row.Lift = row.ResponseRate / baseRate
+		row.CumulativeCapture = float64(cumPositives) / float64(positives)
+		row.CumulativeLift = float64(cumPositives) / float64(end) / baseRate
+		table[b] = row
+		start = end
+	}
+
+	return table
+}
+
+// String formats the gains table for printing.
+func (t GainsTable) String() string {
+	s := "Bucket | Min Prediction | Max Prediction | Observations | Positives | Response Rate | Cum. Capture |   Lift   | Cum. Lift\n"
+	s = s + "-------------------------------------------------------------------------------------------------------------------------\n"
+	for _, r := range t {
+		s = fmt.Sprintf("%s%6d | %14.6g | %14.6g | %12d | %9d | %13.4f | %12.4f | %8.4f | %9.4f\n", s,
+			r.Bucket, r.MinPrediction, r.MaxPrediction, r.Observations, r.Positives, r.ResponseRate, r.CumulativeCapture, r.Lift, r.CumulativeLift)
+	}
+	return s
+}
@@ -0,0 +1,58 @@
+package datautils_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+var gainsPredictions = []float64{0.95, 0.9, 0.85, 0.8, 0.7, 0.6, 0.5, 0.4, 0.3, 0.2}
+var gainsLabels = []float64{1, 1, 0, 1, 0, 0, 1, 0, 0, 0}
+
+func TestCaptureRate(t *testing.T) {
+	tests := []struct {
+		fraction float64
+		capture  float64
+	}{
+		{fraction: 0.1, capture: 0.25},
+		{fraction: 0.2, capture: 0.5},
+		{fraction: 0.5, capture: 0.75},
+		{fraction: 1, capture: 1},
+	}
+
+	for i, test := range tests {
+		if capture := datautils.CaptureRate(gainsPredictions, gainsLabels, test.fraction); capture != test.capture {
+			t.Errorf("Test %d: Expected capture@%v: %v but received %v", i+1, test.fraction, test.capture, capture)
+		}
+	}
+}
+
+func TestGainsTable(t *testing.T) {
+	table := datautils.NewGainsTable(gainsPredictions, gainsLabels, 4)
+
+	expected := datautils.GainsTable{
+		{Bucket: 1, MinPrediction: 0.9, MaxPrediction: 0.95, Observations: 2, Positives: 2, ResponseRate: 1, CumulativeCapture: 0.5, Lift: 2.5, CumulativeLift: 2.5},
+		{Bucket: 2, MinPrediction: 0.7, MaxPrediction: 0.85, Observations: 3, Positives: 1, ResponseRate: 1.0 / 3, CumulativeCapture: 0.75, Lift: 2.5 / 3, CumulativeLift: 1.5},
+		{Bucket: 3, MinPrediction: 0.5, MaxPrediction: 0.6, Observations: 2, Positives: 1, ResponseRate: 0.5, CumulativeCapture: 1, Lift: 1.25, CumulativeLift: 4.0 / 7 / 0.4},
+		{Bucket: 4, MinPrediction: 0.2, MaxPrediction: 0.4, Observations: 3, Positives: 0, ResponseRate: 0, CumulativeCapture: 1, Lift: 0, CumulativeLift: 1},
+	}
+
+	if len(table) != len(expected) {
+		t.Fatalf("Expected %d rows but received %d", len(expected), len(table))
+	}
+	for i, row := range table {
+		e := expected[i]
+		if row.Bucket != e.Bucket || row.MinPrediction != e.MinPrediction || row.MaxPrediction != e.MaxPrediction ||
+			row.Observations != e.Observations || row.Positives != e.Positives ||
+			math.Abs(row.ResponseRate-e.ResponseRate) > 1e-12 || math.Abs(row.CumulativeCapture-e.CumulativeCapture) > 1e-12 ||
+			math.Abs(row.Lift-e.Lift) > 1e-12 || math.Abs(row.CumulativeLift-e.CumulativeLift) > 1e-12 {
+			t.Errorf("Row %d: Expected %+v but received %+v", i+1, e, row)
+		}
+	}
+
+	if lines := strings.Split(strings.TrimSpace(table.String()), "\n"); len(lines) != 6 {
+		t.Errorf("Expected header, separator and 4 rows but received:\n%s", table)
+	}
+}
@@ -0,0 +1,165 @@
+package datautils
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// groupIndices groups the indices of the supplied group identifiers by distinct group.  The groups are returned
+// ordered by group identifier so that iteration over them is deterministic.
+func groupIndices(groups []string) [][]int {
+	m := make(map[string][]int)
+	for i, g := range groups {
+		m[g] = append(m[g], i)
+	}
+	names := make([]string, 0, len(m))
+	for g := range m {
+		names = append(names, g)
+	}
+	sort.Strings(names)
+
+	indices := make([][]int, len(names))
+	for i, g := range names {
+		indices[i] = m[g]
+	}
+	return indices
+}
+
+// groupFold returns a Fold whose test set contains the observations of the groups for which test returns true and
+// whose training set contains the observations of the remaining groups.  The indices within each set are in
+// ascending order.
+func groupFold(groups [][]int, test func(g int) bool) Fold {
+	var fold Fold
+	for g, ind := range groups {
+		if test(g) {
+			fold.Test = append(fold.Test, ind...)
+		} else {
+			fold.Train = append(fold.Train, ind...)
+		}
+	}
+	sort.Ints(fold.Train)
+	sort.Ints(fold.Test)
+	return fold
+}
+
+// GroupKFold partitions a dataset into K folds for K-fold cross-validation such that all the observations of the
+// same group (e.g. the same user or query) are in the same fold.  This prevents information leaking between the
+// training and test sets through correlated observations of the same group which would otherwise lead to overly
+// optimistic evaluations.
+type GroupKFold struct {
+	// K is the number of folds
+	K int
+}
+
+// Split partitions the observations with the specified group identifiers into K folds.  Groups are assigned to
+// folds in descending order of size, each to the fold containing the fewest observations, so that the sizes of
+// the test sets are as near equal as possible.  The indices within the training and test sets of each fold are in
+// ascending order.
+func (k GroupKFold) Split(groups []string) []Fold {
+	indices := groupIndices(groups)
+	if k.K < 2 || k.K > len(indices) {
+		panic("datautils: number of folds must be between 2 and the number of groups")
+	}
+
+	order := allIndices(len(indices))
+	sort.SliceStable(order, func(i, j int) bool { return len(indices[order[i]]) > len(indices[order[j]]) })
+
+	assignment := make([]int, len(indices))
+	sizes := make([]int, k.K)
+	for _, g := range order {
+		var f int
+		for j := range sizes {
+			if sizes[j] < sizes[f] {
+				f = j
+			}
+		}
+		assignment[g] = f
+		sizes[f] += len(indices[g])
+	}
+
+	folds := make([]Fold, k.K)
+	for f := range folds {
+		folds[f] = groupFold(indices, func(g int) bool { return assignment[g] == f })
+	}
+	return folds
+}
+
+// GroupShuffleSplit randomly partitions a dataset into training and test sets, repeatedly, such that all the
+// observations of the same group (e.g. the same user or query) are on the same side of each split.  Unlike
+// GroupKFold, the test sets of the splits may overlap.
+type GroupShuffleSplit struct {
+	// Splits is the number of random splits
+	Splits int
+
+	// TestFraction is the proportion of groups (0 < TestFraction < 1) to be placed in the test set of each split,
+	// rounded to the nearest group.  As groups may differ in size, the proportion of observations in the test set
+	// may differ.
+	TestFraction float64
+
+	// Seed is used to initialise the random number generator so that splits are reproducible
+	Seed int64
+}
+
+// Split partitions the observations with the specified group identifiers into Splits random training and test
+// sets.  The indices within the training and test sets of each split are in ascending order.
+func (s GroupShuffleSplit) Split(groups []string) []Fold {
+	if s.Splits < 1 {
+		panic("datautils: number of splits must be at least 1")
+	}
+	if s.TestFraction <= 0 || s.TestFraction >= 1 {
+		panic("datautils: test fraction must be between 0 and 1")
+	}
+
+	indices := groupIndices(groups)
+	rnd := rand.New(rand.NewSource(s.Seed))
+	folds := make([]Fold, s.Splits)
+	for i := range folds {
+		_, test := splitIndices(allIndices(len(indices)), s.TestFraction, rnd)
+		inTest := make([]bool, len(indices))
+		for _, g := range test {
+			inTest[g] = true
+		}
+		folds[i] = groupFold(indices, func(g int) bool { return inTest[g] })
+	}
+	return folds
+}
+
+// GroupTrainValidationTestSplit randomly partitions the observations with the specified group identifiers into
+// training, validation and test sets such that all the observations of the same group (e.g. the same user or
+// query) are in the same set.  validationFraction and testFraction are the proportions of groups to be placed in
+// the validation and test sets respectively (each rounded to the nearest group) and must sum to less than 1.  A
+// validationFraction of 0 produces a simple group-aware train/test split.  The seed is used to initialise the
+// random number generator so that splits are reproducible.  The returned indices within each set are in
+// ascending order and may be used to select the corresponding rows and labels of a dataset.
+func GroupTrainValidationTestSplit(groups []string, validationFraction, testFraction float64, seed int64) (train, validation, test []int) {
+	if validationFraction < 0 || testFraction <= 0 || validationFraction+testFraction >= 1 {
+		panic("datautils: validation and test fractions must be between 0 and 1 and sum to less than 1")
+	}
+
+	indices := groupIndices(groups)
+	order := allIndices(len(indices))
+	rnd := rand.New(rand.NewSource(seed))
+	rnd.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+	nTest := int(math.Round(testFraction * float64(len(order))))
+	nValidation := int(math.Round(validationFraction * float64(len(order))))
+	if nTest+nValidation > len(order) {
+		nValidation = len(order) - nTest
+	}
+
+	for i, g := range order {
+		switch {
+		case i < nTest:
+			test = append(test, indices[g]...)
+		case i < nTest+nValidation:
+			validation = append(validation, indices[g]...)
+		default:
+			train = append(train, indices[g]...)
+		}
+	}
+	sort.Ints(train)
+	sort.Ints(validation)
+	sort.Ints(test)
+	return train, validation, test
+}
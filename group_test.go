@@ -0,0 +1,91 @@
+package datautils_test
+
+import (
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+var userGroups = []string{"u1", "u2", "u1", "u3", "u4", "u2", "u1", "u5", "u3", "u4"}
+
+// checkGroupsSeparate reports an error if any group has observations in more than one of the sets.
+func checkGroupsSeparate(t *testing.T, name string, sets ...[]int) {
+	t.Helper()
+	side := make(map[string]int)
+	var n int
+	for s, set := range sets {
+		for _, i := range set {
+			if prev, ok := side[userGroups[i]]; ok && prev != s {
+				t.Errorf("%s: Expected group %s in a single set but found in sets %d and %d", name, userGroups[i], prev, s)
+			}
+			side[userGroups[i]] = s
+			n++
+		}
+	}
+	if n != len(userGroups) {
+		t.Errorf("%s: Expected sets to cover all %d observations but covered %d", name, len(userGroups), n)
+	}
+}
+
+func TestGroupKFold(t *testing.T) {
+	folds := datautils.GroupKFold{K: 3}.Split(userGroups)
+	if len(folds) != 3 {
+		t.Fatalf("Expected 3 folds but received %d", len(folds))
+	}
+
+	seen := make(map[int]int)
+	for f, fold := range folds {
+		checkGroupsSeparate(t, "GroupKFold", fold.Train, fold.Test)
+		for _, v := range fold.Test {
+			seen[v]++
+		}
+		// u1 (3 observations) fills the first fold, u2 & u3 (2 each) the second and third then u4 & u5 balance
+		if len(fold.Test) < 3 || len(fold.Test) > 4 {
+			t.Errorf("Expected fold %d test size between 3 and 4 but received %d", f, len(fold.Test))
+		}
+	}
+	for v := range userGroups {
+		if seen[v] != 1 {
+			t.Errorf("Expected observation %d in exactly one test set but found in %d", v, seen[v])
+		}
+	}
+}
+
+func TestGroupShuffleSplit(t *testing.T) {
+	s := datautils.GroupShuffleSplit{Splits: 4, TestFraction: 0.4, Seed: 3}
+	folds := s.Split(userGroups)
+	if len(folds) != 4 {
+		t.Fatalf("Expected 4 splits but received %d", len(folds))
+	}
+	for _, fold := range folds {
+		checkGroupsSeparate(t, "GroupShuffleSplit", fold.Train, fold.Test)
+		distinct := make(map[string]bool)
+		for _, v := range fold.Test {
+			distinct[userGroups[v]] = true
+		}
+		if len(distinct) != 2 {
+			t.Errorf("Expected 2 of 5 userGroups in the test set but received %d", len(distinct))
+		}
+	}
+
+	again := s.Split(userGroups)
+	for i := range folds {
+		if len(folds[i].Test) != len(again[i].Test) {
+			t.Errorf("Expected splits with the same seed to be identical")
+		}
+	}
+}
+
+func TestGroupTrainValidationTestSplit(t *testing.T) {
+	train, validation, test := datautils.GroupTrainValidationTestSplit(userGroups, 0.2, 0.2, 7)
+	checkGroupsSeparate(t, "GroupTrainValidationTestSplit", train, validation, test)
+	if len(validation) == 0 || len(test) == 0 || len(train) == 0 {
+		t.Errorf("Expected non-empty sets but received train %v, validation %v and test %v", train, validation, test)
+	}
+
+	train, validation, test = datautils.GroupTrainValidationTestSplit(userGroups, 0, 0.4, 7)
+	checkGroupsSeparate(t, "GroupTrainValidationTestSplit", train, validation, test)
+	if len(validation) != 0 {
+		t.Errorf("Expected empty validation set but received %v", validation)
+	}
+}
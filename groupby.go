@@ -0,0 +1,152 @@
+package datautils
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// GroupEvaluation contains the values of a set of metrics computed separately for each segment (group) of
+// observations sharing the same value of a categorical attribute (e.g. country, device or query length bucket).
+type GroupEvaluation struct {
+	// Metrics are the names of the metrics in the order of the values of each group
+	Metrics []string
+
+	// Groups are the distinct values of the attribute in sorted order
+	Groups []string
+
+	// Observations contains the number of observations in each group
+	Observations []int
+
+	// Values contains the value of each metric for each group such that Values[i][j] is the value of Metrics[j]
+	// for Groups[i]
+	Values [][]float64
+}
+
+// GroupBy computes each of the metrics separately for the predictions and labels of each group of observations
+// sharing the same value in groups.  Where opts.Queries is specified, the query IDs of each group's observations are
+// passed to the metrics so that ranking metrics are computed over the queries within each group.
+func GroupBy(predictions, labels []float64, groups []string, metrics []Metric, opts MetricOptions) GroupEvaluation {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+	if len(groups) != len(labels) || (opts.Queries != nil && len(opts.Queries) != len(labels)) {
+		panic(ErrLengthMismatch)
+	}
+
+	indices := make(map[string][]int)
+	for i, g := range groups {
+		indices[g] = append(indices[g], i)
+	}
+	e := GroupEvaluation{
+		Metrics:      make([]string, len(metrics)),
+		Groups:       make([]string, 0, len(indices)),
+		Observations: make([]int, len(indices)),
+		Values:       make([][]float64, len(indices)),
+	}
+	for i, m := range metrics {
+		e.Metrics[i] = m.Name()
+	}
+	for g := range indices {
+		e.Groups = append(e.Groups, g)
+	}
+	sort.Strings(e.Groups)
+
+	for i, g := range e.Groups {
+		ind := indices[g]
+		p, l := selectValues(predictions, ind), selectValues(labels, ind)
+		o := opts
+		if opts.Queries != nil {
+			o.Queries = make([]string, len(ind))
+			for j, k := range ind {
+				o.Queries[j] = opts.Queries[k]
+			}
+		}
+
+		e.Observations[i] = len(ind)
+		e.Values[i] = make([]float64, len(metrics))
+		for j, m := range metrics {
+			e.Values[i][j] = m.Compute(p, l, o)
+		}
+	}
+	return e
+}
+
+// String formats the evaluation for printing as a table with a row per group and a column per metric.
+func (e GroupEvaluation) String() string {
+	s := fmt.Sprintf("%-20s | %12s", "Group", "Observations")
+	for _, m := range e.Metrics {
+		s = fmt.Sprintf("%s | %10s", s, m)
+	}
+	s = s + "\n"
+	for i, g := range e.Groups {
+		s = fmt.Sprintf("%s%-20s | %12d", s, g, e.Observations[i])
+		for _, v := range e.Values[i] {
+			s = fmt.Sprintf("%s | %10.4f", s, v)
+		}
+		s = s + "\n"
+	}
+	return s
+}
+
+// WriteCSV writes the evaluation to w as CSV with a header followed by a record per group.  The columns are group
+// and observations followed by a column per metric.  comma is the field delimiter e.g. '\t' for TSV (or ',' if
+// zero).
+func (e GroupEvaluation) WriteCSV(w io.Writer, comma rune) error {
+	records := [][]string{append([]string{"group", "observations"}, e.Metrics...)}
+	for i, g := range e.Groups {
+		record := []string{g, strconv.Itoa(e.Observations[i])}
+		for _, v := range e.Values[i] {
+			record = append(record, formatFloat(v))
+		}
+		records = append(records, record)
+	}
+	return writeCSV(w, comma, records)
+}
+
+// Plot renders the evaluation as a grouped bar chart with a cluster of bars for each group containing a bar, in a
+// distinct colour, for each metric.  Undefined (NaN) metric values are drawn as empty bars.
+func (e GroupEvaluation) Plot() (*plot.Plot, error) {
+	p, err := newPlot()
+	if err != nil {
+		return nil, err
+	}
+	p.Title.Text = PlotTheme.title("Metrics by Group")
+	p.Y.Label.Text = "Value"
+
+	width := vg.Points(10)
+	for j, name := range e.Metrics {
+		values := make(plotter.Values, len(e.Groups))
+		for i := range e.Groups {
+			if v := e.Values[i][j]; !math.IsNaN(v) {
+				values[i] = v
+			}
+		}
+		if len(values) == 0 {
+			break
+		}
+		bars, err := plotter.NewBarChart(values, width)
+		if err != nil {
+			return nil, err
+		}
+		bars.Color = PlotTheme.color(j)
+		bars.LineStyle.Width = 0
+		// centre the cluster of bars on each group's tick
+		bars.Offset = width * vg.Length(2*j+1-len(e.Metrics)) / 2
+		p.Add(bars)
+		p.Legend.Add(name, bars)
+	}
+	p.Legend.Top = true
+
+	p.X.Tick.Label.Rotation = 1.5
+	p.X.Tick.Label.XAlign = draw.XRight
+	p.X.Tick.Marker = ticks{labels: e.Groups, n: len(e.Groups)}
+	return p, nil
+}
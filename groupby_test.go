@@ -0,0 +1,72 @@
+package datautils_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func lookupMetrics(t *testing.T, names ...string) []datautils.Metric {
+	metrics := make([]datautils.Metric, len(names))
+	for i, name := range names {
+		m, err := datautils.LookupMetric(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		metrics[i] = m
+	}
+	return metrics
+}
+
+func TestGroupBy(t *testing.T) {
+	predictions := []float64{0.9, 0.4, 0.7, 0.2, 0.6, 0.8}
+	labels := []float64{1, 0, 0, 1, 1, 1}
+	groups := []string{"uk", "uk", "fr", "uk", "fr", "de"}
+	queries := []string{"a", "a", "b", "b", "b", "c"}
+
+	e := datautils.GroupBy(predictions, labels, groups, lookupMetrics(t, "auc", "recall", "mrr"), datautils.MetricOptions{Threshold: 0.5, Queries: queries})
+
+	if expected := []string{"de", "fr", "uk"}; !reflect.DeepEqual(e.Groups, expected) {
+		t.Errorf("Expected groups: %v but received %v", expected, e.Groups)
+	}
+	if expected := []int{1, 2, 3}; !reflect.DeepEqual(e.Observations, expected) {
+		t.Errorf("Expected observations: %v but received %v", expected, e.Observations)
+	}
+	expected := [][]float64{
+		{math.NaN(), 1, 1},
+		{0, 1, 0.5},
+		{0.5, 0.5, 1},
+	}
+	for i := range expected {
+		if !equalWithNaN(e.Values[i], expected[i]) {
+			t.Errorf("Group %s: Expected values: %v but received %v", e.Groups[i], expected[i], e.Values[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := e.WriteCSV(&buf, ','); err != nil {
+		t.Fatal(err)
+	}
+	if csv := "group,observations,auc,recall,mrr\nde,1,NaN,1,1\nfr,2,0,1,0.5\nuk,3,0.5,0.5,1\n"; buf.String() != csv {
+		t.Errorf("Expected CSV:\n%s\nbut received:\n%s", csv, buf.String())
+	}
+	if s := e.String(); !strings.Contains(s, "uk") || strings.Count(s, "\n") != 4 {
+		t.Errorf("Unexpected table:\n%s", s)
+	}
+	if _, err := e.Plot(); err != nil {
+		t.Errorf("Unexpected error plotting: %v", err)
+	}
+}
+
+func TestGroupByLengthMismatch(t *testing.T) {
+	defer func() {
+		if r := recover(); r != datautils.ErrLengthMismatch {
+			t.Errorf("Expected panic with ErrLengthMismatch but received %v", r)
+		}
+	}()
+	datautils.GroupBy([]float64{0.5, 0.2}, []float64{1, 0}, []string{"a"}, lookupMetrics(t, "auc"), datautils.MetricOptions{})
+}
@@ -0,0 +1,164 @@
+package datautils
+
+import (
+	"hash/fnv"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SparseVector is a vector of which only the non-zero elements are stored.  SparseVector implements mat.Vector
+// so may be used with gonum wherever a (read only) vector is accepted.
+type SparseVector struct {
+	// N is the length of the vector
+	N int
+
+	// Indices contains the indices of the non-zero elements in ascending order
+	Indices []int
+
+	// Data contains the value of each of the non-zero elements corresponding to Indices
+	Data []float64
+}
+
+// Dims returns the dimensions of the vector as a column vector.
+func (v *SparseVector) Dims() (r, c int) { return v.N, 1 }
+
+// At returns the element at row i.  At will panic if j is not 0.
+func (v *SparseVector) At(i, j int) float64 {
+	if j != 0 {
+		panic(mat.ErrColAccess)
+	}
+	return v.AtVec(i)
+}
+
+// AtVec returns the element at index i.
+func (v *SparseVector) AtVec(i int) float64 {
+	if i < 0 || i >= v.N {
+		panic(mat.ErrRowAccess)
+	}
+	if k := sort.SearchInts(v.Indices, i); k < len(v.Indices) && v.Indices[k] == i {
+		return v.Data[k]
+	}
+	return 0
+}
+
+// Len returns the length of the vector.
+func (v *SparseVector) Len() int { return v.N }
+
+// T returns the transpose of the vector (a row vector).
+func (v *SparseVector) T() mat.Matrix { return mat.Transpose{Matrix: v} }
+
+// FeatureHasher vectorises features identified by name (e.g. words or categorical values) into vectors of a fixed
+// length using the hashing trick: the index of each feature is the hash of its name modulo the length of the
+// vector.  This avoids building and storing a vocabulary of all the feature names, so suits features of very high
+// cardinality or whose values are not known in advance, at the cost of occasional collisions where several
+// features share an index.
+type FeatureHasher struct {
+	// Features is the length of the vectors (the number of hashed features)
+	Features int
+
+	// Signed indicates whether the sign of the value of each feature should also be determined by its hash.
+	// Colliding features then tend to cancel out rather than accumulate so that the expected value of each
+	// element is unbiased.
+	Signed bool
+}
+
+// index returns the index and sign of the feature with the specified name.
+func (h FeatureHasher) index(name string) (int, float64) {
+	if h.Features < 1 {
+		panic("datautils: number of hashed features must be at least 1")
+	}
+	f := fnv.New64a()
+	f.Write([]byte(name))
+	// FNV mixes the high bits of short names poorly so finalise as for MurmurHash3 so that every bit of the hash
+	// depends on every bit of the name
+	sum := f.Sum64()
+	sum ^= sum >> 33
+	sum *= 0xff51afd7ed558ccd
+	sum ^= sum >> 33
+	sum *= 0xc4ceb9fe1a85ec53
+	sum ^= sum >> 33
+
+	sign := 1.0
+	if h.Signed && sum>>63 == 1 {
+		sign = -1
+	}
+	return int(sum % uint64(h.Features)), sign
+}
+
+// Hash returns the hashed vector of the supplied features, keyed by name.  The values of features hashed to the
+// same index are summed.
+func (h FeatureHasher) Hash(features map[string]float64) *SparseVector {
+	names := make([]string, 0, len(features))
+	for name := range features {
+		names = append(names, name)
+	}
+	// accumulate in a consistent order so that the floating point sums are deterministic
+	sort.Strings(names)
+
+	values := make(map[int]float64, len(names))
+	for _, name := range names {
+		i, sign := h.index(name)
+		values[i] += sign * features[name]
+	}
+
+	v := &SparseVector{N: h.Features}
+	for i, value := range values {
+		if value != 0 {
+			v.Indices = append(v.Indices, i)
+		}
+	}
+	sort.Ints(v.Indices)
+	v.Data = make([]float64, len(v.Indices))
+	for k, i := range v.Indices {
+		v.Data[k] = values[i]
+	}
+	return v
+}
+
+// HashStrings returns the hashed vector of the supplied tokens (e.g. the words of a document) where each
+// occurrence of a token counts as a feature of value 1.
+func (h FeatureHasher) HashStrings(tokens []string) *SparseVector {
+	counts := make(map[string]float64, len(tokens))
+	for _, t := range tokens {
+		counts[t]++
+	}
+	return h.Hash(counts)
+}
+
+// Transform returns a matrix with one row per element of rows containing its hashed vector (see Hash).
+func (h FeatureHasher) Transform(rows []map[string]float64) *mat.Dense {
+	m := mat.NewDense(len(rows), h.Features, nil)
+	for i, row := range rows {
+		v := h.Hash(row)
+		for k, j := range v.Indices {
+			m.Set(i, j, v.Data[k])
+		}
+	}
+	return m
+}
+
+// TransformCategorical returns a matrix of the hashed categorical columns (e.g. Dataset.Categorical) with one row
+// per observation.  Each column, value pair is hashed as a feature named "column=value" with a value of 1 so that
+// the same value within different columns is hashed as different features.  All the columns must contain the same
+// number of observations.
+func (h FeatureHasher) TransformCategorical(columns map[string][]string) *mat.Dense {
+	var n int
+	for _, values := range columns {
+		n = len(values)
+		break
+	}
+	rows := make([]map[string]float64, n)
+	for i := range rows {
+		rows[i] = make(map[string]float64, len(columns))
+	}
+	for name, values := range columns {
+		if len(values) != n {
+			panic(ErrLengthMismatch)
+		}
+		for i, v := range values {
+			rows[i][name+"="+v] = 1
+		}
+	}
+	return h.Transform(rows)
+}
@@ -0,0 +1,89 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestFeatureHasher(t *testing.T) {
+	features := map[string]float64{"a": 1, "b": 2, "c": 0.5, "d": 3}
+
+	for _, signed := range []bool{false, true} {
+		h := datautils.FeatureHasher{Features: 1 << 10, Signed: signed}
+		v := h.Hash(features)
+
+		if v.Len() != 1<<10 {
+			t.Errorf("Signed %t: Expected vector length %d but received %d", signed, 1<<10, v.Len())
+		}
+		if len(v.Indices) != len(v.Data) {
+			t.Errorf("Signed %t: Expected equal numbers of indices and values but received %d and %d", signed, len(v.Indices), len(v.Data))
+		}
+
+		// sum of absolute values is preserved in the absence of collisions
+		var sum float64
+		for i := 0; i < v.Len(); i++ {
+			sum += math.Abs(v.AtVec(i))
+		}
+		if len(v.Indices) == len(features) && sum != 6.5 {
+			t.Errorf("Signed %t: Expected sum of absolute values 6.5 but received %v", signed, sum)
+		}
+		for k := 1; k < len(v.Indices); k++ {
+			if v.Indices[k] <= v.Indices[k-1] {
+				t.Errorf("Signed %t: Expected indices in ascending order but received %v", signed, v.Indices)
+			}
+		}
+
+		// hashing is deterministic
+		again := h.Hash(features)
+		if !mat.Equal(v, again) {
+			t.Errorf("Signed %t: Expected identical vectors hashing the same features", signed)
+		}
+	}
+}
+
+func TestFeatureHasherCollisions(t *testing.T) {
+	// with a single feature, every feature collides so values are summed
+	h := datautils.FeatureHasher{Features: 1}
+	if v := h.HashStrings([]string{"x", "y", "x"}); v.AtVec(0) != 3 {
+		t.Errorf("Expected colliding token counts to sum to 3 but received %v", v.AtVec(0))
+	}
+
+	signed := datautils.FeatureHasher{Features: 1, Signed: true}
+	var positive, negative int
+	for i := 0; i < 100; i++ {
+		v := signed.Hash(map[string]float64{string(rune('a'+i%26)) + string(rune('a'+i/26)): 1})
+		switch v.AtVec(0) {
+		case 1:
+			positive++
+		case -1:
+			negative++
+		}
+	}
+	if positive == 0 || negative == 0 {
+		t.Errorf("Expected signed hashing to produce both signs but received %d positive and %d negative", positive, negative)
+	}
+}
+
+func TestFeatureHasherTransformCategorical(t *testing.T) {
+	h := datautils.FeatureHasher{Features: 64}
+	m := h.TransformCategorical(map[string][]string{
+		"colour": {"red", "blue", "red"},
+		"shape":  {"square", "square", "circle"},
+	})
+
+	r, c := m.Dims()
+	if r != 3 || c != 64 {
+		t.Fatalf("Expected 3x64 matrix but received %dx%d", r, c)
+	}
+	if sum := floats.Sum(flatten(m)); sum != 6 {
+		t.Errorf("Expected 2 features per row (6 in total) but received %v", sum)
+	}
+	expected := h.Transform([]map[string]float64{{"colour=red": 1, "shape=square": 1}})
+	if !mat.Equal(m.RowView(0).T(), expected) {
+		t.Errorf("Expected first row to match hashing of colour=red and shape=square")
+	}
+}
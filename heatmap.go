@@ -4,49 +4,332 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"strconv"
 
 	"gonum.org/v1/gonum/mat"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/palette"
 	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
 	"gonum.org/v1/plot/vg/draw"
 )
 
 type heatmap struct {
-	x mat.Matrix
+	x    mat.Matrix
+	mask HeatmapMask
 }
 
-func (h heatmap) Dims() (c, r int)   { r, c = h.x.Dims(); return c, r }
-func (h heatmap) Z(c, r int) float64 { return h.x.At(r, c) }
-func (h heatmap) X(c int) float64    { return float64(c) }
-func (h heatmap) Y(r int) float64    { return float64(r) }
+func (h heatmap) Dims() (c, r int) { r, c = h.x.Dims(); return c, r }
+func (h heatmap) Z(c, r int) float64 {
+	if h.mask.masks(r, c) {
+		return math.NaN()
+	}
+	return h.x.At(r, c)
+}
+func (h heatmap) X(c int) float64 { return float64(c) }
+func (h heatmap) Y(r int) float64 { return float64(r) }
 
-type ticks []string
+// ticks labels the ticks for each row/column of a heatmap.  Only every step'th tick is labelled, the remainder
+// being rendered as minor (unlabelled) ticks.  If no labels are specified, ticks are labelled by index.
+type ticks struct {
+	labels []string
+	n      int
+	step   int
+}
 
 func (t ticks) Ticks(min, max float64) []plot.Tick {
+	step := t.step
+	if step < 1 {
+		step = 1
+	}
 	var retval []plot.Tick
-	for i := math.Trunc(min); i <= max; i++ {
-		retval = append(retval, plot.Tick{Value: i, Label: t[int(i)]})
+	for i := math.Max(math.Ceil(min), 0); i <= max && int(i) < t.n; i++ {
+		var label string
+		if int(i)%step == 0 {
+			if t.labels == nil {
+				label = strconv.Itoa(int(i))
+			} else {
+				label = t.labels[int(i)]
+			}
+		}
+		retval = append(retval, plot.Tick{Value: i, Label: label})
 	}
 	return retval
 }
 
-func PlotHeatmap(corr mat.Matrix, xlabels []string, ylabels []string) (p *plot.Plot, err error) {
-	pal := palette.Heat(48, 1)
-	m := heatmap{corr}
-	hm := plotter.NewHeatMap((plotter.GridXYZ)(m), pal)
-	if p, err = plot.New(); err != nil {
-		return
+// HeatmapMask specifies regions of a (typically symmetric) matrix to omit when rendering a heatmap.  Masks may be
+// combined e.g. MaskUpper|MaskDiagonal.
+type HeatmapMask int
+
+const (
+	// MaskUpper omits the upper triangle of the matrix (elements where the column index is greater than the
+	// row index) excluding the diagonal
+	MaskUpper HeatmapMask = 1 << iota
+
+	// MaskLower omits the lower triangle of the matrix (elements where the row index is greater than the
+	// column index) excluding the diagonal
+	MaskLower
+
+	// MaskDiagonal omits the diagonal of the matrix
+	MaskDiagonal
+)
+
+// masks returns true if the element of the matrix at row i, column j is masked.
+func (m HeatmapMask) masks(i, j int) bool {
+	switch {
+	case j > i:
+		return m&MaskUpper != 0
+	case i > j:
+		return m&MaskLower != 0
+	default:
+		return m&MaskDiagonal != 0
+	}
+}
+
+// HeatmapOption configures optional behaviour of PlotHeatmap.
+type HeatmapOption func(*heatmapConfig)
+
+type heatmapConfig struct {
+	annotate     bool
+	annotateFmt  string
+	annotateSize vg.Length
+
+	pal       palette.Palette
+	diverging palette.DivergingColorMap
+	midpoint  float64
+	colors    int
+
+	fixedRange bool
+	min, max   float64
+	symmetric  bool
+
+	mask HeatmapMask
+
+	labelStep     int
+	xRotation     float64
+	xAlign        draw.XAlignment
+	labelFontSize vg.Length
+}
+
+// WithLabelStep labels only every step'th row and column of the heatmap which is useful to prevent labels
+// overlapping for large matrices.
+func WithLabelStep(step int) HeatmapOption {
+	return func(c *heatmapConfig) {
+		c.labelStep = step
+	}
+}
+
+// WithXLabelRotation rotates the column (x axis) labels by the specified angle in radians using the specified
+// horizontal alignment of the labels relative to their ticks.  By default labels are rotated by 1.5 radians
+// and right aligned.
+func WithXLabelRotation(rotation float64, align draw.XAlignment) HeatmapOption {
+	return func(c *heatmapConfig) {
+		c.xRotation = rotation
+		c.xAlign = align
+	}
+}
+
+// WithLabelFontSize sets the font size of the row and column labels (by default 6 points).
+func WithLabelFontSize(size vg.Length) HeatmapOption {
+	return func(c *heatmapConfig) {
+		c.labelFontSize = size
+	}
+}
+
+// WithMask omits the regions of the matrix specified by mask from the heatmap, rendering them as transparent.
+// This is typically used to show only one triangle of a symmetric matrix, such as a correlation matrix, e.g.
+// WithMask(MaskUpper|MaskDiagonal).  Masked elements are excluded when deriving the range of the colour scale.
+func WithMask(mask HeatmapMask) HeatmapOption {
+	return func(c *heatmapConfig) {
+		c.mask = mask
+	}
+}
+
+// WithRange fixes the range of the heatmap's colour scale to [min, max] (e.g. [-1, 1] for correlation matrices)
+// rather than deriving it from the range of values in the matrix so that heatmaps may be compared consistently.
+// Values outside the range are clamped to the colour at the nearest end of the scale.
+func WithRange(min, max float64) HeatmapOption {
+	return func(c *heatmapConfig) {
+		c.fixedRange = true
+		c.min = min
+		c.max = max
+	}
+}
+
+// WithSymmetricRange centres the range of the heatmap's colour scale on zero by extending it to [-a, a] where
+// a is the largest absolute value of the range (either derived from the matrix values or specified using
+// WithRange).
+func WithSymmetricRange() HeatmapOption {
+	return func(c *heatmapConfig) {
+		c.symmetric = true
+	}
+}
+
+// applyRange sets the range of the heatmap's colour scale according to the configuration.
+func (c *heatmapConfig) applyRange(hm *plotter.HeatMap) {
+	if c.fixedRange {
+		hm.Min, hm.Max = c.min, c.max
+	}
+	if c.symmetric {
+		a := math.Max(math.Abs(hm.Min), math.Abs(hm.Max))
+		hm.Min, hm.Max = -a, a
+	}
+}
+
+// WithPalette renders the heatmap using the specified palette rather than the default palette.Heat.  The
+// colours of the palette are mapped linearly across the range of the values in the matrix.
+func WithPalette(p palette.Palette) HeatmapOption {
+	return func(c *heatmapConfig) {
+		c.pal = p
+		c.diverging = nil
+	}
+}
+
+// WithDivergingColorMap renders the heatmap using a palette of the specified number of colours taken from the
+// diverging colour map cm (e.g. moreland.SmoothBlueRed()) with the colour map's convergence point (its neutral
+// colour) positioned at the midpoint value.  This is useful for matrices containing both negative and positive
+// values, e.g. correlation matrices, which should typically use a midpoint of 0.  The range of the colour scale
+// is extended as necessary to include the midpoint.
+func WithDivergingColorMap(cm palette.DivergingColorMap, midpoint float64, colors int) HeatmapOption {
+	return func(c *heatmapConfig) {
+		c.diverging = cm
+		c.midpoint = midpoint
+		c.colors = colors
+		c.pal = nil
+	}
+}
+
+// applyPalette sets the palette of the heatmap according to the configuration, adjusting the range of the
+// heatmap's colour scale as required.
+func (c *heatmapConfig) applyPalette(hm *plotter.HeatMap) {
+	switch {
+	case c.diverging != nil:
+		hm.Min = math.Min(hm.Min, c.midpoint)
+		hm.Max = math.Max(hm.Max, c.midpoint)
+		c.diverging.SetMin(hm.Min)
+		c.diverging.SetMax(hm.Max)
+		c.diverging.SetConvergePoint(c.midpoint)
+		hm.Palette = c.diverging.Palette(c.colors)
+	case c.pal != nil:
+		hm.Palette = c.pal
+	default:
+		hm.Palette = palette.Heat(48, 1)
+	}
+}
+
+// newHeatMap creates a HeatMap of m with its colour scale configured according to the configuration.  Values
+// outside of the range of the colour scale are clamped to the colours at either end of the palette and NaN (or
+// masked) values are transparent.
+func (c *heatmapConfig) newHeatMap(m heatmap) *plotter.HeatMap {
+	hm := plotter.NewHeatMap((plotter.GridXYZ)(m), nil)
+	c.applyRange(hm)
+	c.applyPalette(hm)
+	if colors := hm.Palette.Colors(); len(colors) > 0 {
+		hm.Underflow = colors[0]
+		hm.Overflow = colors[len(colors)-1]
 	}
 	hm.NaN = color.RGBA{0, 0, 0, 0}
+	return hm
+}
+
+// heatMapColor returns the colour of the value v in the heat map hm, selected from its palette in the same way as
+// the plotter.HeatMap draws its cells.
+func heatMapColor(hm *plotter.HeatMap, v float64, colors []color.Color) color.Color {
+	ps := float64(len(colors)-1) / (hm.Max - hm.Min)
+	switch {
+	case v < hm.Min:
+		return hm.Underflow
+	case v > hm.Max:
+		return hm.Overflow
+	case math.IsNaN(v), math.IsInf(ps, 0):
+		return hm.NaN
+	}
+	return colors[int((v-hm.Min)*ps+0.5)]
+}
+
+// WithCellValues annotates each cell of the heatmap with its numeric value formatted according to the
+// specified fmt format string (e.g. "%.2f") and rendered at the specified font size.
+func WithCellValues(format string, fontSize vg.Length) HeatmapOption {
+	return func(c *heatmapConfig) {
+		c.annotate = true
+		c.annotateFmt = format
+		c.annotateSize = fontSize
+	}
+}
+
+// cellLabels creates a plotter to annotate each (non NaN) cell of the heatmap with its value.
+func cellLabels(m heatmap, format string, fontSize vg.Length) (*plotter.Labels, error) {
+	c, r := m.Dims()
+	labels := plotter.XYLabels{
+		XYs:    make(plotter.XYs, 0, c*r),
+		Labels: make([]string, 0, c*r),
+	}
+	for i := 0; i < c; i++ {
+		for j := 0; j < r; j++ {
+			var label string
+			if z := m.Z(i, j); !math.IsNaN(z) {
+				label = fmt.Sprintf(format, z)
+			}
+			labels.XYs = append(labels.XYs, plotter.XY{X: m.X(i), Y: m.Y(j)})
+			labels.Labels = append(labels.Labels, label)
+		}
+	}
+
+	l, err := plotter.NewLabels(labels)
+	if err != nil {
+		return nil, err
+	}
+	for i := range l.TextStyle {
+		l.TextStyle[i].Font.Size = fontSize
+		l.TextStyle[i].XAlign = draw.XCenter
+		l.TextStyle[i].YAlign = draw.YCenter
+	}
+	return l, nil
+}
+
+// PlotHeatmap renders the matrix corr (e.g. a correlation matrix) as a heatmap using the specified labels for the
+// columns (xlabels) and rows (ylabels).  If specified, the number of labels must match the corresponding
+// dimension of the matrix.  If nil, rows and columns are labelled by index.  Optional behaviour may be
+// configured by specifying HeatmapOptions.
+func PlotHeatmap(corr mat.Matrix, xlabels []string, ylabels []string, opts ...HeatmapOption) (p *plot.Plot, err error) {
+	cfg := heatmapConfig{
+		xRotation:     1.5,
+		xAlign:        draw.XRight,
+		labelFontSize: 6,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	r, c := corr.Dims()
+	if xlabels != nil && len(xlabels) != c {
+		return nil, fmt.Errorf("datautils: %d x labels specified for matrix with %d columns", len(xlabels), c)
+	}
+	if ylabels != nil && len(ylabels) != r {
+		return nil, fmt.Errorf("datautils: %d y labels specified for matrix with %d rows", len(ylabels), r)
+	}
+
+	m := heatmap{x: corr, mask: cfg.mask}
+	hm := cfg.newHeatMap(m)
+	pal := hm.Palette
+	if p, err = newPlot(); err != nil {
+		return
+	}
 
 	p.Add(hm)
-	p.X.Tick.Label.Rotation = 1.5
-	p.Y.Tick.Label.Font.Size = 6
-	p.X.Tick.Label.Font.Size = 6
-	p.X.Tick.Label.XAlign = draw.XRight
-	p.X.Tick.Marker = ticks(xlabels)
-	p.Y.Tick.Marker = ticks(ylabels)
+	if cfg.annotate {
+		labels, err := cellLabels(m, cfg.annotateFmt, cfg.annotateSize)
+		if err != nil {
+			return p, err
+		}
+		p.Add(labels)
+	}
+	p.X.Tick.Label.Rotation = cfg.xRotation
+	p.Y.Tick.Label.Font.Size = cfg.labelFontSize
+	p.X.Tick.Label.Font.Size = cfg.labelFontSize
+	p.X.Tick.Label.XAlign = cfg.xAlign
+	p.X.Tick.Marker = ticks{labels: xlabels, n: c, step: cfg.labelStep}
+	p.Y.Tick.Marker = ticks{labels: ylabels, n: r, step: cfg.labelStep}
 
 	l, err := plot.NewLegend()
 	if err != nil {
@@ -0,0 +1,29 @@
+package datautils_test
+
+import (
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPlotHeatmapLabelValidation(t *testing.T) {
+	m := mat.NewDense(2, 3, []float64{1, 0.5, -0.2, 0.5, 1, 0.3})
+
+	tests := []struct {
+		xlabels, ylabels []string
+		err              bool
+	}{
+		{xlabels: []string{"a", "b", "c"}, ylabels: []string{"x", "y"}, err: false},
+		{xlabels: nil, ylabels: nil, err: false},
+		{xlabels: []string{"a", "b"}, ylabels: []string{"x", "y"}, err: true},
+		{xlabels: []string{"a", "b", "c"}, ylabels: []string{"x", "y", "z"}, err: true},
+	}
+
+	for i, test := range tests {
+		_, err := datautils.PlotHeatmap(m, test.xlabels, test.ylabels, datautils.WithLabelStep(2))
+		if (err != nil) != test.err {
+			t.Errorf("Test %d: Expected error: %v but received %v", i+1, test.err, err)
+		}
+	}
+}
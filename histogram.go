@@ -0,0 +1,236 @@
+package datautils
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Histogram represents the distribution of a set of values as counts of the values falling within each of a
+// number of equal width bins.  Bin i covers the range [Edges[i], Edges[i+1]) except for the final bin which also
+// includes its upper edge.
+type Histogram struct {
+	// Edges contains the edges of the bins in ascending order (one more than the number of bins)
+	Edges []float64 `json:"edges"`
+
+	// Counts contains the number of values falling within each bin
+	Counts []float64 `json:"counts"`
+}
+
+// NewHistogram creates a new Histogram of the values with the specified number of equal width bins spanning the
+// range of the values.  NaN values are ignored.  If all the values are equal (or there are no values), the range
+// is widened by 0.5 either side so that the bins have non zero width.
+func NewHistogram(values []float64, bins int) Histogram {
+	min, max := valueRange(values)
+	return NewHistogramRange(values, bins, min, max)
+}
+
+// NewHistogramRange creates a new Histogram of the values with the specified number of equal width bins spanning
+// the range [min, max] e.g. [0, 1] for predicted probabilities.  Histograms with the same bins can be compared
+// directly.  As with ScoreHistogram, values outside the range are counted in the first or last bin as appropriate
+// and NaN values are ignored.
+func NewHistogramRange(values []float64, bins int, min, max float64) Histogram {
+	if bins < 1 {
+		panic("datautils: number of bins must be at least 1")
+	}
+	if !(max > min) {
+		panic("datautils: max must be greater than min")
+	}
+
+	h := Histogram{Edges: make([]float64, bins+1), Counts: make([]float64, bins)}
+	width := (max - min) / float64(bins)
+	for i := range h.Edges {
+		h.Edges[i] = min + float64(i)*width
+	}
+	// avoid floating point error in the final edge
+	h.Edges[bins] = max
+
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		b := int((v - min) / width)
+		if b < 0 {
+			b = 0
+		}
+		if b >= bins {
+			b = bins - 1
+		}
+		h.Counts[b]++
+	}
+	return h
+}
+
+// Total returns the total number of values counted by the histogram.
+func (h Histogram) Total() float64 {
+	var total float64
+	for _, c := range h.Counts {
+		total += c
+	}
+	return total
+}
+
+// Density returns the probability density of each bin i.e. the count of each bin divided by the total count and
+// the width of the bin so that the area of the histogram is 1.  If the histogram is empty, the densities are all
+// 0.
+func (h Histogram) Density() []float64 {
+	total := h.Total()
+	density := make([]float64, len(h.Counts))
+	if total == 0 {
+		return density
+	}
+	for i, c := range h.Counts {
+		density[i] = c / (total * (h.Edges[i+1] - h.Edges[i]))
+	}
+	return density
+}
+
+// Plot renders the histogram as a plot for visualisation.  If density is true, the bars show the probability
+// density of each bin (see Density) rather than the counts.
+func (h Histogram) Plot(density bool) *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+	p.Title.Text = PlotTheme.title("Histogram")
+	p.X.Label.Text = "Value"
+	p.Y.Label.Text = "Count"
+	if density {
+		p.Y.Label.Text = "Density"
+	}
+	p.Add(h.histogram(density, PlotTheme.Primary))
+	return p
+}
+
+// histogram creates a histogram plotter for the histogram, filled with a translucent version of c.
+func (h Histogram) histogram(density bool, c color.Color) *plotter.Histogram {
+	weights := h.Counts
+	if density {
+		weights = h.Density()
+	}
+	hist := &plotter.Histogram{
+		Bins:      make([]plotter.HistogramBin, len(h.Counts)),
+		Width:     h.Edges[1] - h.Edges[0],
+		LineStyle: plotter.DefaultLineStyle,
+	}
+	for i := range hist.Bins {
+		hist.Bins[i] = plotter.HistogramBin{Min: h.Edges[i], Max: h.Edges[i+1], Weight: weights[i]}
+	}
+	hist.FillColor = translucent(c, 96)
+	hist.LineStyle.Color = c
+	return hist
+}
+
+// KDE is a Gaussian kernel density estimate of the distribution of a set of values.  The estimated density is a
+// smooth alternative to a Histogram that does not depend upon the placement of bin edges.
+type KDE struct {
+	// Values contains the (non NaN) values from which the density is estimated
+	Values []float64 `json:"values"`
+
+	// Bandwidth is the standard deviation of the Gaussian kernel placed at each value.  Larger bandwidths give
+	// smoother estimates
+	Bandwidth float64 `json:"bandwidth"`
+}
+
+// NewKDE creates a new Gaussian kernel density estimate of the values using the specified bandwidth or, if
+// bandwidth is 0, a bandwidth chosen using Silverman's rule of thumb (see SilvermanBandwidth).  NaN values are
+// ignored.
+func NewKDE(values []float64, bandwidth float64) KDE {
+	if bandwidth < 0 {
+		panic("datautils: bandwidth must be non-negative")
+	}
+	k := KDE{Values: make([]float64, 0, len(values)), Bandwidth: bandwidth}
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			k.Values = append(k.Values, v)
+		}
+	}
+	if k.Bandwidth == 0 {
+		k.Bandwidth = SilvermanBandwidth(k.Values)
+	}
+	return k
+}
+
+// SilvermanBandwidth returns the bandwidth for a Gaussian kernel density estimate of the values using Silverman's
+// rule of thumb, 0.9 * min(standard deviation, IQR/1.34) * n^(-1/5).  If the values have no spread a bandwidth of
+// 1 is returned.
+func SilvermanBandwidth(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	spread := stat.StdDev(sorted, nil)
+	iqr := (stat.Quantile(0.75, stat.Empirical, sorted, nil) - stat.Quantile(0.25, stat.Empirical, sorted, nil)) / 1.34
+	if iqr > 0 && (iqr < spread || math.IsNaN(spread)) {
+		spread = iqr
+	}
+	if !(spread > 0) {
+		return 1
+	}
+	return 0.9 * spread * math.Pow(float64(len(values)), -0.2)
+}
+
+// Density returns the estimated probability density at x.  If there are no values the density is 0.
+func (k KDE) Density(x float64) float64 {
+	if len(k.Values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range k.Values {
+		z := (x - v) / k.Bandwidth
+		sum += math.Exp(-z * z / 2)
+	}
+	return sum / (float64(len(k.Values)) * k.Bandwidth * math.Sqrt(2*math.Pi))
+}
+
+// kdePoints is the number of points at which a KDE is evaluated for plotting.
+const kdePoints = 200
+
+// points evaluates the density at evenly spaced points spanning [min, max].
+func (k KDE) points(min, max float64) plotter.XYs {
+	pts := make(plotter.XYs, kdePoints)
+	for i := range pts {
+		pts[i].X = min + float64(i)*(max-min)/float64(len(pts)-1)
+		pts[i].Y = k.Density(pts[i].X)
+	}
+	return pts
+}
+
+// extent returns the range over which to plot the density, extending 3 bandwidths beyond the range of the values
+// so that the tails of the kernels are visible.
+func (k KDE) extent() (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, v := range k.Values {
+		min, max = math.Min(min, v), math.Max(max, v)
+	}
+	if len(k.Values) == 0 {
+		min, max = 0, 0
+	}
+	return min - 3*k.Bandwidth, max + 3*k.Bandwidth
+}
+
+// Plot renders the estimated density as a plot for visualisation.
+func (k KDE) Plot() *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+	p.Title.Text = PlotTheme.title(fmt.Sprintf("Kernel Density Estimate, Bandwidth=%g", k.Bandwidth))
+	p.X.Label.Text = "Value"
+	p.Y.Label.Text = "Density"
+
+	line, err := plotter.NewLine(k.points(k.extent()))
+	if err != nil {
+		panic(err)
+	}
+	line.Color = PlotTheme.Primary
+	line.Width = vg.Points(1.5)
+	p.Add(line)
+	return p
+}
@@ -0,0 +1,114 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestHistogram(t *testing.T) {
+	tests := []struct {
+		values  []float64
+		bins    int
+		min     float64
+		max     float64
+		edges   []float64
+		counts  []float64
+		density []float64
+	}{
+		{
+			values:  []float64{0, 0.25, 0.5, 0.75, 1, math.NaN()},
+			bins:    2,
+			min:     math.NaN(),
+			edges:   []float64{0, 0.5, 1},
+			counts:  []float64{2, 3},
+			density: []float64{0.8, 1.2},
+		},
+		{
+			// values outside the range are counted in the first or last bin
+			values:  []float64{-1, 0.1, 0.3, 2},
+			bins:    4,
+			min:     0,
+			max:     1,
+			edges:   []float64{0, 0.25, 0.5, 0.75, 1},
+			counts:  []float64{2, 1, 0, 1},
+			density: []float64{2, 1, 0, 1},
+		},
+		{
+			values:  []float64{3, 3},
+			bins:    1,
+			min:     math.NaN(),
+			edges:   []float64{2.5, 3.5},
+			counts:  []float64{2},
+			density: []float64{1},
+		},
+		{
+			values:  []float64{},
+			bins:    2,
+			min:     math.NaN(),
+			edges:   []float64{-0.5, 0, 0.5},
+			counts:  []float64{0, 0},
+			density: []float64{0, 0},
+		},
+	}
+
+	for i, test := range tests {
+		var h datautils.Histogram
+		if math.IsNaN(test.min) {
+			h = datautils.NewHistogram(test.values, test.bins)
+		} else {
+			h = datautils.NewHistogramRange(test.values, test.bins, test.min, test.max)
+		}
+		if !floats.Equal(test.edges, h.Edges) || !floats.Equal(test.counts, h.Counts) {
+			t.Errorf("Test %d: Expected edges: %v, counts: %v but received %v, %v", i+1, test.edges, test.counts, h.Edges, h.Counts)
+		}
+		if density := h.Density(); !floats.EqualApprox(test.density, density, 1e-12) {
+			t.Errorf("Test %d: Expected density: %v but received %v", i+1, test.density, density)
+		}
+		if p := h.Plot(true); p == nil {
+			t.Errorf("Test %d: Expected plot but received nil", i+1)
+		}
+	}
+}
+
+func TestKDE(t *testing.T) {
+	k := datautils.NewKDE([]float64{0, math.NaN()}, 1)
+	if len(k.Values) != 1 {
+		t.Errorf("Expected NaN values to be ignored but received %v", k.Values)
+	}
+	expected := 1 / math.Sqrt(2*math.Pi)
+	if d := k.Density(0); math.Abs(d-expected) > 1e-12 {
+		t.Errorf("Expected density: %v but received %v", expected, d)
+	}
+
+	// the density should integrate to 1
+	k = datautils.NewKDE([]float64{0.1, 0.4, 0.35, 0.8, 0.65, 0.2}, 0)
+	var area float64
+	for x := -2.0; x < 3; x += 0.001 {
+		area += k.Density(x) * 0.001
+	}
+	if math.Abs(area-1) > 1e-6 {
+		t.Errorf("Expected area: %v but received %v", 1, area)
+	}
+
+	tests := []struct {
+		values    []float64
+		bandwidth float64
+	}{
+		// the IQR (empirical quartiles 2 and 4) / 1.34 is less than the standard deviation
+		{values: []float64{1, 2, 3, 4, 5}, bandwidth: 0.9 * 2 / 1.34 * math.Pow(5, -0.2)},
+		{values: []float64{1, 1, 1, 1, 10}, bandwidth: 0.9 * math.Sqrt(16.2) * math.Pow(5, -0.2)},
+		{values: []float64{1, 1, 1}, bandwidth: 1},
+	}
+	for i, test := range tests {
+		if bw := datautils.SilvermanBandwidth(test.values); math.Abs(bw-test.bandwidth) > 1e-12 {
+			t.Errorf("Test %d: Expected bandwidth: %v but received %v", i+1, test.bandwidth, bw)
+		}
+	}
+
+	if p := k.Plot(); p == nil {
+		t.Errorf("Expected plot but received nil")
+	}
+}
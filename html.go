@@ -0,0 +1,235 @@
+package datautils
+
+import (
+	"fmt"
+	"html/template"
+	"image/color"
+	"io"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	htmlWidth  = 640
+	htmlHeight = 480
+	htmlMargin = 60
+)
+
+type htmlPoint struct {
+	X, Y    float64
+	Tooltip string
+}
+
+type htmlTick struct {
+	X, Y  float64
+	Label string
+}
+
+type htmlCurve struct {
+	Title       string
+	XLabel      string
+	YLabel      string
+	Width       int
+	Height      int
+	Left, Right float64
+	Top, Bottom float64
+	Points      []htmlPoint
+	Path        string
+	XTicks      []htmlTick
+	YTicks      []htmlTick
+}
+
+var curveTemplate = template.Must(template.New("curve").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; }
+circle { fill: rgb(255, 0, 128); fill-opacity: 0.3; }
+circle:hover { fill-opacity: 1; }
+</style>
+</head>
+<body>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}">
+<text x="50%" y="20" text-anchor="middle">{{.Title}}</text>
+<line x1="{{.Left}}" y1="{{.Bottom}}" x2="{{.Right}}" y2="{{.Bottom}}" stroke="black"/>
+<line x1="{{.Left}}" y1="{{.Top}}" x2="{{.Left}}" y2="{{.Bottom}}" stroke="black"/>
+{{range .XTicks}}<text x="{{.X}}" y="{{.Y}}" font-size="12" text-anchor="middle">{{.Label}}</text>
+{{end}}{{range .YTicks}}<text x="{{.X}}" y="{{.Y}}" font-size="12" text-anchor="end">{{.Label}}</text>
+{{end}}<text x="{{.Right}}" y="{{.Bottom}}" dy="40" font-size="14" text-anchor="end">{{.XLabel}}</text>
+<text x="15" y="{{.Top}}" font-size="14" transform="rotate(-90, 15, {{.Top}})" text-anchor="end">{{.YLabel}}</text>
+<path d="{{.Path}}" fill="none" stroke="rgb(255, 0, 128)" stroke-width="2"/>
+{{range .Points}}<circle cx="{{.X}}" cy="{{.Y}}" r="5"><title>{{.Tooltip}}</title></circle>
+{{end}}</svg>
+</body>
+</html>
+`))
+
+// WriteHTML writes the precision recall curve to w as a standalone interactive HTML page containing an embedded
+// SVG rendering of the curve.  Hovering over each point of the curve displays a tooltip showing the threshold,
+// precision and recall at that point.
+func (c PrecisionRecallCurve) WriteHTML(w io.Writer) error {
+	data := htmlCurve{
+		Title:  fmt.Sprintf("Precision-recall Curve, AP=%f", c.AveragePrecision()),
+		XLabel: "Recall",
+		YLabel: "Precision",
+		Width:  htmlWidth,
+		Height: htmlHeight,
+		Left:   htmlMargin,
+		Right:  htmlWidth - htmlMargin/2,
+		Top:    htmlMargin / 2,
+		Bottom: htmlHeight - htmlMargin,
+	}
+	x := func(v float64) float64 { return data.Left + v*(data.Right-data.Left) }
+	y := func(v float64) float64 { return data.Bottom - v*(data.Bottom-data.Top) }
+
+	for i := 0; i <= 10; i++ {
+		v := float64(i) / 10
+		label := fmt.Sprintf("%.1f", v)
+		data.XTicks = append(data.XTicks, htmlTick{X: x(v), Y: data.Bottom + 18, Label: label})
+		data.YTicks = append(data.YTicks, htmlTick{X: data.Left - 6, Y: y(v) + 4, Label: label})
+	}
+
+	for i := range c.Precision {
+		p := htmlPoint{X: x(c.Recall[i]), Y: y(c.Precision[i])}
+		if i < len(c.Thresholds) {
+			p.Tooltip = fmt.Sprintf("Threshold: %g\nPrecision: %f\nRecall: %f", c.Thresholds[i], c.Precision[i], c.Recall[i])
+		} else {
+			p.Tooltip = fmt.Sprintf("Precision: %f\nRecall: %f", c.Precision[i], c.Recall[i])
+		}
+		data.Points = append(data.Points, p)
+
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+		data.Path += fmt.Sprintf("%s%.2f,%.2f ", cmd, p.X, p.Y)
+	}
+
+	return curveTemplate.Execute(w, data)
+}
+
+type htmlCell struct {
+	X, Y    float64
+	Fill    string
+	Tooltip string
+	Value   string
+}
+
+type htmlHeatmap struct {
+	Width, Height int
+	Size          float64
+	Cells         []htmlCell
+	XTicks        []htmlTick
+	YTicks        []htmlTick
+}
+
+var heatmapTemplate = template.Must(template.New("heatmap").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Heatmap</title>
+<style>
+body { font-family: sans-serif; }
+rect:hover { stroke: black; stroke-width: 2; }
+</style>
+</head>
+<body>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}">
+{{$size := .Size}}{{range .Cells}}<rect x="{{.X}}" y="{{.Y}}" width="{{$size}}" height="{{$size}}" fill="{{.Fill}}"><title>{{.Tooltip}}</title></rect>
+{{if .Value}}<text x="{{.X}}" y="{{.Y}}" dx="10" dy="14" font-size="8" text-anchor="middle" pointer-events="none">{{.Value}}</text>
+{{end}}{{end}}{{range .XTicks}}<text x="{{.X}}" y="{{.Y}}" font-size="10" text-anchor="end" transform="rotate(-90, {{.X}}, {{.Y}})">{{.Label}}</text>
+{{end}}{{range .YTicks}}<text x="{{.X}}" y="{{.Y}}" font-size="10" text-anchor="end">{{.Label}}</text>
+{{end}}</svg>
+</body>
+</html>
+`))
+
+// WriteHeatmapHTML writes the matrix m (e.g. a correlation matrix) to w as a standalone interactive HTML page
+// containing an embedded SVG heatmap using the specified labels for the columns (xlabels) and rows (ylabels).
+// Hovering over each cell displays a tooltip showing the row and column labels along with the cell's value.
+// As with PlotHeatmap, labels may be nil in which case rows and columns are labelled by index, row 0 is drawn at
+// the bottom and NaN values are rendered as transparent.  The HeatmapOptions configuring the cells and their
+// colours (WithMask, WithRange, WithSymmetricRange, WithPalette, WithDivergingColorMap, WithLabelStep and
+// WithCellValues) are applied as they are by PlotHeatmap while those configuring the fonts and rotation of the
+// labels are ignored.
+func WriteHeatmapHTML(w io.Writer, m mat.Matrix, xlabels, ylabels []string, opts ...HeatmapOption) error {
+	var cfg heatmapConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	r, c := m.Dims()
+	if xlabels != nil && len(xlabels) != c {
+		return fmt.Errorf("datautils: %d x labels specified for matrix with %d columns", len(xlabels), c)
+	}
+	if ylabels != nil && len(ylabels) != r {
+		return fmt.Errorf("datautils: %d y labels specified for matrix with %d rows", len(ylabels), r)
+	}
+	label := func(labels []string, i int) string {
+		if labels == nil {
+			return fmt.Sprint(i)
+		}
+		return labels[i]
+	}
+	xticks := ticks{labels: xlabels, n: c, step: cfg.labelStep}.Ticks(0, float64(c-1))
+	yticks := ticks{labels: ylabels, n: r, step: cfg.labelStep}.Ticks(0, float64(r-1))
+
+	grid := heatmap{x: m, mask: cfg.mask}
+	hm := cfg.newHeatMap(grid)
+	colors := hm.Palette.Colors()
+
+	const size = 20.0
+	const margin = 120.0
+
+	data := htmlHeatmap{
+		Width:  int(margin + size*float64(c) + size),
+		Height: int(size*float64(r) + margin),
+		Size:   size,
+	}
+	for i := 0; i < r; i++ {
+		// rows are drawn from the bottom up as by PlotHeatmap
+		y := float64(r-1-i) * size
+		if yticks[i].Label != "" {
+			data.YTicks = append(data.YTicks, htmlTick{X: margin - 4, Y: y + size*0.7, Label: yticks[i].Label})
+		}
+		for j := 0; j < c; j++ {
+			v := grid.Z(j, i)
+			if math.IsNaN(v) {
+				continue
+			}
+			cell := htmlCell{
+				X:       margin + float64(j)*size,
+				Y:       y,
+				Fill:    cssColor(heatMapColor(hm, v, colors)),
+				Tooltip: fmt.Sprintf("%s, %s: %g", label(ylabels, i), label(xlabels, j), v),
+			}
+			if cfg.annotate {
+				cell.Value = fmt.Sprintf(cfg.annotateFmt, v)
+			}
+			data.Cells = append(data.Cells, cell)
+		}
+	}
+	for j := 0; j < c; j++ {
+		if xticks[j].Label != "" {
+			data.XTicks = append(data.XTicks, htmlTick{X: margin + float64(j)*size + size*0.7, Y: float64(r)*size + 4, Label: xticks[j].Label})
+		}
+	}
+
+	return heatmapTemplate.Execute(w, data)
+}
+
+// cssColor formats the colour as a CSS rgba() colour.  A nil colour is transparent.
+func cssColor(c color.Color) string {
+	if c == nil {
+		return "transparent"
+	}
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		return "transparent"
+	}
+	// convert from alpha-premultiplied colour
+	return fmt.Sprintf("rgba(%d, %d, %d, %.3f)", r*0xff/a, g*0xff/a, b*0xff/a, float64(a)/0xffff)
+}
@@ -0,0 +1,75 @@
+package datautils_test
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPrecisionRecallCurveWriteHTML(t *testing.T) {
+	curve := datautils.NewPrecisionRecallCurve(datasets[0].probs, datasets[0].labels)
+
+	var buf bytes.Buffer
+	if err := curve.WriteHTML(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	html := buf.String()
+	if n := strings.Count(html, "<circle"); n != len(curve.Precision) {
+		t.Errorf("Expected %d points but received %d", len(curve.Precision), n)
+	}
+	if !strings.Contains(html, "Threshold: 0.35\nPrecision: 0.666667\nRecall: 1.000000") {
+		t.Errorf("Expected tooltip for threshold 0.35 in output:\n%s", html)
+	}
+}
+
+func TestWriteHeatmapHTML(t *testing.T) {
+	m := mat.NewDense(2, 2, []float64{1, 0.5, 0.5, 1})
+
+	var buf bytes.Buffer
+	if err := datautils.WriteHeatmapHTML(&buf, m, []string{"a", "b"}, []string{"a", "b"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	html := buf.String()
+	if n := strings.Count(html, "<rect"); n != 4 {
+		t.Errorf("Expected 4 cells but received %d", n)
+	}
+	if !strings.Contains(html, "<title>b, a: 0.5</title>") {
+		t.Errorf("Expected tooltip for cell (1, 0) in output:\n%s", html)
+	}
+
+	// as with PlotHeatmap, row 0 is drawn at the bottom
+	if !strings.Contains(html, `y="20" width="20" height="20" fill="rgba(`) || !strings.Contains(html, `<title>a, b: 0.5</title>`) {
+		t.Errorf("Expected row 0 at the bottom of the heatmap in output:\n%s", html)
+	}
+
+	buf.Reset()
+	pal := twoColors{color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}}
+	opts := []datautils.HeatmapOption{datautils.WithMask(datautils.MaskUpper), datautils.WithRange(0, 0.5), datautils.WithPalette(pal), datautils.WithCellValues("%.1f", 6)}
+	if err := datautils.WriteHeatmapHTML(&buf, m, nil, nil, opts...); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	html = buf.String()
+	if n := strings.Count(html, "<rect"); n != 3 {
+		t.Errorf("Expected 3 unmasked cells but received %d", n)
+	}
+	// values above the range are clamped to the last colour of the palette
+	if n := strings.Count(html, `fill="rgba(0, 0, 255, 1.000)"`); n != 3 {
+		t.Errorf("Expected 3 cells coloured with the last colour of the palette but received %d in output:\n%s", n, html)
+	}
+	if n := strings.Count(html, ">1.0</text>"); n != 2 {
+		t.Errorf("Expected 2 cells annotated with 1.0 but received %d in output:\n%s", n, html)
+	}
+
+	if err := datautils.WriteHeatmapHTML(&buf, m, []string{"a"}, nil); err == nil {
+		t.Errorf("Expected error for mismatched labels but received none")
+	}
+}
+
+// twoColors is a palette of two colours.
+type twoColors []color.Color
+
+func (p twoColors) Colors() []color.Color { return p }
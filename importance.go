@@ -0,0 +1,178 @@
+package datautils
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// FeatureImportance is the permutation importance of a single feature.
+type FeatureImportance struct {
+	// Column is the index of the feature's column
+	Column int
+
+	// Feature is the name of the feature
+	Feature string
+
+	// Drops contains the drop in the metric for each repeated permutation of the feature
+	Drops []float64
+
+	// Mean and StdDev are the mean and standard deviation of the drops
+	Mean, StdDev float64
+
+	// Lower and Upper are the bounds of the confidence interval of the mean drop.  If there is only a single
+	// repeat, they are NaN.
+	Lower, Upper float64
+}
+
+// FeatureImportances contains the permutation importances of a set of features ranked in descending order of mean
+// drop.
+type FeatureImportances []FeatureImportance
+
+// PermutationImportance evaluates the importance of each feature to a trained model, whatever its type, by
+// measuring how much the model's performance drops when the relationship between the feature and the labels is
+// broken by randomly shuffling the feature's values.  Shuffling retains the distribution of the feature's values
+// so, unlike removing the feature, the model need not be retrained.  Importances should be evaluated on held out
+// validation data as features the model has overfitted to appear important on training data.  Correlated features
+// share their importance as shuffling one leaves the information it holds available to the model through the
+// others.
+type PermutationImportance struct {
+	// Metric is the performance metric of the model where higher values indicate better performance.  Metrics
+	// where lower values are better (e.g. log loss) should be negated.
+	Metric MetricFunc
+
+	// Repeats is the number of times each feature is shuffled
+	Repeats int
+
+	// Confidence is the confidence level of the intervals of the mean drops e.g. 0.95
+	Confidence float64
+
+	// Seed is used to initialise the random number generator so that results are reproducible
+	Seed int64
+}
+
+// Run calculates the permutation importance of each column (feature) of the validation matrix m.  predict returns
+// the model's predictions for the rows of a matrix and labels contains the ground truth labels of the rows of m.
+// names contains the name of each column or, if nil, columns are named by index.  The drop for each repeat is the
+// metric of the model's predictions for m less the metric of its predictions once the feature has been shuffled.
+// Confidence intervals of the mean drops are calculated using Student's t distribution.  predict is called
+// sequentially and must not retain or modify the matrix it is passed.  The returned importances are ranked in
+// descending order of mean drop with ties retaining column order.
+func (p PermutationImportance) Run(m mat.Matrix, names []string, labels []float64, predict func(mat.Matrix) []float64) FeatureImportances {
+	r, c := m.Dims()
+	if len(labels) != r || names != nil && len(names) != c {
+		panic(ErrLengthMismatch)
+	}
+	if p.Repeats < 1 {
+		panic("datautils: number of repeats must be at least 1")
+	}
+	if p.Confidence <= 0 || p.Confidence >= 1 {
+		panic("datautils: confidence must be between 0 and 1")
+	}
+
+	rnd := rand.New(rand.NewSource(p.Seed))
+	shuffled := mat.DenseCopyOf(m)
+	baseline := p.Metric(predict(shuffled), labels)
+
+	importances := make(FeatureImportances, c)
+	column := make([]float64, r)
+	for j := range importances {
+		mat.Col(column, j, m)
+		drops := make([]float64, p.Repeats)
+		for k := range drops {
+			for i, v := range rnd.Perm(r) {
+				shuffled.Set(i, j, column[v])
+			}
+			drops[k] = baseline - p.Metric(predict(shuffled), labels)
+		}
+		shuffled.SetCol(j, column)
+
+		importances[j] = FeatureImportance{Column: j, Feature: featureName(names, j), Drops: drops}
+		p.summarise(&importances[j])
+	}
+
+	sort.SliceStable(importances, func(a, b int) bool { return importances[a].Mean > importances[b].Mean })
+	return importances
+}
+
+// summarise calculates the mean, standard deviation and confidence interval of the drops of f.
+func (p PermutationImportance) summarise(f *FeatureImportance) {
+	n := float64(len(f.Drops))
+	f.Mean = stat.Mean(f.Drops, nil)
+	f.Lower, f.Upper = math.NaN(), math.NaN()
+	if len(f.Drops) < 2 {
+		return
+	}
+	f.StdDev = stat.StdDev(f.Drops, nil)
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: n - 1}.Quantile(1 - (1-p.Confidence)/2)
+	margin := t * f.StdDev / math.Sqrt(n)
+	f.Lower, f.Upper = f.Mean-margin, f.Mean+margin
+}
+
+// String returns a fixed width textual representation of the ranked feature importances suitable for printing.
+func (f FeatureImportances) String() string {
+	s := "Rank | Feature              |  Mean Drop |   Std Dev  |    Lower   |    Upper\n"
+	s = s + "------------------------------------------------------------------------------\n"
+	for i, v := range f {
+		s = fmt.Sprintf("%s%4d | %-20s | %10.4g | %10.4g | %10.4g | %10.4g\n", s, i+1, v.Feature, v.Mean, v.StdDev, v.Lower, v.Upper)
+	}
+	return s
+}
+
+// Plot renders the mean drop of each feature as a bar chart, in ranked order, with error bars showing the
+// confidence intervals of the means where available.
+func (f FeatureImportances) Plot() (*plot.Plot, error) {
+	p, err := newPlot()
+	if err != nil {
+		return nil, err
+	}
+	p.Title.Text = PlotTheme.title("Permutation Importance")
+	p.Y.Label.Text = "Mean Drop in Metric"
+
+	labels := make([]string, len(f))
+	means := make(plotter.Values, len(f))
+	var points plotter.XYs
+	var errs plotter.YErrors
+	for i, v := range f {
+		labels[i] = v.Feature
+		means[i] = v.Mean
+		if !math.IsNaN(v.Lower) {
+			points = append(points, plotter.XY{X: float64(i), Y: v.Mean})
+			errs = append(errs, struct{ Low, High float64 }{v.Mean - v.Lower, v.Upper - v.Mean})
+		}
+	}
+
+	if len(f) > 0 {
+		bars, err := plotter.NewBarChart(means, vg.Points(10))
+		if err != nil {
+			return nil, err
+		}
+		bars.Color = PlotTheme.Primary
+		bars.LineStyle.Width = 0
+		p.Add(bars)
+	}
+	if len(points) > 0 {
+		bars, err := plotter.NewYErrorBars(struct {
+			plotter.XYs
+			plotter.YErrors
+		}{points, errs})
+		if err != nil {
+			return nil, err
+		}
+		p.Add(bars)
+	}
+
+	p.X.Tick.Label.Rotation = 1.5
+	p.X.Tick.Label.XAlign = draw.XRight
+	p.X.Tick.Marker = ticks{labels: labels, n: len(f)}
+	return p, nil
+}
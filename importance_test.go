@@ -0,0 +1,76 @@
+package datautils_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+// negativeMSE returns the negated mean squared error of the predictions so that higher values are better.
+func negativeMSE(predictions, labels []float64) float64 {
+	var sum float64
+	for i := range predictions {
+		sum += (predictions[i] - labels[i]) * (predictions[i] - labels[i])
+	}
+	return -sum / float64(len(predictions))
+}
+
+func TestPermutationImportance(t *testing.T) {
+	m := mat.NewDense(20, 2, nil)
+	labels := make([]float64, 20)
+	for i := 0; i < 20; i++ {
+		m.Set(i, 0, float64(i))
+		m.Set(i, 1, float64((i*7)%5))
+		labels[i] = 2 * float64(i)
+	}
+	original := mat.DenseCopyOf(m)
+
+	// the model only uses the first feature
+	predict := func(x mat.Matrix) []float64 {
+		r, _ := x.Dims()
+		predictions := make([]float64, r)
+		for i := range predictions {
+			predictions[i] = 2 * x.At(i, 0)
+		}
+		return predictions
+	}
+
+	p := datautils.PermutationImportance{Metric: negativeMSE, Repeats: 5, Confidence: 0.95, Seed: 1}
+	importances := p.Run(m, []string{"signal", "noise"}, labels, predict)
+
+	if len(importances) != 2 || importances[0].Feature != "signal" || importances[1].Feature != "noise" {
+		t.Fatalf("Expected importances ranked signal, noise but received %v", importances)
+	}
+	signal, noise := importances[0], importances[1]
+	if len(signal.Drops) != 5 || signal.Mean <= 0 || !(signal.Lower < signal.Mean && signal.Mean < signal.Upper) {
+		t.Errorf("Unexpected importance of signal feature: %+v", signal)
+	}
+	if noise.Column != 1 || noise.Mean != 0 || noise.StdDev != 0 || noise.Lower != 0 || noise.Upper != 0 {
+		t.Errorf("Expected zero importance of noise feature but received %+v", noise)
+	}
+	if !mat.Equal(m, original) {
+		t.Errorf("Expected validation matrix to be unmodified")
+	}
+
+	// results are reproducible for the same seed
+	again := p.Run(m, nil, labels, predict)
+	if again[0].Feature != "0" || again[0].Mean != signal.Mean {
+		t.Errorf("Expected reproducible importance %v but received %v", signal.Mean, again[0].Mean)
+	}
+
+	if s := importances.String(); !strings.Contains(s, "signal") || strings.Count(s, "\n") != 4 {
+		t.Errorf("Unexpected importances table:\n%s", s)
+	}
+	if plt, err := importances.Plot(); err != nil || plt == nil {
+		t.Errorf("Unexpected error plotting importances: %v", err)
+	}
+
+	// confidence intervals require more than one repeat
+	p.Repeats = 1
+	if single := p.Run(m, nil, labels, predict); !math.IsNaN(single[0].Lower) || !math.IsNaN(single[0].Upper) {
+		t.Errorf("Expected NaN confidence interval for a single repeat but received %+v", single[0])
+	}
+}
@@ -0,0 +1,227 @@
+package datautils
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// JSONFloat is a float64 that may be encoded as JSON even when NaN or infinite (which encoding/json does not
+// support).  Finite values are encoded as JSON numbers while NaN and the infinities are encoded as the strings
+// "NaN", "Infinity" and "-Infinity" as in the JSON mapping of Protocol Buffers (and so as expected by services
+// such as MLflow).  It is used for all the JSON encoded metrics of the package and its sub packages so that a
+// metric is encoded identically regardless of where it is written.
+type JSONFloat float64
+
+// MarshalJSON implements the json.Marshaler interface.
+func (f JSONFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	switch {
+	case math.IsNaN(v):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(v, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(v, -1):
+		return []byte(`"-Infinity"`), nil
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (f *JSONFloat) UnmarshalJSON(b []byte) error {
+	switch string(b) {
+	case `"NaN"`:
+		*f = JSONFloat(math.NaN())
+		return nil
+	case `"Infinity"`:
+		*f = JSONFloat(math.Inf(1))
+		return nil
+	case `"-Infinity"`:
+		*f = JSONFloat(math.Inf(-1))
+		return nil
+	}
+	var v float64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*f = JSONFloat(v)
+	return nil
+}
+
+func toJSONFloats(s []float64) []JSONFloat {
+	if s == nil {
+		return nil
+	}
+	f := make([]JSONFloat, len(s))
+	for i, v := range s {
+		f[i] = JSONFloat(v)
+	}
+	return f
+}
+
+func fromJSONFloats(f []JSONFloat) []float64 {
+	if f == nil {
+		return nil
+	}
+	s := make([]float64, len(f))
+	for i, v := range f {
+		s[i] = float64(v)
+	}
+	return s
+}
+
+type precisionRecallCurveJSON struct {
+	Precision  []JSONFloat `json:"precision"`
+	Recall     []JSONFloat `json:"recall"`
+	Thresholds []JSONFloat `json:"thresholds"`
+	Positives  int         `json:"positives"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c PrecisionRecallCurve) MarshalJSON() ([]byte, error) {
+	return json.Marshal(precisionRecallCurveJSON{
+		Precision:  toJSONFloats(c.Precision),
+		Recall:     toJSONFloats(c.Recall),
+		Thresholds: toJSONFloats(c.Thresholds),
+		Positives:  c.positives,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *PrecisionRecallCurve) UnmarshalJSON(b []byte) error {
+	var v precisionRecallCurveJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*c = PrecisionRecallCurve{
+		Precision:  fromJSONFloats(v.Precision),
+		Recall:     fromJSONFloats(v.Recall),
+		Thresholds: fromJSONFloats(v.Thresholds),
+		positives:  v.Positives,
+	}
+	return nil
+}
+
+type rocCurveJSON struct {
+	FPR        []JSONFloat `json:"fpr"`
+	TPR        []JSONFloat `json:"tpr"`
+	Thresholds []JSONFloat `json:"thresholds"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c ROCCurve) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rocCurveJSON{
+		FPR:        toJSONFloats(c.FPR),
+		TPR:        toJSONFloats(c.TPR),
+		Thresholds: toJSONFloats(c.Thresholds),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *ROCCurve) UnmarshalJSON(b []byte) error {
+	var v rocCurveJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*c = ROCCurve{
+		FPR:        fromJSONFloats(v.FPR),
+		TPR:        fromJSONFloats(v.TPR),
+		Thresholds: fromJSONFloats(v.Thresholds),
+	}
+	return nil
+}
+
+type rankingEvaluationJSON struct {
+	Relevancies      []JSONFloat `json:"relevancies"`
+	Predictions      []JSONFloat `json:"predictions"`
+	PredictedRankInd []int       `json:"predicted_rank_ind"`
+	PerfectRankInd   []int       `json:"perfect_rank_ind"`
+	Unretrieved      []JSONFloat `json:"unretrieved,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r RankingEvaluation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rankingEvaluationJSON{
+		Relevancies:      toJSONFloats(r.Relevancies),
+		Predictions:      toJSONFloats(r.Predictions),
+		PredictedRankInd: r.PredictedRankInd,
+		PerfectRankInd:   r.PerfectRankInd,
+		Unretrieved:      toJSONFloats(r.Unretrieved),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *RankingEvaluation) UnmarshalJSON(b []byte) error {
+	var v rankingEvaluationJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*r = RankingEvaluation{
+		Relevancies:      fromJSONFloats(v.Relevancies),
+		Predictions:      fromJSONFloats(v.Predictions),
+		PredictedRankInd: v.PredictedRankInd,
+		PerfectRankInd:   v.PerfectRankInd,
+		Unretrieved:      fromJSONFloats(v.Unretrieved),
+	}
+	return nil
+}
+
+type confidenceIntervalJSON struct {
+	Estimate   JSONFloat `json:"estimate"`
+	Lower      JSONFloat `json:"lower"`
+	Upper      JSONFloat `json:"upper"`
+	Confidence float64   `json:"confidence"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (ci ConfidenceInterval) MarshalJSON() ([]byte, error) {
+	return json.Marshal(confidenceIntervalJSON{
+		Estimate:   JSONFloat(ci.Estimate),
+		Lower:      JSONFloat(ci.Lower),
+		Upper:      JSONFloat(ci.Upper),
+		Confidence: ci.Confidence,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (ci *ConfidenceInterval) UnmarshalJSON(b []byte) error {
+	var v confidenceIntervalJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*ci = ConfidenceInterval{
+		Estimate:   float64(v.Estimate),
+		Lower:      float64(v.Lower),
+		Upper:      float64(v.Upper),
+		Confidence: v.Confidence,
+	}
+	return nil
+}
+
+type crossValidationScoreJSON struct {
+	Scores []JSONFloat `json:"scores"`
+	Mean   JSONFloat   `json:"mean"`
+	StdDev JSONFloat   `json:"std_dev"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s CrossValidationScore) MarshalJSON() ([]byte, error) {
+	return json.Marshal(crossValidationScoreJSON{
+		Scores: toJSONFloats(s.Scores),
+		Mean:   JSONFloat(s.Mean),
+		StdDev: JSONFloat(s.StdDev),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *CrossValidationScore) UnmarshalJSON(b []byte) error {
+	var v crossValidationScoreJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*s = CrossValidationScore{
+		Scores: fromJSONFloats(v.Scores),
+		Mean:   float64(v.Mean),
+		StdDev: float64(v.StdDev),
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package datautils_test
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestPrecisionRecallCurveJSON(t *testing.T) {
+	for i, d := range datasets {
+		curve := datautils.NewPrecisionRecallCurve(d.probs, d.labels)
+
+		b, err := json.Marshal(curve)
+		if err != nil {
+			t.Fatalf("Test %d: Unexpected error: %v", i+1, err)
+		}
+		var decoded datautils.PrecisionRecallCurve
+		if err := json.Unmarshal(b, &decoded); err != nil {
+			t.Fatalf("Test %d: Unexpected error: %v", i+1, err)
+		}
+		if !floats.Equal(curve.Precision, decoded.Precision) || !floats.Equal(curve.Recall, decoded.Recall) ||
+			!floats.Equal(curve.Thresholds, decoded.Thresholds) {
+			t.Errorf("Test %d: Expected %v but received %v", i+1, curve, decoded)
+		}
+		if curve.RPrecision() != decoded.RPrecision() {
+			t.Errorf("Test %d: Expected RPrecision: %v but received %v", i+1, curve.RPrecision(), decoded.RPrecision())
+		}
+	}
+
+	b, _ := json.Marshal(datautils.NewPrecisionRecallCurve([]float64{0.2, math.Inf(-1)}, []float64{0, 1}))
+	if !strings.Contains(string(b), `"thresholds":["-Infinity",0.2]`) {
+		t.Errorf("Expected infinite threshold to be encoded but received %s", b)
+	}
+}
+
+func TestRankingEvaluationAndConfusionMatrixJSON(t *testing.T) {
+	evaluation := datautils.NewRankingEvaluation(datasets[2].probs, datasets[2].labels)
+	matrix := datautils.NewWeightedConfusionMatrix(datasets[0].probs, datasets[0].labels, []float64{1, 2, 3, 4}, 0.5)
+
+	tests := []struct {
+		value   interface{}
+		decoded interface{}
+		field   string
+	}{
+		{value: evaluation, decoded: &datautils.RankingEvaluation{}, field: `"predicted_rank_ind"`},
+		{value: matrix, decoded: &datautils.ConfusionMatrix{}, field: `"true_pos"`},
+	}
+
+	for i, test := range tests {
+		b, err := json.Marshal(test.value)
+		if err != nil {
+			t.Fatalf("Test %d: Unexpected error: %v", i+1, err)
+		}
+		if !strings.Contains(string(b), test.field) {
+			t.Errorf("Test %d: Expected field %s in %s", i+1, test.field, b)
+		}
+		if err := json.Unmarshal(b, test.decoded); err != nil {
+			t.Fatalf("Test %d: Unexpected error: %v", i+1, err)
+		}
+		if decoded := reflect.ValueOf(test.decoded).Elem().Interface(); !reflect.DeepEqual(test.value, decoded) {
+			t.Errorf("Test %d: Expected %+v but received %+v", i+1, test.value, decoded)
+		}
+	}
+}
+
+func TestJSONFloat(t *testing.T) {
+	tests := []struct {
+		value   float64
+		encoded string
+	}{
+		{value: 0.25, encoded: `0.25`},
+		{value: math.NaN(), encoded: `"NaN"`},
+		{value: math.Inf(1), encoded: `"Infinity"`},
+		{value: math.Inf(-1), encoded: `"-Infinity"`},
+	}
+	for i, test := range tests {
+		b, err := json.Marshal(datautils.JSONFloat(test.value))
+		if err != nil || string(b) != test.encoded {
+			t.Errorf("Test %d: Expected %s but received %s (error %v)", i+1, test.encoded, b, err)
+		}
+		var f datautils.JSONFloat
+		if err := json.Unmarshal(b, &f); err != nil || !equalWithNaN([]float64{test.value}, []float64{float64(f)}) {
+			t.Errorf("Test %d: Expected %v but received %v (error %v)", i+1, test.value, f, err)
+		}
+	}
+
+	for _, invalid := range []string{`"+Inf"`, `"-Inf"`, `"1"`} {
+		var f datautils.JSONFloat
+		if err := json.Unmarshal([]byte(invalid), &f); err == nil {
+			t.Errorf("Expected error decoding %s but received %v", invalid, f)
+		}
+	}
+}
+
+func TestROCCurveAndRankingEvaluationJSONWithInfinities(t *testing.T) {
+	predictions := []float64{0.9, math.Inf(1), 0.4, math.Inf(-1)}
+	labels := []float64{1, 0, 1, 0}
+
+	curve := datautils.NewROCCurve(predictions, labels)
+	b, err := json.Marshal(curve)
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling ROC curve: %v", err)
+	}
+	if !strings.Contains(string(b), `"thresholds":["Infinity"`) {
+		t.Errorf("Expected infinite threshold to be encoded but received %s", b)
+	}
+	var decodedCurve datautils.ROCCurve
+	if err := json.Unmarshal(b, &decodedCurve); err != nil {
+		t.Fatalf("Unexpected error unmarshalling ROC curve: %v", err)
+	}
+	if !floats.Equal(curve.FPR, decodedCurve.FPR) || !floats.Equal(curve.TPR, decodedCurve.TPR) ||
+		!floats.Equal(curve.Thresholds, decodedCurve.Thresholds) {
+		t.Errorf("Expected %v but received %v", curve, decodedCurve)
+	}
+
+	// a curve without negatives has undefined (NaN) false positive rates
+	b, err = json.Marshal(datautils.NewROCCurve([]float64{0.5}, []float64{1}))
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling ROC curve with NaN rates: %v", err)
+	}
+	if !strings.Contains(string(b), `"fpr":[0,"NaN"]`) {
+		t.Errorf("Expected NaN false positive rate to be encoded as \"NaN\" but received %s", b)
+	}
+
+	evaluation := datautils.NewRankingEvaluation(predictions, labels)
+	evaluation.Unretrieved = []float64{2}
+	b, err = json.Marshal(evaluation)
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling ranking evaluation: %v", err)
+	}
+	var decoded datautils.RankingEvaluation
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Unexpected error unmarshalling ranking evaluation: %v", err)
+	}
+	if !reflect.DeepEqual(evaluation, decoded) {
+		t.Errorf("Expected %+v but received %+v", evaluation, decoded)
+	}
+}
+
+func TestConfidenceIntervalJSON(t *testing.T) {
+	ci := datautils.ConfidenceInterval{Estimate: 0.5, Lower: math.NaN(), Upper: math.Inf(1), Confidence: 0.95}
+
+	b, err := json.Marshal(ci)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var decoded datautils.ConfidenceInterval
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decoded.Estimate != 0.5 || !math.IsNaN(decoded.Lower) || !math.IsInf(decoded.Upper, 1) || decoded.Confidence != 0.95 {
+		t.Errorf("Expected %+v but received %+v", ci, decoded)
+	}
+}
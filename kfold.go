@@ -0,0 +1,123 @@
+package datautils
+
+import (
+	"math/rand"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// Fold represents a single partition of a dataset into training and test sets as indices into the dataset's
+// rows/labels.
+type Fold struct {
+	Train []int `json:"train"`
+	Test  []int `json:"test"`
+}
+
+// KFold partitions a dataset into K folds for K-fold cross-validation.  Each observation appears in the test
+// set of exactly one fold and in the training set of all other folds.
+type KFold struct {
+	// K is the number of folds
+	K int
+
+	// Shuffle indicates whether the observations should be randomly shuffled before being assigned to folds.
+	// If false, observations are assigned to folds in their original order.
+	Shuffle bool
+
+	// Stratify indicates whether the proportions of each class (distinct label value) should be preserved in
+	// each fold
+	Stratify bool
+
+	// Seed is used to initialise the random number generator when shuffling so that folds are reproducible
+	Seed int64
+}
+
+// Split partitions the observations with the specified labels into K folds.  Without stratification, only the
+// number of labels is significant and the observations are divided into K contiguous (after any shuffling) test
+// sets of as near equal size as possible.  The indices within the training and test sets of each fold are in
+// ascending order.
+func (k KFold) Split(labels []float64) []Fold {
+	if k.K < 2 || k.K > len(labels) {
+		panic("datautils: number of folds must be between 2 and the number of observations")
+	}
+
+	var rnd *rand.Rand
+	if k.Shuffle {
+		rnd = rand.New(rand.NewSource(k.Seed))
+	}
+	shuffle := func(ind []int) {
+		if rnd != nil {
+			rnd.Shuffle(len(ind), func(i, j int) { ind[i], ind[j] = ind[j], ind[i] })
+		}
+	}
+
+	assignment := make([]int, len(labels))
+
+	if k.Stratify {
+		// deal the observations of each class in turn across the folds so that each fold receives
+		// (as near as possible) the same number of observations of each class
+		var pos int
+		for _, class := range classIndices(labels) {
+			shuffle(class)
+			for _, v := range class {
+				assignment[v] = pos % k.K
+				pos++
+			}
+		}
+	} else {
+		ind := allIndices(len(labels))
+		shuffle(ind)
+		size, remainder := len(ind)/k.K, len(ind)%k.K
+		var start int
+		for f := 0; f < k.K; f++ {
+			end := start + size
+			if f < remainder {
+				end++
+			}
+			for _, v := range ind[start:end] {
+				assignment[v] = f
+			}
+			start = end
+		}
+	}
+
+	folds := make([]Fold, k.K)
+	for i, f := range assignment {
+		for j := range folds {
+			if j == f {
+				folds[j].Test = append(folds[j].Test, i)
+			} else {
+				folds[j].Train = append(folds[j].Train, i)
+			}
+		}
+	}
+	return folds
+}
+
+// CrossValidationScore summarises the values of a metric across the folds of a cross-validation.
+type CrossValidationScore struct {
+	// Scores contains the value of the metric for each fold
+	Scores []float64
+
+	// Mean and StdDev are the mean and (sample) standard deviation of the metric across the folds
+	Mean, StdDev float64
+}
+
+// CrossValidate runs the supplied evaluate function for each of the specified folds and aggregates the
+// resulting metrics.  The evaluate function would typically train a model using the training set of the
+// fold and return one or more metrics, keyed by name, evaluated using the predictions of the model for the
+// test set.  The returned map contains a summary of each named metric across all the folds.
+func CrossValidate(folds []Fold, evaluate func(fold Fold) map[string]float64) map[string]CrossValidationScore {
+	scores := make(map[string][]float64)
+	for _, fold := range folds {
+		for name, v := range evaluate(fold) {
+			scores[name] = append(scores[name], v)
+		}
+	}
+
+	results := make(map[string]CrossValidationScore, len(scores))
+	for name, s := range scores {
+		mean, std := stat.MeanStdDev(s, nil)
+		results[name] = CrossValidationScore{Scores: s, Mean: mean, StdDev: std}
+	}
+	return results
+}
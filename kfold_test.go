@@ -0,0 +1,76 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestKFoldSplit(t *testing.T) {
+	labels := []float64{0, 0, 0, 0, 1, 1, 1, 1, 1, 1, 1}
+
+	tests := []struct {
+		kfold     datautils.KFold
+		testSizes []int
+	}{
+		{kfold: datautils.KFold{K: 3}, testSizes: []int{4, 4, 3}},
+		{kfold: datautils.KFold{K: 3, Shuffle: true, Seed: 5}, testSizes: []int{4, 4, 3}},
+		{kfold: datautils.KFold{K: 2, Stratify: true}, testSizes: []int{6, 5}},
+		{kfold: datautils.KFold{K: 4, Shuffle: true, Stratify: true, Seed: 2}, testSizes: []int{3, 3, 3, 2}},
+	}
+
+	for i, test := range tests {
+		folds := test.kfold.Split(labels)
+		if len(folds) != test.kfold.K {
+			t.Errorf("Test %d: Expected %d folds but received %d", i+1, test.kfold.K, len(folds))
+		}
+
+		seen := make(map[int]int)
+		for f, fold := range folds {
+			if len(fold.Test) != test.testSizes[f] {
+				t.Errorf("Test %d: Expected fold %d test size %d but received %d", i+1, f, test.testSizes[f], len(fold.Test))
+			}
+			if len(fold.Train)+len(fold.Test) != len(labels) {
+				t.Errorf("Test %d: Expected fold %d to cover all %d observations", i+1, f, len(labels))
+			}
+			for _, v := range fold.Test {
+				seen[v]++
+			}
+			if test.kfold.Stratify {
+				var pos int
+				for _, v := range fold.Test {
+					pos += int(labels[v])
+				}
+				if neg := len(fold.Test) - pos; neg < 4/test.kfold.K || neg > 4/test.kfold.K+1 {
+					t.Errorf("Test %d: Expected fold %d to preserve class balance but received %d negatives", i+1, f, neg)
+				}
+			}
+		}
+		for v := range labels {
+			if seen[v] != 1 {
+				t.Errorf("Test %d: Expected observation %d in exactly one test set but found in %d", i+1, v, seen[v])
+			}
+		}
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	folds := datautils.KFold{K: 4}.Split(make([]float64, 8))
+
+	results := datautils.CrossValidate(folds, func(fold datautils.Fold) map[string]float64 {
+		return map[string]float64{
+			"size":  float64(len(fold.Test)),
+			"first": float64(fold.Test[0]),
+		}
+	})
+
+	size := results["size"]
+	if size.Mean != 2 || size.StdDev != 0 || len(size.Scores) != 4 {
+		t.Errorf("Expected size mean 2 and std dev 0 across 4 folds but received %+v", size)
+	}
+	first := results["first"]
+	if first.Mean != 3 || math.Abs(first.StdDev-math.Sqrt(20.0/3.0)) > 0.000001 {
+		t.Errorf("Expected first mean 3 and std dev %v but received %+v", math.Sqrt(20.0/3.0), first)
+	}
+}
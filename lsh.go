@@ -0,0 +1,154 @@
+package datautils
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// LSH configures a locality sensitive hashing index for approximate nearest neighbour retrieval (see LSHIndex).
+// Each hash table assigns every item of the corpus to a bucket according to which side of Bits random hyperplanes
+// through the origin the item lies (Charikar, 2002).  Items at a small angle to each other are likely to fall on
+// the same side of every hyperplane and so share a bucket, making the index suited to cosine similarity and, for
+// embeddings normalised to unit length, the dot product.  More bits produce smaller buckets, and so faster
+// searches, at the cost of more similar items falling into different buckets.  More tables or probes recover those
+// items at the cost of examining more candidates.
+type LSH struct {
+	// Tables is the number of independent hash tables
+	Tables int
+
+	// Bits is the number of random hyperplanes (between 1 and 64) used to hash items in each table
+	Bits int
+
+	// Probes is the number of additional buckets of each table to search beyond the query's own bucket (between
+	// 0 and Bits).  The additional buckets are those differing from the query's bucket by a single hyperplane,
+	// probed in order of the query's proximity to the hyperplane (Lv et al., 2007).
+	Probes int
+
+	// Seed is used to initialise the random number generator so that the hyperplanes are reproducible
+	Seed int64
+}
+
+// LSHIndex is a Retriever performing approximate nearest neighbour retrieval using locality sensitive hashing.
+// Rather than comparing a query with every item of the corpus, as ExactSearch does, only the items sharing a
+// bucket with the query in one of the index's hash tables are compared and ranked using the similarity function.
+// Retrieval is therefore much faster for large corpora but may miss some of the most similar items.  An LSHIndex is
+// safe for concurrent use.
+type LSHIndex struct {
+	config     LSH
+	rows       [][]float64
+	similarity SimilarityFunc
+	planes     [][][]float64
+	tables     []map[uint64][]int
+}
+
+// Index builds an LSHIndex for the corpus, containing the embedding of each item as a row, ranking the candidate
+// items retrieved for each query with the specified similarity function.  The hash tables are built concurrently.
+func (l LSH) Index(corpus mat.Matrix, similarity SimilarityFunc) *LSHIndex {
+	if l.Tables < 1 {
+		panic("datautils: number of hash tables must be at least 1")
+	}
+	if l.Bits < 1 || l.Bits > 64 {
+		panic("datautils: number of bits must be between 1 and 64")
+	}
+	if l.Probes < 0 || l.Probes > l.Bits {
+		panic("datautils: number of probes must be between 0 and the number of bits")
+	}
+
+	r, c := corpus.Dims()
+	rnd := rand.New(rand.NewSource(l.Seed))
+	planes := make([][][]float64, l.Tables)
+	for t := range planes {
+		planes[t] = make([][]float64, l.Bits)
+		for b := range planes[t] {
+			planes[t][b] = make([]float64, c)
+			for j := range planes[t][b] {
+				planes[t][b][j] = rnd.NormFloat64()
+			}
+		}
+	}
+
+	rows := make([][]float64, r)
+	for i := range rows {
+		rows[i] = mat.Row(make([]float64, c), i, corpus)
+	}
+
+	x := &LSHIndex{
+		config:     l,
+		rows:       rows,
+		similarity: similarity,
+		planes:     planes,
+		tables:     make([]map[uint64][]int, l.Tables),
+	}
+	bounds := chunks(l.Tables, runtime.GOMAXPROCS(0))
+	parallelFor(len(bounds), func(b int) {
+		margins := make([]float64, l.Bits)
+		for t := bounds[b][0]; t < bounds[b][1]; t++ {
+			table := make(map[uint64][]int)
+			for i, row := range rows {
+				h := x.hash(t, row, margins)
+				table[h] = append(table[h], i)
+			}
+			x.tables[t] = table
+		}
+	})
+	return x
+}
+
+// hash returns the bucket of table t for v, storing the signed distance of v from each of the table's hyperplanes
+// in margins.
+func (x *LSHIndex) hash(t int, v []float64, margins []float64) uint64 {
+	if len(v) != len(x.planes[t][0]) {
+		panic(mat.ErrShape)
+	}
+	var h uint64
+	for b, plane := range x.planes[t] {
+		margins[b] = floats.Dot(plane, v)
+		if margins[b] >= 0 {
+			h |= 1 << uint(b)
+		}
+	}
+	return h
+}
+
+// Search returns (at most) the k items most similar to the query, of those sharing a probed bucket with the query
+// in any of the index's hash tables, in descending order of similarity, ties ordered by ascending row index.  Fewer
+// than k items are returned if fewer candidates are found.
+func (x *LSHIndex) Search(query []float64, k int) []Neighbour {
+	if k < 1 {
+		panic(ErrOutOfBounds)
+	}
+
+	seen := make(map[int]struct{})
+	h := make(neighbourHeap, 0, k)
+	margins := make([]float64, x.config.Bits)
+	order := make([]int, x.config.Bits)
+	for t, table := range x.tables {
+		bucket := x.hash(t, query, margins)
+
+		// probe the buckets across the hyperplanes nearest the query first
+		for b := range order {
+			order[b] = b
+		}
+		sort.Slice(order, func(i, j int) bool { return math.Abs(margins[order[i]]) < math.Abs(margins[order[j]]) })
+
+		for p := 0; p <= x.config.Probes; p++ {
+			probe := bucket
+			if p > 0 {
+				probe ^= 1 << uint(order[p-1])
+			}
+			for _, i := range table[probe] {
+				if _, ok := seen[i]; ok {
+					continue
+				}
+				seen[i] = struct{}{}
+				h.offer(Neighbour{Index: i, Score: x.similarity(query, x.rows[i])}, k)
+			}
+		}
+	}
+	return h.sorted()
+}
@@ -0,0 +1,113 @@
+package datautils_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+// randomEmbeddings returns an r x c matrix of normally distributed values.
+func randomEmbeddings(rnd *rand.Rand, r, c int) *mat.Dense {
+	data := make([]float64, r*c)
+	for i := range data {
+		data[i] = rnd.NormFloat64()
+	}
+	return mat.NewDense(r, c, data)
+}
+
+func TestLSHIndexSearch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	corpus := randomEmbeddings(rnd, 2000, 16)
+	index := datautils.LSH{Tables: 8, Bits: 8, Probes: 2, Seed: 1}.Index(corpus, datautils.CosineSimilarity)
+	exact := datautils.ExactSearch{Corpus: corpus, Similarity: datautils.CosineSimilarity}
+
+	// queries close to items of the corpus should retrieve those items first
+	queries := mat.NewDense(50, 16, nil)
+	for i := 0; i < 50; i++ {
+		row := corpus.RawRowView(i * 40)
+		for j, v := range row {
+			queries.Set(i, j, v+rnd.NormFloat64()*0.05)
+		}
+	}
+
+	var found, total int
+	for i := 0; i < 50; i++ {
+		query := queries.RawRowView(i)
+		approx := index.Search(query, 10)
+		if len(approx) == 0 || approx[0].Index != i*40 {
+			t.Errorf("Query %d: Expected nearest neighbour %d but received %v", i, i*40, approx)
+		}
+		for j := 1; j < len(approx); j++ {
+			if approx[j].Score > approx[j-1].Score {
+				t.Errorf("Query %d: Expected neighbours in descending order of similarity but received %v", i, approx)
+			}
+		}
+
+		inApprox := make(map[int]bool)
+		for _, n := range approx {
+			inApprox[n.Index] = true
+		}
+		for _, n := range exact.Search(query, 10) {
+			if inApprox[n.Index] {
+				found++
+			}
+			total++
+		}
+	}
+	if recall := float64(found) / float64(total); recall < 0.7 {
+		t.Errorf("Expected approximate search to find at least 70%% of the exact neighbours but found %v", recall)
+	}
+}
+
+func TestLSHIndexProbes(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	corpus := randomEmbeddings(rnd, 1000, 8)
+	queries := randomEmbeddings(rnd, 20, 8)
+
+	// probing more buckets examines a superset of the candidates so cannot reduce similarity
+	narrow := datautils.LSH{Tables: 2, Bits: 10, Seed: 3}.Index(corpus, datautils.CosineSimilarity)
+	wide := datautils.LSH{Tables: 2, Bits: 10, Probes: 10, Seed: 3}.Index(corpus, datautils.CosineSimilarity)
+	for i := 0; i < 20; i++ {
+		n, w := narrow.Search(queries.RawRowView(i), 5), wide.Search(queries.RawRowView(i), 5)
+		if len(w) < len(n) {
+			t.Fatalf("Query %d: Expected at least %d neighbours with probing but received %d", i, len(n), len(w))
+		}
+		for j := range n {
+			if w[j].Score < n[j].Score {
+				t.Errorf("Query %d: Expected probing not to reduce similarity of neighbour %d but received %v < %v", i, j, w[j].Score, n[j].Score)
+			}
+		}
+	}
+}
+
+func TestLSHIndexEvaluateRetrieval(t *testing.T) {
+	index := datautils.LSH{Tables: 4, Bits: 2, Probes: 2, Seed: 1}.Index(corpusEmbeddings, datautils.CosineSimilarity)
+	qrels := datautils.Qrels{"0": {"0": 1}, "1": {"1": 1}}
+
+	eval := datautils.EvaluateRetrieval(queryEmbeddings, nil, index, nil, qrels, 1)
+	if eval.Recall != 1 || eval.MRR != 1 || eval.NDCG != 1 {
+		t.Errorf("Unexpected evaluation of approximate retrieval: %+v", eval)
+	}
+}
+
+func TestLSHConfigValidation(t *testing.T) {
+	tests := []datautils.LSH{
+		{Tables: 0, Bits: 8},
+		{Tables: 1, Bits: 0},
+		{Tables: 1, Bits: 65},
+		{Tables: 1, Bits: 8, Probes: 9},
+	}
+
+	for i, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Test %d: Expected panic but received none", i+1)
+				}
+			}()
+			test.Index(corpusEmbeddings, datautils.CosineSimilarity)
+		}()
+	}
+}
@@ -2,8 +2,9 @@ package datautils
 
 import (
 	"fmt"
-	"image/color"
 	"math"
+	"sort"
+	"strconv"
 
 	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/plot"
@@ -22,32 +23,51 @@ type RankingEvaluation struct {
 	// Ground truth relevancy values in original ordering
 	Relevancies []float64
 
+	// Predicted relevancy values (e.g. probabilities/similarity scores) in original ordering
+	Predictions []float64
+
 	// ranked indexes of relevancy values, ranked according to predicted relevancy/probabilty values
 	PredictedRankInd []int
 
 	// ranked indexes of relevancy values, ranked according to ground truth relevancy values (a perfect ranking)
 	PerfectRankInd []int
+
+	// Unretrieved contains the relevancy values of relevant items that were not ranked at all e.g. relevant
+	// documents a retrieval system failed to return.  They take no part in the ranking but count towards the
+	// total number of relevant items for recall and average precision and towards the perfect ranking used to
+	// normalise discounted cumulative gain
+	Unretrieved []float64
 }
 
 // NewRankingEvaluation creates a new RankingEvaluation type from the specified predicted
 // relevancies (predictions) and ground truth relevancy values (labels).  The ordering
-// of both slices must correspond and the lengths must match.
-func NewRankingEvaluation(predictions, labels []float64) RankingEvaluation {
-	if len(predictions) != len(labels) {
-		panic("Prediction/Label length mismatch")
+// of both slices must correspond and the lengths must match (see ValidateLengths).  The predictions and labels
+// may be of any Float type.  []float64 slices are retained by the evaluation as its Predictions and Relevancies
+// while other types are converted to new []float64 slices.  NaN predictions are ranked last.
+func NewRankingEvaluation[P, L Float](predictions []P, labels []L) RankingEvaluation {
+	return must(NewRankingEvaluationE(predictions, labels))
+}
+
+// NewRankingEvaluationE creates a new RankingEvaluation in the same way as NewRankingEvaluation but returns
+// ErrLengthMismatch, rather than panicking, if the lengths of the predictions and labels do not match.
+func NewRankingEvaluationE[P, L Float](predictions []P, labels []L) (RankingEvaluation, error) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		return RankingEvaluation{}, err
 	}
 
-	thresholds := make([]float64, len(predictions))
-	predInd := make([]int, len(predictions))
-	relevance := make([]float64, len(labels))
-	perfInd := make([]int, len(labels))
+	return rankingEvaluation(float64Values(predictions), float64Values(labels), make([]float64, len(predictions)), make([]int, len(predictions)), make([]int, len(labels))), nil
+}
 
+// rankingEvaluation creates a new RankingEvaluation storing the predicted and perfect rankings in predInd and
+// perfInd respectively.  scratch is used to sort first the predictions and then the labels so its contents are
+// overwritten.  All three slices must be the same length as the predictions.
+func rankingEvaluation(predictions, labels, scratch []float64, predInd, perfInd []int) RankingEvaluation {
 	// rank predictions/similarities
-	copy(thresholds, predictions)
-	floats.Argsort(thresholds, predInd)
+	copy(scratch, predictions)
+	argsort(scratch, predInd)
 
-	copy(relevance, labels)
-	floats.Argsort(relevance, perfInd)
+	copy(scratch, labels)
+	argsort(scratch, perfInd)
 
 	// reverse order so highest similarity/probability is ranked higher/first
 	reverse(predInd)
@@ -55,6 +75,7 @@ func NewRankingEvaluation(predictions, labels []float64) RankingEvaluation {
 
 	return RankingEvaluation{
 		Relevancies:      labels,
+		Predictions:      predictions,
 		PredictedRankInd: predInd,
 		PerfectRankInd:   perfInd,
 	}
@@ -64,14 +85,20 @@ func NewRankingEvaluation(predictions, labels []float64) RankingEvaluation {
 // gain or sum of relevancy values at each rank up to the kth ranked item.  Where k is the cut-off
 // (specify len(Relevancies) for ALL items/no cut-off).
 func (r RankingEvaluation) CumulativeGain(k int) float64 {
-	if k < 1 || k > len(r.Relevancies) {
-		panic("index k is out of bounds")
+	return must(r.CumulativeGainE(k))
+}
+
+// CumulativeGainE calculates the cumulative gain in the same way as CumulativeGain but returns ErrOutOfBounds,
+// rather than panicking, if k is not a valid cut-off (see ValidateCutoff).
+func (r RankingEvaluation) CumulativeGainE(k int) (float64, error) {
+	if err := r.ValidateCutoff(k); err != nil {
+		return 0, err
 	}
 	var sum float64
 	for _, v := range r.PredictedRankInd[:k] {
 		sum += r.Relevancies[v]
 	}
-	return sum
+	return sum, nil
 }
 
 // TraditionalRelevancy is the traditional formulation of the relevancy function for calculating discounted
@@ -103,12 +130,19 @@ func (r RankingEvaluation) discountedCumulativeGain(k int, rankings []int, rel R
 // discounted according to rank so that relevancy values at lower ranks are more heavily discounted and therefore
 // contribute less to the sum.  Where k is the cut-off (specify len(Relevancies) for ALL items/no
 // cut-off) and rel is the relevancy function to use.  See TraditionalRelevancy and EmphasisedRelevancy for
-// two popular formulations of the relevancy function - either of which may be specified for this parameter.
+// two popular formulations of the relevancy function - either of which may be specified for this parameter.  See
+// DiscountedCumulativeGainWith to use an alternative to the logarithmic rank discount.
 func (r RankingEvaluation) DiscountedCumulativeGain(k int, rel RelevancyFunction) float64 {
-	if k < 1 || k > len(r.Relevancies) {
-		panic("index k is out of bounds")
+	return must(r.DiscountedCumulativeGainE(k, rel))
+}
+
+// DiscountedCumulativeGainE calculates the discounted cumulative gain in the same way as
+// DiscountedCumulativeGain but returns ErrOutOfBounds, rather than panicking, if k is not a valid cut-off.
+func (r RankingEvaluation) DiscountedCumulativeGainE(k int, rel RelevancyFunction) (float64, error) {
+	if err := r.ValidateCutoff(k); err != nil {
+		return 0, err
 	}
-	return r.discountedCumulativeGain(k, r.PredictedRankInd, rel)
+	return r.discountedCumulativeGain(k, r.PredictedRankInd, rel), nil
 }
 
 // NormalisedDiscountedCumulativeGain calculates the normalised discounted cumulative gain for the ranking.
@@ -117,14 +151,286 @@ func (r RankingEvaluation) DiscountedCumulativeGain(k int, rel RelevancyFunction
 // cut-off) and rel is the relevancy function to use.  See TraditionalRelevancy and EmphasisedRelevancy for
 // two popular formulations of the relevancy function - either of which may be specified for this parameter.
 func (r RankingEvaluation) NormalisedDiscountedCumulativeGain(k int, rel RelevancyFunction) float64 {
-	if k < 1 || k > len(r.Relevancies) {
-		panic("index k is out of bounds")
+	return must(r.NormalisedDiscountedCumulativeGainE(k, rel))
+}
+
+// NormalisedDiscountedCumulativeGainE calculates the normalised discounted cumulative gain in the same way as
+// NormalisedDiscountedCumulativeGain but returns ErrOutOfBounds, rather than panicking, if k is not a valid
+// cut-off.
+func (r RankingEvaluation) NormalisedDiscountedCumulativeGainE(k int, rel RelevancyFunction) (float64, error) {
+	if err := r.ValidateCutoff(k); err != nil {
+		return 0, err
+	}
+	if r.noRelevant() {
+		// no relevant items so the DCG of any ranking will match a perfect ordering
+		return 1.0, nil
+	}
+	return r.discountedCumulativeGain(k, r.PredictedRankInd, rel) / r.idealDiscountedCumulativeGains(k, rel)[k-1], nil
+}
+
+// noRelevant returns true if there are no relevant items, ranked or unretrieved.
+func (r RankingEvaluation) noRelevant() bool {
+	return len(r.Unretrieved) == 0 && (len(r.Relevancies) == 0 || floats.Max(r.Relevancies) == 0)
+}
+
+// idealRelevancies returns the relevancy values of the top k items of a perfect ranking of both the ranked and
+// the unretrieved items in descending order.
+func (r RankingEvaluation) idealRelevancies(k int) []float64 {
+	unretrieved := r.Unretrieved
+	if len(unretrieved) > 0 {
+		unretrieved = append([]float64(nil), unretrieved...)
+		sort.Sort(sort.Reverse(sort.Float64Slice(unretrieved)))
+	}
+
+	ideal := make([]float64, 0, k)
+	perfect := r.PerfectRankInd
+	for len(ideal) < k && (len(perfect) > 0 || len(unretrieved) > 0) {
+		if len(unretrieved) == 0 || (len(perfect) > 0 && r.Relevancies[perfect[0]] >= unretrieved[0]) {
+			ideal = append(ideal, r.Relevancies[perfect[0]])
+			perfect = perfect[1:]
+		} else {
+			ideal = append(ideal, unretrieved[0])
+			unretrieved = unretrieved[1:]
+		}
+	}
+	return ideal
+}
+
+// idealDiscountedCumulativeGains returns the discounted cumulative gain of a perfect ranking (see
+// idealRelevancies) at every cut-off from 1 to k.
+func (r RankingEvaluation) idealDiscountedCumulativeGains(k int, rel RelevancyFunction) []float64 {
+	gains := r.idealRelevancies(k)
+	var sum float64
+	for i, v := range gains {
+		sum += rel(v) / math.Log2(float64(i+2))
+		gains[i] = sum
+	}
+	return gains
+}
+
+// NormalisedDiscountedCumulativeGains calculates the normalised discounted cumulative gain at each of the
+// specified cut-offs in a single pass over the ranking, returning the values in the same order as cutoffs.  This
+// is equivalent to, but more efficient than, calling NormalisedDiscountedCumulativeGain for each cut-off in turn.
+// Each cut-off must be valid (see ValidateCutoff) and rel is the relevancy function to use.
+func (r RankingEvaluation) NormalisedDiscountedCumulativeGains(cutoffs []int, rel RelevancyFunction) []float64 {
+	return must(r.NormalisedDiscountedCumulativeGainsE(cutoffs, rel))
+}
+
+// NormalisedDiscountedCumulativeGainsE calculates the normalised discounted cumulative gains in the same way as
+// NormalisedDiscountedCumulativeGains but returns ErrOutOfBounds, rather than panicking, if any of the cut-offs
+// are invalid.
+func (r RankingEvaluation) NormalisedDiscountedCumulativeGainsE(cutoffs []int, rel RelevancyFunction) ([]float64, error) {
+	var max int
+	for _, k := range cutoffs {
+		if err := r.ValidateCutoff(k); err != nil {
+			return nil, err
+		}
+		if k > max {
+			max = k
+		}
+	}
+
+	ndcg := make([]float64, len(cutoffs))
+	if r.noRelevant() {
+		// no relevant items so the DCG of any ranking will match a perfect ordering
+		for i := range ndcg {
+			ndcg[i] = 1.0
+		}
+		return ndcg, nil
+	}
+
+	dcg := r.cumulativeDiscountedGains(max, r.PredictedRankInd, rel)
+	idcg := r.idealDiscountedCumulativeGains(max, rel)
+	for i, k := range cutoffs {
+		ndcg[i] = dcg[k-1] / idcg[k-1]
+	}
+	return ndcg, nil
+}
+
+// cumulativeDiscountedGains returns the discounted cumulative gain at every cut-off from 1 to k.
+func (r RankingEvaluation) cumulativeDiscountedGains(k int, rankings []int, rel RelevancyFunction) []float64 {
+	gains := make([]float64, k)
+	var sum float64
+	for i, v := range rankings[:k] {
+		sum += rel(r.Relevancies[v]) / math.Log2(float64(i+2))
+		gains[i] = sum
+	}
+	return gains
+}
+
+// tieAwareDiscountedCumulativeGain calculates the expected discounted cumulative gain, at cut-off k, over all
+// possible orderings of items with tied predictions.  For each group of items with tied predictions, the mean
+// gain of the group is applied at each of the ranks occupied by the group.
+func (r RankingEvaluation) tieAwareDiscountedCumulativeGain(k int, rel RelevancyFunction) float64 {
+	tied := func(i, j int) bool {
+		return r.Predictions != nil && r.Predictions[r.PredictedRankInd[i]] == r.Predictions[r.PredictedRankInd[j]]
+	}
+
+	var sum float64
+	for start := 0; start < k; {
+		end := start + 1
+		for end < len(r.PredictedRankInd) && tied(start, end) {
+			end++
+		}
+		var gain, discount float64
+		for i := start; i < end; i++ {
+			gain += rel(r.Relevancies[r.PredictedRankInd[i]])
+			if i < k {
+				discount += 1 / math.Log2(float64(i+2))
+			}
+		}
+		sum += gain / float64(end-start) * discount
+		start = end
+	}
+	return sum
+}
+
+// TieAwareDiscountedCumulativeGain calculates the discounted cumulative gain for the ranking in the same way
+// as DiscountedCumulativeGain but accounting for ties in the predictions.  Where several items have the same
+// predicted relevancy, their relative order in the ranking is arbitrary and so DiscountedCumulativeGain may
+// vary depending upon how the ties happen to be broken.  TieAwareDiscountedCumulativeGain instead returns the
+// expected discounted cumulative gain over all possible orderings of tied items (McSherry & Najork, 2008) which
+// is deterministic.
+func (r RankingEvaluation) TieAwareDiscountedCumulativeGain(k int, rel RelevancyFunction) float64 {
+	return must(r.TieAwareDiscountedCumulativeGainE(k, rel))
+}
+
+// TieAwareDiscountedCumulativeGainE calculates the tie aware discounted cumulative gain in the same way as
+// TieAwareDiscountedCumulativeGain but returns ErrOutOfBounds, rather than panicking, if k is not a valid cut-off.
+func (r RankingEvaluation) TieAwareDiscountedCumulativeGainE(k int, rel RelevancyFunction) (float64, error) {
+	if err := r.ValidateCutoff(k); err != nil {
+		return 0, err
+	}
+	return r.tieAwareDiscountedCumulativeGain(k, rel), nil
+}
+
+// TieAwareNormalisedDiscountedCumulativeGain calculates the normalised discounted cumulative gain for the
+// ranking in the same way as NormalisedDiscountedCumulativeGain but using the expected discounted cumulative
+// gain over all possible orderings of items with tied predictions (see TieAwareDiscountedCumulativeGain).
+func (r RankingEvaluation) TieAwareNormalisedDiscountedCumulativeGain(k int, rel RelevancyFunction) float64 {
+	return must(r.TieAwareNormalisedDiscountedCumulativeGainE(k, rel))
+}
+
+// TieAwareNormalisedDiscountedCumulativeGainE calculates the tie aware normalised discounted cumulative gain in
+// the same way as TieAwareNormalisedDiscountedCumulativeGain but returns ErrOutOfBounds, rather than panicking,
+// if k is not a valid cut-off.
+func (r RankingEvaluation) TieAwareNormalisedDiscountedCumulativeGainE(k int, rel RelevancyFunction) (float64, error) {
+	if err := r.ValidateCutoff(k); err != nil {
+		return 0, err
 	}
-	if floats.Max(r.Relevancies) == 0 {
+	if r.noRelevant() {
 		// no relevant items so the DCG of any ranking will match a perfect ordering
-		return 1.0
+		return 1.0, nil
+	}
+	return r.tieAwareDiscountedCumulativeGain(k, rel) / r.idealDiscountedCumulativeGains(k, rel)[k-1], nil
+}
+
+// ReciprocalRank calculates the reciprocal rank for the ranking.  This is the multiplicative inverse of the rank
+// of the first relevant item i.e. 1 if the first relevant item is ranked first, 0.5 if ranked second, etc.  As with
+// average precision, any relevancy value greater than 0 is considered relevant.  If there are no relevant items
+// then the reciprocal rank is 0.
+func (r RankingEvaluation) ReciprocalRank() float64 {
+	for i, v := range r.PredictedRankInd {
+		if r.Relevancies[v] > 0 {
+			return 1 / float64(i+1)
+		}
+	}
+	return 0
+}
+
+// HitAt returns 1 if any relevant item appears within the top k ranked items, otherwise 0.  As with average
+// precision, any relevancy value greater than 0 is considered relevant.  Averaged across a set of queries this
+// gives the hit rate (see EvaluationSet.HitRate).
+func (r RankingEvaluation) HitAt(k int) float64 {
+	return must(r.HitAtE(k))
+}
+
+// HitAtE is equivalent to HitAt but returns ErrOutOfBounds, rather than panicking, if k is not a valid cut-off.
+func (r RankingEvaluation) HitAtE(k int) (float64, error) {
+	if err := r.ValidateCutoff(k); err != nil {
+		return 0, err
+	}
+	for _, v := range r.PredictedRankInd[:k] {
+		if r.Relevancies[v] > 0 {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// RecallAt returns the proportion of all the relevant items that appear within the top k ranked items.  As with
+// average precision, any relevancy value greater than 0 is considered relevant.  If there are no relevant items
+// then the recall is 0.  Unretrieved relevant items are counted as relevant items that were not found.
+func (r RankingEvaluation) RecallAt(k int) float64 {
+	return must(r.RecallAtE(k))
+}
+
+// RecallAtE is equivalent to RecallAt but returns ErrOutOfBounds, rather than panicking, if k is not a valid
+// cut-off.
+func (r RankingEvaluation) RecallAtE(k int) (float64, error) {
+	if err := r.ValidateCutoff(k); err != nil {
+		return 0, err
+	}
+	relevant, hits := len(r.Unretrieved), 0
+	for i, v := range r.PredictedRankInd {
+		if r.Relevancies[v] > 0 {
+			relevant++
+			if i < k {
+				hits++
+			}
+		}
+	}
+	if relevant == 0 {
+		return 0, nil
+	}
+	return float64(hits) / float64(relevant), nil
+}
+
+// AveragePrecisionAt calculates the average precision at cut-off k (AP@k) for the ranking using the specified
+// relevance threshold to convert graded relevancy values to binary relevance.  Items with a relevancy value
+// greater than or equal to threshold are considered relevant (in keeping with trec_eval) so, for integer graded
+// relevance, a threshold of 1 matches the rule used by PrecisionRecallCurve of any value greater than 0 being
+// relevant.  AP@k is the sum of the precision at the rank of each relevant item within the top k, divided by the
+// number of relevant items (including Unretrieved items) or k, whichever is smaller, so that a perfect ranking
+// scores 1.  If there are no relevant items then the average precision is 0.
+func (r RankingEvaluation) AveragePrecisionAt(k int, threshold float64) float64 {
+	return must(r.AveragePrecisionAtE(k, threshold))
+}
+
+// AveragePrecisionAtE calculates AP@k in the same way as AveragePrecisionAt but returns ErrOutOfBounds, rather
+// than panicking, if k is not a valid cut-off.
+func (r RankingEvaluation) AveragePrecisionAtE(k int, threshold float64) (float64, error) {
+	if err := r.ValidateCutoff(k); err != nil {
+		return 0, err
+	}
+
+	var relevant int
+	for _, v := range r.Relevancies {
+		if v >= threshold {
+			relevant++
+		}
 	}
-	return r.discountedCumulativeGain(k, r.PredictedRankInd, rel) / r.discountedCumulativeGain(k, r.PerfectRankInd, rel)
+	for _, v := range r.Unretrieved {
+		if v >= threshold {
+			relevant++
+		}
+	}
+	if relevant == 0 {
+		return 0, nil
+	}
+
+	var sum float64
+	var hits int
+	for i, v := range r.PredictedRankInd[:k] {
+		if r.Relevancies[v] >= threshold {
+			hits++
+			sum += float64(hits) / float64(i+1)
+		}
+	}
+	if relevant > k {
+		relevant = k
+	}
+	return sum / float64(relevant), nil
 }
 
 // PrecisionRecallCurve represents a precision recall curve for visualising and measuring the performance of a
@@ -158,52 +464,72 @@ type PrecisionRecallCurve struct {
 // ground truth labels[5].  As Precision Recall curves and average precision (summarising the curve as a single
 // metric/area under the curve) represent a binary class/relevance measure we assume that any label value greater
 // than 0 represents a positive/relative observation (and 0 label values represent a negative/non-relevant
-// observation).
-func NewPrecisionRecallCurve(predictions, labels []float64) PrecisionRecallCurve {
-	if len(predictions) != len(labels) {
-		panic("Prediction/Label length mismatch")
-	}
+// observation).  NaN predictions are ranked last so that the curve does not depend on the order of the input (see
+// HandleNonFinite to drop or reject them instead).  The predictions and labels may be of any Float type e.g.
+// []float32 model outputs.
+func NewPrecisionRecallCurve[P, L Float](predictions []P, labels []L) PrecisionRecallCurve {
+	return must(NewPrecisionRecallCurveE(predictions, labels))
+}
 
-	thresholds := make([]float64, len(predictions))
-	recall := make([]float64, len(predictions))
-	precision := make([]float64, len(predictions))
-	ind := make([]int, len(predictions))
+// NewPrecisionRecallCurveE creates a new precision recall curve in the same way as NewPrecisionRecallCurve but
+// returns ErrLengthMismatch, rather than panicking, if the lengths of the predictions and labels do not match.
+func NewPrecisionRecallCurveE[P, L Float](predictions []P, labels []L) (PrecisionRecallCurve, error) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		return PrecisionRecallCurve{}, err
+	}
 
 	// count total positive/relevant observations from ground truth
-	// as floats.Norm() does not work for zero norm we will use floats.Count() instead
-	positives := floats.Count(func(x float64) bool {
-		return (x > 0)
-	}, labels)
+	var positives int
+	for _, v := range labels {
+		if v > 0 {
+			positives++
+		}
+	}
+
+	// rank predictions/similarities
+	var sorted []float64
+	var ind []int
+	if positives > 0 {
+		sorted, ind = sortedPredictions(predictions)
+	}
+	return precisionRecallCurve(sorted, ind, labels, positives, make([]float64, len(predictions)), make([]float64, len(predictions))), nil
+}
 
+// precisionRecallCurve creates a new PrecisionRecallCurve from the predictions sorted in ascending order along
+// with their original indices (as returned by sortedPredictions) and the labels containing the specified number
+// of positives.  The precision and recall at each rank are calculated into the supplied precision and recall
+// buffers which must be at least as long as the predictions.  The returned curve shares the underlying arrays of
+// the buffers and sorted.
+func precisionRecallCurve[L Float](sorted []float64, ind []int, labels []L, positives int, precision, recall []float64) PrecisionRecallCurve {
 	if positives == 0 {
 		return PrecisionRecallCurve{
 			Precision:  append(precision[:0], 1),
 			Recall:     append(recall[:0], 0),
-			Thresholds: thresholds[:0],
+			Thresholds: []float64{},
 			positives:  positives,
 		}
 	}
 
-	// rank predictions/similarities
-	copy(thresholds, predictions)
-	floats.Argsort(thresholds, ind)
-
-	var hits int
 	var k int
 
-	for i := len(ind) - 1; i >= 0; i-- {
-		// assume that any label value over 0 is positive/relevant.  Average precision works on a binary label
-		// but in some cases we may use non-binary/multi-class labels e.g. for degrees of relevancy in information
-		// retrieval
-		if labels[ind[i]] > 0 {
-			hits++
-		}
-		recall[k] = float64(hits) / float64(positives)
-		precision[k] = float64(hits) / float64(k+1)
-		if recall[k] == 1 {
-			break
+	if parallel(len(ind)) {
+		k = parallelPrecisionRecall(labels, ind, positives, precision, recall)
+	} else {
+		var hits int
+		for i := len(ind) - 1; i >= 0; i-- {
+			// assume that any label value over 0 is positive/relevant.  Average precision works on a binary label
+			// but in some cases we may use non-binary/multi-class labels e.g. for degrees of relevancy in information
+			// retrieval
+			if labels[ind[i]] > 0 {
+				hits++
+			}
+			recall[k] = float64(hits) / float64(positives)
+			precision[k] = float64(hits) / float64(k+1)
+			if recall[k] == 1 {
+				break
+			}
+			k++
 		}
-		k++
 	}
 	// truncate precision and recall to where the last relevant/positive item was ranked (recall==1)
 	precision = precision[:k+1]
@@ -216,24 +542,48 @@ func NewPrecisionRecallCurve(predictions, labels []float64) PrecisionRecallCurve
 	return PrecisionRecallCurve{
 		Precision:  append(precision, 1),
 		Recall:     append(recall, 0),
-		Thresholds: thresholds[len(thresholds)-k-1:],
+		Thresholds: sorted[len(sorted)-k-1:],
 		positives:  positives,
 	}
 }
 
 // Plot renders the entire precision recall curve as a plot for visualisation.
 func (c PrecisionRecallCurve) Plot() *plot.Plot {
-	p, err := plot.New()
+	return c.plot(nil)
+}
+
+// PlotWithBand renders the entire precision recall curve as a plot, in the same way as Plot, with the supplied
+// confidence band (see BootstrapPrecisionRecallBand) shaded around the curve to show the uncertainty of the curve
+// e.g. for small test sets.
+func (c PrecisionRecallCurve) PlotWithBand(band ConfidenceBand) *plot.Plot {
+	return c.plot(&band)
+}
+
+func (c PrecisionRecallCurve) plot(band *ConfidenceBand) *plot.Plot {
+	p, err := newPlot()
 	if err != nil {
 		panic(err)
 	}
 
 	ap := c.AveragePrecision()
 
-	p.Title.Text = fmt.Sprintf("Precision-recall Curve, AP=%f", ap)
+	p.Title.Text = PlotTheme.title(fmt.Sprintf("Precision-recall Curve, AP=%f", ap))
 	p.X.Label.Text = "Recall"
 	p.Y.Label.Text = "Precision"
 
+	if band != nil {
+		addBand(p, *band)
+	}
+
+	line := c.line()
+	line.Color = PlotTheme.Primary
+	p.Add(line)
+
+	return p
+}
+
+// line creates a line plotter for the curve.
+func (c PrecisionRecallCurve) line() *plotter.Line {
 	pts := make(plotter.XYs, len(c.Precision))
 	for i := range pts {
 		pts[i].X = c.Recall[i]
@@ -244,28 +594,97 @@ func (c PrecisionRecallCurve) Plot() *plot.Plot {
 	if err != nil {
 		panic(err)
 	}
-	line.Color = color.RGBA{R: 255, B: 128, A: 255}
-	p.Add(line)
+	return line
+}
+
+// PlotPrecisionRecallCurves renders several precision recall curves (e.g. for different models) overlaid on a
+// single plot for comparison.  The curves are keyed by name and each curve is drawn in a distinct colour with
+// a legend entry showing its name and average precision.
+func PlotPrecisionRecallCurves(curves map[string]PrecisionRecallCurve) *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+
+	p.Title.Text = PlotTheme.title("Precision-recall Curves")
+	p.X.Label.Text = "Recall"
+	p.Y.Label.Text = "Precision"
+
+	names := make([]string, 0, len(curves))
+	for name := range curves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		c := curves[name]
+		line := c.line()
+		line.Color = PlotTheme.color(i)
+		p.Add(line)
+		p.Legend.Add(fmt.Sprintf("%s (AP=%f)", name, c.AveragePrecision()), line)
+	}
 
 	return p
 }
 
 // AveragePrecision calculates the average precision based on the predictions and labels the curve was
 // constructed with.  Average Precision represents the area under the curve of the precision recall curve
-// and is a method for summarising the curve in a single metric.
+// and is a method for summarising the curve in a single metric.  It is calculated as the step-wise sum
+//
+//	AP = Σ (R_k - R_k-1) * P_k
+//
+// over all ranks k, where P_k and R_k are the precision and recall @ k.  This is equivalent to the mean of the
+// precision at the rank of each positive/relevant item and is the definition used by TREC and scikit-learn.
 func (c PrecisionRecallCurve) AveragePrecision() float64 {
-	//	var sum float64
+	var sum float64
+	for i := 0; i < len(c.Precision)-1; i++ {
+		sum += (c.Recall[i] - c.Recall[i+1]) * c.Precision[i]
+	}
+	return sum
+}
 
+// TrapezoidalAveragePrecision calculates the area under the precision recall curve using the trapezoidal rule
+//
+//	AUC-PR = Σ (R_k - R_k-1) * (P_k + P_k-1) / 2
+//
+// over all ranks k, including the point @ 0 where precision is 1 and recall is 0.  Linear interpolation between
+// points in precision recall space is known to be overly optimistic, so this variant will generally be higher
+// than AveragePrecision, but it is required for comparison with results reported as trapezoidal AUC-PR.
+func (c PrecisionRecallCurve) TrapezoidalAveragePrecision() float64 {
 	var sum float64
 	for i := 0; i < len(c.Precision)-1; i++ {
-		sum += (c.Recall[i+1] - c.Recall[i]) * c.Precision[i]
+		sum += (c.Recall[i] - c.Recall[i+1]) * (c.Precision[i] + c.Precision[i+1]) / 2
+	}
+	return sum
+}
+
+// InterpolatedAveragePrecision calculates the all-point interpolated average precision as used by PASCAL VOC
+// (2010 onwards)
+//
+//	AP = Σ (R_k - R_k-1) * max(P_j for all j where R_j >= R_k)
+//
+// over all ranks k.  Replacing the precision at each rank with the interpolated precision (the maximum precision
+// at any equal or higher recall) removes the "wiggles" from the curve so the result is always at least
+// AveragePrecision.  For the 11 point variant see AverageInterpolatedPrecision.
+func (c PrecisionRecallCurve) InterpolatedAveragePrecision() float64 {
+	var sum, max float64
+	// points are ordered by descending recall so the running maximum is the interpolated precision
+	for i := 0; i < len(c.Precision)-1; i++ {
+		if c.Precision[i] > max {
+			max = c.Precision[i]
+		}
+		sum += (c.Recall[i] - c.Recall[i+1]) * max
 	}
-	return -sum
+	return sum
 }
 
-// AverageInterpolatedPrecision calculates the average interpolated precision based on the predictions and labels
-// the curve was constructed with.  Average Interpolated Precision represents the area under the curve of the precision
-// recall curve using interpolated precision for 11 fixed recall values {0.0, 0.1, 0.2, ... 1.0}.
+// AverageInterpolatedPrecision calculates the 11 point average interpolated precision based on the predictions and
+// labels the curve was constructed with.  Average Interpolated Precision represents the area under the curve of the
+// precision recall curve using interpolated precision (see InterpolatedPrecisionAt) for 11 fixed recall values
+//
+//	AP = 1/11 * Σ InterpolatedPrecisionAt(r) for r in {0.0, 0.1, 0.2, ... 1.0}
+//
+// as used by PASCAL VOC prior to 2010.
 func (c PrecisionRecallCurve) AverageInterpolatedPrecision() float64 {
 	var sum float64
 	for i := 0; i <= 10; i++ {
@@ -285,14 +704,53 @@ func (c PrecisionRecallCurve) RPrecision() float64 {
 // if a search returns 10 (k=10) results what is the proportion of those 10 results that are relevant or
 // if we are only interested in the relevancy of the top ranked item (k=1) is that item relevant or not.
 func (c PrecisionRecallCurve) PrecisionAt(k int) float64 {
-	return c.Precision[len(c.Precision)-1-k]
+	return must(c.MetricsAt(k)).Precision
+}
+
+// RecallAt calculates the Recall@k.  This represents the proportion of all positive/relevant items that are
+// ranked within the top k.  As with PrecisionAt, k must lie within the range accepted by ValidateCutoff.
+func (c PrecisionRecallCurve) RecallAt(k int) float64 {
+	return must(c.MetricsAt(k)).Recall
+}
+
+// F1At calculates the F1@k.  This is the harmonic mean of Precision@k and Recall@k (or 0 if both are 0).  As
+// with PrecisionAt, k must lie within the range accepted by ValidateCutoff.
+func (c PrecisionRecallCurve) F1At(k int) float64 {
+	return must(c.MetricsAt(k)).F1
+}
+
+// CutoffMetrics contains the precision, recall and F1 score at a cut-off, k.
+type CutoffMetrics struct {
+	K         int     `json:"k"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+}
+
+// MetricsAt calculates Precision@k, Recall@k and F1@k together.  Unlike PrecisionAt, RecallAt and F1At,
+// MetricsAt does not panic if k lies outside the range of the curve but instead returns ErrOutOfBounds (see
+// ValidateCutoff) so it serves as the error returning variant of all three.
+func (c PrecisionRecallCurve) MetricsAt(k int) (CutoffMetrics, error) {
+	if err := c.ValidateCutoff(k); err != nil {
+		return CutoffMetrics{}, err
+	}
+	i := len(c.Precision) - 1 - k
+	return CutoffMetrics{K: k, Precision: c.Precision[i], Recall: c.Recall[i], F1: f1(c.Precision[i], c.Recall[i])}, nil
+}
+
+// f1 returns the harmonic mean of precision and recall or 0 if both are 0.
+func f1(precision, recall float64) float64 {
+	if precision+recall == 0 {
+		return 0
+	}
+	return 2 * precision * recall / (precision + recall)
 }
 
 // InterpolatedPrecisionAt calculates an interpolated Precision@r.  This can be used to calculate the precision for
 // a specific recall value that does not necessarily occur explicitly in the ranking.  It is calculated by taking the
-// maximum precision value over all recalls greater than r.
+// maximum precision value over all recalls greater than or equal to r (including the point @ 0 where precision is 1).
 func (c PrecisionRecallCurve) InterpolatedPrecisionAt(r float64) float64 {
-	// max precision [ recall > r]
+	// max precision [ recall >= r]
 	var inds []int
 	var err error
 	if inds, err = floats.Find(inds, func(x float64) bool { return (x >= r) }, c.Recall, -1); err != nil {
@@ -308,70 +766,376 @@ func (c PrecisionRecallCurve) InterpolatedPrecisionAt(r float64) float64 {
 	return max
 }
 
+// RecallAtPrecision returns the highest recall achievable by any threshold of the curve while maintaining at least
+// the specified precision, along with that threshold e.g. to determine the recall at 99% precision.  The result is
+// not interpolated between the points of the curve so that it corresponds to a threshold that can actually be
+// deployed.  Where several thresholds achieve the same recall, the highest is returned.  If no ranked items
+// achieve the precision, the recall is 0 and the threshold +Inf (classifying nothing as positive).
+func (c PrecisionRecallCurve) RecallAtPrecision(precision float64) (recall, threshold float64) {
+	recall, threshold = 0, math.Inf(1)
+	for i := len(c.Precision) - 2; i >= 0; i-- {
+		if c.Precision[i] >= precision && c.Recall[i] > recall {
+			recall, threshold = c.Recall[i], c.Thresholds[i]
+		}
+	}
+	return recall, threshold
+}
+
+// PrecisionAtRecall returns the highest precision achievable by any threshold of the curve while achieving at
+// least the specified recall, along with that threshold e.g. to determine the precision at 90% recall.  Where
+// several thresholds achieve the same precision, the highest is returned.  If no threshold achieves the recall
+// (e.g. there are no positive observations), NaN is returned for both.
+func (c PrecisionRecallCurve) PrecisionAtRecall(recall float64) (precision, threshold float64) {
+	precision, threshold = math.NaN(), math.NaN()
+	for i := len(c.Precision) - 2; i >= 0; i-- {
+		if c.Recall[i] >= recall && (math.IsNaN(precision) || c.Precision[i] > precision) {
+			precision, threshold = c.Precision[i], c.Thresholds[i]
+		}
+	}
+	return precision, threshold
+}
+
 type ConfusionMatrix struct {
-	Observations, Pos, Neg, TruePos, TrueNeg, FalsePos, FalseNeg int
+	Observations int `json:"observations"`
+	Pos          int `json:"pos"`
+	Neg          int `json:"neg"`
+	TruePos      int `json:"true_pos"`
+	TrueNeg      int `json:"true_neg"`
+	FalsePos     int `json:"false_pos"`
+	FalseNeg     int `json:"false_neg"`
+
+	// Weighted indicates whether the matrix was constructed with per-sample weights (see
+	// NewWeightedConfusionMatrix) in which case metrics are calculated from Weights rather than the counts
+	Weighted bool `json:"weighted"`
+
+	// Weights contains the sums of the per-sample weights for each cell of a weighted matrix
+	Weights ConfusionWeights `json:"weights"`
+
+	// ZeroDivision is the policy for metrics whose denominators are zero e.g. Precision when no observations are
+	// predicted positive.  By default such metrics are NaN
+	ZeroDivision ZeroDivisionPolicy `json:"-"`
+}
+
+// ZeroDivisionPolicy specifies the value of ConfusionMatrix metrics that are undefined because their denominators
+// are zero e.g. Precision when no observations are predicted positive or Recall when there are no positive
+// observations.
+type ZeroDivisionPolicy int
+
+const (
+	// ZeroDivisionNaN returns NaN for undefined metrics
+	ZeroDivisionNaN ZeroDivisionPolicy = iota
+
+	// ZeroDivisionZero returns 0 for undefined metrics, as commonly reported by other tools
+	ZeroDivisionZero
+
+	// ZeroDivisionPanic panics with an error wrapping ErrZeroDivision for undefined metrics.  The error returning
+	// accessors (e.g. ConfusionMatrix.PrecisionE) return the error instead of panicking and
+	// ConfusionMatrix.ValidateZeroDivision checks all the metrics up front.
+	ZeroDivisionPanic
+)
+
+// confusionMetrics are the metrics of a ConfusionMatrix by name.
+var confusionMetrics = []struct {
+	name   string
+	metric func(ConfusionMatrix) float64
+}{
+	{name: "precision", metric: ConfusionMatrix.Precision},
+	{name: "recall", metric: ConfusionMatrix.Recall},
+	{name: "accuracy", metric: ConfusionMatrix.Accuracy},
+	{name: "f1", metric: ConfusionMatrix.F1},
+	{name: "mcc", metric: ConfusionMatrix.MCC},
+	{name: "kappa", metric: ConfusionMatrix.Kappa},
+	{name: "specificity", metric: ConfusionMatrix.Specificity},
+}
+
+// ConfusionWeights contains the sums of the per-sample weights of the observations falling into each cell of
+// a weighted ConfusionMatrix.
+type ConfusionWeights struct {
+	Observations float64 `json:"observations"`
+	Pos          float64 `json:"pos"`
+	Neg          float64 `json:"neg"`
+	TruePos      float64 `json:"true_pos"`
+	TrueNeg      float64 `json:"true_neg"`
+	FalsePos     float64 `json:"false_pos"`
+	FalseNeg     float64 `json:"false_neg"`
+}
+
+func NewConfusionMatrix[P, L Float](predictions []P, labels []L, threshold float64) ConfusionMatrix {
+	return must(NewConfusionMatrixE(predictions, labels, threshold))
 }
 
-func NewConfusionMatrix(predictions []float64, labels []float64, threshold float64) ConfusionMatrix {
+// NewConfusionMatrixE creates a new ConfusionMatrix in the same way as NewConfusionMatrix but returns
+// ErrLengthMismatch, rather than panicking, if the lengths of the predictions and labels do not match.
+func NewConfusionMatrixE[P, L Float](predictions []P, labels []L, threshold float64) (ConfusionMatrix, error) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		return ConfusionMatrix{}, err
+	}
+
 	var matrix ConfusionMatrix
-	var y float64
 	for i, v := range labels {
-		matrix.Observations++
+		matrix.add(float64(predictions[i]) >= threshold, float64(v), 1)
+	}
+	return matrix, nil
+}
+
+// NewConfusionMatrices creates a ConfusionMatrix for each of the specified thresholds, returned in the same order
+// as thresholds, equivalent to calling NewConfusionMatrix for each threshold.  Rather than rescanning the
+// predictions for every threshold, the matrices are looked up from a single sweep over the sorted predictions
+// (as used by NewThresholdTable), taking O((n+t)log(n)) rather than O(n*t) time for n predictions and t
+// thresholds.
+func NewConfusionMatrices[P, L Float](predictions []P, labels []L, thresholds []float64) []ConfusionMatrix {
+	return must(NewConfusionMatricesE(predictions, labels, thresholds))
+}
+
+// NewConfusionMatricesE creates a ConfusionMatrix for each threshold in the same way as NewConfusionMatrices
+// but returns ErrLengthMismatch, rather than panicking, if the lengths of the predictions and labels do not match.
+func NewConfusionMatricesE[P, L Float](predictions []P, labels []L, thresholds []float64) ([]ConfusionMatrix, error) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		return nil, err
+	}
+
+	candidates, sweep := thresholdSweep(predictions, labels)
+	return matricesAt(candidates, sweep, thresholds), nil
+}
+
+// NewWeightedConfusionMatrix creates a new ConfusionMatrix in the same way as NewConfusionMatrix but weighting
+// each observation by the corresponding per-sample weight.  The counts of the resulting matrix reflect the
+// number of observations as normal and Weights contains the sums of the weights which are used to calculate
+// the (weighted) metrics e.g. Precision, Recall, F1, etc.  The weights must be of the same Float type as the
+// labels.
+func NewWeightedConfusionMatrix[P, L Float](predictions []P, labels, weights []L, threshold float64) ConfusionMatrix {
+	return must(NewWeightedConfusionMatrixE(predictions, labels, weights, threshold))
+}
+
+// NewWeightedConfusionMatrixE creates a new weighted ConfusionMatrix in the same way as
+// NewWeightedConfusionMatrix but returns ErrLengthMismatch, rather than panicking, if the lengths of the
+// predictions, labels and weights do not match.
+func NewWeightedConfusionMatrixE[P, L Float](predictions []P, labels, weights []L, threshold float64) (ConfusionMatrix, error) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		return ConfusionMatrix{}, err
+	}
+	if err := ValidateLengths(weights, labels); err != nil {
+		return ConfusionMatrix{}, err
+	}
+
+	matrix := ConfusionMatrix{Weighted: true}
+	for i, v := range labels {
+		matrix.add(float64(predictions[i]) >= threshold, float64(v), float64(weights[i]))
+	}
+	return matrix, nil
+}
+
+// add adds a single observation with the specified predicted class and label to the matrix.  Labels of 1
+// are considered positive and all other values negative.  The weight is only recorded if the matrix is weighted.
+func (c *ConfusionMatrix) add(predicted bool, label float64, weight float64) {
+	if !c.Weighted {
+		weight = 0
+	}
+	c.Observations++
+	c.Weights.Observations += weight
 
-		if predictions[i] >= threshold {
-			y = 1.0
+	// evaluate result and collect stats
+	if label == 1 {
+		c.Pos++
+		c.Weights.Pos += weight
+		if predicted {
+			c.TruePos++
+			c.Weights.TruePos += weight
 		} else {
-			y = 0.0
+			c.FalseNeg++
+			c.Weights.FalseNeg += weight
 		}
-
-		// evaluate result and collect stats
-		if v == 1 {
-			matrix.Pos++
-			if y == 1 {
-				matrix.TruePos++
-			} else {
-				matrix.FalseNeg++
-			}
+	} else {
+		c.Neg++
+		c.Weights.Neg += weight
+		if predicted {
+			c.FalsePos++
+			c.Weights.FalsePos += weight
 		} else {
-			matrix.Neg++
-			if y == 1 {
-				matrix.FalsePos++
-			} else {
-				matrix.TrueNeg++
-			}
+			c.TrueNeg++
+			c.Weights.TrueNeg += weight
 		}
 	}
-	return matrix
 }
 
+// predictPositive moves a single observation of an unweighted matrix with the specified label from the predicted
+// negative cells to the predicted positive cells.
+func (c *ConfusionMatrix) predictPositive(label float64) {
+	if label == 1 {
+		c.FalseNeg--
+		c.TruePos++
+	} else {
+		c.TrueNeg--
+		c.FalsePos++
+	}
+}
+
+// cells returns the true positive, true negative, false positive and false negative cells of the matrix used
+// for calculating metrics.  These are the sums of the weights for a weighted matrix and the counts otherwise.
+func (c ConfusionMatrix) cells() (tp, tn, fp, fn float64) {
+	if c.Weighted {
+		return c.Weights.TruePos, c.Weights.TrueNeg, c.Weights.FalsePos, c.Weights.FalseNeg
+	}
+	return float64(c.TruePos), float64(c.TrueNeg), float64(c.FalsePos), float64(c.FalseNeg)
+}
+
+// cell formats a single cell of the matrix for String() using the sum of weights for a weighted matrix and the
+// count otherwise.
+func (c ConfusionMatrix) cell(count int, weight float64) string {
+	if c.Weighted {
+		return strconv.FormatFloat(weight, 'g', 6, 64)
+	}
+	return strconv.Itoa(count)
+}
+
+// divide divides the numerator of the named metric by its denominator applying the matrix's ZeroDivision policy
+// if the denominator is zero.  An error wrapping ErrZeroDivision is returned for the ZeroDivisionPanic policy.
+func (c ConfusionMatrix) divide(metric string, numerator, denominator float64) (float64, error) {
+	if denominator != 0 {
+		return numerator / denominator, nil
+	}
+	switch c.ZeroDivision {
+	case ZeroDivisionZero:
+		return 0, nil
+	case ZeroDivisionPanic:
+		return math.NaN(), fmt.Errorf("%w: %s undefined", ErrZeroDivision, metric)
+	}
+	return math.NaN(), nil
+}
+
+// formatMetric formats a metric value for String(), rendering undefined (NaN) values as "undefined".
+func formatMetric(v float64) string {
+	if math.IsNaN(v) {
+		return "undefined"
+	}
+	return fmt.Sprintf("%f", v)
+}
+
+// String formats the matrix as a table along with its recall, precision, accuracy and F1 score.  Undefined
+// metrics are rendered as "undefined" rather than applying the ZeroDivision policy so that String never panics.
 func (c ConfusionMatrix) String() string {
 	var s string
 
+	if c.ZeroDivision == ZeroDivisionPanic {
+		c.ZeroDivision = ZeroDivisionNaN
+	}
 	horiz := "------------------------------------------------------------------------------------------------------\n"
 
-	s = fmt.Sprintf("Observations = %-10d |       Predicted No       |       Predicted Yes      |\n", c.Observations)
+	s = fmt.Sprintf("Observations = %-10s |       Predicted No       |       Predicted Yes      |\n", c.cell(c.Observations, c.Weights.Observations))
 	s = s + horiz
-	s = fmt.Sprintf("%sActual No                 |       TN = %-10d    |       FP = %-10d    |\n", s, c.TrueNeg, c.FalsePos)
-	s = fmt.Sprintf("%sActual Yes                |       FN = %-10d    |       TP = %-10d    |  Recall = %f\n", s, c.FalseNeg, c.TruePos, c.Recall())
+	s = fmt.Sprintf("%sActual No                 |       TN = %-10s    |       FP = %-10s    |\n", s, c.cell(c.TrueNeg, c.Weights.TrueNeg), c.cell(c.FalsePos, c.Weights.FalsePos))
+	s = fmt.Sprintf("%sActual Yes                |       FN = %-10s    |       TP = %-10s    |  Recall = %s\n", s, c.cell(c.FalseNeg, c.Weights.FalseNeg), c.cell(c.TruePos, c.Weights.TruePos), formatMetric(c.Recall()))
 	s = s + horiz
-	s = fmt.Sprintf("%s                                                     |   Precision = %-10f |  Accuracy = %f\n", s, c.Precision(), c.Accuracy())
-	s = fmt.Sprintf("%sF1 Score = %f\n", s, c.F1())
+	s = fmt.Sprintf("%s                                                     |   Precision = %-10s |  Accuracy = %s\n", s, formatMetric(c.Precision()), formatMetric(c.Accuracy()))
+	s = fmt.Sprintf("%sF1 Score = %s, Support = %s\n", s, formatMetric(c.F1()), strconv.FormatFloat(c.Support(), 'g', 6, 64))
 
 	return s
 }
 
+// Support returns the number of actual positive observations (TruePos + FalseNeg), or the sum of their weights
+// for a weighted matrix, over which Recall and F1 are calculated.  A support of 0 means that Recall is undefined.
+func (c ConfusionMatrix) Support() float64 {
+	tp, _, _, fn := c.cells()
+	return tp + fn
+}
+
+// Precision calculates the proportion of observations predicted positive that are actually positive.  Precision
+// is undefined if no observations are predicted positive.
 func (c ConfusionMatrix) Precision() float64 {
-	return float64(c.TruePos) / float64(c.TruePos+c.FalsePos)
+	return must(c.PrecisionE())
+}
+
+// PrecisionE calculates the precision in the same way as Precision but returns an error wrapping ErrZeroDivision,
+// rather than panicking, if it is undefined under the ZeroDivisionPanic policy.
+func (c ConfusionMatrix) PrecisionE() (float64, error) {
+	tp, _, fp, _ := c.cells()
+	return c.divide("precision", tp, tp+fp)
 }
 
+// Recall calculates the proportion of actual positives that were predicted positive.  Recall is undefined if there
+// are no positive observations.
 func (c ConfusionMatrix) Recall() float64 {
-	return float64(c.TruePos) / float64(c.TruePos+c.FalseNeg)
+	return must(c.RecallE())
+}
+
+// RecallE calculates the recall in the same way as Recall but returns an error wrapping ErrZeroDivision, rather
+// than panicking, if it is undefined under the ZeroDivisionPanic policy.
+func (c ConfusionMatrix) RecallE() (float64, error) {
+	tp, _, _, fn := c.cells()
+	return c.divide("recall", tp, tp+fn)
 }
 
+// Accuracy calculates the proportion of observations that were predicted correctly.  Accuracy is undefined if the
+// matrix is empty.
 func (c ConfusionMatrix) Accuracy() float64 {
-	return float64(c.TrueNeg+c.TruePos) / float64(c.Observations)
+	return must(c.AccuracyE())
+}
+
+// AccuracyE calculates the accuracy in the same way as Accuracy but returns an error wrapping ErrZeroDivision,
+// rather than panicking, if it is undefined under the ZeroDivisionPanic policy.
+func (c ConfusionMatrix) AccuracyE() (float64, error) {
+	tp, tn, fp, fn := c.cells()
+	return c.divide("accuracy", tn+tp, tp+tn+fp+fn)
 }
 
+// F1 calculates the F1 score, the harmonic mean of precision and recall, as 2TP/(2TP+FP+FN).  F1 is 0 if there are
+// no true positives but some false positives or false negatives and is only undefined if there are no true
+// positives, false positives or false negatives.
 func (c ConfusionMatrix) F1() float64 {
-	return 2 * ((c.Precision() * c.Recall()) / (c.Precision() + c.Recall()))
+	return must(c.F1E())
+}
+
+// F1E calculates the F1 score in the same way as F1 but returns an error wrapping ErrZeroDivision, rather than
+// panicking, if it is undefined under the ZeroDivisionPanic policy.
+func (c ConfusionMatrix) F1E() (float64, error) {
+	tp, _, fp, fn := c.cells()
+	return c.divide("f1", 2*tp, 2*tp+fp+fn)
+}
+
+// MCC calculates the Matthews Correlation Coefficient.  This is the correlation coefficient between the observed
+// and predicted classifications and ranges from -1 (total disagreement) through 0 (no better than random) to
+// +1 (perfect prediction).  Unlike accuracy, it remains informative when the classes are very imbalanced.  MCC is
+// undefined if any row or column of the matrix is empty.
+func (c ConfusionMatrix) MCC() float64 {
+	return must(c.MCCE())
+}
+
+// MCCE calculates the Matthews Correlation Coefficient in the same way as MCC but returns an error wrapping
+// ErrZeroDivision, rather than panicking, if it is undefined under the ZeroDivisionPanic policy.
+func (c ConfusionMatrix) MCCE() (float64, error) {
+	tp, tn, fp, fn := c.cells()
+	return c.divide("mcc", tp*tn-fp*fn, math.Sqrt((tp+fp)*(tp+fn)*(tn+fp)*(tn+fn)))
+}
+
+// Kappa calculates Cohen's Kappa.  This measures the agreement between the observed and predicted classifications
+// after correcting for the agreement expected by chance given the marginal totals.  1 represents perfect agreement
+// and 0 represents agreement no better than chance.  Kappa is undefined if the matrix is empty or the agreement
+// expected by chance is perfect.
+func (c ConfusionMatrix) Kappa() float64 {
+	return must(c.KappaE())
+}
+
+// KappaE calculates Cohen's Kappa in the same way as Kappa but returns an error wrapping ErrZeroDivision, rather
+// than panicking, if it is undefined under the ZeroDivisionPanic policy.
+func (c ConfusionMatrix) KappaE() (float64, error) {
+	tp, tn, fp, fn := c.cells()
+	n := tp + tn + fp + fn
+	if n == 0 {
+		return c.divide("kappa", 0, 0)
+	}
+	expected := ((tp+fp)*(tp+fn) + (tn+fn)*(tn+fp)) / (n * n)
+	return c.divide("kappa", (tp+tn)/n-expected, 1-expected)
+}
+
+// Specificity calculates the specificity or true negative rate.  This is the proportion of actual negatives that
+// were correctly predicted as negative.
+func (c ConfusionMatrix) Specificity() float64 {
+	return must(c.SpecificityE())
+}
+
+// SpecificityE calculates the specificity in the same way as Specificity but returns an error wrapping
+// ErrZeroDivision, rather than panicking, if it is undefined under the ZeroDivisionPanic policy.
+func (c ConfusionMatrix) SpecificityE() (float64, error) {
+	_, tn, fp, _ := c.cells()
+	return c.divide("specificity", tn, tn+fp)
 }
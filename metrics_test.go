@@ -1,7 +1,9 @@
 package datautils_test
 
 import (
+	"errors"
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/gonum/floats"
@@ -139,6 +141,35 @@ func TestAveragePrecision(t *testing.T) {
 	}
 }
 
+func TestAveragePrecisionVariants(t *testing.T) {
+	tests := []struct {
+		labels       []float64
+		probs        []float64
+		step         float64
+		trapezoidal  float64
+		interpolated float64
+	}{
+		{labels: datasets[0].labels, probs: datasets[0].probs, step: 5.0 / 6.0, trapezoidal: 19.0 / 24.0, interpolated: 5.0 / 6.0},
+		{labels: datasets[1].labels, probs: datasets[1].probs, step: 0.5, trapezoidal: 1.0 / 3.0, interpolated: 0.5},
+		{labels: datasets[2].labels, probs: datasets[2].probs, step: 0.5, trapezoidal: 67.0 / 180.0, interpolated: 0.5},
+		{labels: datasets[3].labels, probs: datasets[3].probs, step: 0, trapezoidal: 0, interpolated: 0},
+		{labels: []float64{0, 1, 1}, probs: []float64{0.9, 0.8, 0.7}, step: 7.0 / 12.0, trapezoidal: 5.0 / 12.0, interpolated: 2.0 / 3.0},
+	}
+
+	for i, test := range tests {
+		curve := datautils.NewPrecisionRecallCurve(test.probs, test.labels)
+		if ap := curve.AveragePrecision(); math.Abs(ap-test.step) > 1e-12 {
+			t.Errorf("Test %d: Expected AP: %v but received %v", i+1, test.step, ap)
+		}
+		if ap := curve.TrapezoidalAveragePrecision(); math.Abs(ap-test.trapezoidal) > 1e-12 {
+			t.Errorf("Test %d: Expected trapezoidal AP: %v but received %v", i+1, test.trapezoidal, ap)
+		}
+		if ap := curve.InterpolatedAveragePrecision(); math.Abs(ap-test.interpolated) > 1e-12 {
+			t.Errorf("Test %d: Expected interpolated AP: %v but received %v", i+1, test.interpolated, ap)
+		}
+	}
+}
+
 func TestAverageInterpolatedPrecision(t *testing.T) {
 	// Test the metric functions
 	tests := []struct {
@@ -186,6 +217,41 @@ func TestPrecisionAtK(t *testing.T) {
 	}
 }
 
+func TestMetricsAtK(t *testing.T) {
+	tests := []struct {
+		dataset int
+		recalls []float64
+		f1s     []float64
+	}{
+		{dataset: 0, recalls: []float64{0, 0.5, 0.5, 1}, f1s: []float64{0, 2.0 / 3.0, 0.5, 0.8}},
+		{dataset: 1, recalls: []float64{0, 0, 0.5, 0.5, 1}, f1s: []float64{0, 0, 0.5, 0.4, 2.0 / 3.0}},
+		{dataset: 3, recalls: []float64{0}, f1s: []float64{0}},
+	}
+
+	for i, test := range tests {
+		curve := datautils.NewPrecisionRecallCurve(datasets[test.dataset].probs, datasets[test.dataset].labels)
+		for k := range test.recalls {
+			if r := curve.RecallAt(k); r != test.recalls[k] {
+				t.Errorf("Test %d: Expected R@%d: %v but received %v", i+1, k, test.recalls[k], r)
+			}
+			if f := curve.F1At(k); math.Abs(f-test.f1s[k]) > 1e-12 {
+				t.Errorf("Test %d: Expected F1@%d: %v but received %v", i+1, k, test.f1s[k], f)
+			}
+			m, err := curve.MetricsAt(k)
+			if err != nil {
+				t.Errorf("Test %d: Unexpected error for metrics@%d: %v", i+1, k, err)
+			}
+			if m.K != k || m.Precision != curve.PrecisionAt(k) || m.Recall != test.recalls[k] || math.Abs(m.F1-test.f1s[k]) > 1e-12 {
+				t.Errorf("Test %d: Expected metrics@%d: P=%v R=%v F1=%v but received %+v", i+1, k, curve.PrecisionAt(k), test.recalls[k], test.f1s[k], m)
+			}
+		}
+
+		if _, err := curve.MetricsAt(len(test.recalls)); err != datautils.ErrOutOfBounds {
+			t.Errorf("Test %d: Expected error: %v but received %v", i+1, datautils.ErrOutOfBounds, err)
+		}
+	}
+}
+
 func TestRPrecision(t *testing.T) {
 	// Test the metric functions
 	tests := []struct {
@@ -231,3 +297,306 @@ func TestInterpolatedPrecisionAtR(t *testing.T) {
 		}
 	}
 }
+
+func TestReciprocalRank(t *testing.T) {
+	tests := []float64{1, 0.5, 0.5, 0, 0}
+
+	for i, test := range tests {
+		evaluation := datautils.NewRankingEvaluation(datasets[i].probs, datasets[i].labels)
+		if test != evaluation.ReciprocalRank() {
+			t.Errorf("Test %d: Expected reciprocal rank: %v but received %v", i+1, test, evaluation.ReciprocalRank())
+		}
+	}
+}
+
+func TestConfusionMatrixMCCAndKappa(t *testing.T) {
+	tests := []struct {
+		threshold float64
+		mcc       float64
+		kappa     float64
+	}{
+		{threshold: 0.5, mcc: 0.5773502691896258, kappa: 0.5},
+		{threshold: 0.3, mcc: 0.5773502691896258, kappa: 0.5},
+		{threshold: 0.38, mcc: 0, kappa: 0},
+	}
+
+	for i, test := range tests {
+		matrix := datautils.NewConfusionMatrix(datasets[0].probs, datasets[0].labels, test.threshold)
+		if mcc := matrix.MCC(); math.Abs(mcc-test.mcc) > 0.000001 {
+			t.Errorf("Test %d: Expected MCC: %v but received %v", i+1, test.mcc, mcc)
+		}
+		if kappa := matrix.Kappa(); math.Abs(kappa-test.kappa) > 0.000001 {
+			t.Errorf("Test %d: Expected Kappa: %v but received %v", i+1, test.kappa, kappa)
+		}
+	}
+}
+
+func TestWeightedConfusionMatrix(t *testing.T) {
+	tests := []struct {
+		weights   []float64
+		precision float64
+		recall    float64
+		accuracy  float64
+	}{
+		{weights: []float64{1, 1, 1, 1}, precision: 1, recall: 0.5, accuracy: 0.75},
+		{weights: []float64{1, 1, 3, 1}, precision: 1, recall: 0.25, accuracy: 0.5},
+		{weights: []float64{0.5, 2, 1, 1}, precision: 1, recall: 0.5, accuracy: 3.5 / 4.5},
+	}
+
+	for i, test := range tests {
+		matrix := datautils.NewWeightedConfusionMatrix(datasets[0].probs, datasets[0].labels, test.weights, 0.5)
+		if matrix.Observations != 4 || matrix.TruePos != 1 || matrix.FalseNeg != 1 || matrix.TrueNeg != 2 {
+			t.Errorf("Test %d: Expected unweighted counts to be unaffected by weights but received %+v", i+1, matrix)
+		}
+		if p := matrix.Precision(); math.Abs(p-test.precision) > 0.000001 {
+			t.Errorf("Test %d: Expected precision: %v but received %v", i+1, test.precision, p)
+		}
+		if r := matrix.Recall(); math.Abs(r-test.recall) > 0.000001 {
+			t.Errorf("Test %d: Expected recall: %v but received %v", i+1, test.recall, r)
+		}
+		if a := matrix.Accuracy(); math.Abs(a-test.accuracy) > 0.000001 {
+			t.Errorf("Test %d: Expected accuracy: %v but received %v", i+1, test.accuracy, a)
+		}
+	}
+
+	matrix := datautils.NewWeightedConfusionMatrix(datasets[0].probs, datasets[0].labels, []float64{1, 1, 3, 1}, 0.5)
+	if s := matrix.String(); !strings.Contains(s, "FN = 3 ") || !strings.Contains(s, "Observations = 6 ") {
+		t.Errorf("Expected weighted counts in String() but received:\n%s", s)
+	}
+}
+
+func TestConfusionMatrixZeroDivision(t *testing.T) {
+	// all observations are negative and predicted negative so only accuracy and specificity are defined
+	matrix := datautils.NewConfusionMatrix([]float64{0.1, 0.2}, []float64{0, 0}, 0.5)
+
+	metrics := []func(datautils.ConfusionMatrix) float64{
+		datautils.ConfusionMatrix.Precision,
+		datautils.ConfusionMatrix.Recall,
+		datautils.ConfusionMatrix.F1,
+		datautils.ConfusionMatrix.MCC,
+		datautils.ConfusionMatrix.Kappa,
+	}
+	for i, metric := range metrics {
+		matrix.ZeroDivision = datautils.ZeroDivisionNaN
+		if v := metric(matrix); !math.IsNaN(v) {
+			t.Errorf("Metric %d: Expected NaN but received %v", i+1, v)
+		}
+		matrix.ZeroDivision = datautils.ZeroDivisionZero
+		if v := metric(matrix); v != 0 {
+			t.Errorf("Metric %d: Expected 0 but received %v", i+1, v)
+		}
+	}
+	if a, s := matrix.Accuracy(), matrix.Specificity(); a != 1 || s != 1 {
+		t.Errorf("Expected accuracy and specificity of 1 but received %v and %v", a, s)
+	}
+	if s := matrix.Support(); s != 0 {
+		t.Errorf("Expected support: 0 but received %v", s)
+	}
+
+	matrix.ZeroDivision = datautils.ZeroDivisionPanic
+	err := matrix.ValidateZeroDivision()
+	if !errors.Is(err, datautils.ErrZeroDivision) || !strings.HasSuffix(err.Error(), "precision, recall, f1, mcc, kappa undefined") {
+		t.Errorf("Expected ErrZeroDivision naming the undefined metrics but received %v", err)
+	}
+	if s := matrix.String(); !strings.Contains(s, "Recall = undefined") || !strings.Contains(s, "Support = 0") {
+		t.Errorf("Expected undefined metrics in String() but received:\n%s", s)
+	}
+	func() {
+		defer func() {
+			if r, ok := recover().(error); !ok || !errors.Is(r, datautils.ErrZeroDivision) {
+				t.Errorf("Expected panic with ErrZeroDivision but received %v", r)
+			}
+		}()
+		matrix.Precision()
+	}()
+	if v, err := matrix.PrecisionE(); !errors.Is(err, datautils.ErrZeroDivision) || !math.IsNaN(v) {
+		t.Errorf("Expected NaN and ErrZeroDivision from PrecisionE but received %v and %v", v, err)
+	}
+	if v, err := matrix.AccuracyE(); err != nil || v != 1 {
+		t.Errorf("Expected accuracy of 1 from AccuracyE but received %v and %v", v, err)
+	}
+	matrix.ZeroDivision = datautils.ZeroDivisionZero
+	if v, err := matrix.RecallE(); err != nil || v != 0 {
+		t.Errorf("Expected recall of 0 from RecallE with ZeroDivisionZero but received %v and %v", v, err)
+	}
+
+	defined := datautils.NewConfusionMatrix(datasets[0].probs, datasets[0].labels, 0.5)
+	defined.ZeroDivision = datautils.ZeroDivisionPanic
+	if err := defined.ValidateZeroDivision(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if s := defined.Support(); s != 2 {
+		t.Errorf("Expected support: 2 but received %v", s)
+	}
+
+	// F1 is 0, rather than undefined, without true positives if there are false positives and false negatives
+	wrong := datautils.NewConfusionMatrix([]float64{0.9, 0.1}, []float64{0, 1}, 0.5)
+	wrong.ZeroDivision = datautils.ZeroDivisionPanic
+	if f1, err := wrong.F1E(); err != nil || f1 != 0 {
+		t.Errorf("Expected F1: 0 but received %v and %v", f1, err)
+	}
+
+	f1, err := datautils.LookupMetric("f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := f1.Compute([]float64{0.1}, []float64{0}, datautils.MetricOptions{Threshold: 0.5, ZeroDivision: datautils.ZeroDivisionZero}); v != 0 {
+		t.Errorf("Expected f1 metric: 0 but received %v", v)
+	}
+}
+
+func TestNewConfusionMatrices(t *testing.T) {
+	predictions := []float64{0.9, 0.4, math.NaN(), 0.4, 0.7, 0.1, 0.2}
+	labels := []float32{1, 0, 1, 1, 0, 2, 1}
+	thresholds := []float64{0.5, math.Inf(1), 0.4, math.NaN(), 0, 0.95, 0.4, math.Inf(-1)}
+
+	matrices := datautils.NewConfusionMatrices(predictions, labels, thresholds)
+	if len(matrices) != len(thresholds) {
+		t.Fatalf("Expected %d matrices but received %d", len(thresholds), len(matrices))
+	}
+	for i, threshold := range thresholds {
+		if expected := datautils.NewConfusionMatrix(predictions, labels, threshold); matrices[i] != expected {
+			t.Errorf("Threshold %v: Expected %+v but received %+v", threshold, expected, matrices[i])
+		}
+	}
+
+	if matrices := datautils.NewConfusionMatrices(predictions, labels, nil); len(matrices) != 0 {
+		t.Errorf("Expected no matrices but received %v", matrices)
+	}
+}
+
+func TestTieAwareNormalisedDiscountedCumulativeGain(t *testing.T) {
+	tests := []struct {
+		probs  []float64
+		labels []float64
+		k      int
+		ndcg   float64
+	}{
+		{probs: []float64{0.5, 0.5, 0.1}, labels: []float64{1, 0, 0}, k: 3, ndcg: (1 + 1/math.Log2(3)) / 2},
+		{probs: []float64{0.5, 0.5, 0.1}, labels: []float64{0, 1, 0}, k: 3, ndcg: (1 + 1/math.Log2(3)) / 2},
+		{probs: []float64{0.5, 0.5, 0.1}, labels: []float64{1, 0, 0}, k: 1, ndcg: 0.5},
+		// without ties the result matches NormalisedDiscountedCumulativeGain
+		{probs: datasets[2].probs, labels: datasets[2].labels, k: 6, ndcg: 0.6653497124326151},
+		{probs: datasets[4].probs, labels: datasets[4].labels, k: 17, ndcg: 1},
+	}
+
+	for i, test := range tests {
+		evaluation := datautils.NewRankingEvaluation(test.probs, test.labels)
+		ndcg := evaluation.TieAwareNormalisedDiscountedCumulativeGain(test.k, datautils.TraditionalRelevancy)
+		if math.Abs(ndcg-test.ndcg) > 0.000001 {
+			t.Errorf("Test %d: Expected tie aware NDCG: %v but received %v", i+1, test.ndcg, ndcg)
+		}
+	}
+}
+
+func TestHitAt(t *testing.T) {
+	tests := [][]float64{{1, 1, 1, 1}, {0, 1, 1, 1, 1}, {0, 1, 1, 1, 1, 1}, {0, 0}}
+
+	for i, test := range tests {
+		evaluation := datautils.NewRankingEvaluation(datasets[i].probs, datasets[i].labels)
+		for k, v := range test {
+			if hit := evaluation.HitAt(k + 1); hit != v {
+				t.Errorf("Test %d: Expected Hit@%d: %v but received %v", i+1, k+1, v, hit)
+			}
+		}
+	}
+}
+
+func TestRecallAt(t *testing.T) {
+	tests := [][]float64{{0.5, 0.5, 1, 1}, {0, 0.5, 0.5, 1, 1}, {0, 1.0 / 3, 1.0 / 3, 2.0 / 3, 2.0 / 3, 1}, {0, 0}}
+
+	for i, test := range tests {
+		evaluation := datautils.NewRankingEvaluation(datasets[i].probs, datasets[i].labels)
+		for k, v := range test {
+			if recall := evaluation.RecallAt(k + 1); math.Abs(recall-v) > 1e-12 {
+				t.Errorf("Test %d: Expected Recall@%d: %v but received %v", i+1, k+1, v, recall)
+			}
+		}
+	}
+}
+
+func TestNormalisedDiscountedCumulativeGains(t *testing.T) {
+	for i, d := range datasets {
+		evaluation := datautils.NewRankingEvaluation(d.probs, d.labels)
+
+		cutoffs := []int{len(d.labels), 1}
+		if len(d.labels) > 2 {
+			cutoffs = append(cutoffs, 3)
+		}
+		ndcgs := evaluation.NormalisedDiscountedCumulativeGains(cutoffs, datautils.EmphasisedRelevancy)
+
+		for j, k := range cutoffs {
+			if expected := evaluation.NormalisedDiscountedCumulativeGain(k, datautils.EmphasisedRelevancy); ndcgs[j] != expected {
+				t.Errorf("Test %d: Expected NDCG@%d: %v but received %v", i+1, k, expected, ndcgs[j])
+			}
+		}
+	}
+}
+
+func TestAveragePrecisionAt(t *testing.T) {
+	evaluation := datautils.NewRankingEvaluation([]float64{0.9, 0.8, 0.7, 0.6}, []float64{2, 0, 1, 3})
+
+	tests := []struct {
+		k         int
+		threshold float64
+		ap        float64
+	}{
+		{k: 4, threshold: 1, ap: (1 + 2.0/3 + 3.0/4) / 3},
+		{k: 2, threshold: 1, ap: 0.5},
+		{k: 4, threshold: 2, ap: 0.75},
+		{k: 3, threshold: 2, ap: 0.5},
+		{k: 1, threshold: 2, ap: 1},
+		{k: 4, threshold: 4, ap: 0},
+	}
+
+	for i, test := range tests {
+		if ap := evaluation.AveragePrecisionAt(test.k, test.threshold); math.Abs(ap-test.ap) > 1e-12 {
+			t.Errorf("Test %d: Expected AP@%d: %v but received %v", i+1, test.k, test.ap, ap)
+		}
+	}
+
+	// with no cut-off and a threshold of 1, AP@k matches the average precision of the precision recall curve
+	for i, d := range datasets {
+		evaluation := datautils.NewRankingEvaluation(d.probs, d.labels)
+		expected := datautils.NewPrecisionRecallCurve(d.probs, d.labels).AveragePrecision()
+		if ap := evaluation.AveragePrecisionAt(len(d.labels), 1); math.Abs(ap-expected) > 1e-12 {
+			t.Errorf("Dataset %d: Expected AP: %v but received %v", i+1, expected, ap)
+		}
+	}
+}
+
+func TestRecallAtPrecisionAndPrecisionAtRecall(t *testing.T) {
+	curve := datautils.NewPrecisionRecallCurve(datasets[0].probs, datasets[0].labels)
+
+	recallTests := []struct {
+		precision, recall, threshold float64
+	}{
+		{precision: 1, recall: 0.5, threshold: 0.8},
+		{precision: 0.7, recall: 0.5, threshold: 0.8},
+		{precision: 0.6, recall: 1, threshold: 0.35},
+		{precision: 1.1, recall: 0, threshold: math.Inf(1)},
+	}
+	for i, test := range recallTests {
+		if recall, threshold := curve.RecallAtPrecision(test.precision); recall != test.recall || threshold != test.threshold {
+			t.Errorf("Test %d: Expected recall: %v, threshold: %v but received %v, %v", i+1, test.recall, test.threshold, recall, threshold)
+		}
+	}
+
+	precisionTests := []struct {
+		recall, precision, threshold float64
+	}{
+		{recall: 1, precision: 2.0 / 3, threshold: 0.35},
+		{recall: 0.5, precision: 1, threshold: 0.8},
+		{recall: 0, precision: 1, threshold: 0.8},
+	}
+	for i, test := range precisionTests {
+		if precision, threshold := curve.PrecisionAtRecall(test.recall); precision != test.precision || threshold != test.threshold {
+			t.Errorf("Test %d: Expected precision: %v, threshold: %v but received %v, %v", i+1, test.precision, test.threshold, precision, threshold)
+		}
+	}
+
+	noPositives := datautils.NewPrecisionRecallCurve(datasets[3].probs, datasets[3].labels)
+	if precision, threshold := noPositives.PrecisionAtRecall(0.5); !math.IsNaN(precision) || !math.IsNaN(threshold) {
+		t.Errorf("Expected precision: NaN, threshold: NaN but received %v, %v", precision, threshold)
+	}
+}
@@ -0,0 +1,146 @@
+package datautils
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// colors is a palette.Palette of a fixed set of colours.
+type colors []color.Color
+
+func (c colors) Colors() []color.Color { return c }
+
+// MissingFractions returns the fraction of the values of each column of m that are missing (NaN).
+func MissingFractions(m mat.Matrix) []float64 {
+	r, c := m.Dims()
+	fractions := make([]float64, c)
+	if r == 0 {
+		return fractions
+	}
+	for j := range fractions {
+		var n int
+		for i := 0; i < r; i++ {
+			if math.IsNaN(m.At(i, j)) {
+				n++
+			}
+		}
+		fractions[j] = float64(n) / float64(r)
+	}
+	return fractions
+}
+
+// MissingCooccurrence returns a symmetric matrix containing the fraction of the rows of m in which the values
+// of both column i and column j are missing (NaN) at element i, j.  The diagonal therefore contains the fraction
+// of values missing from each column (see MissingFractions).  Columns whose values tend to be missing together
+// suggest a common cause and so may need to be imputed together or the rows dropped.
+func MissingCooccurrence(m mat.Matrix) *mat.SymDense {
+	r, c := m.Dims()
+	co := mat.NewSymDense(c, nil)
+	if r == 0 {
+		return co
+	}
+	missing := make([]int, 0, c)
+	counts := make([]int, c*c)
+	for i := 0; i < r; i++ {
+		missing = missing[:0]
+		for j := 0; j < c; j++ {
+			if math.IsNaN(m.At(i, j)) {
+				missing = append(missing, j)
+			}
+		}
+		for a, j := range missing {
+			for _, k := range missing[a:] {
+				counts[j*c+k]++
+			}
+		}
+	}
+	for j := 0; j < c; j++ {
+		for k := j; k < c; k++ {
+			co.SetSym(j, k, float64(counts[j*c+k])/float64(r))
+		}
+	}
+	return co
+}
+
+// PlotMissingBar renders the fraction of the values of each column of m that are missing (NaN) as a bar chart
+// using the specified labels for the columns.  If nil, columns are labelled by index.
+func PlotMissingBar(m mat.Matrix, labels []string) (*plot.Plot, error) {
+	fractions := MissingFractions(m)
+	if labels != nil && len(labels) != len(fractions) {
+		return nil, fmt.Errorf("datautils: %d labels specified for matrix with %d columns", len(labels), len(fractions))
+	}
+
+	p, err := newPlot()
+	if err != nil {
+		return nil, err
+	}
+	p.Title.Text = PlotTheme.title("Missing Values")
+	p.Y.Label.Text = "Fraction Missing"
+	p.Y.Min, p.Y.Max = 0, 1
+
+	if len(fractions) > 0 {
+		bars, err := plotter.NewBarChart(plotter.Values(fractions), vg.Points(10))
+		if err != nil {
+			return nil, err
+		}
+		bars.Color = PlotTheme.Primary
+		bars.LineStyle.Width = 0
+		p.Add(bars)
+	}
+
+	p.X.Tick.Label.Rotation = 1.5
+	p.X.Tick.Label.XAlign = draw.XRight
+	p.X.Tick.Marker = ticks{labels: labels, n: len(fractions)}
+	return p, nil
+}
+
+// PlotMissingMatrix renders the pattern of missing (NaN) values within m as a heatmap with one cell per value
+// of the matrix, missing values being shown dark and present values light.  This shows at a glance which columns
+// have missing values, whether they are missing in contiguous runs of rows and whether values tend to be missing
+// from several columns of the same rows.  labels specifies the names of the columns and if nil, columns are
+// labelled by index.  Optional behaviour of the heatmap may be configured by specifying HeatmapOptions e.g.
+// WithLabelStep to label fewer rows of large matrices.
+func PlotMissingMatrix(m mat.Matrix, labels []string, opts ...HeatmapOption) (*plot.Plot, error) {
+	r, c := m.Dims()
+	missing := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if math.IsNaN(m.At(i, j)) {
+				missing.Set(i, j, 1)
+			}
+		}
+	}
+
+	opts = append([]HeatmapOption{
+		WithRange(0, 1),
+		WithPalette(colors{color.Gray{Y: 235}, color.Gray{Y: 40}}),
+	}, opts...)
+	p, err := PlotHeatmap(missing, labels, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	p.Title.Text = PlotTheme.title("Missing Values")
+	p.Y.Label.Text = "Row"
+	return p, nil
+}
+
+// PlotMissingCooccurrence renders the fraction of rows in which the values of each pair of columns of m are both
+// missing (see MissingCooccurrence) as a heatmap using the specified labels for the columns.  If nil, columns are
+// labelled by index.  Optional behaviour of the heatmap may be configured by specifying HeatmapOptions e.g.
+// WithMask(MaskUpper) to show only one triangle of the (symmetric) matrix.
+func PlotMissingCooccurrence(m mat.Matrix, labels []string, opts ...HeatmapOption) (*plot.Plot, error) {
+	opts = append([]HeatmapOption{WithRange(0, 1), WithCellValues("%.2f", 5)}, opts...)
+	p, err := PlotHeatmap(MissingCooccurrence(m), labels, labels, opts...)
+	if err != nil {
+		return nil, err
+	}
+	p.Title.Text = PlotTheme.title("Missing Value Co-occurrence")
+	return p, nil
+}
@@ -0,0 +1,63 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func missingMatrix() *mat.Dense {
+	nan := math.NaN()
+	return mat.NewDense(4, 3, []float64{
+		1, nan, nan,
+		2, nan, 3,
+		3, 1, nan,
+		4, nan, nan,
+	})
+}
+
+func TestMissingFractions(t *testing.T) {
+	expected := []float64{0, 0.75, 0.75}
+	if fractions := datautils.MissingFractions(missingMatrix()); !floats.Equal(expected, fractions) {
+		t.Errorf("Expected missing fractions: %v but received %v", expected, fractions)
+	}
+}
+
+func TestMissingCooccurrence(t *testing.T) {
+	expected := mat.NewDense(3, 3, []float64{
+		0, 0, 0,
+		0, 0.75, 0.5,
+		0, 0.5, 0.75,
+	})
+	if co := datautils.MissingCooccurrence(missingMatrix()); !mat.Equal(expected, co) {
+		t.Errorf("Expected co-occurrence: %v but received %v", expected, co)
+	}
+}
+
+func TestPlotMissing(t *testing.T) {
+	m := missingMatrix()
+
+	tests := []struct {
+		labels []string
+		valid  bool
+	}{
+		{labels: []string{"a", "b", "c"}, valid: true},
+		{labels: nil, valid: true},
+		{labels: []string{"a", "b"}, valid: false},
+	}
+
+	for i, test := range tests {
+		if _, err := datautils.PlotMissingBar(m, test.labels); (err == nil) != test.valid {
+			t.Errorf("Test %d: Expected valid: %t for bar plot but received error %v", i+1, test.valid, err)
+		}
+		if _, err := datautils.PlotMissingMatrix(m, test.labels, datautils.WithLabelStep(2)); (err == nil) != test.valid {
+			t.Errorf("Test %d: Expected valid: %t for matrix plot but received error %v", i+1, test.valid, err)
+		}
+		if _, err := datautils.PlotMissingCooccurrence(m, test.labels); (err == nil) != test.valid {
+			t.Errorf("Test %d: Expected valid: %t for co-occurrence plot but received error %v", i+1, test.valid, err)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+// Package mlflowexport records evaluation metrics and plots computed with datautils in MLflow experiment tracking
+// so that evaluation results land alongside training runs with a single call.  Results may either be logged to a
+// tracking server through its REST API e.g.
+//
+//	e := mlflowexport.NewClassificationEvaluation(predictions, labels, 0.5)
+//	e.Params = map[string]string{"model": "ranker-v2"}
+//	c := mlflowexport.Client{URL: "http://mlflow:5000"}
+//	err := c.LogEvaluation(ctx, runID, e)
+//
+// or written directly into the directory of a run in an MLflow file store (e.g. mlruns/<experiment>/<run>) with
+// WriteRunDir.
+package mlflowexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+)
+
+// DefaultPlotSize is the width and height, in centimetres, at which plots are rendered if not specified.
+const DefaultPlotSize = 15
+
+// Limits on the number of each kind of entity included in a single log-batch request.  MLflow accepts at most
+// 1000 metrics, 100 params and 100 tags per request and 1000 entities in total.
+const (
+	maxBatchMetrics = 800
+	maxBatchParams  = 100
+	maxBatchTags    = 100
+)
+
+// Evaluation is a set of evaluation results to be recorded against an MLflow run.
+type Evaluation struct {
+	// Metrics are the metric values keyed by metric name.  MLflow restricts names to alphanumerics, underscores,
+	// dashes, periods, spaces and slashes.
+	Metrics map[string]float64
+
+	// Params and Tags are recorded as the run's parameters and tags respectively.
+	Params map[string]string
+	Tags   map[string]string
+
+	// Plots are recorded as artifacts keyed by their path relative to the run's artifact root e.g.
+	// "plots/roc.png".  The image format (e.g. PNG, SVG, PDF) is determined by the file extension of the path.
+	Plots map[string]*plot.Plot
+
+	// PlotWidth and PlotHeight are the size, in centimetres, at which plots are rendered.  If zero,
+	// DefaultPlotSize is used.
+	PlotWidth, PlotHeight float64
+
+	// Step is the step (e.g. training epoch) against which the metrics are recorded.
+	Step int64
+
+	// Timestamp is the time against which the metrics are recorded.  If zero, the current time is used.
+	Timestamp time.Time
+}
+
+// NewClassificationEvaluation creates an Evaluation of binary classifier predictions against labels containing
+// the average precision and ROC AUC, the precision, recall, F1 score, accuracy and MCC at threshold, and
+// precision recall and ROC curve plots (precision_recall.png and roc.png).
+func NewClassificationEvaluation(predictions, labels []float64, threshold float64) Evaluation {
+	pr := datautils.NewPrecisionRecallCurve(predictions, labels)
+	roc := datautils.NewROCCurve(predictions, labels)
+	m := datautils.NewConfusionMatrix(predictions, labels, threshold)
+
+	return Evaluation{
+		Metrics: map[string]float64{
+			"average_precision": pr.AveragePrecision(),
+			"roc_auc":           roc.AUC(),
+			"precision":         m.Precision(),
+			"recall":            m.Recall(),
+			"f1":                m.F1(),
+			"accuracy":          m.Accuracy(),
+			"mcc":               m.MCC(),
+		},
+		Params: map[string]string{
+			"threshold": strconv.FormatFloat(threshold, 'g', -1, 64),
+		},
+		Plots: map[string]*plot.Plot{
+			"precision_recall.png": pr.Plot(),
+			"roc.png":              roc.Plot(),
+		},
+	}
+}
+
+// timestamp returns the time against which metrics are recorded in milliseconds since the Unix epoch.
+func (e Evaluation) timestamp() int64 {
+	t := e.Timestamp
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// render renders each of the plots and calls fn with its artifact path and image in order of path.
+func (e Evaluation) render(fn func(name string, img []byte) error) error {
+	width, height := e.PlotWidth, e.PlotHeight
+	if width == 0 {
+		width = DefaultPlotSize
+	}
+	if height == 0 {
+		height = DefaultPlotSize
+	}
+
+	names := make([]string, 0, len(e.Plots))
+	for name := range e.Plots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		format := strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+		w, err := e.Plots[name].WriterTo(vg.Length(width)*vg.Centimeter, vg.Length(height)*vg.Centimeter, format)
+		if err != nil {
+			return fmt.Errorf("mlflowexport: failed to render plot %q: %w", name, err)
+		}
+		var buf bytes.Buffer
+		if _, err := w.WriteTo(&buf); err != nil {
+			return fmt.Errorf("mlflowexport: failed to render plot %q: %w", name, err)
+		}
+		if err := fn(name, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Client logs evaluations to an MLflow tracking server through its REST API.  Plots are uploaded through the
+// server's artifact proxy so the server must be started with artifact serving enabled (the default since MLflow
+// 2.0).
+type Client struct {
+	// URL is the base URL of the tracking server e.g. http://localhost:5000.
+	URL string
+
+	// Token, if set, is sent as a bearer token with each request.
+	Token string
+
+	// HTTPClient is the client used to make requests.  If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// metric, param and tag are the JSON representations of MLflow entities.
+type metric struct {
+	Key       string              `json:"key"`
+	Value     datautils.JSONFloat `json:"value"`
+	Timestamp int64               `json:"timestamp"`
+	Step      int64               `json:"step"`
+}
+
+type param struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type tag param
+
+// LogEvaluation logs the metrics, parameters and tags of e to the existing run with the specified ID and uploads
+// its plots to the run's artifacts.  Large evaluations are split across as many requests as necessary.
+func (c Client) LogEvaluation(ctx context.Context, runID string, e Evaluation) error {
+	ts := e.timestamp()
+	metrics := make([]metric, 0, len(e.Metrics))
+	for _, k := range metricNames(e.Metrics) {
+		metrics = append(metrics, metric{Key: k, Value: datautils.JSONFloat(e.Metrics[k]), Timestamp: ts, Step: e.Step})
+	}
+	params := make([]param, 0, len(e.Params))
+	for _, k := range sortedKeys(e.Params) {
+		params = append(params, param{Key: k, Value: e.Params[k]})
+	}
+	tags := make([]tag, 0, len(e.Tags))
+	for _, k := range sortedKeys(e.Tags) {
+		tags = append(tags, tag{Key: k, Value: e.Tags[k]})
+	}
+
+	for len(metrics) > 0 || len(params) > 0 || len(tags) > 0 {
+		batch := struct {
+			RunID   string   `json:"run_id"`
+			Metrics []metric `json:"metrics,omitempty"`
+			Params  []param  `json:"params,omitempty"`
+			Tags    []tag    `json:"tags,omitempty"`
+		}{RunID: runID}
+		n := batchSize(len(metrics), maxBatchMetrics)
+		batch.Metrics, metrics = metrics[:n], metrics[n:]
+		n = batchSize(len(params), maxBatchParams)
+		batch.Params, params = params[:n], params[n:]
+		n = batchSize(len(tags), maxBatchTags)
+		batch.Tags, tags = tags[:n], tags[n:]
+
+		body, err := json.Marshal(batch)
+		if err != nil {
+			return fmt.Errorf("mlflowexport: failed to encode metrics: %w", err)
+		}
+		if err := c.do(ctx, http.MethodPost, "/api/2.0/mlflow/runs/log-batch", "application/json", body, nil); err != nil {
+			return err
+		}
+	}
+
+	if len(e.Plots) == 0 {
+		return nil
+	}
+	root, err := c.artifactRoot(ctx, runID)
+	if err != nil {
+		return err
+	}
+	return e.render(func(name string, img []byte) error {
+		return c.do(ctx, http.MethodPut, "/api/2.0/mlflow-artifacts/artifacts/"+path.Join(root, name), "application/octet-stream", img, nil)
+	})
+}
+
+// artifactRoot returns the path of the run's artifact root relative to the tracking server's artifact proxy.
+func (c Client) artifactRoot(ctx context.Context, runID string) (string, error) {
+	var resp struct {
+		Run struct {
+			Info struct {
+				ArtifactURI string `json:"artifact_uri"`
+			} `json:"info"`
+		} `json:"run"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/2.0/mlflow/runs/get?run_id="+url.QueryEscape(runID), "", nil, &resp); err != nil {
+		return "", err
+	}
+	uri, err := url.Parse(resp.Run.Info.ArtifactURI)
+	if err != nil || uri.Scheme != "mlflow-artifacts" {
+		return "", fmt.Errorf("mlflowexport: run %s artifact location %q is not served by the tracking server", runID, resp.Run.Info.ArtifactURI)
+	}
+	if uri.Opaque != "" {
+		return strings.Trim(uri.Opaque, "/"), nil
+	}
+	return strings.Trim(uri.Path, "/"), nil
+}
+
+// do makes a request to the tracking server decoding any JSON response into v if it is not nil.
+func (c Client) do(ctx context.Context, method, endpoint, contentType string, body []byte, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.URL, "/")+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("mlflowexport: failed to create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mlflowexport: request to %s failed: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		var apiErr struct {
+			Code    string `json:"error_code"`
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("mlflowexport: request to %s failed: %s: %s: %s", endpoint, resp.Status, apiErr.Code, apiErr.Message)
+		}
+		return fmt.Errorf("mlflowexport: request to %s failed: %s", endpoint, resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("mlflowexport: failed to decode response from %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// WriteRunDir writes e into the directory of an existing run in an MLflow file store (e.g.
+// mlruns/<experiment id>/<run id>) using the store's layout: metric values are appended to metrics/<name>,
+// parameters and tags are written to params/<name> and tags/<name> respectively and plots are saved beneath
+// artifacts.  This allows results to be recorded without a tracking server e.g. for later upload or where the
+// file store is shared.
+func WriteRunDir(dir string, e Evaluation) error {
+	ts := e.timestamp()
+	for _, k := range metricNames(e.Metrics) {
+		line := fmt.Sprintf("%d %s %d\n", ts, strconv.FormatFloat(e.Metrics[k], 'g', -1, 64), e.Step)
+		if err := writeRunFile(filepath.Join(dir, "metrics", k), []byte(line), true); err != nil {
+			return err
+		}
+	}
+	for _, k := range sortedKeys(e.Params) {
+		if err := writeRunFile(filepath.Join(dir, "params", k), []byte(e.Params[k]), false); err != nil {
+			return err
+		}
+	}
+	for _, k := range sortedKeys(e.Tags) {
+		if err := writeRunFile(filepath.Join(dir, "tags", k), []byte(e.Tags[k]), false); err != nil {
+			return err
+		}
+	}
+	return e.render(func(name string, img []byte) error {
+		return writeRunFile(filepath.Join(dir, "artifacts", filepath.FromSlash(name)), img, false)
+	})
+}
+
+// writeRunFile writes data to the file at path, creating any missing parent directories, either appending to or
+// replacing any existing content.
+func writeRunFile(path string, data []byte, appendData bool) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("mlflowexport: %w", err)
+	}
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendData {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flag, 0644)
+	if err != nil {
+		return fmt.Errorf("mlflowexport: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("mlflowexport: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("mlflowexport: %w", err)
+	}
+	return nil
+}
+
+// batchSize returns the number of the remaining n entities to include in the next batch.
+func batchSize(n, max int) int {
+	if n > max {
+		return max
+	}
+	return n
+}
+
+// metricNames returns the names of the metrics in m in sorted order.
+func metricNames(m map[string]float64) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
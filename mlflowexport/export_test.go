@@ -0,0 +1,197 @@
+package mlflowexport_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/james-bowman/datautils/mlflowexport"
+	"gonum.org/v1/plot"
+)
+
+// trackingServer is a fake MLflow tracking server recording the requests it receives.
+type trackingServer struct {
+	batches   []map[string]json.RawMessage
+	artifacts map[string]string
+	auth      []string
+}
+
+func (s *trackingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/2.0/mlflow/runs/log-batch":
+		var batch map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.batches = append(s.batches, batch)
+		fmt.Fprint(w, "{}")
+	case r.Method == http.MethodGet && r.URL.Path == "/api/2.0/mlflow/runs/get":
+		if r.URL.Query().Get("run_id") != "run1" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error_code": "RESOURCE_DOES_NOT_EXIST", "message": "Run not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"run": {"info": {"run_id": "run1", "artifact_uri": "mlflow-artifacts:/0/run1/artifacts"}}}`)
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/2.0/mlflow-artifacts/artifacts/"):
+		data, _ := io.ReadAll(r.Body)
+		s.artifacts[strings.TrimPrefix(r.URL.Path, "/api/2.0/mlflow-artifacts/artifacts/")] = string(data)
+		fmt.Fprint(w, "{}")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newPlot(t *testing.T) *plot.Plot {
+	p, err := plot.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLogEvaluation(t *testing.T) {
+	tracking := &trackingServer{artifacts: make(map[string]string)}
+	srv := httptest.NewServer(tracking)
+	defer srv.Close()
+
+	e := mlflowexport.Evaluation{
+		Metrics:   map[string]float64{"roc_auc": 0.75, "precision": math.NaN()},
+		Params:    map[string]string{"model": "ranker-v2"},
+		Tags:      map[string]string{"stage": "eval"},
+		Plots:     map[string]*plot.Plot{"plots/roc.svg": newPlot(t)},
+		Step:      3,
+		Timestamp: time.Unix(1700000000, 0),
+	}
+	c := mlflowexport.Client{URL: srv.URL + "/", Token: "secret"}
+	if err := c.LogEvaluation(context.Background(), "run1", e); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tracking.batches) != 1 {
+		t.Fatalf("Expected 1 batch but received %d", len(tracking.batches))
+	}
+	batch := tracking.batches[0]
+	expected := map[string]string{
+		"run_id":  `"run1"`,
+		"metrics": `[{"key":"precision","value":"NaN","timestamp":1700000000000,"step":3},{"key":"roc_auc","value":0.75,"timestamp":1700000000000,"step":3}]`,
+		"params":  `[{"key":"model","value":"ranker-v2"}]`,
+		"tags":    `[{"key":"stage","value":"eval"}]`,
+	}
+	for k, v := range expected {
+		if string(batch[k]) != v {
+			t.Errorf("Expected %s: %s but received %s", k, v, batch[k])
+		}
+	}
+	if _, ok := tracking.artifacts["0/run1/artifacts/plots/roc.svg"]; !ok || len(tracking.artifacts) != 1 {
+		t.Errorf("Expected plot artifact 0/run1/artifacts/plots/roc.svg but received %v", tracking.artifacts)
+	}
+	for _, auth := range tracking.auth {
+		if auth != "Bearer secret" {
+			t.Errorf("Expected bearer token but received %q", auth)
+		}
+	}
+}
+
+func TestLogEvaluationBatches(t *testing.T) {
+	tracking := &trackingServer{artifacts: make(map[string]string)}
+	srv := httptest.NewServer(tracking)
+	defer srv.Close()
+
+	e := mlflowexport.Evaluation{Metrics: make(map[string]float64), Params: make(map[string]string)}
+	for i := 0; i < 1000; i++ {
+		e.Metrics[fmt.Sprintf("metric_%04d", i)] = float64(i)
+	}
+	for i := 0; i < 150; i++ {
+		e.Params[fmt.Sprintf("param_%03d", i)] = "x"
+	}
+	if err := (mlflowexport.Client{URL: srv.URL}).LogEvaluation(context.Background(), "run1", e); err != nil {
+		t.Fatal(err)
+	}
+
+	var metrics, params []int
+	for _, batch := range tracking.batches {
+		var m, p []json.RawMessage
+		json.Unmarshal(batch["metrics"], &m)
+		json.Unmarshal(batch["params"], &p)
+		metrics = append(metrics, len(m))
+		params = append(params, len(p))
+	}
+	if !reflect.DeepEqual(metrics, []int{800, 200}) || !reflect.DeepEqual(params, []int{100, 50}) {
+		t.Errorf("Expected batches of [800 200] metrics and [100 50] params but received %v and %v", metrics, params)
+	}
+}
+
+func TestLogEvaluationErrors(t *testing.T) {
+	tracking := &trackingServer{artifacts: make(map[string]string)}
+	srv := httptest.NewServer(tracking)
+	defer srv.Close()
+
+	e := mlflowexport.Evaluation{Metrics: map[string]float64{"f1": 0.5}, Plots: map[string]*plot.Plot{"roc.png": newPlot(t)}}
+	err := (mlflowexport.Client{URL: srv.URL}).LogEvaluation(context.Background(), "missing", e)
+	if err == nil || !strings.Contains(err.Error(), "Run not found") {
+		t.Errorf("Expected error reporting the missing run but received %v", err)
+	}
+
+	err = (mlflowexport.Client{URL: srv.URL + "/unknown"}).LogEvaluation(context.Background(), "run1", e)
+	if err == nil {
+		t.Errorf("Expected error for unknown endpoint")
+	}
+}
+
+func TestWriteRunDir(t *testing.T) {
+	dir := t.TempDir()
+	e := mlflowexport.Evaluation{
+		Metrics:   map[string]float64{"roc_auc": 0.75},
+		Params:    map[string]string{"model": "ranker-v2"},
+		Tags:      map[string]string{"stage": "eval"},
+		Plots:     map[string]*plot.Plot{"plots/roc.png": newPlot(t)},
+		Timestamp: time.Unix(1700000000, 0),
+	}
+	if err := mlflowexport.WriteRunDir(dir, e); err != nil {
+		t.Fatal(err)
+	}
+	e.Metrics["roc_auc"], e.Step = 0.8, 1
+	if err := mlflowexport.WriteRunDir(dir, e); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"metrics/roc_auc": "1700000000000 0.75 0\n1700000000000 0.8 1\n",
+		"params/model":    "ranker-v2",
+		"tags/stage":      "eval",
+	}
+	for name, content := range expected {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("Expected %s to contain %q but found %q", name, content, data)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dir, "artifacts", "plots", "roc.png")); err != nil || info.Size() == 0 {
+		t.Errorf("Expected non-empty plot artifact: %v", err)
+	}
+}
+
+func TestNewClassificationEvaluation(t *testing.T) {
+	e := mlflowexport.NewClassificationEvaluation([]float64{0.9, 0.8, 0.3, 0.1}, []float64{1, 0, 1, 0}, 0.5)
+	if e.Metrics["roc_auc"] != 0.75 || e.Metrics["precision"] != 0.5 || e.Params["threshold"] != "0.5" {
+		t.Errorf("Unexpected evaluation: %+v", e)
+	}
+	if len(e.Plots) != 2 || e.Plots["roc.png"] == nil || e.Plots["precision_recall.png"] == nil {
+		t.Errorf("Expected precision recall and ROC plots but received %v", e.Plots)
+	}
+}
@@ -0,0 +1,233 @@
+package datautils
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot"
+)
+
+// TopKAccuracy calculates the top-k accuracy of multi-class predictions.  This is the proportion of samples for
+// which the true class is among the k classes with the highest predicted scores.  scores is a matrix of predicted
+// scores (e.g. probabilities) with a row for each sample and a column for each class and classes contains the
+// index of the true class (column) of each sample.  Where the score of the true class is tied with other classes,
+// the tie is resolved in favour of the true class.
+func TopKAccuracy(scores mat.Matrix, classes []int, k int) float64 {
+	r, c := scores.Dims()
+	if r != len(classes) {
+		panic(ErrLengthMismatch)
+	}
+	if k < 1 || k > c {
+		panic(ErrOutOfBounds)
+	}
+
+	var hits int
+	for i, class := range classes {
+		truth := scores.At(i, class)
+		var higher int
+		for j := 0; j < c; j++ {
+			if scores.At(i, j) > truth {
+				higher++
+			}
+		}
+		if higher < k {
+			hits++
+		}
+	}
+	return float64(hits) / float64(r)
+}
+
+// OneVsRestCurves contains the precision recall and ROC curves for evaluating a multi-class model one-vs-rest.
+// Each class is treated in turn as the positive class, with all other classes as negative, giving a curve per
+// class.  Micro-averaged curves pool the (sample, class) pairs of all classes into a single binary problem so
+// that each sample contributes equally.  Macro-averaged curves take the unweighted mean of the per-class curves
+// so that each class contributes equally irrespective of its frequency.  Classes with no samples are excluded
+// from the macro averages as their curves are undefined.
+type OneVsRestCurves struct {
+	// PrecisionRecall contains the precision recall curve of each class, indexed by class index
+	PrecisionRecall []PrecisionRecallCurve
+
+	// ROC contains the ROC curve of each class, indexed by class index
+	ROC []ROCCurve
+
+	// MicroPrecisionRecall is the micro-averaged precision recall curve
+	MicroPrecisionRecall PrecisionRecallCurve
+
+	// MicroROC is the micro-averaged ROC curve
+	MicroROC ROCCurve
+
+	// MacroPrecisionRecall is the macro-averaged precision recall curve formed by averaging the interpolated
+	// precision (see PrecisionRecallCurve.InterpolatedPrecisionAt) of each class at every recall value
+	// occurring in any of the per-class curves.  As it does not correspond to a ranking of predictions, it has no
+	// Thresholds and only its Precision, Recall and AveragePrecision are meaningful.
+	MacroPrecisionRecall PrecisionRecallCurve
+
+	// MacroROC is the macro-averaged ROC curve formed by averaging the interpolated true positive rate (see
+	// ROCCurve.TPRAt) of each class at every false positive rate occurring in any of the per-class curves.  As
+	// it does not correspond to a ranking of predictions, it has no Thresholds.
+	MacroROC ROCCurve
+
+	// included records the classes included in the macro averages
+	included []int
+}
+
+// NewOneVsRestCurves creates per-class, micro-averaged and macro-averaged precision recall and ROC curves for the
+// supplied multi-class predictions.  scores is a matrix of predicted scores (e.g. probabilities) with a row for
+// each sample and a column for each class and classes contains the index of the true class (column) of each
+// sample (see LabelEncoder).
+func NewOneVsRestCurves(scores mat.Matrix, classes []int) OneVsRestCurves {
+	r, _ := scores.Dims()
+	if r != len(classes) {
+		panic(ErrLengthMismatch)
+	}
+	return newOneVsRestCurves(scores, func(j int) []float64 { return Binarize(classes, j) })
+}
+
+// newOneVsRestCurves creates the per-class and averaged curves for the scores where binary returns the binary
+// labels of the samples for class (column) j.
+func newOneVsRestCurves(scores mat.Matrix, binary func(j int) []float64) OneVsRestCurves {
+	r, c := scores.Dims()
+	curves := OneVsRestCurves{
+		PrecisionRecall: make([]PrecisionRecallCurve, c),
+		ROC:             make([]ROCCurve, c),
+	}
+
+	predictions := make([]float64, 0, r*c)
+	labels := make([]float64, 0, r*c)
+	for j := 0; j < c; j++ {
+		col := mat.Col(nil, j, scores)
+		labs := binary(j)
+		curves.PrecisionRecall[j] = NewPrecisionRecallCurve(col, labs)
+		curves.ROC[j] = NewROCCurve(col, labs)
+		if curves.PrecisionRecall[j].positives > 0 {
+			curves.included = append(curves.included, j)
+		}
+		predictions = append(predictions, col...)
+		labels = append(labels, labs...)
+	}
+
+	curves.MicroPrecisionRecall = NewPrecisionRecallCurve(predictions, labels)
+	curves.MicroROC = NewROCCurve(predictions, labels)
+	curves.MacroPrecisionRecall = curves.macroPrecisionRecall()
+	curves.MacroROC = curves.macroROC()
+
+	return curves
+}
+
+func (o OneVsRestCurves) macroPrecisionRecall() PrecisionRecallCurve {
+	var grid []float64
+	for _, j := range o.included {
+		grid = append(grid, o.PrecisionRecall[j].Recall...)
+	}
+	grid = distinct(grid)
+
+	// precision recall curves are ordered by descending recall, ending with precision 1 @ recall 0
+	curve := PrecisionRecallCurve{
+		Precision: make([]float64, len(grid)),
+		Recall:    make([]float64, len(grid)),
+	}
+	for i, r := range grid {
+		k := len(grid) - 1 - i
+		curve.Recall[k] = r
+		if r == 0 {
+			curve.Precision[k] = 1
+			continue
+		}
+		for _, j := range o.included {
+			curve.Precision[k] += o.PrecisionRecall[j].InterpolatedPrecisionAt(r)
+		}
+		curve.Precision[k] /= float64(len(o.included))
+	}
+	return curve
+}
+
+func (o OneVsRestCurves) macroROC() ROCCurve {
+	grid := []float64{0}
+	for _, j := range o.included {
+		grid = append(grid, o.ROC[j].FPR...)
+	}
+	grid = distinct(grid)
+
+	// start the curve at the origin, as for all ROC curves, before averaging at each false positive rate
+	curve := ROCCurve{
+		FPR: append([]float64{0}, grid...),
+		TPR: make([]float64, len(grid)+1),
+	}
+	for i, fpr := range grid {
+		for _, j := range o.included {
+			curve.TPR[i+1] += o.ROC[j].TPRAt(fpr)
+		}
+		curve.TPR[i+1] /= float64(len(o.included))
+	}
+	return curve
+}
+
+// distinct returns the distinct values of s in ascending order.  s is sorted in place.
+func distinct(s []float64) []float64 {
+	sort.Float64s(s)
+	var n int
+	for i, v := range s {
+		if i == 0 || v != s[n-1] {
+			s[n] = v
+			n++
+		}
+	}
+	return s[:n]
+}
+
+// MacroAveragePrecision returns the unweighted mean of the average precision of each class.
+func (o OneVsRestCurves) MacroAveragePrecision() float64 {
+	var sum float64
+	for _, j := range o.included {
+		sum += o.PrecisionRecall[j].AveragePrecision()
+	}
+	return sum / float64(len(o.included))
+}
+
+// MacroAUC returns the unweighted mean of the area under the ROC curve of each class.
+func (o OneVsRestCurves) MacroAUC() float64 {
+	var sum float64
+	for _, j := range o.included {
+		sum += o.ROC[j].AUC()
+	}
+	return sum / float64(len(o.included))
+}
+
+// PlotPrecisionRecall renders the per-class, micro-averaged and macro-averaged precision recall curves overlaid
+// on a single plot (see PlotPrecisionRecallCurves).  names contains the name of each class for the legend (e.g.
+// LabelEncoder.Classes) or may be nil in which case the class indices are used.
+func (o OneVsRestCurves) PlotPrecisionRecall(names []string) *plot.Plot {
+	curves := map[string]PrecisionRecallCurve{
+		"micro-average": o.MicroPrecisionRecall,
+		"macro-average": o.MacroPrecisionRecall,
+	}
+	for j, c := range o.PrecisionRecall {
+		curves[className(names, j)] = c
+	}
+	return PlotPrecisionRecallCurves(curves)
+}
+
+// PlotROC renders the per-class, micro-averaged and macro-averaged ROC curves overlaid on a single plot (see
+// PlotROCCurves).  names contains the name of each class for the legend (e.g. LabelEncoder.Classes) or may be
+// nil in which case the class indices are used.
+func (o OneVsRestCurves) PlotROC(names []string) *plot.Plot {
+	curves := map[string]ROCCurve{
+		"micro-average": o.MicroROC,
+		"macro-average": o.MacroROC,
+	}
+	for j, c := range o.ROC {
+		curves[className(names, j)] = c
+	}
+	return PlotROCCurves(curves)
+}
+
+func className(names []string, class int) string {
+	if names == nil {
+		return fmt.Sprintf("class %d", class)
+	}
+	if len(names) <= class {
+		panic(ErrOutOfBounds)
+	}
+	return names[class]
+}
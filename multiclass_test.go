@@ -0,0 +1,85 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestTopKAccuracy(t *testing.T) {
+	scores := mat.NewDense(4, 3, []float64{
+		0.7, 0.2, 0.1,
+		0.2, 0.3, 0.5,
+		0.1, 0.6, 0.3,
+		0.4, 0.4, 0.2,
+	})
+	classes := []int{0, 1, 2, 1}
+
+	tests := []struct {
+		k        int
+		accuracy float64
+	}{
+		{k: 1, accuracy: 0.5},
+		{k: 2, accuracy: 1},
+		{k: 3, accuracy: 1},
+	}
+
+	for i, test := range tests {
+		if accuracy := datautils.TopKAccuracy(scores, classes, test.k); accuracy != test.accuracy {
+			t.Errorf("Test %d: Expected top-%d accuracy: %v but received %v", i+1, test.k, test.accuracy, accuracy)
+		}
+	}
+}
+
+func TestOneVsRestCurves(t *testing.T) {
+	scores := mat.NewDense(4, 3, []float64{
+		0.7, 0.2, 0.1,
+		0.2, 0.3, 0.5,
+		0.1, 0.6, 0.3,
+		0.4, 0.4, 0.2,
+	})
+	classes := []int{0, 2, 1, 2}
+
+	curves := datautils.NewOneVsRestCurves(scores, classes)
+
+	if len(curves.ROC) != 3 || len(curves.PrecisionRecall) != 3 {
+		t.Fatalf("Expected 3 per-class curves but received %d ROC and %d PR", len(curves.ROC), len(curves.PrecisionRecall))
+	}
+
+	aucs := []float64{1, 1, 0.75}
+	for j, auc := range aucs {
+		if v := curves.ROC[j].AUC(); v != auc {
+			t.Errorf("Class %d: Expected AUC: %v but received %v", j, auc, v)
+		}
+		expected := datautils.NewPrecisionRecallCurve(mat.Col(nil, j, scores), datautils.Binarize(classes, j))
+		if ap := curves.PrecisionRecall[j].AveragePrecision(); ap != expected.AveragePrecision() {
+			t.Errorf("Class %d: Expected AP: %v but received %v", j, expected.AveragePrecision(), ap)
+		}
+	}
+
+	if auc, expected := curves.MacroAUC(), 2.75/3; math.Abs(auc-expected) > 1e-12 {
+		t.Errorf("Expected macro AUC: %v but received %v", expected, auc)
+	}
+
+	// pooled positive scores 0.7, 0.6 and 0.5 outrank all 8 negative scores while 0.2 outranks 2 and ties with 2
+	// so 27 of the 32 positive/negative pairs are correctly ordered (counting ties as half)
+	if auc, expected := curves.MicroROC.AUC(), 27.0/32.0; auc != expected {
+		t.Errorf("Expected micro AUC: %v but received %v", expected, auc)
+	}
+
+	macro := curves.MacroROC
+	if n := len(macro.FPR); macro.FPR[0] != 0 || macro.TPR[0] != 0 || macro.FPR[n-1] != 1 || macro.TPR[n-1] != 1 {
+		t.Errorf("Expected macro ROC curve from (0, 0) to (1, 1) but received FPR %v and TPR %v", macro.FPR, macro.TPR)
+	}
+	// mean TPR is 5/6 at FPR 0 and 1/3 rising linearly to 1 at FPR 1/2
+	if auc, expected := macro.AUC(), 67.0/72.0; math.Abs(auc-expected) > 1e-12 {
+		t.Errorf("Expected macro ROC curve AUC: %v but received %v", expected, auc)
+	}
+
+	pr := curves.MacroPrecisionRecall
+	if n := len(pr.Recall); pr.Recall[0] != 1 || pr.Recall[n-1] != 0 || pr.Precision[n-1] != 1 {
+		t.Errorf("Expected macro precision recall curve from recall 1 to 0 but received precision %v and recall %v", pr.Precision, pr.Recall)
+	}
+}
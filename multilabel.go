@@ -0,0 +1,180 @@
+package datautils
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// validateMultiLabel checks that the matrices of scores and binary labels of a multi-label evaluation have the
+// same dimensions.
+func validateMultiLabel(scores, labels mat.Matrix) {
+	r, c := scores.Dims()
+	if lr, lc := labels.Dims(); lr != r || lc != c {
+		panic(mat.ErrShape)
+	}
+}
+
+// NewMultiLabelCurves creates per-label, micro-averaged and macro-averaged precision recall and ROC curves for
+// multi-label predictions where each sample may belong to any number of labels (classes).  scores is a matrix of
+// predicted scores (e.g. probabilities) and labels a binary matrix, in which a value greater than 0 indicates the
+// sample has the label, each with a row for each sample and a column for each label.  Each label is evaluated in
+// turn as a binary problem, so the average precision of label j is given by PrecisionRecall[j].AveragePrecision(),
+// and micro-averaged curves pool the (sample, label) pairs of all labels (see OneVsRestCurves).
+func NewMultiLabelCurves(scores, labels mat.Matrix) OneVsRestCurves {
+	validateMultiLabel(scores, labels)
+	return newOneVsRestCurves(scores, func(j int) []float64 { return mat.Col(nil, j, labels) })
+}
+
+// higherOrEqual returns the number of the values in row i of scores greater than or equal to v and, of those, the
+// number that are relevant (greater than 0) in row i of labels.
+func higherOrEqual(scores, labels mat.Matrix, i int, v float64) (n, relevant int) {
+	_, c := scores.Dims()
+	for k := 0; k < c; k++ {
+		if scores.At(i, k) >= v {
+			n++
+			if labels.At(i, k) > 0 {
+				relevant++
+			}
+		}
+	}
+	return n, relevant
+}
+
+// SampleAveragePrecisions calculates the average precision of the ranking of each sample's labels by score for
+// multi-label predictions (see NewMultiLabelCurves for a description of the arguments).  For each of a sample's
+// relevant labels, the proportion of the labels scored at least as highly that are also relevant is calculated and
+// the sample's average precision is the mean of these proportions.  Samples with no relevant labels (or only
+// relevant labels) have an average precision of 1.
+func SampleAveragePrecisions(scores, labels mat.Matrix) []float64 {
+	validateMultiLabel(scores, labels)
+	r, c := scores.Dims()
+
+	precisions := make([]float64, r)
+	for i := range precisions {
+		var sum float64
+		var relevant int
+		for j := 0; j < c; j++ {
+			if labels.At(i, j) > 0 {
+				n, rel := higherOrEqual(scores, labels, i, scores.At(i, j))
+				sum += float64(rel) / float64(n)
+				relevant++
+			}
+		}
+		if relevant == 0 || relevant == c {
+			precisions[i] = 1
+			continue
+		}
+		precisions[i] = sum / float64(relevant)
+	}
+	return precisions
+}
+
+// LabelRankingAveragePrecision calculates the label ranking average precision (LRAP) of multi-label predictions
+// (see NewMultiLabelCurves for a description of the arguments), the mean of the average precision of each sample
+// (see SampleAveragePrecisions).  LRAP ranges up to 1, which indicates every sample's relevant labels are scored
+// above its irrelevant labels.
+func LabelRankingAveragePrecision(scores, labels mat.Matrix) float64 {
+	precisions := SampleAveragePrecisions(scores, labels)
+	var sum float64
+	for _, p := range precisions {
+		sum += p
+	}
+	return sum / float64(len(precisions))
+}
+
+// LabelRankingLoss calculates the ranking loss of multi-label predictions (see NewMultiLabelCurves for a
+// description of the arguments).  For each sample, this is the proportion of the pairs of a relevant and an
+// irrelevant label that are incorrectly ordered i.e. where the irrelevant label is scored at least as highly as the
+// relevant label.  The loss is averaged over all samples and ranges from 0, for perfect rankings, to 1.  Samples
+// with no relevant labels (or only relevant labels) have no pairs and contribute 0.
+func LabelRankingLoss(scores, labels mat.Matrix) float64 {
+	validateMultiLabel(scores, labels)
+	r, c := scores.Dims()
+
+	var sum float64
+	for i := 0; i < r; i++ {
+		var misordered, pairs int
+		for j := 0; j < c; j++ {
+			if labels.At(i, j) <= 0 {
+				continue
+			}
+			for k := 0; k < c; k++ {
+				if labels.At(i, k) <= 0 {
+					pairs++
+					if scores.At(i, k) >= scores.At(i, j) {
+						misordered++
+					}
+				}
+			}
+		}
+		if pairs > 0 {
+			sum += float64(misordered) / float64(pairs)
+		}
+	}
+	return sum / float64(r)
+}
+
+// OneError calculates the one-error of multi-label predictions (see NewMultiLabelCurves for a description of the
+// arguments), the proportion of samples whose highest scored label is not relevant.  Where several labels share the
+// highest score, the first (lowest column index) is taken.  0 indicates every sample's top label is relevant.
+func OneError(scores, labels mat.Matrix) float64 {
+	validateMultiLabel(scores, labels)
+	r, c := scores.Dims()
+
+	var errors int
+	for i := 0; i < r; i++ {
+		top := 0
+		for j := 1; j < c; j++ {
+			if scores.At(i, j) > scores.At(i, top) {
+				top = j
+			}
+		}
+		if labels.At(i, top) <= 0 {
+			errors++
+		}
+	}
+	return float64(errors) / float64(r)
+}
+
+// CoverageError calculates the coverage error of multi-label predictions (see NewMultiLabelCurves for a
+// description of the arguments).  This is the mean number of the highest scored labels that must be included to
+// cover all of a sample's relevant labels, with tied scores resolved against the relevant labels.  The best value is
+// the mean number of relevant labels per sample.  Samples with no relevant labels contribute 0.
+func CoverageError(scores, labels mat.Matrix) float64 {
+	validateMultiLabel(scores, labels)
+	r, c := scores.Dims()
+
+	var sum float64
+	for i := 0; i < r; i++ {
+		var lowest float64
+		found := false
+		for j := 0; j < c; j++ {
+			if v := scores.At(i, j); labels.At(i, j) > 0 && (!found || v < lowest) {
+				lowest, found = v, true
+			}
+		}
+		if found {
+			n, _ := higherOrEqual(scores, labels, i, lowest)
+			sum += float64(n)
+		}
+	}
+	return sum / float64(r)
+}
+
+// HammingLoss calculates the Hamming loss of multi-label predictions (see NewMultiLabelCurves for a description of
+// the arguments), the proportion of all (sample, label) pairs that are misclassified.  Labels are predicted for a
+// sample where their score is greater than or equal to the threshold.  0 indicates every label of every sample is
+// correctly predicted.
+func HammingLoss(scores, labels mat.Matrix, threshold float64) float64 {
+	validateMultiLabel(scores, labels)
+	r, c := scores.Dims()
+
+	var wrong int
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if (scores.At(i, j) >= threshold) != (labels.At(i, j) > 0) {
+				wrong++
+			}
+		}
+	}
+	return float64(wrong) / float64(r*c)
+}
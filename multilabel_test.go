@@ -0,0 +1,125 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	multiLabelScores = mat.NewDense(3, 3, []float64{
+		0.75, 0.5, 1,
+		1, 0.2, 0.1,
+		0.3, 0.8, 0.6,
+	})
+	multiLabels = mat.NewDense(3, 3, []float64{
+		1, 0, 0,
+		0, 0, 1,
+		0, 1, 1,
+	})
+)
+
+func TestNewMultiLabelCurves(t *testing.T) {
+	curves := datautils.NewMultiLabelCurves(multiLabelScores, multiLabels)
+
+	// label 0: ranked 1 (-), 0.75 (+), 0.3 (-); label 1: ranked 0.8 (+), 0.5 (-), 0.2 (-); label 2: ranked 1 (-),
+	// 0.6 (+), 0.1 (+)
+	expected := []float64{0.5, 1, (0.5 + 2.0/3) / 2}
+	for j, ap := range expected {
+		if v := curves.PrecisionRecall[j].AveragePrecision(); math.Abs(v-ap) > 1e-12 {
+			t.Errorf("Label %d: Expected AP: %v but received %v", j, ap, v)
+		}
+	}
+	if v, e := curves.MacroAveragePrecision(), (expected[0]+expected[1]+expected[2])/3; math.Abs(v-e) > 1e-12 {
+		t.Errorf("Expected macro AP: %v but received %v", e, v)
+	}
+
+	micro := datautils.NewPrecisionRecallCurve(flatten(multiLabelScores.T()), flatten(multiLabels.T()))
+	if v, e := curves.MicroPrecisionRecall.AveragePrecision(), micro.AveragePrecision(); math.Abs(v-e) > 1e-12 {
+		t.Errorf("Expected micro AP: %v but received %v", e, v)
+	}
+}
+
+func TestLabelRankingAveragePrecision(t *testing.T) {
+	scores := mat.NewDense(2, 3, []float64{0.75, 0.5, 1, 1, 0.2, 0.1})
+	labels := mat.NewDense(2, 3, []float64{1, 0, 0, 0, 0, 1})
+	if v := datautils.LabelRankingAveragePrecision(scores, labels); math.Abs(v-5.0/12) > 1e-12 {
+		t.Errorf("Expected LRAP: %v but received %v", 5.0/12, v)
+	}
+
+	// samples without relevant labels score 1
+	if v := datautils.LabelRankingAveragePrecision(scores, mat.NewDense(2, 3, nil)); v != 1 {
+		t.Errorf("Expected LRAP: 1 but received %v", v)
+	}
+}
+
+func TestSampleAveragePrecisions(t *testing.T) {
+	expected := []float64{0.5, 1.0 / 3, 1}
+	if v := datautils.SampleAveragePrecisions(multiLabelScores, multiLabels); !floats.EqualApprox(v, expected, 1e-12) {
+		t.Errorf("Expected average precisions: %v but received %v", expected, v)
+	}
+}
+
+func TestLabelRankingLoss(t *testing.T) {
+	tests := []struct {
+		scores, labels *mat.Dense
+		expected       float64
+	}{
+		// misordered pairs: row 0 1 of 2, row 1 2 of 2, row 2 0 of 2
+		{scores: multiLabelScores, labels: multiLabels, expected: 0.5},
+		// tied scores are misordered
+		{scores: mat.NewDense(1, 2, []float64{0.5, 0.5}), labels: mat.NewDense(1, 2, []float64{1, 0}), expected: 1},
+		{scores: multiLabelScores, labels: mat.NewDense(3, 3, nil), expected: 0},
+	}
+
+	for i, test := range tests {
+		if v := datautils.LabelRankingLoss(test.scores, test.labels); math.Abs(v-test.expected) > 1e-12 {
+			t.Errorf("Test %d: Expected ranking loss: %v but received %v", i+1, test.expected, v)
+		}
+	}
+}
+
+func TestOneError(t *testing.T) {
+	// the top scored labels of rows 0 and 1 are not relevant
+	if v := datautils.OneError(multiLabelScores, multiLabels); math.Abs(v-2.0/3) > 1e-12 {
+		t.Errorf("Expected one-error: %v but received %v", 2.0/3, v)
+	}
+}
+
+func TestCoverageError(t *testing.T) {
+	tests := []struct {
+		scores, labels *mat.Dense
+		expected       float64
+	}{
+		{
+			scores:   mat.NewDense(2, 3, []float64{1, 0, 0, 0, 1, 1}),
+			labels:   mat.NewDense(2, 3, []float64{1, 0, 0, 0, 1, 1}),
+			expected: 1.5,
+		},
+		{scores: multiLabelScores, labels: multiLabels, expected: (2 + 3 + 2.0) / 3},
+		{scores: multiLabelScores, labels: mat.NewDense(3, 3, nil), expected: 0},
+	}
+
+	for i, test := range tests {
+		if v := datautils.CoverageError(test.scores, test.labels); math.Abs(v-test.expected) > 1e-12 {
+			t.Errorf("Test %d: Expected coverage error: %v but received %v", i+1, test.expected, v)
+		}
+	}
+}
+
+func TestHammingLoss(t *testing.T) {
+	// misclassified pairs: (0, 1), (0, 2), (1, 0), (1, 2)
+	if v := datautils.HammingLoss(multiLabelScores, multiLabels, 0.5); math.Abs(v-4.0/9) > 1e-12 {
+		t.Errorf("Expected Hamming loss: %v but received %v", 4.0/9, v)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for mismatched dimensions but received none")
+		}
+	}()
+	datautils.HammingLoss(multiLabelScores, mat.NewDense(3, 2, nil), 0.5)
+}
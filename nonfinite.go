@@ -0,0 +1,130 @@
+package datautils
+
+import (
+	"fmt"
+	"math"
+)
+
+// NonFinitePolicy specifies how HandleNonFinite treats observations that cannot be evaluated as is i.e. those with
+// NaN predictions or non-finite (NaN or infinite) labels.  NaN predictions cannot be ranked (leaving the order of
+// the other predictions undefined when sorted) and non-finite labels produce NaN metrics so, left unhandled, they
+// silently corrupt the metrics.  Infinite predictions are ordered like any other score so are always retained.
+type NonFinitePolicy int
+
+const (
+	// NonFiniteError reports the non-finite values as an error wrapping ErrNonFinite
+	NonFiniteError NonFinitePolicy = iota
+
+	// NonFiniteDrop drops observations with NaN predictions or non-finite labels
+	NonFiniteDrop
+
+	// NonFiniteLowest treats NaN predictions as the lowest possible score (-Inf) so that they are ranked last and
+	// never predicted positive.  Observations with non-finite labels are dropped as they cannot be evaluated
+	NonFiniteLowest
+)
+
+var nonFinitePolicyNames = []string{"error", "drop", "lowest"}
+
+// String returns the name of the policy i.e. "error", "drop" or "lowest".
+func (p NonFinitePolicy) String() string {
+	if p < 0 || int(p) >= len(nonFinitePolicyNames) {
+		return fmt.Sprintf("NonFinitePolicy(%d)", int(p))
+	}
+	return nonFinitePolicyNames[p]
+}
+
+// MarshalText encodes the policy as its name so that policies may be written to configuration files.
+func (p NonFinitePolicy) MarshalText() ([]byte, error) {
+	if p < 0 || int(p) >= len(nonFinitePolicyNames) {
+		return nil, fmt.Errorf("datautils: unknown non-finite policy %d", int(p))
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes the policy from its name, either "error", "drop" or "lowest", so that policies may be
+// read from configuration files (e.g. Pipeline) and command line flags.
+func (p *NonFinitePolicy) UnmarshalText(text []byte) error {
+	for i, name := range nonFinitePolicyNames {
+		if string(text) == name {
+			*p = NonFinitePolicy(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("datautils: unknown non-finite policy %q", text)
+}
+
+// HandleNonFinite applies the policy to any observations with NaN predictions or non-finite labels, returning the
+// predictions and labels to evaluate.  Where observations are dropped, kept contains the indices of the retained
+// observations so that any associated data (e.g. query IDs) may be selected to match; otherwise kept is nil.  The
+// supplied slices are never modified but are returned as is if there is nothing to handle.  With NonFiniteError,
+// the returned error reports the number of each kind of non-finite value and the index of the first to help
+// diagnose their source.  ErrLengthMismatch is returned if the lengths of predictions and labels do not match.
+func HandleNonFinite(predictions, labels []float64, policy NonFinitePolicy) (p, l []float64, kept []int, err error) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		return nil, nil, nil, err
+	}
+	if policy < 0 || int(policy) >= len(nonFinitePolicyNames) {
+		return nil, nil, nil, fmt.Errorf("datautils: unknown non-finite policy %d", int(policy))
+	}
+
+	var nanPredictions, nonFiniteLabels int
+	firstPrediction, firstLabel := -1, -1
+	for i, v := range predictions {
+		if math.IsNaN(v) {
+			nanPredictions++
+			if firstPrediction < 0 {
+				firstPrediction = i
+			}
+		}
+		if math.IsNaN(labels[i]) || math.IsInf(labels[i], 0) {
+			nonFiniteLabels++
+			if firstLabel < 0 {
+				firstLabel = i
+			}
+		}
+	}
+	if nanPredictions == 0 && nonFiniteLabels == 0 {
+		return predictions, labels, nil, nil
+	}
+
+	if policy == NonFiniteError {
+		msg := fmt.Sprintf("%d NaN predictions", nanPredictions)
+		if firstPrediction >= 0 {
+			msg = fmt.Sprintf("%s (first at index %d)", msg, firstPrediction)
+		}
+		msg = fmt.Sprintf("%s, %d non-finite labels", msg, nonFiniteLabels)
+		if firstLabel >= 0 {
+			msg = fmt.Sprintf("%s (first at index %d)", msg, firstLabel)
+		}
+		return nil, nil, nil, fmt.Errorf("%w: %s", ErrNonFinite, msg)
+	}
+
+	if policy == NonFiniteLowest && nonFiniteLabels == 0 {
+		p = make([]float64, len(predictions))
+		for i, v := range predictions {
+			if math.IsNaN(v) {
+				v = math.Inf(-1)
+			}
+			p[i] = v
+		}
+		return p, labels, nil, nil
+	}
+
+	n := len(predictions) - nonFiniteLabels
+	p, l, kept = make([]float64, 0, n), make([]float64, 0, n), make([]int, 0, n)
+	for i, v := range predictions {
+		if math.IsNaN(labels[i]) || math.IsInf(labels[i], 0) {
+			continue
+		}
+		if math.IsNaN(v) {
+			if policy == NonFiniteDrop {
+				continue
+			}
+			v = math.Inf(-1)
+		}
+		p = append(p, v)
+		l = append(l, labels[i])
+		kept = append(kept, i)
+	}
+	return p, l, kept, nil
+}
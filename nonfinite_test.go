@@ -0,0 +1,140 @@
+package datautils_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestHandleNonFinite(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	predictions := []float64{0.9, nan, 0.4, inf, 0.2}
+	labels := []float64{1, 0, nan, 0, 1}
+
+	tests := []struct {
+		policy      datautils.NonFinitePolicy
+		predictions []float64
+		labels      []float64
+		kept        []int
+	}{
+		{policy: datautils.NonFiniteDrop, predictions: []float64{0.9, inf, 0.2}, labels: []float64{1, 0, 1}, kept: []int{0, 3, 4}},
+		{policy: datautils.NonFiniteLowest, predictions: []float64{0.9, math.Inf(-1), inf, 0.2}, labels: []float64{1, 0, 0, 1}, kept: []int{0, 1, 3, 4}},
+	}
+	for _, test := range tests {
+		p, l, kept, err := datautils.HandleNonFinite(predictions, labels, test.policy)
+		if err != nil {
+			t.Errorf("%v: Unexpected error: %v", test.policy, err)
+			continue
+		}
+		if !reflect.DeepEqual(p, test.predictions) || !reflect.DeepEqual(l, test.labels) || !reflect.DeepEqual(kept, test.kept) {
+			t.Errorf("%v: Expected %v, %v, %v but received %v, %v, %v", test.policy, test.predictions, test.labels, test.kept, p, l, kept)
+		}
+	}
+	if math.IsInf(predictions[1], 0) || !math.IsNaN(labels[2]) {
+		t.Errorf("Expected supplied slices to be unmodified but received %v and %v", predictions, labels)
+	}
+
+	// NaN predictions alone are replaced in place of dropping observations so none are dropped
+	p, l, kept, err := datautils.HandleNonFinite(predictions[:2], labels[:2], datautils.NonFiniteLowest)
+	if err != nil || !reflect.DeepEqual(p, []float64{0.9, math.Inf(-1)}) || !reflect.DeepEqual(l, labels[:2]) || kept != nil {
+		t.Errorf("Unexpected result replacing NaN predictions: %v, %v, %v, %v", p, l, kept, err)
+	}
+
+	_, _, _, err = datautils.HandleNonFinite(predictions, labels, datautils.NonFiniteError)
+	if !errors.Is(err, datautils.ErrNonFinite) {
+		t.Fatalf("Expected ErrNonFinite but received %v", err)
+	}
+	if msg := "1 NaN predictions (first at index 1), 1 non-finite labels (first at index 2)"; !strings.HasSuffix(err.Error(), msg) {
+		t.Errorf("Expected error ending %q but received %q", msg, err)
+	}
+
+	finite := []float64{0.2, 0.8}
+	if p, l, kept, err := datautils.HandleNonFinite(finite, finite, datautils.NonFiniteError); err != nil || &p[0] != &finite[0] || &l[0] != &finite[0] || kept != nil {
+		t.Errorf("Expected finite input to be returned as is but received %v, %v, %v, %v", p, l, kept, err)
+	}
+}
+
+func TestNonFinitePolicyText(t *testing.T) {
+	var config struct {
+		Policy datautils.NonFinitePolicy `json:"policy"`
+	}
+	if err := json.Unmarshal([]byte(`{"policy": "lowest"}`), &config); err != nil || config.Policy != datautils.NonFiniteLowest {
+		t.Errorf("Expected lowest policy but received %v (%v)", config.Policy, err)
+	}
+	if data, err := json.Marshal(config); err != nil || string(data) != `{"policy":"lowest"}` {
+		t.Errorf("Unexpected JSON: %s (%v)", data, err)
+	}
+	if err := json.Unmarshal([]byte(`{"policy": "ignore"}`), &config); err == nil {
+		t.Error("Expected error unmarshalling unknown policy")
+	}
+	if s := datautils.NonFinitePolicy(7).String(); s != "NonFinitePolicy(7)" {
+		t.Errorf("Unexpected name of unknown policy: %s", s)
+	}
+}
+
+func TestPredictionLogHandleNonFinite(t *testing.T) {
+	l := datautils.PredictionLog{
+		Predictions: []float64{0.9, 0.3, 0.5},
+		Labels:      []float64{1, math.NaN(), 0},
+		Queries:     []string{"a", "b", "c"},
+		Groups:      map[string][]string{"country": {"uk", "fr", "de"}},
+	}
+	handled, err := l.HandleNonFinite(datautils.NonFiniteDrop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := datautils.PredictionLog{
+		Predictions: []float64{0.9, 0.5},
+		Labels:      []float64{1, 0},
+		Queries:     []string{"a", "c"},
+		Groups:      map[string][]string{"country": {"uk", "de"}},
+	}
+	if !reflect.DeepEqual(handled, expected) {
+		t.Errorf("Expected %+v but received %+v", expected, handled)
+	}
+	if _, err := l.HandleNonFinite(datautils.NonFiniteError); !errors.Is(err, datautils.ErrNonFinite) {
+		t.Errorf("Expected ErrNonFinite but received %v", err)
+	}
+}
+
+func TestNaNPredictionsIndependentOfInputOrder(t *testing.T) {
+	nan := math.NaN()
+	predictions := []float64{0.9, nan, 0.4, 0.7, nan, 0.2, 0.6}
+	labels := []float64{1, 0, 1, 0, 0, 1, 1}
+
+	// NaN predictions are ranked last as if they were the lowest possible score
+	lowest, lowestLabels, _, err := datautils.HandleNonFinite(predictions, labels, datautils.NonFiniteLowest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auc := datautils.NewROCCurve(lowest, lowestLabels).AUC()
+	ap := datautils.NewPrecisionRecallCurve(lowest, lowestLabels).AveragePrecision()
+
+	// the NaN predictions tie so the AUC is also independent of their labels
+	mixed := append([]float64(nil), labels...)
+	mixed[1] = 1
+	mixedAUC := datautils.NewROCCurve(lowest, mixed).AUC()
+
+	for shift := 0; shift < len(predictions); shift++ {
+		p, l, m := make([]float64, len(predictions)), make([]float64, len(labels)), make([]float64, len(labels))
+		for i := range predictions {
+			// rotate and reverse the input
+			j := len(predictions) - 1 - (i+shift)%len(predictions)
+			p[j], l[j], m[j] = predictions[i], labels[i], mixed[i]
+		}
+		if v := datautils.NewROCCurve(p, l).AUC(); math.Abs(v-auc) > 1e-12 {
+			t.Errorf("Shift %d: Expected AUC: %v but received %v", shift, auc, v)
+		}
+		if v := datautils.NewPrecisionRecallCurve(p, l).AveragePrecision(); math.Abs(v-ap) > 1e-12 {
+			t.Errorf("Shift %d: Expected average precision: %v but received %v", shift, ap, v)
+		}
+		if v := datautils.NewROCCurve(p, m).AUC(); math.Abs(v-mixedAUC) > 1e-12 {
+			t.Errorf("Shift %d: Expected AUC with mixed NaN labels: %v but received %v", shift, mixedAUC, v)
+		}
+	}
+}
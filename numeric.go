@@ -0,0 +1,36 @@
+package datautils
+
+// Float is the set of floating point types accepted for predictions, labels and weights by the metric
+// constructors e.g. NewRankingEvaluation, NewPrecisionRecallCurve, NewROCCurve and NewConfusionMatrix.  Model
+// outputs are frequently float32 tensors and accepting them directly avoids converting billions of scores into
+// a []float64 first, which would double the memory required.  The resulting curves and matrices always hold
+// float64 values.
+type Float interface {
+	~float32 | ~float64
+}
+
+// sortedPredictions returns a float64 copy of predictions sorted into ascending order, with any NaN predictions
+// first, along with the original indices of the sorted values (see argsort).  A copy is required for sorting regardless of the type of the
+// predictions so float32 values are converted as they are copied rather than in a separate pass.
+func sortedPredictions[P Float](predictions []P) ([]float64, []int) {
+	sorted := make([]float64, len(predictions))
+	ind := make([]int, len(predictions))
+	for i, v := range predictions {
+		sorted[i] = float64(v)
+	}
+	argsort(sorted, ind)
+	return sorted, ind
+}
+
+// float64Values returns values as a []float64.  A []float64 is returned as is, sharing its underlying array,
+// while values of other types are converted into a new slice.
+func float64Values[T Float](values []T) []float64 {
+	if v, ok := interface{}(values).([]float64); ok {
+		return v
+	}
+	converted := make([]float64, len(values))
+	for i, v := range values {
+		converted[i] = float64(v)
+	}
+	return converted
+}
@@ -0,0 +1,67 @@
+package datautils_test
+
+import (
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestFloat32Constructors(t *testing.T) {
+	tests := []struct {
+		predictions []float64
+		labels      []float64
+	}{
+		{
+			predictions: []float64{0.125, 0.5, 0.375, 0.875},
+			labels:      []float64{0, 0, 1, 1},
+		},
+		{
+			predictions: []float64{0.5, 0.25, 0.5, 0.75, 0.125, 0.5},
+			labels:      []float64{1, 0, 0, 1, 0, 1},
+		},
+		{
+			predictions: []float64{0.5, 0.25},
+			labels:      []float64{0, 0},
+		},
+	}
+
+	for ti, test := range tests {
+		predictions := make([]float32, len(test.predictions))
+		labels := make([]float32, len(test.labels))
+		for i := range test.predictions {
+			predictions[i] = float32(test.predictions[i])
+			labels[i] = float32(test.labels[i])
+		}
+
+		expectedPR := datautils.NewPrecisionRecallCurve(test.predictions, test.labels)
+		pr := datautils.NewPrecisionRecallCurve(predictions, labels)
+		if !floats.Equal(expectedPR.Precision, pr.Precision) || !floats.Equal(expectedPR.Recall, pr.Recall) ||
+			!floats.Equal(expectedPR.Thresholds, pr.Thresholds) {
+			t.Errorf("Test %d: Expected precision recall curve: %v but received %v", ti+1, expectedPR, pr)
+		}
+
+		// mixed float32 predictions and float64 labels
+		expectedROC := datautils.NewROCCurve(test.predictions, test.labels)
+		roc := datautils.NewROCCurve(predictions, test.labels)
+		if !equalWithNaN(expectedROC.FPR, roc.FPR) || !equalWithNaN(expectedROC.TPR, roc.TPR) ||
+			!floats.Equal(expectedROC.Thresholds, roc.Thresholds) {
+			t.Errorf("Test %d: Expected ROC curve: %v but received %v", ti+1, expectedROC, roc)
+		}
+
+		expectedMatrix := datautils.NewConfusionMatrix(test.predictions, test.labels, 0.5)
+		matrix := datautils.NewConfusionMatrix(predictions, labels, 0.5)
+		if expectedMatrix != matrix {
+			t.Errorf("Test %d: Expected confusion matrix: %v but received %v", ti+1, expectedMatrix, matrix)
+		}
+
+		expectedRanking := datautils.NewRankingEvaluation(test.predictions, test.labels)
+		ranking := datautils.NewRankingEvaluation(predictions, labels)
+		if !floats.Equal(expectedRanking.Predictions, ranking.Predictions) ||
+			!floats.Equal(expectedRanking.Relevancies, ranking.Relevancies) ||
+			expectedRanking.ReciprocalRank() != ranking.ReciprocalRank() ||
+			expectedRanking.CumulativeGain(2) != ranking.CumulativeGain(2) {
+			t.Errorf("Test %d: Expected ranking evaluation: %v but received %v", ti+1, expectedRanking, ranking)
+		}
+	}
+}
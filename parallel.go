@@ -0,0 +1,206 @@
+package datautils
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// ParallelThreshold is the minimum number of predictions for which NewRankingEvaluation and
+// NewPrecisionRecallCurve sort and accumulate using multiple goroutines (one per available CPU as reported by
+// runtime.GOMAXPROCS).  Smaller datasets are processed sequentially as the overhead of coordinating goroutines
+// outweighs the benefit.  Set to 0 to always process sequentially.
+var ParallelThreshold = 1 << 20
+
+// parallel returns true if a dataset of n items should be processed in parallel.
+func parallel(n int) bool {
+	return ParallelThreshold > 0 && n >= ParallelThreshold && runtime.GOMAXPROCS(0) > 1
+}
+
+// argsort sorts s into ascending order, in the same way as floats.Argsort, storing the original indices of the
+// sorted values in inds.  Unlike floats.Argsort, NaN values are ordered first (as the lowest values) so that the
+// order is well defined and the sorted values, and so any metrics derived from them, do not depend on the order
+// of the input.  Large slices (see ParallelThreshold) are sorted in parallel.
+func argsort(s []float64, inds []int) {
+	if !parallel(len(s)) {
+		if len(s) != len(inds) {
+			panic("floats: length of inds does not match length of slice")
+		}
+		for i := range inds {
+			inds[i] = i
+		}
+		sort.Sort(argsorter{s: s, inds: inds})
+		return
+	}
+	parallelArgsort(s, inds, runtime.GOMAXPROCS(0))
+}
+
+// less reports whether a is ordered before b by argsort i.e. a is less than b or a is NaN and b is not.
+func less(a, b float64) bool {
+	return a < b || (math.IsNaN(a) && !math.IsNaN(b))
+}
+
+type argsorter struct {
+	s    []float64
+	inds []int
+}
+
+func (a argsorter) Len() int           { return len(a.s) }
+func (a argsorter) Less(i, j int) bool { return less(a.s[i], a.s[j]) }
+func (a argsorter) Swap(i, j int) {
+	a.s[i], a.s[j] = a.s[j], a.s[i]
+	a.inds[i], a.inds[j] = a.inds[j], a.inds[i]
+}
+
+// chunks divides the range [0, n) into at most k contiguous chunks of near equal size returning the start and
+// end of each chunk.
+func chunks(n, k int) [][2]int {
+	if k > n {
+		k = n
+	}
+	if k < 1 {
+		k = 1
+	}
+	bounds := make([][2]int, 0, k)
+	size, remainder := n/k, n%k
+	var start int
+	for i := 0; i < k; i++ {
+		end := start + size
+		if i < remainder {
+			end++
+		}
+		bounds = append(bounds, [2]int{start, end})
+		start = end
+	}
+	return bounds
+}
+
+// parallelFor calls f(i) for each i in [0, n) concurrently, returning once all calls have completed.
+func parallelFor(n int, f func(i int)) {
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			f(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// parallelArgsort sorts s into ascending order, with NaN values first (see argsort), storing the original indices of the sorted values in inds.  s is
+// divided into chunks which are sorted concurrently by separate goroutines before adjacent sorted chunks are
+// merged, also concurrently, until a single sorted run remains.
+func parallelArgsort(s []float64, inds []int, workers int) {
+	if len(s) != len(inds) {
+		panic("floats: length of inds does not match length of slice")
+	}
+	for i := range inds {
+		inds[i] = i
+	}
+
+	runs := chunks(len(s), workers)
+	parallelFor(len(runs), func(i int) {
+		lo, hi := runs[i][0], runs[i][1]
+		sort.Sort(argsorter{s: s[lo:hi], inds: inds[lo:hi]})
+	})
+
+	src, srcInds := s, inds
+	dst, dstInds := make([]float64, len(s)), make([]int, len(inds))
+	for len(runs) > 1 {
+		merged := make([][2]int, (len(runs)+1)/2)
+		parallelFor(len(merged), func(i int) {
+			a := runs[2*i]
+			if 2*i+1 == len(runs) {
+				// odd run out is carried over unmerged
+				copy(dst[a[0]:a[1]], src[a[0]:a[1]])
+				copy(dstInds[a[0]:a[1]], srcInds[a[0]:a[1]])
+				merged[i] = a
+				return
+			}
+			b := runs[2*i+1]
+			mergeRuns(dst, dstInds, src, srcInds, a[0], a[1], b[1])
+			merged[i] = [2]int{a[0], b[1]}
+		})
+		runs = merged
+		src, dst = dst, src
+		srcInds, dstInds = dstInds, srcInds
+	}
+
+	// the result is held in src which may be the scratch buffer rather than the caller's slices
+	if &src[0] != &s[0] {
+		copy(s, src)
+		copy(inds, srcInds)
+	}
+}
+
+// mergeRuns merges the adjacent sorted runs src[lo:mid] and src[mid:hi] (and their corresponding indices) into
+// dst[lo:hi].
+func mergeRuns(dst []float64, dstInds []int, src []float64, srcInds []int, lo, mid, hi int) {
+	i, j := lo, mid
+	for k := lo; k < hi; k++ {
+		if j >= hi || (i < mid && !less(src[j], src[i])) {
+			dst[k], dstInds[k] = src[i], srcInds[i]
+			i++
+		} else {
+			dst[k], dstInds[k] = src[j], srcInds[j]
+			j++
+		}
+	}
+}
+
+// parallelPrecisionRecall calculates the precision and recall at each rank, where the ranking is given by ind
+// in ascending order of prediction (so that rank r corresponds to ind[len(ind)-1-r]), until all positive
+// observations have been ranked.  The ranking is divided into chunks with the hits within each chunk counted
+// concurrently before the precision and recall for each chunk are also calculated concurrently.  The rank at
+// which the final positive observation occurs (recall==1) is returned.
+func parallelPrecisionRecall[L Float](labels []L, ind []int, positives int, precision, recall []float64) int {
+	n := len(ind)
+	bounds := chunks(n, runtime.GOMAXPROCS(0))
+	isHit := func(r int) bool { return labels[ind[n-1-r]] > 0 }
+
+	hits := make([]int, len(bounds))
+	parallelFor(len(bounds), func(c int) {
+		for r := bounds[c][0]; r < bounds[c][1]; r++ {
+			if isHit(r) {
+				hits[c]++
+			}
+		}
+	})
+
+	// convert hits per chunk into the cumulative hits preceding each chunk and locate the final positive
+	offsets := make([]int, len(bounds))
+	var total, k int
+	for c := range bounds {
+		offsets[c] = total
+		if total+hits[c] == positives {
+			h := total
+			for k = bounds[c][0]; ; k++ {
+				if isHit(k) {
+					h++
+				}
+				if h == positives {
+					break
+				}
+			}
+			bounds = bounds[:c+1]
+			bounds[c][1] = k + 1
+			break
+		}
+		total += hits[c]
+	}
+
+	parallelFor(len(bounds), func(c int) {
+		h := offsets[c]
+		for r := bounds[c][0]; r < bounds[c][1]; r++ {
+			if isHit(r) {
+				h++
+			}
+			recall[r] = float64(h) / float64(positives)
+			precision[r] = float64(h) / float64(r+1)
+		}
+	})
+
+	return k
+}
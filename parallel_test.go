@@ -0,0 +1,47 @@
+package datautils_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestParallelEvaluation(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	predictions := make([]float64, 10007)
+	labels := make([]float64, len(predictions))
+	for i := range predictions {
+		predictions[i] = rnd.Float64()
+		if rnd.Float64() < 0.1 {
+			labels[i] = float64(1 + rnd.Intn(3))
+		}
+	}
+
+	sequentialCurve := datautils.NewPrecisionRecallCurve(predictions, labels)
+	sequentialEvaluation := datautils.NewRankingEvaluation(predictions, labels)
+
+	defer func(threshold int) { datautils.ParallelThreshold = threshold }(datautils.ParallelThreshold)
+	datautils.ParallelThreshold = 2
+
+	parallelCurve := datautils.NewPrecisionRecallCurve(predictions, labels)
+	parallelEvaluation := datautils.NewRankingEvaluation(predictions, labels)
+
+	if !floats.Equal(sequentialCurve.Precision, parallelCurve.Precision) ||
+		!floats.Equal(sequentialCurve.Recall, parallelCurve.Recall) ||
+		!floats.Equal(sequentialCurve.Thresholds, parallelCurve.Thresholds) {
+		t.Errorf("Expected parallel precision recall curve to match sequential curve")
+	}
+	for i, v := range sequentialEvaluation.PredictedRankInd {
+		if parallelEvaluation.PredictedRankInd[i] != v {
+			t.Errorf("Expected parallel predicted ranking to match sequential ranking at rank %d", i)
+			break
+		}
+	}
+	n := len(predictions)
+	if s, p := sequentialEvaluation.NormalisedDiscountedCumulativeGain(n, datautils.TraditionalRelevancy),
+		parallelEvaluation.NormalisedDiscountedCumulativeGain(n, datautils.TraditionalRelevancy); s != p {
+		t.Errorf("Expected parallel NDCG: %v to match sequential NDCG: %v", p, s)
+	}
+}
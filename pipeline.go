@@ -0,0 +1,407 @@
+package datautils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Pipeline is a declarative evaluation which computes a set of metrics over one or more datasets of predictions,
+// optionally broken down by group attributes, and writes a consolidated report.  Pipelines may be configured
+// directly as Go structs or read from JSON configuration files with ReadPipeline e.g.
+//
+//	{
+//		"datasets": [{"name": "ranker-v2", "path": "predictions.jsonl"}],
+//		"metrics": ["auc", "f1", "ndcg@k"],
+//		"cutoffs": [5, 10],
+//		"threshold": 0.5,
+//		"group_by": ["country"],
+//		"outputs": [{"path": "report.csv"}]
+//	}
+type Pipeline struct {
+	// Datasets are the datasets of predictions to evaluate.
+	Datasets []PipelineDataset `json:"datasets"`
+
+	// Metrics are the names of the metrics to compute (see MetricRegistry.Lookup).  Metrics whose names end in
+	// "@k" (e.g. ndcg@k) are computed at each of Cutoffs.
+	Metrics []string `json:"metrics"`
+
+	// Cutoffs are the cut-offs at which to compute metrics named with the "@k" suffix.
+	Cutoffs []int `json:"cutoffs,omitempty"`
+
+	// Threshold is the classification threshold used by metrics calculated from a confusion matrix (e.g. f1).
+	Threshold float64 `json:"threshold"`
+
+	// GroupBy are the names of group attribute fields by which to break down the metrics.  The metrics of each
+	// dataset are computed over all its predictions and then separately for each distinct value of each field.
+	GroupBy []string `json:"group_by,omitempty"`
+
+	// NonFinite is the policy for handling NaN predictions and non-finite labels (e.g. missing labels) in the
+	// datasets, either "error", "drop" or "lowest" (see NonFinitePolicy).  By default, datasets containing them
+	// are rejected with an error.
+	NonFinite NonFinitePolicy `json:"non_finite,omitempty"`
+
+	// Outputs are the files to which the report is written.
+	Outputs []PipelineOutput `json:"outputs,omitempty"`
+
+	// Registry is the registry from which metrics are looked up.  If nil, DefaultMetricRegistry is used.
+	Registry *MetricRegistry `json:"-"`
+}
+
+// PipelineDataset is a file of predictions evaluated by a Pipeline.
+type PipelineDataset struct {
+	// Name identifies the dataset within the report.  If empty, Path is used.
+	Name string `json:"name,omitempty"`
+
+	// Path is the path of the file containing the predictions.
+	Path string `json:"path"`
+
+	// Format is the format of the file, either "jsonl" for a prediction log (see ReadPredictionLog) or "csv" for
+	// CSV with a header naming the columns.  If empty, the format is determined by the file extension of Path.
+	Format string `json:"format,omitempty"`
+
+	// Fields are the names of the fields (or CSV columns) containing the predictions, labels and query IDs.  The
+	// Timestamp, Groups and TimeLayout fields are ignored.  If Score is empty, DefaultPredictionLogFields is used.
+	// As with prediction logs, fields absent from CSV files are read as missing values.
+	Fields PredictionLogFields `json:"fields"`
+}
+
+// PipelineOutput is a file to which a Pipeline writes its report.
+type PipelineOutput struct {
+	// Path is the path of the file.
+	Path string `json:"path"`
+
+	// Format is the format of the report, either "json" or "csv" (see PipelineReport.WriteJSON and
+	// PipelineReport.WriteCSV).  If empty, the format is determined by the file extension of Path.
+	Format string `json:"format,omitempty"`
+}
+
+// PipelineReport is the consolidated report of the metrics computed by a Pipeline.
+type PipelineReport struct {
+	// Metrics are the names of the computed metrics, with cut-offs expanded (e.g. ndcg@5, ndcg@10), in the order
+	// of PipelineResult.Values.
+	Metrics []string
+
+	// Results contains the metrics of each dataset followed by those of each of its groups.
+	Results []PipelineResult
+}
+
+// PipelineResult holds the metrics computed over a dataset or a group within it.
+type PipelineResult struct {
+	// Dataset is the name of the dataset.
+	Dataset string
+
+	// GroupBy is the name of the group attribute field and Group the value of the attribute shared by the
+	// predictions, or both are empty if the metrics were computed over the whole dataset.
+	GroupBy, Group string
+
+	// Observations is the number of predictions over which the metrics were computed.
+	Observations int
+
+	// Values contains the value of each metric in the order of PipelineReport.Metrics.
+	Values []float64
+}
+
+// ReadPipeline reads a Pipeline from its JSON configuration.  Unknown configuration fields are rejected so that
+// misspelt settings are not silently ignored.
+func ReadPipeline(r io.Reader) (Pipeline, error) {
+	var p Pipeline
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return Pipeline{}, fmt.Errorf("datautils: failed to read pipeline: %w", err)
+	}
+	return p, nil
+}
+
+// metrics looks up the metrics of the pipeline, expanding those with the "@k" suffix at each cut-off.
+func (p Pipeline) metrics() ([]Metric, error) {
+	registry := p.Registry
+	if registry == nil {
+		registry = DefaultMetricRegistry
+	}
+	if len(p.Metrics) == 0 {
+		return nil, fmt.Errorf("datautils: no metrics specified")
+	}
+
+	var metrics []Metric
+	for _, name := range p.Metrics {
+		names := []string{name}
+		if prefix := strings.TrimSuffix(name, "@k"); prefix != name {
+			if len(p.Cutoffs) == 0 {
+				return nil, fmt.Errorf("datautils: no cut-offs specified for metric %q", name)
+			}
+			names = names[:0]
+			for _, k := range p.Cutoffs {
+				names = append(names, prefix+"@"+strconv.Itoa(k))
+			}
+		}
+		for _, name := range names {
+			m, err := registry.Lookup(name)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics, nil
+}
+
+// Run evaluates each of the datasets, writes the report to each of the outputs and returns it.  The metrics and
+// output formats are validated before any datasets are read.
+func (p Pipeline) Run() (PipelineReport, error) {
+	metrics, err := p.metrics()
+	if err != nil {
+		return PipelineReport{}, err
+	}
+	for _, out := range p.Outputs {
+		if _, err := out.format(); err != nil {
+			return PipelineReport{}, err
+		}
+	}
+
+	report := PipelineReport{Metrics: make([]string, len(metrics))}
+	for i, m := range metrics {
+		report.Metrics[i] = m.Name()
+	}
+
+	for _, d := range p.Datasets {
+		l, err := d.read(p.GroupBy)
+		if err != nil {
+			return PipelineReport{}, err
+		}
+		name := d.Name
+		if name == "" {
+			name = d.Path
+		}
+		if l, err = l.HandleNonFinite(p.NonFinite); err != nil {
+			return PipelineReport{}, fmt.Errorf("datautils: dataset %q: %w", d.Path, err)
+		}
+
+		opts := MetricOptions{Threshold: p.Threshold, Queries: l.Queries}
+		result := PipelineResult{Dataset: name, Observations: len(l.Predictions), Values: make([]float64, len(metrics))}
+		for i, m := range metrics {
+			result.Values[i] = m.Compute(l.Predictions, l.Labels, opts)
+		}
+		report.Results = append(report.Results, result)
+
+		for _, field := range p.GroupBy {
+			e := GroupBy(l.Predictions, l.Labels, l.Groups[field], metrics, opts)
+			for i, g := range e.Groups {
+				report.Results = append(report.Results, PipelineResult{
+					Dataset:      name,
+					GroupBy:      field,
+					Group:        g,
+					Observations: e.Observations[i],
+					Values:       e.Values[i],
+				})
+			}
+		}
+	}
+
+	for _, out := range p.Outputs {
+		if err := out.write(report); err != nil {
+			return PipelineReport{}, err
+		}
+	}
+	return report, nil
+}
+
+// read reads the predictions, labels, query IDs and the specified group attributes of the dataset.
+func (d PipelineDataset) read(groups []string) (PredictionLog, error) {
+	fields := d.Fields
+	if fields.Score == "" {
+		fields = DefaultPredictionLogFields
+	}
+	fields.Timestamp, fields.TimeLayout, fields.Groups = "", "", groups
+	if fields.Label == "" {
+		return PredictionLog{}, fmt.Errorf("datautils: dataset %q: label field not specified", d.Path)
+	}
+
+	format := d.Format
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(d.Path), "."))
+	}
+
+	f, err := os.Open(d.Path)
+	if err != nil {
+		return PredictionLog{}, fmt.Errorf("datautils: %w", err)
+	}
+	defer f.Close()
+
+	var l PredictionLog
+	switch format {
+	case "jsonl":
+		l, err = ReadPredictionLog(f, fields)
+	case "csv":
+		l, err = readPredictionCSV(f, fields)
+	default:
+		return PredictionLog{}, fmt.Errorf("datautils: dataset %q: unsupported format %q", d.Path, format)
+	}
+	if err != nil {
+		return PredictionLog{}, fmt.Errorf("datautils: dataset %q: %w", d.Path, err)
+	}
+	return l, nil
+}
+
+// readPredictionCSV reads the specified fields from CSV data with a header naming the columns.  Other columns are
+// ignored.  As with ReadPredictionLog, the score column must be present whereas absent label, query and group
+// columns are read as NaN and empty strings.
+func readPredictionCSV(r io.Reader, fields PredictionLogFields) (PredictionLog, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return PredictionLog{}, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return PredictionLog{}, fmt.Errorf("no CSV records found")
+	}
+	header, records := records[0], records[1:]
+	if indexOf(header, fields.Score) == -1 {
+		return PredictionLog{}, fmt.Errorf("score column %q not found", fields.Score)
+	}
+
+	isMissing := make(map[string]bool, len(DefaultMissingValues))
+	for _, v := range DefaultMissingValues {
+		isMissing[v] = true
+	}
+	numeric := func(name string) ([]float64, error) {
+		col := indexOf(header, name)
+		values := make([]float64, len(records))
+		for i, record := range records {
+			if col == -1 {
+				values[i] = math.NaN()
+				continue
+			}
+			v, err := parseField(record[col], isMissing)
+			if err != nil {
+				return nil, fmt.Errorf("record %d, column %q: %w", i+1, name, err)
+			}
+			values[i] = v
+		}
+		return values, nil
+	}
+	strs := func(name string) []string {
+		col := indexOf(header, name)
+		values := make([]string, len(records))
+		if col != -1 {
+			for i, record := range records {
+				values[i] = strings.TrimSpace(record[col])
+			}
+		}
+		return values
+	}
+
+	var l PredictionLog
+	if l.Predictions, err = numeric(fields.Score); err != nil {
+		return PredictionLog{}, err
+	}
+	if fields.Label != "" {
+		if l.Labels, err = numeric(fields.Label); err != nil {
+			return PredictionLog{}, err
+		}
+	}
+	if fields.Query != "" {
+		l.Queries = strs(fields.Query)
+	}
+	if len(fields.Groups) > 0 {
+		l.Groups = make(map[string][]string, len(fields.Groups))
+		for _, g := range fields.Groups {
+			l.Groups[g] = strs(g)
+		}
+	}
+	return l, nil
+}
+
+// format returns the format of the output.
+func (o PipelineOutput) format() (string, error) {
+	format := o.Format
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(o.Path), "."))
+	}
+	if format != "json" && format != "csv" {
+		return "", fmt.Errorf("datautils: output %q: unsupported format %q", o.Path, format)
+	}
+	return format, nil
+}
+
+// write writes the report to the output.
+func (o PipelineOutput) write(report PipelineReport) error {
+	format, err := o.format()
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(o.Path)
+	if err != nil {
+		return fmt.Errorf("datautils: %w", err)
+	}
+	if format == "json" {
+		err = report.WriteJSON(f)
+	} else {
+		err = report.WriteCSV(f, ',')
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("datautils: %w", err)
+	}
+	return nil
+}
+
+// pipelineResultJSON is the JSON representation of a PipelineResult.
+type pipelineResultJSON struct {
+	Dataset      string               `json:"dataset"`
+	GroupBy      string               `json:"group_by,omitempty"`
+	Group        string               `json:"group,omitempty"`
+	Observations int                  `json:"observations"`
+	Metrics      map[string]JSONFloat `json:"metrics"`
+}
+
+// WriteJSON writes the report to w as a JSON array with an object per result containing the dataset, group_by,
+// group, observations and metrics (keyed by name) of the result.  Undefined (NaN) metrics are written as "NaN"
+// (see JSONFloat).
+func (r PipelineReport) WriteJSON(w io.Writer) error {
+	results := make([]pipelineResultJSON, len(r.Results))
+	for i, res := range r.Results {
+		results[i] = pipelineResultJSON{
+			Dataset:      res.Dataset,
+			GroupBy:      res.GroupBy,
+			Group:        res.Group,
+			Observations: res.Observations,
+			Metrics:      make(map[string]JSONFloat, len(r.Metrics)),
+		}
+		for j, name := range r.Metrics {
+			results[i].Metrics[name] = JSONFloat(res.Values[j])
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(results); err != nil {
+		return fmt.Errorf("datautils: failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the report to w as CSV with a header followed by a record per result.  The columns are dataset,
+// group_by, group and observations followed by a column per metric.  comma is the field delimiter e.g. '\t' for
+// TSV (or ',' if zero).
+func (r PipelineReport) WriteCSV(w io.Writer, comma rune) error {
+	records := [][]string{append([]string{"dataset", "group_by", "group", "observations"}, r.Metrics...)}
+	for _, res := range r.Results {
+		record := []string{res.Dataset, res.GroupBy, res.Group, strconv.Itoa(res.Observations)}
+		for _, v := range res.Values {
+			record = append(record, formatFloat(v))
+		}
+		records = append(records, record)
+	}
+	return writeCSV(w, comma, records)
+}
@@ -0,0 +1,158 @@
+package datautils_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestPipeline(t *testing.T) {
+	dir := t.TempDir()
+	jsonl := `{"score": 0.9, "label": 1, "query_id": "a", "country": "uk"}
+{"score": 0.4, "label": 0, "query_id": "a", "country": "uk"}
+{"score": 0.7, "label": 0, "query_id": "b", "country": "fr"}
+{"score": 0.2, "label": 1, "query_id": "b", "country": "uk"}
+`
+	csv := "p,y,country,id\n0.9,1,uk,x\n0.4,0,uk,y\n0.7,0,fr,z\n0.2,1,uk,w\n"
+	if err := os.WriteFile(filepath.Join(dir, "run.jsonl"), []byte(jsonl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "run.csv"), []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := `{
+		"datasets": [
+			{"name": "jsonl", "path": "` + filepath.Join(dir, "run.jsonl") + `"},
+			{"path": "` + filepath.Join(dir, "run.csv") + `", "fields": {"score": "p", "label": "y"}}
+		],
+		"metrics": ["auc", "precision", "hr@k"],
+		"cutoffs": [1, 2],
+		"threshold": 0.5,
+		"group_by": ["country"],
+		"outputs": [{"path": "` + filepath.Join(dir, "report.csv") + `"}, {"path": "` + filepath.Join(dir, "report.out") + `", "format": "json"}]
+	}`
+	p, err := datautils.ReadPipeline(strings.NewReader(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"auc", "precision", "hr@1", "hr@2"}; !reflect.DeepEqual(report.Metrics, expected) {
+		t.Errorf("Expected metrics: %v but received %v", expected, report.Metrics)
+	}
+	expected := []datautils.PipelineResult{
+		// hit rates are averaged over queries a and b for the prediction log but over a single query for the CSV
+		{Dataset: "jsonl", Observations: 4, Values: []float64{0.5, 0.5, 0.5, 1}},
+		{Dataset: "jsonl", GroupBy: "country", Group: "fr", Observations: 1, Values: []float64{math.NaN(), 0, 0, 0}},
+		{Dataset: "jsonl", GroupBy: "country", Group: "uk", Observations: 3, Values: []float64{0.5, 1, 1, 1}},
+		{Dataset: filepath.Join(dir, "run.csv"), Observations: 4, Values: []float64{0.5, 0.5, 1, 1}},
+		{Dataset: filepath.Join(dir, "run.csv"), GroupBy: "country", Group: "fr", Observations: 1, Values: []float64{math.NaN(), 0, 0, 0}},
+		{Dataset: filepath.Join(dir, "run.csv"), GroupBy: "country", Group: "uk", Observations: 3, Values: []float64{0.5, 1, 1, 1}},
+	}
+	if len(report.Results) != len(expected) {
+		t.Fatalf("Expected %d results but received %d", len(expected), len(report.Results))
+	}
+	for i, res := range report.Results {
+		e := expected[i]
+		if res.Dataset != e.Dataset || res.GroupBy != e.GroupBy || res.Group != e.Group || res.Observations != e.Observations || !equalWithNaN(res.Values, e.Values) {
+			t.Errorf("Result %d: Expected %+v but received %+v", i+1, e, res)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "report.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf, ','); err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != buf.String() || !strings.HasPrefix(buf.String(), "dataset,group_by,group,observations,auc,precision,hr@1,hr@2\njsonl,,,4,0.5,0.5,0.5,1\n") {
+		t.Errorf("Unexpected CSV report:\n%s", data)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "report.out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []struct {
+		Dataset string
+		Group   string
+		Metrics map[string]datautils.JSONFloat
+	}
+	if err := json.Unmarshal(data, &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 6 || results[1].Group != "fr" || !math.IsNaN(float64(results[1].Metrics["auc"])) || results[0].Metrics["hr@1"] != 0.5 {
+		t.Errorf("Unexpected JSON report:\n%s", data)
+	}
+}
+
+func TestPipelineErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "run.jsonl")
+	if err := os.WriteFile(path, []byte(`{"score": 0.9, "label": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dataset := []datautils.PipelineDataset{{Path: path}}
+
+	tests := []datautils.Pipeline{
+		{Datasets: dataset},
+		{Datasets: dataset, Metrics: []string{"nope"}},
+		{Datasets: dataset, Metrics: []string{"ndcg@k"}},
+		{Datasets: dataset, Metrics: []string{"auc"}, Outputs: []datautils.PipelineOutput{{Path: filepath.Join(dir, "report.xml")}}},
+		{Datasets: []datautils.PipelineDataset{{Path: filepath.Join(dir, "missing.jsonl")}}, Metrics: []string{"auc"}},
+		{Datasets: []datautils.PipelineDataset{{Path: path, Format: "parquet"}}, Metrics: []string{"auc"}},
+		{Datasets: []datautils.PipelineDataset{{Path: path, Fields: datautils.PredictionLogFields{Score: "score"}}}, Metrics: []string{"auc"}},
+	}
+	for i, p := range tests {
+		if _, err := p.Run(); err == nil {
+			t.Errorf("Test %d: Expected error running pipeline", i+1)
+		}
+	}
+
+	if _, err := datautils.ReadPipeline(strings.NewReader(`{"metric": ["auc"]}`)); err == nil {
+		t.Errorf("Expected error reading pipeline with unknown field")
+	}
+}
+
+func TestPipelineNonFinite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "run.jsonl")
+	data := "{\"score\": 0.9, \"label\": 1, \"country\": \"uk\"}\n{\"score\": 0.2, \"label\": null, \"country\": \"fr\"}\n{\"score\": 0.4, \"label\": 0, \"country\": \"uk\"}\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := datautils.ReadPipeline(strings.NewReader(`{"datasets": [{"path": "` + path + `"}], "metrics": ["auc"], "group_by": ["country"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Run(); !errors.Is(err, datautils.ErrNonFinite) {
+		t.Errorf("Expected ErrNonFinite running pipeline with missing label but received %v", err)
+	}
+
+	p, err = datautils.ReadPipeline(strings.NewReader(`{"datasets": [{"path": "` + path + `"}], "metrics": ["auc"], "group_by": ["country"], "non_finite": "drop"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 2 || report.Results[0].Observations != 2 || report.Results[1].Group != "uk" || report.Results[0].Values[0] != 1 {
+		t.Errorf("Unexpected results: %+v", report.Results)
+	}
+}
@@ -0,0 +1,289 @@
+package datautils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// PredictionLogFields specifies the names of the fields of the JSON objects within a prediction log (see
+// ReadPredictionLog).  Fields with empty names are not read.
+type PredictionLogFields struct {
+	// Score is the name of the field containing the model's prediction (score) which must be present in every
+	// record
+	Score string
+
+	// Label is the name of the field containing the ground truth label
+	Label string
+
+	// Query is the name of the field containing the query ID for ranking evaluations
+	Query string
+
+	// Timestamp is the name of the field containing the time of the prediction
+	Timestamp string
+
+	// Groups are the names of the fields containing group attributes (e.g. protected attributes for fairness
+	// evaluations)
+	Groups []string
+
+	// TimeLayout is the layout (see time.Parse) of string timestamps.  If empty, time.RFC3339Nano is used.
+	// Numeric timestamps are read as (possibly fractional) seconds since the Unix epoch
+	TimeLayout string
+}
+
+// DefaultPredictionLogFields are the conventional field names of prediction logs.
+var DefaultPredictionLogFields = PredictionLogFields{
+	Score:     "score",
+	Label:     "label",
+	Query:     "query_id",
+	Timestamp: "timestamp",
+}
+
+// PredictionLog contains the records of a prediction log read with ReadPredictionLog with a value in each slice
+// for each record in the order read.
+type PredictionLog struct {
+	// Predictions contains the score of each record
+	Predictions []float64
+
+	// Labels contains the label of each record, with missing (or null) labels read as NaN, or nil if no label
+	// field was specified
+	Labels []float64
+
+	// Queries contains the query ID of each record, with missing query IDs read as empty strings, or nil if no
+	// query field was specified
+	Queries []string
+
+	// Timestamps contains the time of each record, with missing timestamps read as the zero time, or nil if no
+	// timestamp field was specified
+	Timestamps []time.Time
+
+	// Groups contains the values of each group attribute for each record, keyed by field name, with missing values
+	// read as empty strings, or nil if no group fields were specified
+	Groups map[string][]string
+}
+
+// ReadPredictionLog reads a prediction log in JSON Lines format, where each non blank line is a JSON object
+// recording a single prediction, extracting the fields specified by fields.  Scores and labels may be JSON numbers,
+// booleans (read as 1 and 0), null (read as NaN) or the non-finite strings accepted by JSONFloat e.g. "Infinity".
+// Query IDs and group attributes may be JSON strings or other scalar values which are read using their JSON
+// representation e.g. a numeric query ID of 17 is read as "17".  Fields other than those specified are ignored.
+func ReadPredictionLog(r io.Reader, fields PredictionLogFields) (PredictionLog, error) {
+	if fields.Score == "" {
+		return PredictionLog{}, fmt.Errorf("datautils: score field not specified")
+	}
+	layout := fields.TimeLayout
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+
+	var l PredictionLog
+	if len(fields.Groups) > 0 {
+		l.Groups = make(map[string][]string, len(fields.Groups))
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var line int
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var record map[string]json.RawMessage
+		if err := json.Unmarshal(text, &record); err != nil {
+			return PredictionLog{}, fmt.Errorf("datautils: line %d: %w", line, err)
+		}
+
+		raw, ok := record[fields.Score]
+		if !ok {
+			return PredictionLog{}, fmt.Errorf("datautils: line %d: score field %q not found", line, fields.Score)
+		}
+		score, err := parseJSONValue(raw)
+		if err != nil {
+			return PredictionLog{}, fmt.Errorf("datautils: line %d, field %q: %w", line, fields.Score, err)
+		}
+		l.Predictions = append(l.Predictions, score)
+
+		if fields.Label != "" {
+			label := math.NaN()
+			if raw, ok := record[fields.Label]; ok {
+				if label, err = parseJSONValue(raw); err != nil {
+					return PredictionLog{}, fmt.Errorf("datautils: line %d, field %q: %w", line, fields.Label, err)
+				}
+			}
+			l.Labels = append(l.Labels, label)
+		}
+
+		if fields.Query != "" {
+			l.Queries = append(l.Queries, jsonString(record[fields.Query]))
+		}
+
+		if fields.Timestamp != "" {
+			var t time.Time
+			if raw, ok := record[fields.Timestamp]; ok {
+				if t, err = parseJSONTime(raw, layout); err != nil {
+					return PredictionLog{}, fmt.Errorf("datautils: line %d, field %q: %w", line, fields.Timestamp, err)
+				}
+			}
+			l.Timestamps = append(l.Timestamps, t)
+		}
+
+		for _, g := range fields.Groups {
+			l.Groups[g] = append(l.Groups[g], jsonString(record[g]))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return PredictionLog{}, fmt.Errorf("datautils: failed to read prediction log: %w", err)
+	}
+	if len(l.Predictions) == 0 {
+		return PredictionLog{}, fmt.Errorf("datautils: no prediction log records found")
+	}
+	return l, nil
+}
+
+// parseJSONValue parses a JSON number, boolean, null or non-finite string (see JSONFloat) into a float64.
+func parseJSONValue(raw json.RawMessage) (float64, error) {
+	switch string(raw) {
+	case "true":
+		return 1, nil
+	case "false":
+		return 0, nil
+	case "null":
+		return math.NaN(), nil
+	}
+	var f JSONFloat
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return 0, fmt.Errorf("invalid value %s", raw)
+	}
+	return float64(f), nil
+}
+
+// jsonString returns the value of a JSON string or, for other values, their JSON representation.  Missing (nil) and
+// null values are returned as the empty string.
+func jsonString(raw json.RawMessage) string {
+	if raw == nil || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// parseJSONTime parses a JSON string timestamp using layout or a JSON number as seconds since the Unix epoch.
+// null values are parsed as the zero time.
+func parseJSONTime(raw json.RawMessage, layout string) (time.Time, error) {
+	if string(raw) == "null" {
+		return time.Time{}, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return time.Parse(layout, s)
+	}
+	var secs float64
+	if err := json.Unmarshal(raw, &secs); err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %s", raw)
+	}
+	whole, frac := math.Modf(secs)
+	return time.Unix(int64(whole), int64(frac*1e9)).UTC(), nil
+}
+
+// WindowedMetric calculates the specified metric over consecutive windows of time as for the WindowedMetric
+// function.  WindowedMetric will panic if the log has no labels or timestamps.
+func (l PredictionLog) WindowedMetric(metric MetricFunc, window, step time.Duration) []MetricWindow {
+	if l.Labels == nil || l.Timestamps == nil {
+		panic("datautils: prediction log has no labels or timestamps")
+	}
+	return WindowedMetric(l.Timestamps, l.Predictions, l.Labels, metric, window, step)
+}
+
+// EvaluationSet creates an EvaluationSet with a ranking evaluation of the records of each query (see
+// NewQueryEvaluationSet).  EvaluationSet will panic if the log has no labels or query IDs.
+func (l PredictionLog) EvaluationSet() EvaluationSet {
+	if l.Labels == nil || l.Queries == nil {
+		panic("datautils: prediction log has no labels or query IDs")
+	}
+	return NewQueryEvaluationSet(l.Predictions, l.Labels, l.Queries)
+}
+
+// FairnessReport creates a FairnessReport (see NewFairnessReport) comparing the groups defined by the values of the
+// specified group attribute.  FairnessReport will panic if the log has no labels or values of the group attribute.
+func (l PredictionLog) FairnessReport(group string, threshold float64) FairnessReport {
+	groups, ok := l.Groups[group]
+	if l.Labels == nil || !ok {
+		panic(fmt.Sprintf("datautils: prediction log has no labels or values of group %q", group))
+	}
+	return NewFairnessReport(l.Predictions, l.Labels, groups, threshold)
+}
+
+// HandleNonFinite applies the policy to records with NaN scores or non-finite (e.g. missing) labels as for the
+// HandleNonFinite function, returning a log containing the records to evaluate.  Where records are dropped, their
+// query IDs, timestamps and group attributes are dropped with them.  HandleNonFinite will panic if the log has no
+// labels.
+func (l PredictionLog) HandleNonFinite(policy NonFinitePolicy) (PredictionLog, error) {
+	if l.Labels == nil {
+		panic("datautils: prediction log has no labels")
+	}
+	predictions, labels, kept, err := HandleNonFinite(l.Predictions, l.Labels, policy)
+	if err != nil {
+		return PredictionLog{}, err
+	}
+
+	handled := l
+	handled.Predictions, handled.Labels = predictions, labels
+	if kept == nil {
+		return handled, nil
+	}
+	if l.Queries != nil {
+		handled.Queries = selectStrings(l.Queries, kept)
+	}
+	if l.Timestamps != nil {
+		handled.Timestamps = make([]time.Time, len(kept))
+		for i, v := range kept {
+			handled.Timestamps[i] = l.Timestamps[v]
+		}
+	}
+	if l.Groups != nil {
+		handled.Groups = make(map[string][]string, len(l.Groups))
+		for name, values := range l.Groups {
+			handled.Groups[name] = selectStrings(values, kept)
+		}
+	}
+	return handled, nil
+}
+
+// NewQueryEvaluationSet creates an EvaluationSet from flat slices of predictions and labels grouped into queries by
+// the corresponding query IDs in queries, as typically found in prediction logs.  The set contains a ranking
+// evaluation (see NewRankingEvaluation) of the predictions of each distinct query ID.  The relative order of each
+// query's predictions is preserved so that tied predictions are ranked consistently.  As with
+// NewRankingEvaluation, the predictions and labels may be of any Float type.
+func NewQueryEvaluationSet[P, L Float](predictions []P, labels []L, queries []string) EvaluationSet {
+	return must(NewQueryEvaluationSetE(predictions, labels, queries))
+}
+
+// NewQueryEvaluationSetE creates an EvaluationSet in the same way as NewQueryEvaluationSet but returns
+// ErrLengthMismatch, rather than panicking, if the lengths of the predictions, labels and queries do not match.
+func NewQueryEvaluationSetE[P, L Float](predictions []P, labels []L, queries []string) (EvaluationSet, error) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		return nil, err
+	}
+	if len(queries) != len(labels) {
+		return nil, ErrLengthMismatch
+	}
+
+	indices := make(map[string][]int)
+	for i, q := range queries {
+		indices[q] = append(indices[q], i)
+	}
+	set := make(EvaluationSet, len(indices))
+	for q, ind := range indices {
+		set[q] = NewRankingEvaluation(selectValues(predictions, ind), selectValues(labels, ind))
+	}
+	return set, nil
+}
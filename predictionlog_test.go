@@ -0,0 +1,120 @@
+package datautils_test
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/james-bowman/datautils"
+)
+
+const predictionLog = `{"score": 0.9, "label": 1, "query_id": "q1", "timestamp": "2024-01-01T00:00:00Z", "gender": "f"}
+{"score": 0.4, "label": false, "query_id": "q1", "timestamp": "2024-01-01T12:00:00Z", "gender": "m"}
+
+{"score": 0.7, "label": null, "query_id": 2, "timestamp": 1704153600, "gender": "m", "extra": [1, 2]}
+{"score": 0.2, "query_id": 2, "timestamp": 1704153600.5}
+`
+
+func TestReadPredictionLog(t *testing.T) {
+	fields := datautils.DefaultPredictionLogFields
+	fields.Groups = []string{"gender"}
+	l, err := datautils.ReadPredictionLog(strings.NewReader(predictionLog), fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []float64{0.9, 0.4, 0.7, 0.2}; !reflect.DeepEqual(l.Predictions, expected) {
+		t.Errorf("Expected predictions: %v but received %v", expected, l.Predictions)
+	}
+	if expected := []float64{1, 0, math.NaN(), math.NaN()}; !equalWithNaN(l.Labels, expected) {
+		t.Errorf("Expected labels: %v but received %v", expected, l.Labels)
+	}
+	if expected := []string{"q1", "q1", "2", "2"}; !reflect.DeepEqual(l.Queries, expected) {
+		t.Errorf("Expected queries: %v but received %v", expected, l.Queries)
+	}
+	expectedTimes := []time.Time{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 2, 0, 0, 0, 5e8, time.UTC),
+	}
+	for i, ts := range l.Timestamps {
+		if !ts.Equal(expectedTimes[i]) {
+			t.Errorf("Record %d: Expected timestamp: %v but received %v", i+1, expectedTimes[i], ts)
+		}
+	}
+	if expected := []string{"f", "m", "m", ""}; !reflect.DeepEqual(l.Groups["gender"], expected) {
+		t.Errorf("Expected genders: %q but received %q", expected, l.Groups["gender"])
+	}
+}
+
+func TestReadPredictionLogFields(t *testing.T) {
+	data := `{"p": 0.3, "y": 1, "ts": "01/02/2024"}` + "\n" + `{"p": "Infinity", "y": 0, "ts": "02/02/2024"}`
+	l, err := datautils.ReadPredictionLog(strings.NewReader(data), datautils.PredictionLogFields{
+		Score:      "p",
+		Label:      "y",
+		Timestamp:  "ts",
+		TimeLayout: "02/01/2006",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Predictions[1] != math.Inf(1) || l.Labels[0] != 1 || l.Queries != nil || l.Groups != nil {
+		t.Errorf("Unexpected prediction log: %+v", l)
+	}
+	if expected := time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC); !l.Timestamps[1].Equal(expected) {
+		t.Errorf("Expected timestamp: %v but received %v", expected, l.Timestamps[1])
+	}
+}
+
+func TestReadPredictionLogErrors(t *testing.T) {
+	tests := []struct {
+		data   string
+		fields datautils.PredictionLogFields
+	}{
+		{data: `{"score": 0.5}`, fields: datautils.PredictionLogFields{}},
+		{data: `{"label": 1}`, fields: datautils.DefaultPredictionLogFields},
+		{data: `{"score": "high"}`, fields: datautils.DefaultPredictionLogFields},
+		{data: `{"score": 0.5, "label": [1]}`, fields: datautils.DefaultPredictionLogFields},
+		{data: `{"score": 0.5, "timestamp": "yesterday"}`, fields: datautils.DefaultPredictionLogFields},
+		{data: `{"score": 0.5`, fields: datautils.DefaultPredictionLogFields},
+		{data: "\n\n", fields: datautils.DefaultPredictionLogFields},
+	}
+
+	for i, test := range tests {
+		if _, err := datautils.ReadPredictionLog(strings.NewReader(test.data), test.fields); err == nil {
+			t.Errorf("Test %d: Expected error reading %q", i+1, test.data)
+		}
+	}
+}
+
+func TestPredictionLogEvaluators(t *testing.T) {
+	fields := datautils.DefaultPredictionLogFields
+	fields.Groups = []string{"gender"}
+	l, err := datautils.ReadPredictionLog(strings.NewReader(predictionLog), fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Labels[2], l.Labels[3] = 0, 1
+
+	set := l.EvaluationSet()
+	if queries := set.Queries(); !reflect.DeepEqual(queries, []string{"2", "q1"}) {
+		t.Errorf("Expected queries [2 q1] but received %v", queries)
+	}
+	if mrr := set.MeanReciprocalRank(); mrr != 0.75 {
+		t.Errorf("Expected MRR: 0.75 but received %v", mrr)
+	}
+
+	windows := l.WindowedMetric(averagePrecision, 24*time.Hour, 24*time.Hour)
+	if len(windows) != 2 || windows[0].Observations != 2 || windows[1].Observations != 2 {
+		t.Errorf("Expected 2 daily windows of 2 observations but received %+v", windows)
+	}
+
+	// the record without a gender forms its own group
+	report := l.FairnessReport("gender", 0.5)
+	if len(report.Groups) != 3 || report.Groups[2].Group != "m" || report.Groups[2].Matrix.Observations != 2 {
+		t.Errorf("Unexpected fairness report: %+v", report)
+	}
+}
@@ -0,0 +1,106 @@
+package datautils
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+// PrecisionRecallGainCurve represents a Precision-Recall-Gain (PRG) curve as proposed by Flach & Kull (2015).
+// Precision and recall are rescaled relative to the always-positive classifier, whose precision is the
+// proportion of positives, π, so that precision gain = (precision - π) / ((1 - π) * precision) and likewise for
+// recall gain.  Unlike the standard precision recall curve, linear interpolation between points of the PRG curve
+// is valid and the area under it (AUPRG) relates directly to the expected F score.  Only the region of the curve
+// where recall gain is at least 0 is retained and the points are ordered by ascending recall gain (descending
+// threshold).
+type PrecisionRecallGainCurve struct {
+	// PrecisionGain is a slice containing the precision gain at each point of the curve
+	PrecisionGain []float64
+
+	// RecallGain is a slice containing the recall gain at each point of the curve
+	RecallGain []float64
+
+	// Thresholds is a slice containing the prediction at each point of the curve, shared with the precision recall
+	// curve.  The point where the curve crosses a recall gain of 0, if interpolated, has a threshold of NaN.
+	Thresholds []float64
+}
+
+// NewPrecisionRecallGainCurve creates a new precision recall gain curve from the supplied predictions and ground
+// truth labels.  The curve is derived from the points of the precision recall curve (see
+// NewPrecisionRecallCurve) for the same predictions and labels, with any label value greater than 0 representing
+// a positive observation.  If there are no positive observations the curve is empty.
+func NewPrecisionRecallGainCurve[P, L Float](predictions []P, labels []L) PrecisionRecallGainCurve {
+	pr := NewPrecisionRecallCurve(predictions, labels)
+
+	var curve PrecisionRecallGainCurve
+	if pr.positives == 0 {
+		return curve
+	}
+	pi := float64(pr.positives) / float64(len(labels))
+	gain := func(v float64) float64 {
+		return (v - pi) / ((1 - pi) * v)
+	}
+
+	prevRG, prevPG := math.Inf(-1), math.Inf(-1)
+	// iterate by descending threshold skipping the final point (precision and recall @ 0)
+	for i := len(pr.Precision) - 2; i >= 0; i-- {
+		rg, pg := gain(pr.Recall[i]), gain(pr.Precision[i])
+		if rg >= 0 {
+			if len(curve.RecallGain) == 0 && prevRG < 0 && !math.IsInf(prevRG, -1) {
+				// interpolate the point where the curve crosses recall gain 0
+				t := -prevRG / (rg - prevRG)
+				curve.RecallGain = append(curve.RecallGain, 0)
+				curve.PrecisionGain = append(curve.PrecisionGain, prevPG+t*(pg-prevPG))
+				curve.Thresholds = append(curve.Thresholds, math.NaN())
+			}
+			curve.RecallGain = append(curve.RecallGain, rg)
+			curve.PrecisionGain = append(curve.PrecisionGain, pg)
+			curve.Thresholds = append(curve.Thresholds, pr.Thresholds[i])
+		}
+		prevRG, prevPG = rg, pg
+	}
+
+	return curve
+}
+
+// AUPRG calculates the area under the precision recall gain curve using the trapezoidal rule.  Precision gain
+// may be negative for predictions that perform worse than the always-positive classifier and so the area may
+// also be negative.
+func (c PrecisionRecallGainCurve) AUPRG() float64 {
+	var sum float64
+	for i := 0; i < len(c.RecallGain)-1; i++ {
+		sum += (c.RecallGain[i+1] - c.RecallGain[i]) * (c.PrecisionGain[i+1] + c.PrecisionGain[i]) / 2
+	}
+	return sum
+}
+
+// Plot renders the precision recall gain curve as a plot for visualisation.  The axes are limited to the unit
+// square which contains the meaningful region of the curve.
+func (c PrecisionRecallGainCurve) Plot() *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+
+	p.Title.Text = PlotTheme.title(fmt.Sprintf("Precision-recall-gain Curve, AUPRG=%f", c.AUPRG()))
+	p.X.Label.Text = "Recall Gain"
+	p.Y.Label.Text = "Precision Gain"
+	p.X.Min, p.X.Max = 0, 1
+	p.Y.Min, p.Y.Max = 0, 1
+
+	pts := make(plotter.XYs, len(c.RecallGain))
+	for i := range pts {
+		pts[i].X = c.RecallGain[i]
+		pts[i].Y = c.PrecisionGain[i]
+	}
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		panic(err)
+	}
+	line.Color = PlotTheme.Primary
+	p.Add(line)
+
+	return p
+}
@@ -0,0 +1,56 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestPrecisionRecallGainCurve(t *testing.T) {
+	tests := []struct {
+		predictions   []float64
+		labels        []float64
+		recallGain    []float64
+		precisionGain []float64
+		auprg         float64
+	}{
+		{
+			predictions:   []float64{0.9, 0.8, 0.7, 0.6},
+			labels:        []float64{1, 0, 1, 0},
+			recallGain:    []float64{0, 0, 1},
+			precisionGain: []float64{1, 0, 0.5},
+			auprg:         0.25,
+		},
+		{
+			// the curve crosses recall gain 0 between the 2nd and 3rd ranked predictions
+			predictions:   []float64{7, 6, 5, 4, 3, 2, 1},
+			labels:        []float64{1, 0, 1, 0, 1, 0, 0},
+			recallGain:    []float64{0, 5.0 / 8, 5.0 / 8, 1},
+			precisionGain: []float64{5.0 / 12, 5.0 / 8, 0.25, 0.5},
+			auprg:         179.0 / 384,
+		},
+		{
+			predictions:   []float64{0.9, 0.8},
+			labels:        []float64{0, 0},
+			recallGain:    nil,
+			precisionGain: nil,
+			auprg:         0,
+		},
+	}
+
+	for i, test := range tests {
+		curve := datautils.NewPrecisionRecallGainCurve(test.predictions, test.labels)
+
+		if !floats.EqualApprox(curve.RecallGain, test.recallGain, 1e-12) {
+			t.Errorf("Test %d: Expected recall gain: %v but received %v", i+1, test.recallGain, curve.RecallGain)
+		}
+		if !floats.EqualApprox(curve.PrecisionGain, test.precisionGain, 1e-12) {
+			t.Errorf("Test %d: Expected precision gain: %v but received %v", i+1, test.precisionGain, curve.PrecisionGain)
+		}
+		if auprg := curve.AUPRG(); math.Abs(auprg-test.auprg) > 1e-12 {
+			t.Errorf("Test %d: Expected AUPRG: %v but received %v", i+1, test.auprg, auprg)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package promexport publishes evaluation metrics computed with datautils as Prometheus metrics so that
+// evaluation jobs can be scraped by existing monitoring infrastructure.  The Collector implements
+// prometheus.Collector and so may be registered with any prometheus.Registerer and served with promhttp e.g.
+//
+//	c := promexport.NewCollector("model_eval")
+//	prometheus.MustRegister(c)
+//	http.Handle("/metrics", promhttp.Handler())
+//	...
+//	c.SetAveragePrecision("ranker-v2", datautils.NewPrecisionRecallCurve(predictions, labels))
+package promexport
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/james-bowman/datautils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector holds the latest values of evaluation metrics, labelled by model, and exposes them to Prometheus.
+// Summary metrics (average precision, ROC AUC and NDCG@k) are exposed as gauges reflecting the most recent
+// evaluation.  Confusion matrix cells are exposed as counters accumulated across all matrices added so that
+// evaluations computed over successive batches (see datautils.ConfusionMatrixBuilder) can be aggregated with
+// PromQL functions such as rate().  A Collector is safe for concurrent use.
+type Collector struct {
+	ap    *prometheus.Desc
+	auc   *prometheus.Desc
+	ndcg  *prometheus.Desc
+	cells *prometheus.Desc
+
+	mu     sync.RWMutex
+	values map[string]sample
+}
+
+// sample is the current value of a single labelled metric.
+type sample struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	value     float64
+	labels    []string
+}
+
+// NewCollector creates a new Collector with all metric names prefixed by namespace.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		ap: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "average_precision"),
+			"Average precision of the model's precision recall curve.", []string{"model"}, nil),
+		auc: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "roc_auc"),
+			"Area under the model's ROC curve.", []string{"model"}, nil),
+		ndcg: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "ndcg"),
+			"Normalised discounted cumulative gain of the model at cut-off k.", []string{"model", "k"}, nil),
+		cells: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "confusion_matrix_total"),
+			"Cumulative count (or weight) of observations in each cell of the model's confusion matrix.", []string{"model", "cell"}, nil),
+		values: make(map[string]sample),
+	}
+}
+
+// set records the value of the metric described by desc with the specified label values.  If add is true, the
+// value is added to any existing value rather than replacing it.
+func (c *Collector) set(desc *prometheus.Desc, valueType prometheus.ValueType, value float64, add bool, labels ...string) {
+	key := desc.String() + "\xff" + strings.Join(labels, "\xff")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s, ok := c.values[key]; ok && add {
+		value += s.value
+	}
+	c.values[key] = sample{desc: desc, valueType: valueType, value: value, labels: labels}
+}
+
+// SetAveragePrecision sets the average precision gauge for the model from the supplied precision recall curve.
+func (c *Collector) SetAveragePrecision(model string, curve datautils.PrecisionRecallCurve) {
+	c.set(c.ap, prometheus.GaugeValue, curve.AveragePrecision(), false, model)
+}
+
+// SetAUC sets the ROC AUC gauge for the model from the supplied ROC curve.
+func (c *Collector) SetAUC(model string, curve datautils.ROCCurve) {
+	c.set(c.auc, prometheus.GaugeValue, curve.AUC(), false, model)
+}
+
+// SetNDCG sets the NDCG gauge for the model at cut-off k e.g. as calculated by
+// datautils.EvaluationSet.MeanNormalisedDiscountedCumulativeGains.
+func (c *Collector) SetNDCG(model string, k int, ndcg float64) {
+	c.set(c.ndcg, prometheus.GaugeValue, ndcg, false, model, strconv.Itoa(k))
+}
+
+// AddConfusionMatrix adds the cells of the supplied confusion matrix to the model's confusion matrix counters.
+// The cells are labelled tp, tn, fp and fn.  For weighted matrices (see datautils.NewWeightedConfusionMatrix)
+// the sums of weights are added rather than the counts.
+func (c *Collector) AddConfusionMatrix(model string, m datautils.ConfusionMatrix) {
+	cells := map[string]float64{
+		"tp": float64(m.TruePos),
+		"tn": float64(m.TrueNeg),
+		"fp": float64(m.FalsePos),
+		"fn": float64(m.FalseNeg),
+	}
+	if m.Weighted {
+		cells = map[string]float64{
+			"tp": m.Weights.TruePos,
+			"tn": m.Weights.TrueNeg,
+			"fp": m.Weights.FalsePos,
+			"fn": m.Weights.FalseNeg,
+		}
+	}
+	for cell, v := range cells {
+		c.set(c.cells, prometheus.CounterValue, v, true, model, cell)
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ap
+	ch <- c.auc
+	ch <- c.ndcg
+	ch <- c.cells
+}
+
+// Collect implements prometheus.Collector.  Metrics are collected in a deterministic order.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := c.values[k]
+		ch <- prometheus.MustNewConstMetric(s.desc, s.valueType, s.value, s.labels...)
+	}
+}
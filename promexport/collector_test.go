@@ -0,0 +1,73 @@
+package promexport_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"github.com/james-bowman/datautils/promexport"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// collect returns the values of the metrics collected from c keyed by metric name and label values.
+func collect(t *testing.T, c prometheus.Collector) map[string]float64 {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+
+	values := make(map[string]float64)
+	for m := range ch {
+		var out dto.Metric
+		if err := m.Write(&out); err != nil {
+			t.Fatalf("Unexpected error writing metric: %v", err)
+		}
+		desc := m.Desc().String()
+		name := desc[strings.Index(desc, `fqName: "`)+9:]
+		name = name[:strings.Index(name, `"`)]
+		for _, l := range out.GetLabel() {
+			name += "," + l.GetValue()
+		}
+		if out.GetCounter() != nil {
+			values[name] = out.GetCounter().GetValue()
+		} else {
+			values[name] = out.GetGauge().GetValue()
+		}
+	}
+	return values
+}
+
+func TestCollector(t *testing.T) {
+	predictions := []float64{0.1, 0.4, 0.35, 0.8}
+	labels := []float64{0, 0, 1, 1}
+
+	c := promexport.NewCollector("eval")
+	c.SetAveragePrecision("m1", datautils.NewPrecisionRecallCurve(predictions, labels))
+	c.SetAUC("m1", datautils.NewROCCurve(predictions, labels))
+	c.SetNDCG("m1", 10, 0.5)
+	c.SetNDCG("m1", 10, 0.75)
+	c.AddConfusionMatrix("m1", datautils.NewConfusionMatrix(predictions, labels, 0.5))
+	c.AddConfusionMatrix("m1", datautils.NewConfusionMatrix(predictions, labels, 0.3))
+
+	expected := map[string]float64{
+		"eval_average_precision,m1":         0.8333333333333333,
+		"eval_roc_auc,m1":                   0.75,
+		"eval_ndcg,m1,10":                   0.75,
+		"eval_confusion_matrix_total,m1,tp": 3,
+		"eval_confusion_matrix_total,m1,tn": 3,
+		"eval_confusion_matrix_total,m1,fp": 1,
+		"eval_confusion_matrix_total,m1,fn": 1,
+	}
+
+	values := collect(t, c)
+	if len(values) != len(expected) {
+		t.Errorf("Expected %d metrics but received %d: %v", len(expected), len(values), values)
+	}
+	for name, v := range expected {
+		if values[name] != v {
+			t.Errorf("Expected %s: %v but received %v", name, v, values[name])
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package datautils
+
+import "math"
+
+// Coverage calculates the catalog coverage of a set of recommendation lists.  This is the proportion of items
+// within the catalog that are recommended in at least one of the lists.  Recommended items not present in the
+// catalog are ignored.  If the catalog is empty, 0 is returned.
+func Coverage(recommendations [][]string, catalog []string) float64 {
+	items := make(map[string]bool, len(catalog))
+	for _, item := range catalog {
+		items[item] = false
+	}
+	if len(items) == 0 {
+		return 0
+	}
+
+	var covered int
+	for _, list := range recommendations {
+		for _, item := range list {
+			if seen, ok := items[item]; ok && !seen {
+				items[item] = true
+				covered++
+			}
+		}
+	}
+	return float64(covered) / float64(len(items))
+}
+
+// Novelty calculates the mean novelty of a set of recommendation lists.  The novelty of an item is its
+// self-information, -log2(p), where p is the popularity of the item expressed as a probability e.g. the
+// proportion of users that have interacted with it.  Less popular items are therefore more novel.  The novelty
+// of each list is the mean novelty of its items and the result is the mean across all lists.  Items with no
+// (or zero) popularity have undefined novelty and are skipped, as are lists containing no such items.  If there
+// are no lists, 0 is returned.
+func Novelty(recommendations [][]string, popularity map[string]float64) float64 {
+	return meanList(recommendations, func(list []string) (float64, bool) {
+		var sum float64
+		var n int
+		for _, item := range list {
+			if p := popularity[item]; p > 0 {
+				sum -= math.Log2(p)
+				n++
+			}
+		}
+		if n == 0 {
+			return 0, false
+		}
+		return sum / float64(n), true
+	})
+}
+
+// IntraListDiversity calculates the mean intra-list diversity of a set of recommendation lists.  The diversity
+// of a list is the mean pairwise distance between all the items within it as measured by the supplied distance
+// function (e.g. 1 - cosine similarity of item embeddings or the Jaccard distance of item categories).  The
+// result is the mean diversity across all lists.  Lists with fewer than 2 items have undefined diversity and
+// are skipped.  If there are no lists with at least 2 items, 0 is returned.
+func IntraListDiversity(recommendations [][]string, distance func(a, b string) float64) float64 {
+	return meanList(recommendations, func(list []string) (float64, bool) {
+		if len(list) < 2 {
+			return 0, false
+		}
+		var sum float64
+		for i := range list {
+			for j := i + 1; j < len(list); j++ {
+				sum += distance(list[i], list[j])
+			}
+		}
+		pairs := len(list) * (len(list) - 1) / 2
+		return sum / float64(pairs), true
+	})
+}
+
+// Serendipity calculates the mean serendipity of a set of recommendation lists.  The serendipity of a list is
+// the proportion of its items that are both unexpected and relevant to the user.  An item is unexpected if it
+// does not appear in the corresponding list of expected recommendations, typically those produced by a
+// primitive baseline such as recommending the most popular items.  recommendations, expected and relevant must
+// all be the same length with the lists at each index corresponding to the same user.  Empty recommendation
+// lists are skipped.  If there are no non-empty lists, 0 is returned.
+func Serendipity(recommendations, expected, relevant [][]string) float64 {
+	if len(recommendations) != len(expected) || len(recommendations) != len(relevant) {
+		panic(ErrLengthMismatch)
+	}
+
+	var sum float64
+	var n int
+	for i, list := range recommendations {
+		if len(list) == 0 {
+			continue
+		}
+		unexpected := make(map[string]bool, len(list))
+		for _, item := range list {
+			unexpected[item] = true
+		}
+		for _, item := range expected[i] {
+			delete(unexpected, item)
+		}
+		var serendipitous int
+		for _, item := range relevant[i] {
+			if unexpected[item] {
+				serendipitous++
+				delete(unexpected, item)
+			}
+		}
+		sum += float64(serendipitous) / float64(len(list))
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// meanList calculates the mean of the per list metric calculated by f across the recommendation lists.  Lists
+// for which f reports the metric as undefined are excluded.  If no lists are included, 0 is returned.
+func meanList(recommendations [][]string, f func([]string) (float64, bool)) float64 {
+	var sum float64
+	var n int
+	for _, list := range recommendations {
+		if v, ok := f(list); ok {
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
@@ -0,0 +1,68 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+var recommendations = [][]string{
+	{"a", "b", "c"},
+	{"a", "d"},
+	{"e"},
+}
+
+func TestCoverage(t *testing.T) {
+	tests := []struct {
+		catalog  []string
+		coverage float64
+	}{
+		{catalog: []string{"a", "b", "c", "d", "e", "f", "g", "h"}, coverage: 5.0 / 8.0},
+		{catalog: []string{"a", "z"}, coverage: 0.5},
+		{catalog: nil, coverage: 0},
+	}
+
+	for i, test := range tests {
+		if coverage := datautils.Coverage(recommendations, test.catalog); coverage != test.coverage {
+			t.Errorf("Test %d: Expected coverage: %v but received %v", i+1, test.coverage, coverage)
+		}
+	}
+}
+
+func TestNovelty(t *testing.T) {
+	popularity := map[string]float64{"a": 0.5, "b": 0.25, "c": 0.125, "d": 0.5}
+
+	// list 1: (1 + 2 + 3) / 3 = 2, list 2: (1 + 1) / 2 = 1, list 3: no known items so skipped
+	expected := 1.5
+	if novelty := datautils.Novelty(recommendations, popularity); novelty != expected {
+		t.Errorf("Expected novelty: %v but received %v", expected, novelty)
+	}
+}
+
+func TestIntraListDiversity(t *testing.T) {
+	categories := map[string]string{"a": "x", "b": "x", "c": "y", "d": "y", "e": "z"}
+	distance := func(a, b string) float64 {
+		if categories[a] == categories[b] {
+			return 0
+		}
+		return 1
+	}
+
+	// list 1: pairs (a,b)=0, (a,c)=1, (b,c)=1 => 2/3, list 2: (a,d)=1, list 3: skipped
+	expected := (2.0/3.0 + 1) / 2
+	if diversity := datautils.IntraListDiversity(recommendations, distance); math.Abs(diversity-expected) > 1e-12 {
+		t.Errorf("Expected intra-list diversity: %v but received %v", expected, diversity)
+	}
+}
+
+func TestSerendipity(t *testing.T) {
+	expected := [][]string{{"a"}, {"a"}, {"a"}}
+	relevant := [][]string{{"a", "c"}, {"d", "e"}, {"b"}}
+
+	// list 1: c is unexpected and relevant => 1/3, list 2: d => 1/2, list 3: none => 0
+	want := (1.0/3.0 + 0.5) / 3
+	if serendipity := datautils.Serendipity(recommendations, expected, relevant); math.Abs(serendipity-want) > 1e-12 {
+		t.Errorf("Expected serendipity: %v but received %v", want, serendipity)
+	}
+}
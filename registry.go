@@ -0,0 +1,224 @@
+package datautils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MetricOptions holds the additional inputs required by some metrics.  Metrics ignore any options they do not
+// use.
+type MetricOptions struct {
+	// Threshold is the classification threshold used by metrics calculated from a confusion matrix (e.g. f1).
+	Threshold float64
+
+	// ZeroDivision is the policy for metrics calculated from a confusion matrix whose denominators are zero (see
+	// ConfusionMatrix.ZeroDivision).
+	ZeroDivision ZeroDivisionPolicy
+
+	// Queries are the query IDs grouping predictions for ranking metrics (e.g. ndcg@10) which are averaged across
+	// queries.  If nil, all the predictions are treated as the ranking of a single query.
+	Queries []string
+
+	// Relevancy is the relevancy function used by gain based ranking metrics (e.g. ndcg@k).  If nil,
+	// TraditionalRelevancy is used.
+	Relevancy RelevancyFunction
+}
+
+// evaluationSet returns an EvaluationSet of the predictions grouped by the query IDs in the options.
+func (o MetricOptions) evaluationSet(predictions, labels []float64) EvaluationSet {
+	if o.Queries == nil {
+		return EvaluationSet{"": NewRankingEvaluation(predictions, labels)}
+	}
+	return NewQueryEvaluationSet(predictions, labels, o.Queries)
+}
+
+// relevancy returns the relevancy function specified in the options or TraditionalRelevancy if none was specified.
+func (o MetricOptions) relevancy() RelevancyFunction {
+	if o.Relevancy == nil {
+		return TraditionalRelevancy
+	}
+	return o.Relevancy
+}
+
+// Metric is an evaluation metric identified by name allowing metrics to be selected at runtime e.g. from
+// configuration.
+type Metric interface {
+	// Name returns the name of the metric e.g. "auc" or "ndcg@10".
+	Name() string
+
+	// Compute calculates the metric for the predictions against the labels.  As with the underlying metric
+	// constructors, Compute panics if the lengths of predictions and labels (or opts.Queries) differ.
+	Compute(predictions, labels []float64, opts MetricOptions) float64
+}
+
+// NewMetric creates a new Metric with the specified name, calculated by compute.
+func NewMetric(name string, compute func(predictions, labels []float64, opts MetricOptions) float64) Metric {
+	return namedMetric{name: name, compute: compute}
+}
+
+// namedMetric is a Metric calculated by a function.
+type namedMetric struct {
+	name    string
+	compute func(predictions, labels []float64, opts MetricOptions) float64
+}
+
+func (m namedMetric) Name() string { return m.name }
+
+func (m namedMetric) Compute(predictions, labels []float64, opts MetricOptions) float64 {
+	return m.compute(predictions, labels, opts)
+}
+
+// MetricRegistry is a collection of metrics that may be looked up by name.  As well as metrics with fixed names,
+// a registry may contain families of metrics parameterised by a cut-off, k, which are named prefix@k (e.g. ndcg@10).
+// A MetricRegistry is safe for concurrent use.
+type MetricRegistry struct {
+	mu       sync.RWMutex
+	metrics  map[string]Metric
+	families map[string]func(k int) Metric
+}
+
+// NewMetricRegistry creates a new, empty MetricRegistry.  Most callers will use DefaultMetricRegistry, which
+// contains all the built in metrics, rather than creating their own.
+func NewMetricRegistry() *MetricRegistry {
+	return &MetricRegistry{
+		metrics:  make(map[string]Metric),
+		families: make(map[string]func(k int) Metric),
+	}
+}
+
+// Register adds the metric to the registry under its name.  Register panics if the name is empty, contains '@' or
+// is already registered.
+func (r *MetricRegistry) Register(m Metric) {
+	name := m.Name()
+	if name == "" || strings.Contains(name, "@") {
+		panic(fmt.Sprintf("datautils: invalid metric name %q", name))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.metrics[name]; ok {
+		panic(fmt.Sprintf("datautils: metric %q already registered", name))
+	}
+	r.metrics[name] = m
+}
+
+// RegisterCutoff adds a family of metrics named prefix@k to the registry, where metric returns the metric for
+// cut-off k.  Cut-offs in looked up names must be positive integers.  RegisterCutoff panics if the prefix is
+// empty, contains '@' or is already registered.
+func (r *MetricRegistry) RegisterCutoff(prefix string, metric func(k int) Metric) {
+	if prefix == "" || strings.Contains(prefix, "@") {
+		panic(fmt.Sprintf("datautils: invalid metric name %q", prefix))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.families[prefix]; ok {
+		panic(fmt.Sprintf("datautils: metric %q already registered", prefix+"@k"))
+	}
+	r.families[prefix] = metric
+}
+
+// Lookup returns the metric with the specified name.  An error is returned if no such metric is registered or the
+// cut-off of a parameterised metric (e.g. ndcg@0) is invalid.
+func (r *MetricRegistry) Lookup(name string) (Metric, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := strings.Index(name, "@")
+	if i == -1 {
+		if m, ok := r.metrics[name]; ok {
+			return m, nil
+		}
+		return nil, fmt.Errorf("datautils: unknown metric %q", name)
+	}
+	family, ok := r.families[name[:i]]
+	if !ok {
+		return nil, fmt.Errorf("datautils: unknown metric %q", name)
+	}
+	k, err := strconv.Atoi(name[i+1:])
+	if err != nil || k < 1 {
+		return nil, fmt.Errorf("datautils: invalid cut-off for metric %q", name)
+	}
+	return family(k), nil
+}
+
+// Names returns the names of the registered metrics in sorted order with families of parameterised metrics
+// named prefix@k.
+func (r *MetricRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.metrics)+len(r.families))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	for prefix := range r.families {
+		names = append(names, prefix+"@k")
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultMetricRegistry is the registry used by RegisterMetric and LookupMetric.  It contains the built in
+// metrics:
+//
+//   - ap and auc: the average precision and area under the ROC curve of the predictions
+//   - precision, recall, f1, accuracy, specificity, mcc and kappa: calculated from the confusion matrix at
+//     MetricOptions.Threshold
+//   - mrr, ndcg@k, hr@k and recall@k: the mean reciprocal rank, NDCG, hit rate and recall at cut-off k averaged
+//     across the queries in MetricOptions.Queries
+var DefaultMetricRegistry = newDefaultMetricRegistry()
+
+// RegisterMetric adds the metric to DefaultMetricRegistry (see MetricRegistry.Register).
+func RegisterMetric(m Metric) {
+	DefaultMetricRegistry.Register(m)
+}
+
+// LookupMetric returns the metric with the specified name from DefaultMetricRegistry (see MetricRegistry.Lookup).
+func LookupMetric(name string) (Metric, error) {
+	return DefaultMetricRegistry.Lookup(name)
+}
+
+// newDefaultMetricRegistry creates a new MetricRegistry containing the built in metrics.
+func newDefaultMetricRegistry() *MetricRegistry {
+	r := NewMetricRegistry()
+
+	r.Register(NewMetric("ap", func(predictions, labels []float64, opts MetricOptions) float64 {
+		return NewPrecisionRecallCurve(predictions, labels).AveragePrecision()
+	}))
+	r.Register(NewMetric("auc", func(predictions, labels []float64, opts MetricOptions) float64 {
+		return NewROCCurve(predictions, labels).AUC()
+	}))
+
+	for _, m := range confusionMetrics {
+		f := m.metric
+		r.Register(NewMetric(m.name, func(predictions, labels []float64, opts MetricOptions) float64 {
+			matrix := NewConfusionMatrix(predictions, labels, opts.Threshold)
+			matrix.ZeroDivision = opts.ZeroDivision
+			return f(matrix)
+		}))
+	}
+
+	r.Register(NewMetric("mrr", func(predictions, labels []float64, opts MetricOptions) float64 {
+		return opts.evaluationSet(predictions, labels).MeanReciprocalRank()
+	}))
+	r.RegisterCutoff("ndcg", func(k int) Metric {
+		return NewMetric("ndcg@"+strconv.Itoa(k), func(predictions, labels []float64, opts MetricOptions) float64 {
+			return opts.evaluationSet(predictions, labels).MeanNormalisedDiscountedCumulativeGains([]int{k}, opts.relevancy())[0]
+		})
+	})
+	r.RegisterCutoff("hr", func(k int) Metric {
+		return NewMetric("hr@"+strconv.Itoa(k), func(predictions, labels []float64, opts MetricOptions) float64 {
+			return opts.evaluationSet(predictions, labels).HitRate(k)
+		})
+	})
+	r.RegisterCutoff("recall", func(k int) Metric {
+		return NewMetric("recall@"+strconv.Itoa(k), func(predictions, labels []float64, opts MetricOptions) float64 {
+			return opts.evaluationSet(predictions, labels).MeanRecallAt(k)
+		})
+	})
+	return r
+}
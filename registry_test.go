@@ -0,0 +1,104 @@
+package datautils_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestLookupMetric(t *testing.T) {
+	predictions := []float64{0.9, 0.4, 0.7, 0.2, 0.6, 0.1}
+	labels := []float64{1, 0, 0, 1, 1, 0}
+	queries := []string{"a", "a", "b", "b", "c", "c"}
+	opts := datautils.MetricOptions{Threshold: 0.5, Queries: queries}
+
+	set := datautils.NewQueryEvaluationSet(predictions, labels, queries)
+	matrix := datautils.NewConfusionMatrix(predictions, labels, 0.5)
+	tests := []struct {
+		name     string
+		expected float64
+	}{
+		{name: "ap", expected: datautils.NewPrecisionRecallCurve(predictions, labels).AveragePrecision()},
+		{name: "auc", expected: datautils.NewROCCurve(predictions, labels).AUC()},
+		{name: "f1", expected: matrix.F1()},
+		{name: "mcc", expected: matrix.MCC()},
+		{name: "specificity", expected: matrix.Specificity()},
+		{name: "mrr", expected: set.MeanReciprocalRank()},
+		{name: "ndcg@2", expected: set.MeanNormalisedDiscountedCumulativeGains([]int{2}, datautils.TraditionalRelevancy)[0]},
+		{name: "hr@1", expected: set.HitRate(1)},
+		{name: "recall@1", expected: set.MeanRecallAt(1)},
+	}
+
+	for _, test := range tests {
+		m, err := datautils.LookupMetric(test.name)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if m.Name() != test.name {
+			t.Errorf("Expected metric name %q but received %q", test.name, m.Name())
+		}
+		if v := m.Compute(predictions, labels, opts); v != test.expected {
+			t.Errorf("%s: Expected %v but received %v", test.name, test.expected, v)
+		}
+	}
+}
+
+func TestMetricSingleQuery(t *testing.T) {
+	predictions := []float64{0.9, 0.4, 0.7, 0.2}
+	labels := []float64{0, 0, 1, 1}
+
+	m, err := datautils.LookupMetric("mrr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mrr := m.Compute(predictions, labels, datautils.MetricOptions{}); mrr != 0.5 {
+		t.Errorf("Expected MRR of a single query: 0.5 but received %v", mrr)
+	}
+}
+
+func TestLookupMetricErrors(t *testing.T) {
+	for _, name := range []string{"", "nope", "ndcg", "ndcg@0", "ndcg@x", "ap@5", "nope@5"} {
+		if _, err := datautils.LookupMetric(name); err == nil {
+			t.Errorf("Expected error looking up metric %q", name)
+		}
+	}
+}
+
+func TestMetricRegistry(t *testing.T) {
+	r := datautils.NewMetricRegistry()
+	r.Register(datautils.NewMetric("positives", func(predictions, labels []float64, opts datautils.MetricOptions) float64 {
+		return float64(datautils.NewConfusionMatrix(predictions, labels, opts.Threshold).TruePos)
+	}))
+	r.RegisterCutoff("top", func(k int) datautils.Metric {
+		return datautils.NewMetric("top", func(predictions, labels []float64, opts datautils.MetricOptions) float64 {
+			return predictions[k-1]
+		})
+	})
+
+	if names := r.Names(); !reflect.DeepEqual(names, []string{"positives", "top@k"}) {
+		t.Errorf("Expected names [positives top@k] but received %v", names)
+	}
+	m, err := r.Lookup("top@2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := m.Compute([]float64{0.3, 0.2}, []float64{1, 0}, datautils.MetricOptions{}); v != 0.2 {
+		t.Errorf("Expected 0.2 but received %v", v)
+	}
+	if _, err := r.Lookup("auc"); err == nil {
+		t.Errorf("Expected built in metrics to be absent from a new registry")
+	}
+
+	for _, name := range []string{"positives", "", "top@1"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic registering metric %q", name)
+				}
+			}()
+			r.Register(datautils.NewMetric(name, nil))
+		}()
+	}
+}
@@ -0,0 +1,158 @@
+package datautils
+
+import (
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// RandomOverSample balances the classes (distinct label values) of the supplied features and labels by randomly
+// duplicating rows of the minority classes, sampling with replacement, until every class has as many rows as the
+// largest class.  The returned dataset contains all of the original rows, in their original order, followed by
+// the duplicated rows.  The seed is used to initialise the random number generator so that resampling is
+// reproducible.
+func RandomOverSample(features mat.Matrix, labels []float64, seed int64) Dataset {
+	validateResample(features, labels)
+	rnd := rand.New(rand.NewSource(seed))
+
+	rows := allIndices(len(labels))
+	classes := classIndices(labels)
+	majority := largestClass(classes)
+	for _, class := range classes {
+		for n := len(class); n < majority; n++ {
+			rows = append(rows, class[rnd.Intn(len(class))])
+		}
+	}
+
+	return Dataset{Features: selectRows(features, rows), Labels: selectValues(labels, rows)}
+}
+
+// RandomUnderSample balances the classes (distinct label values) of the supplied features and labels by randomly
+// discarding rows of the majority classes, sampling without replacement, until every class has as many rows as
+// the smallest class.  The retained rows keep their original relative ordering.  The seed is used to initialise
+// the random number generator so that resampling is reproducible.
+func RandomUnderSample(features mat.Matrix, labels []float64, seed int64) Dataset {
+	validateResample(features, labels)
+	rnd := rand.New(rand.NewSource(seed))
+
+	classes := classIndices(labels)
+	minority := len(labels)
+	for _, class := range classes {
+		if len(class) < minority {
+			minority = len(class)
+		}
+	}
+
+	var rows []int
+	for _, class := range classes {
+		shuffled := make([]int, len(class))
+		copy(shuffled, class)
+		rnd.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		rows = append(rows, shuffled[:minority]...)
+	}
+	sort.Ints(rows)
+
+	return Dataset{Features: selectRows(features, rows), Labels: selectValues(labels, rows)}
+}
+
+// SMOTE balances the classes (distinct label values) of the supplied features and labels using the Synthetic
+// Minority Over-sampling TEchnique.  Rather than duplicating rows, synthetic rows are generated for the minority
+// classes until every class has as many rows as the largest class.  Each synthetic row is generated by selecting
+// a random row of the class and one of its k nearest neighbours within the same class (by Euclidean distance) and
+// interpolating at a random point along the line between them.  If a class has k or fewer rows, all other rows of
+// the class are used as neighbours and a class with a single row is simply duplicated.  The returned dataset
+// contains all of the original rows, in their original order, followed by the synthetic rows.  The seed is used to
+// initialise the random number generator so that resampling is reproducible.
+func SMOTE(features mat.Matrix, labels []float64, k int, seed int64) Dataset {
+	validateResample(features, labels)
+	if k < 1 {
+		panic("datautils: k must be at least 1")
+	}
+	rnd := rand.New(rand.NewSource(seed))
+
+	_, c := features.Dims()
+	r := len(labels)
+	classes := classIndices(labels)
+	majority := largestClass(classes)
+
+	var synthetic [][]float64
+	var syntheticLabels []float64
+	for _, class := range classes {
+		if len(class) == majority {
+			continue
+		}
+		rows := make([][]float64, len(class))
+		for i, row := range class {
+			rows[i] = mat.Row(nil, row, features)
+		}
+		neighbours := nearestNeighbours(rows, k)
+		for n := len(class); n < majority; n++ {
+			i := rnd.Intn(len(rows))
+			v := make([]float64, c)
+			copy(v, rows[i])
+			if len(neighbours[i]) > 0 {
+				nn := rows[neighbours[i][rnd.Intn(len(neighbours[i]))]]
+				gap := rnd.Float64()
+				for j := range v {
+					v[j] += gap * (nn[j] - v[j])
+				}
+			}
+			synthetic = append(synthetic, v)
+			syntheticLabels = append(syntheticLabels, labels[class[0]])
+		}
+	}
+
+	resampled := mat.NewDense(r+len(synthetic), c, nil)
+	for i := 0; i < r; i++ {
+		resampled.SetRow(i, mat.Row(nil, i, features))
+	}
+	for i, v := range synthetic {
+		resampled.SetRow(r+i, v)
+	}
+
+	return Dataset{Features: resampled, Labels: append(selectValues(labels, allIndices(r)), syntheticLabels...)}
+}
+
+func validateResample(features mat.Matrix, labels []float64) {
+	if r, _ := features.Dims(); r != len(labels) {
+		panic(ErrLengthMismatch)
+	}
+	if len(labels) == 0 {
+		panic("datautils: no rows to resample")
+	}
+}
+
+// largestClass returns the number of rows in the largest of the supplied classes.
+func largestClass(classes [][]int) int {
+	var largest int
+	for _, class := range classes {
+		if len(class) > largest {
+			largest = len(class)
+		}
+	}
+	return largest
+}
+
+// nearestNeighbours returns the indices of the (up to) k nearest rows to each of the supplied rows by Euclidean
+// distance, excluding the row itself.  Ties are resolved in favour of the lowest index.
+func nearestNeighbours(rows [][]float64, k int) [][]int {
+	neighbours := make([][]int, len(rows))
+	for i := range rows {
+		candidates := make([]int, 0, len(rows)-1)
+		dists := make([]float64, len(rows))
+		for j := range rows {
+			if j != i {
+				candidates = append(candidates, j)
+				dists[j] = floats.Distance(rows[i], rows[j], 2)
+			}
+		}
+		sort.SliceStable(candidates, func(a, b int) bool { return dists[candidates[a]] < dists[candidates[b]] })
+		if len(candidates) > k {
+			candidates = candidates[:k]
+		}
+		neighbours[i] = candidates
+	}
+	return neighbours
+}
@@ -0,0 +1,105 @@
+package datautils_test
+
+import (
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func imbalanced() (*mat.Dense, []float64) {
+	features := mat.NewDense(6, 2, []float64{
+		0, 0,
+		1, 1,
+		2, 2,
+		3, 3,
+		10, 10,
+		12, 12,
+	})
+	labels := []float64{0, 0, 0, 0, 1, 1}
+	return features, labels
+}
+
+func classCounts(labels []float64) map[float64]int {
+	counts := make(map[float64]int)
+	for _, v := range labels {
+		counts[v]++
+	}
+	return counts
+}
+
+func TestRandomOverSample(t *testing.T) {
+	features, labels := imbalanced()
+	d := datautils.RandomOverSample(features, labels, 42)
+
+	if r, _ := d.Features.Dims(); r != 8 || len(d.Labels) != 8 {
+		t.Fatalf("Expected 8 rows but received %d features and %d labels", r, len(d.Labels))
+	}
+	if counts := classCounts(d.Labels); counts[0] != 4 || counts[1] != 4 {
+		t.Errorf("Expected 4 rows of each class but received %v", counts)
+	}
+	for i := 0; i < 6; i++ {
+		if d.Features.At(i, 0) != features.At(i, 0) || d.Labels[i] != labels[i] {
+			t.Errorf("Expected original row %d to be retained in place", i)
+		}
+	}
+	for i := 6; i < 8; i++ {
+		if v := d.Features.At(i, 0); d.Labels[i] != 1 || (v != 10 && v != 12) {
+			t.Errorf("Expected row %d to duplicate a minority class row but received %v (label %v)", i, v, d.Labels[i])
+		}
+	}
+}
+
+func TestRandomUnderSample(t *testing.T) {
+	features, labels := imbalanced()
+	d := datautils.RandomUnderSample(features, labels, 42)
+
+	if r, _ := d.Features.Dims(); r != 4 || len(d.Labels) != 4 {
+		t.Fatalf("Expected 4 rows but received %d features and %d labels", r, len(d.Labels))
+	}
+	if counts := classCounts(d.Labels); counts[0] != 2 || counts[1] != 2 {
+		t.Errorf("Expected 2 rows of each class but received %v", counts)
+	}
+	for i := 0; i < 4; i++ {
+		// features were constructed so that each row's values identify the original row
+		if v := d.Features.At(i, 1); v != d.Features.At(i, 0) {
+			t.Errorf("Expected row %d to be an original row but received %v", i, mat.Row(nil, i, d.Features))
+		}
+		if i > 0 && d.Features.At(i, 0) <= d.Features.At(i-1, 0) {
+			t.Errorf("Expected rows to retain their original ordering")
+		}
+	}
+}
+
+func TestSMOTE(t *testing.T) {
+	features, labels := imbalanced()
+	d := datautils.SMOTE(features, labels, 1, 42)
+
+	if r, _ := d.Features.Dims(); r != 8 || len(d.Labels) != 8 {
+		t.Fatalf("Expected 8 rows but received %d features and %d labels", r, len(d.Labels))
+	}
+	for i := 6; i < 8; i++ {
+		x, y := d.Features.At(i, 0), d.Features.At(i, 1)
+		// synthetic rows must lie on the line between the two minority class rows
+		if d.Labels[i] != 1 || x != y || x < 10 || x > 12 {
+			t.Errorf("Expected synthetic row %d between minority rows but received (%v, %v) label %v", i, x, y, d.Labels[i])
+		}
+	}
+}
+
+func TestResampleReproducible(t *testing.T) {
+	features, labels := imbalanced()
+	tests := map[string]func(seed int64) datautils.Dataset{
+		"RandomOverSample":  func(seed int64) datautils.Dataset { return datautils.RandomOverSample(features, labels, seed) },
+		"RandomUnderSample": func(seed int64) datautils.Dataset { return datautils.RandomUnderSample(features, labels, seed) },
+		"SMOTE":             func(seed int64) datautils.Dataset { return datautils.SMOTE(features, labels, 1, seed) },
+	}
+
+	for name, resample := range tests {
+		a, b := resample(7), resample(7)
+		if !mat.Equal(a.Features, b.Features) || !floats.Equal(a.Labels, b.Labels) {
+			t.Errorf("%s: Expected identical resamples for identical seeds", name)
+		}
+	}
+}
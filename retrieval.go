@@ -0,0 +1,202 @@
+package datautils
+
+import (
+	"container/heap"
+	"math"
+	"runtime"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// SimilarityFunc returns the similarity of the vectors a and b where higher values indicate greater similarity.
+type SimilarityFunc func(a, b []float64) float64
+
+// DotProduct returns the dot (inner) product of a and b.  For embeddings normalised to unit length this is
+// equivalent to, but cheaper than, CosineSimilarity.
+func DotProduct(a, b []float64) float64 {
+	return floats.Dot(a, b)
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, ranging from -1 to 1.  If either vector has
+// zero length, 0 is returned.
+func CosineSimilarity(a, b []float64) float64 {
+	norms := floats.Norm(a, 2) * floats.Norm(b, 2)
+	if norms == 0 {
+		return 0
+	}
+	return floats.Dot(a, b) / norms
+}
+
+// Neighbour is an item of a corpus retrieved for a query.
+type Neighbour struct {
+	// Index is the row index of the item within the corpus
+	Index int
+
+	// Score is the similarity of the item to the query
+	Score float64
+}
+
+// Retriever retrieves the items of a corpus most similar to a query.  Implementations may perform exact or
+// approximate retrieval and must be safe for concurrent use by multiple goroutines.
+type Retriever interface {
+	// Search returns (at most) the k items most similar to the query in descending order of similarity
+	Search(query []float64, k int) []Neighbour
+}
+
+// ExactSearch is a Retriever performing exact (brute force) retrieval by comparing the query with every row of
+// the corpus.
+type ExactSearch struct {
+	// Corpus contains the embedding of each item of the corpus as a row
+	Corpus mat.Matrix
+
+	// Similarity is the similarity function used to compare queries with the corpus
+	Similarity SimilarityFunc
+}
+
+// Search returns the k rows of the corpus most similar to the query in descending order of similarity, ties
+// ordered by ascending row index.  If the corpus contains fewer than k rows then all the rows are returned.
+func (s ExactSearch) Search(query []float64, k int) []Neighbour {
+	r, c := s.Corpus.Dims()
+	if len(query) != c {
+		panic(mat.ErrShape)
+	}
+	if k < 1 {
+		panic(ErrOutOfBounds)
+	}
+
+	h := make(neighbourHeap, 0, k)
+	row := make([]float64, c)
+	for i := 0; i < r; i++ {
+		h.offer(Neighbour{Index: i, Score: s.Similarity(query, mat.Row(row, i, s.Corpus))}, k)
+	}
+	return h.sorted()
+}
+
+// neighbourHeap is a min-heap of Neighbours ordered by similarity.
+type neighbourHeap []Neighbour
+
+// less reports whether a is less similar than b treating NaN scores as least similar and, for equal scores, the
+// higher index as less similar.
+func (h neighbourHeap) less(a, b Neighbour) bool {
+	sa, sb := a.Score, b.Score
+	if math.IsNaN(sa) {
+		sa = math.Inf(-1)
+	}
+	if math.IsNaN(sb) {
+		sb = math.Inf(-1)
+	}
+	if sa != sb {
+		return sa < sb
+	}
+	return a.Index > b.Index
+}
+
+// offer adds n to the heap, retaining only the k most similar neighbours, so that the least similar neighbour is
+// replaced as more similar ones are found.
+func (h *neighbourHeap) offer(n Neighbour, k int) {
+	switch {
+	case len(*h) < k:
+		heap.Push(h, n)
+	case h.less((*h)[0], n):
+		(*h)[0] = n
+		heap.Fix(h, 0)
+	}
+}
+
+// sorted sorts the neighbours into descending order of similarity, ties ordered by ascending index.
+func (h neighbourHeap) sorted() []Neighbour {
+	sort.Slice(h, func(i, j int) bool { return h.less(h[j], h[i]) })
+	return h
+}
+
+func (h neighbourHeap) Len() int            { return len(h) }
+func (h neighbourHeap) Less(i, j int) bool  { return h.less(h[i], h[j]) }
+func (h neighbourHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *neighbourHeap) Push(x interface{}) { *h = append(*h, x.(Neighbour)) }
+func (h *neighbourHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// RetrievalEvaluation contains the results of evaluating embedding based retrieval with EvaluateRetrieval.
+type RetrievalEvaluation struct {
+	// Run contains the items retrieved for each query and their similarity scores
+	Run Run
+
+	// Set contains the ranking evaluation of each query from which further metrics may be calculated
+	Set EvaluationSet
+
+	// Recall is the mean recall at the cut-off (Recall@k)
+	Recall float64
+
+	// MRR is the mean reciprocal rank of the first relevant item within the cut-off (MRR@k).  Queries without a
+	// relevant item in the top k score 0.
+	MRR float64
+
+	// NDCG is the mean normalised discounted cumulative gain at the cut-off (NDCG@k)
+	NDCG float64
+}
+
+// EvaluateRetrieval evaluates embedding based retrieval, such as a dense retrieval or recommendation model, by
+// retrieving the top k items for each query and scoring the results against relevance judgements.  Each row of
+// queries is the embedding of a query, identified by the corresponding element of queryIDs, and retriever (e.g. an
+// ExactSearch over the corpus embeddings) retrieves items identified by the corresponding element of corpusIDs.
+// If queryIDs or corpusIDs are nil, queries and items are identified by their row index.  Only queries with
+// relevance judgements in qrels are evaluated and they are retrieved concurrently.  As with
+// NewTRECEvaluationSet, relevant items that are not retrieved within the top k are not ranked but are recorded in
+// the Unretrieved field of each query's RankingEvaluation so that they still count against recall and NDCG.
+func EvaluateRetrieval(queries mat.Matrix, queryIDs []string, retriever Retriever, corpusIDs []string, qrels Qrels, k int) RetrievalEvaluation {
+	r, c := queries.Dims()
+	if queryIDs != nil && len(queryIDs) != r {
+		panic(ErrLengthMismatch)
+	}
+	if k < 1 {
+		panic(ErrOutOfBounds)
+	}
+
+	var rows []int
+	for i := 0; i < r; i++ {
+		if _, ok := qrels[featureName(queryIDs, i)]; ok {
+			rows = append(rows, i)
+		}
+	}
+
+	results := make([][]Neighbour, len(rows))
+	bounds := chunks(len(rows), runtime.GOMAXPROCS(0))
+	parallelFor(len(bounds), func(b int) {
+		query := make([]float64, c)
+		for i := bounds[b][0]; i < bounds[b][1]; i++ {
+			results[i] = retriever.Search(mat.Row(query, rows[i], queries), k)
+		}
+	})
+
+	run := make(Run, len(rows))
+	for i, neighbours := range results {
+		docs := make(map[string]float64, len(neighbours))
+		for _, n := range neighbours {
+			if corpusIDs != nil && n.Index >= len(corpusIDs) {
+				panic(ErrLengthMismatch)
+			}
+			docs[featureName(corpusIDs, n.Index)] = n.Score
+		}
+		run[featureName(queryIDs, rows[i])] = docs
+	}
+
+	set := NewTRECEvaluationSet(run, qrels)
+	return RetrievalEvaluation{
+		Run:    run,
+		Set:    set,
+		Recall: set.MeanRecallAt(k),
+		MRR: set.mean(func(r RankingEvaluation) float64 {
+			if rr := r.ReciprocalRank(); rr >= 1/float64(k) {
+				return rr
+			}
+			return 0
+		}),
+		NDCG: set.MeanNormalisedDiscountedCumulativeGains([]int{k}, TraditionalRelevancy)[0],
+	}
+}
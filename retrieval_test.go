@@ -0,0 +1,114 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	corpusEmbeddings = mat.NewDense(4, 2, []float64{
+		1, 0,
+		0, 1,
+		1, 1,
+		-1, 0,
+	})
+	queryEmbeddings = mat.NewDense(3, 2, []float64{
+		1, 0.1,
+		0, 1,
+		5, 5,
+	})
+)
+
+func TestSimilarityFuncs(t *testing.T) {
+	a, b := []float64{1, 2, 2}, []float64{2, 0, 0}
+
+	if dot := datautils.DotProduct(a, b); dot != 2 {
+		t.Errorf("Expected dot product: 2 but received %v", dot)
+	}
+	if cos := datautils.CosineSimilarity(a, b); math.Abs(cos-1.0/3) > 1e-12 {
+		t.Errorf("Expected cosine similarity: %v but received %v", 1.0/3, cos)
+	}
+	if cos := datautils.CosineSimilarity(a, []float64{0, 0, 0}); cos != 0 {
+		t.Errorf("Expected cosine similarity with zero vector: 0 but received %v", cos)
+	}
+}
+
+func TestExactSearch(t *testing.T) {
+	search := datautils.ExactSearch{Corpus: corpusEmbeddings, Similarity: datautils.CosineSimilarity}
+
+	tests := []struct {
+		query    []float64
+		k        int
+		expected []int
+	}{
+		{query: []float64{1, 0.1}, k: 2, expected: []int{0, 2}},
+		{query: []float64{1, 0.1}, k: 4, expected: []int{0, 2, 1, 3}},
+		// ties are ordered by ascending index
+		{query: []float64{0, 1}, k: 3, expected: []int{1, 2, 0}},
+		// k exceeding the size of the corpus returns every row
+		{query: []float64{0, 1}, k: 10, expected: []int{1, 2, 0, 3}},
+	}
+
+	for i, test := range tests {
+		neighbours := search.Search(test.query, test.k)
+		if len(neighbours) != len(test.expected) {
+			t.Fatalf("Test %d: Expected %d neighbours but received %d", i+1, len(test.expected), len(neighbours))
+		}
+		for j, n := range neighbours {
+			if n.Index != test.expected[j] {
+				t.Errorf("Test %d: Expected neighbour %d: %d but received %d", i+1, j, test.expected[j], n.Index)
+			}
+			if expected := datautils.CosineSimilarity(test.query, corpusEmbeddings.RawRowView(n.Index)); n.Score != expected {
+				t.Errorf("Test %d: Expected score %v for neighbour %d but received %v", i+1, expected, j, n.Score)
+			}
+		}
+	}
+}
+
+func TestEvaluateRetrieval(t *testing.T) {
+	search := datautils.ExactSearch{Corpus: corpusEmbeddings, Similarity: datautils.CosineSimilarity}
+	queryIDs := []string{"q0", "q1", "q2"}
+	corpusIDs := []string{"d0", "d1", "d2", "d3"}
+	// q2 has no judgements so is not evaluated
+	qrels := datautils.Qrels{
+		"q0": {"d2": 1, "d3": 1},
+		"q1": {"d1": 1},
+	}
+	ndcg := 1 / math.Log2(3) / (1 + 1/math.Log2(3))
+
+	tests := []struct {
+		k      int
+		recall float64
+		mrr    float64
+		ndcg   float64
+	}{
+		{k: 1, recall: 0.5, mrr: 0.5, ndcg: 0.5},
+		{k: 2, recall: 0.75, mrr: 0.75, ndcg: (ndcg + 1) / 2},
+	}
+
+	for i, test := range tests {
+		eval := datautils.EvaluateRetrieval(queryEmbeddings, queryIDs, search, corpusIDs, qrels, test.k)
+
+		if len(eval.Run) != 2 || len(eval.Run["q0"]) != test.k || len(eval.Set) != 2 {
+			t.Errorf("Test %d: Unexpected run: %v", i+1, eval.Run)
+		}
+		if math.Abs(eval.Recall-test.recall) > 1e-12 {
+			t.Errorf("Test %d: Expected Recall@%d: %v but received %v", i+1, test.k, test.recall, eval.Recall)
+		}
+		if math.Abs(eval.MRR-test.mrr) > 1e-12 {
+			t.Errorf("Test %d: Expected MRR@%d: %v but received %v", i+1, test.k, test.mrr, eval.MRR)
+		}
+		if math.Abs(eval.NDCG-test.ndcg) > 1e-12 {
+			t.Errorf("Test %d: Expected NDCG@%d: %v but received %v", i+1, test.k, test.ndcg, eval.NDCG)
+		}
+	}
+
+	// without IDs queries and items are identified by row index
+	eval := datautils.EvaluateRetrieval(queryEmbeddings, nil, search, nil, datautils.Qrels{"1": {"1": 1}}, 1)
+	if eval.Run["1"]["1"] != 1 || eval.Recall != 1 || eval.MRR != 1 || eval.NDCG != 1 {
+		t.Errorf("Unexpected evaluation identified by row index: %+v", eval)
+	}
+}
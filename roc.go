@@ -0,0 +1,331 @@
+package datautils
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// ROCCurve represents a Receiver Operating Characteristic (ROC) curve for visualising and measuring the
+// performance of a binary classifier across all possible decision thresholds.  The curve plots the true positive
+// rate (recall) against the false positive rate (1 - specificity) as the threshold is lowered.  The points are
+// ordered by descending threshold so FPR[0] and TPR[0] are always 0 (with a threshold of +Inf) and the final
+// point is always (1, 1).
+type ROCCurve struct {
+	// FPR is a slice containing the false positive rate at each threshold
+	FPR []float64
+
+	// TPR is a slice containing the true positive rate at each threshold
+	TPR []float64
+
+	// Thresholds is a slice containing the distinct predictions (probability/similarity scores) in descending
+	// order, preceded by +Inf.  Predictions greater than or equal to Thresholds[i] are classified as positive
+	// at point i of the curve.
+	Thresholds []float64
+}
+
+// NewROCCurve creates a new ROC curve from the supplied predictions and ground truth labels.  Both the supplied
+// predictions and labels slices can be in any order providing they are identical lengths and their order matches
+// e.g. predictions[5] corresponds to the ground truth labels[5].  As with NewPrecisionRecallCurve, any label value
+// greater than 0 is assumed to represent a positive observation.  Tied predictions are treated as a single
+// threshold so the curve moves diagonally across them.  If the labels contain no positive (or no negative)
+// observations, the TPR (or FPR) is undefined and will be NaN.  NaN predictions are ranked last, tied with each
+// other, so that the curve does not depend on the order of the input (see HandleNonFinite to drop or reject them
+// instead).  The predictions and labels may be of any Float type e.g. []float32 model outputs.
+func NewROCCurve[P, L Float](predictions []P, labels []L) ROCCurve {
+	return must(NewROCCurveE(predictions, labels))
+}
+
+// NewROCCurveE creates a new ROC curve in the same way as NewROCCurve but returns ErrLengthMismatch, rather
+// than panicking, if the lengths of the predictions and labels do not match.
+func NewROCCurveE[P, L Float](predictions []P, labels []L) (ROCCurve, error) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		return ROCCurve{}, err
+	}
+
+	var positives, negatives float64
+	for _, v := range labels {
+		if v > 0 {
+			positives++
+		} else {
+			negatives++
+		}
+	}
+
+	sorted, ind := sortedPredictions(predictions)
+	return rocCurve(sorted, ind, labels, positives, negatives), nil
+}
+
+// rocCurve creates a new ROCCurve from the predictions sorted in ascending order along with their original
+// indices (as returned by sortedPredictions) and the labels containing the specified numbers of positives and
+// negatives.
+func rocCurve[L Float](sorted []float64, ind []int, labels []L, positives, negatives float64) ROCCurve {
+	curve := ROCCurve{
+		FPR:        []float64{0},
+		TPR:        []float64{0},
+		Thresholds: []float64{math.Inf(1)},
+	}
+
+	var tp, fp float64
+	for i := len(ind) - 1; i >= 0; i-- {
+		if labels[ind[i]] > 0 {
+			tp++
+		} else {
+			fp++
+		}
+		// only emit a point once all predictions tied at this threshold have been counted.  NaN predictions are
+		// sorted first and tied with each other so that they form a single final point
+		if i > 0 && (sorted[i-1] == sorted[i] || math.IsNaN(sorted[i-1]) && math.IsNaN(sorted[i])) {
+			continue
+		}
+		curve.FPR = append(curve.FPR, fp/negatives)
+		curve.TPR = append(curve.TPR, tp/positives)
+		curve.Thresholds = append(curve.Thresholds, sorted[i])
+	}
+
+	return curve
+}
+
+// AUC calculates the area under the ROC curve using the trapezoidal rule.  This is equivalent to the
+// probability that a randomly chosen positive observation is ranked higher than a randomly chosen negative
+// observation (with ties counting as half).
+func (c ROCCurve) AUC() float64 {
+	var sum float64
+	for i := 0; i < len(c.FPR)-1; i++ {
+		sum += (c.FPR[i+1] - c.FPR[i]) * (c.TPR[i+1] + c.TPR[i]) / 2
+	}
+	return sum
+}
+
+// TPRAt returns the true positive rate of the curve at the supplied false positive rate, linearly interpolating
+// between the points of the curve where necessary.  Where the curve rises vertically at the supplied false
+// positive rate, the highest true positive rate is returned.
+func (c ROCCurve) TPRAt(fpr float64) float64 {
+	// index of the first point with a FPR greater than fpr
+	i := sort.Search(len(c.FPR), func(i int) bool { return c.FPR[i] > fpr })
+	if i == 0 {
+		return 0
+	}
+	if i == len(c.FPR) || c.FPR[i-1] == fpr {
+		return c.TPR[i-1]
+	}
+	t := (fpr - c.FPR[i-1]) / (c.FPR[i] - c.FPR[i-1])
+	return c.TPR[i-1] + t*(c.TPR[i]-c.TPR[i-1])
+}
+
+// TPRAtFPR returns the highest true positive rate (sensitivity) achievable by any threshold of the curve without
+// exceeding the specified false positive rate (1 - specificity), along with that threshold e.g. to determine the
+// sensitivity at 99% specificity.  Unlike TPRAt, the result is not interpolated between the points of the curve
+// so that it corresponds to a threshold that can actually be deployed.  Where several thresholds achieve the same
+// true positive rate, the highest is returned.
+func (c ROCCurve) TPRAtFPR(fpr float64) (tpr, threshold float64) {
+	tpr, threshold = c.TPR[0], c.Thresholds[0]
+	for i := 1; i < len(c.FPR) && c.FPR[i] <= fpr; i++ {
+		if c.TPR[i] > tpr {
+			tpr, threshold = c.TPR[i], c.Thresholds[i]
+		}
+	}
+	return tpr, threshold
+}
+
+// FPRAtTPR returns the lowest false positive rate achievable by any threshold of the curve while achieving at
+// least the specified true positive rate, along with that threshold e.g. to determine the specificity (1 - FPR)
+// at 95% sensitivity.  Where several thresholds achieve the same false positive rate, the highest is returned.
+// If no threshold achieves the true positive rate (e.g. there are no positive observations), NaN is returned for
+// both.
+func (c ROCCurve) FPRAtTPR(tpr float64) (fpr, threshold float64) {
+	for i := range c.TPR {
+		if c.TPR[i] >= tpr {
+			return c.FPR[i], c.Thresholds[i]
+		}
+	}
+	return math.NaN(), math.NaN()
+}
+
+// Plot renders the ROC curve as a plot for visualisation.
+func (c ROCCurve) Plot() *plot.Plot {
+	return c.plot(nil)
+}
+
+// PlotWithBand renders the ROC curve as a plot, in the same way as Plot, with the supplied confidence band (see
+// BootstrapROCBand) shaded around the curve to show the uncertainty of the curve e.g. for small test sets.
+func (c ROCCurve) PlotWithBand(band ConfidenceBand) *plot.Plot {
+	return c.plot(&band)
+}
+
+func (c ROCCurve) plot(band *ConfidenceBand) *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+
+	p.Title.Text = PlotTheme.title(fmt.Sprintf("ROC Curve, AUC=%f", c.AUC()))
+	p.X.Label.Text = "False Positive Rate"
+	p.Y.Label.Text = "True Positive Rate"
+
+	if band != nil {
+		addBand(p, *band)
+	}
+
+	line := c.line()
+	line.Color = PlotTheme.Primary
+	p.Add(line)
+
+	return p
+}
+
+// line creates a line plotter for the curve.
+func (c ROCCurve) line() *plotter.Line {
+	pts := make(plotter.XYs, len(c.FPR))
+	for i := range pts {
+		pts[i].X = c.FPR[i]
+		pts[i].Y = c.TPR[i]
+	}
+
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		panic(err)
+	}
+	return line
+}
+
+// PlotROCCurves renders several ROC curves (e.g. for different models) overlaid on a single plot for
+// comparison.  The curves are keyed by name and each curve is drawn in a distinct colour with a legend entry
+// showing its name and AUC.
+func PlotROCCurves(curves map[string]ROCCurve) *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+
+	p.Title.Text = PlotTheme.title("ROC Curves")
+	p.X.Label.Text = "False Positive Rate"
+	p.Y.Label.Text = "True Positive Rate"
+
+	names := make([]string, 0, len(curves))
+	for name := range curves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		c := curves[name]
+		line := c.line()
+		line.Color = PlotTheme.color(i)
+		p.Add(line)
+		p.Legend.Add(fmt.Sprintf("%s (AUC=%f)", name, c.AUC()), line)
+	}
+
+	return p
+}
+
+// ConvexHull returns the ROC convex hull (ROCCH) of the curve.  This is the upper convex hull of the points of the
+// curve and contains only those thresholds that are optimal for some combination of class priors and
+// misclassification costs.  Any point on the hull between two thresholds is achievable by randomly choosing
+// between the two thresholds in proportion so the area under the hull is the AUC of the best classifier
+// achievable from the model.  Points of the curve lying on a straight line between two hull points are omitted.
+func (c ROCCurve) ConvexHull() ROCCurve {
+	var hull []int
+	for i := range c.FPR {
+		if math.IsNaN(c.FPR[i]) || math.IsNaN(c.TPR[i]) {
+			// rates are undefined without both positive and negative observations
+			return c
+		}
+		for len(hull) >= 2 {
+			o, a := hull[len(hull)-2], hull[len(hull)-1]
+			// remove the last hull point unless the new point turns clockwise (below the line through it)
+			cross := (c.FPR[a]-c.FPR[o])*(c.TPR[i]-c.TPR[o]) - (c.TPR[a]-c.TPR[o])*(c.FPR[i]-c.FPR[o])
+			if cross < 0 {
+				break
+			}
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, i)
+	}
+
+	h := ROCCurve{
+		FPR:        make([]float64, len(hull)),
+		TPR:        make([]float64, len(hull)),
+		Thresholds: make([]float64, len(hull)),
+	}
+	for i, v := range hull {
+		h.FPR[i], h.TPR[i], h.Thresholds[i] = c.FPR[v], c.TPR[v], c.Thresholds[v]
+	}
+	return h
+}
+
+// OptimalOperatingPoint returns the point of the curve, and its threshold, minimising the expected
+// misclassification cost per observation, p(+)C(FN)(1-TPR) + p(-)C(FP)FPR, for the specified prior probability
+// of the positive class and costs of a false negative and false positive.  The optimal point always lies on the
+// convex hull (see ConvexHull) where it is touched by an iso-cost line with slope p(-)C(FP) / p(+)C(FN).
+// Predictions greater than or equal to the threshold should be classified as positive.  Where several thresholds
+// achieve the same cost, the highest is returned.
+func (c ROCCurve) OptimalOperatingPoint(prior, costFN, costFP float64) (threshold, fpr, tpr float64) {
+	validateCosts(prior, costFN, costFP)
+
+	var min float64
+	for i := range c.FPR {
+		cost := prior*costFN*(1-c.TPR[i]) + (1-prior)*costFP*c.FPR[i]
+		if i == 0 || cost < min {
+			min = cost
+			threshold, fpr, tpr = c.Thresholds[i], c.FPR[i], c.TPR[i]
+		}
+	}
+	return threshold, fpr, tpr
+}
+
+// validateCosts panics if the specified prior probability of the positive class or misclassification costs are
+// invalid.
+func validateCosts(prior, costFN, costFP float64) {
+	if costFN < 0 || costFP < 0 || costFN+costFP == 0 {
+		panic("datautils: misclassification costs must be non-negative and not both zero")
+	}
+	if prior < 0 || prior > 1 {
+		panic("datautils: prior must be between 0 and 1")
+	}
+}
+
+// PlotWithIsoCost renders the ROC curve as a plot, in the same way as Plot, along with its convex hull (dashed)
+// and the iso-cost line through the optimal operating point (see OptimalOperatingPoint) for the specified prior
+// probability of the positive class and misclassification costs.  All points on an iso-cost line have the same
+// expected cost so the optimal operating point is where the line touches the convex hull.
+func (c ROCCurve) PlotWithIsoCost(prior, costFN, costFP float64) *plot.Plot {
+	threshold, fpr, tpr := c.OptimalOperatingPoint(prior, costFN, costFP)
+
+	p := c.plot(nil)
+	p.X.Min, p.X.Max = 0, 1
+	p.Y.Min, p.Y.Max = 0, 1
+
+	hull := c.ConvexHull().line()
+	hull.Color = color.Gray{Y: 128}
+	hull.Dashes = []vg.Length{vg.Points(4), vg.Points(4)}
+	p.Add(hull)
+	p.Legend.Add("Convex hull", hull)
+
+	// clip the iso-cost line, TPR = tpr + slope * (FPR - fpr), to the unit square
+	var pts plotter.XYs
+	switch slope := (1 - prior) * costFP / (prior * costFN); {
+	case math.IsInf(slope, 1) || math.IsNaN(slope):
+		pts = plotter.XYs{{X: fpr, Y: 0}, {X: fpr, Y: 1}}
+	case slope == 0:
+		pts = plotter.XYs{{X: 0, Y: tpr}, {X: 1, Y: tpr}}
+	default:
+		x0 := math.Max(0, fpr-tpr/slope)
+		x1 := math.Min(1, fpr+(1-tpr)/slope)
+		pts = plotter.XYs{{X: x0, Y: tpr + slope*(x0-fpr)}, {X: x1, Y: tpr + slope*(x1-fpr)}}
+	}
+	iso, err := plotter.NewLine(pts)
+	if err != nil {
+		panic(err)
+	}
+	iso.Color = color.Gray{Y: 64}
+	p.Add(iso)
+	p.Legend.Add(fmt.Sprintf("Iso-cost line (threshold=%g)", threshold), iso)
+
+	return p
+}
@@ -0,0 +1,171 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestROCCurve(t *testing.T) {
+	tests := []struct {
+		predictions []float64
+		labels      []float64
+		fpr         []float64
+		tpr         []float64
+		thresholds  []float64
+		auc         float64
+	}{
+		{
+			predictions: []float64{0.1, 0.4, 0.35, 0.8},
+			labels:      []float64{0, 0, 1, 1},
+			fpr:         []float64{0, 0, 0.5, 0.5, 1},
+			tpr:         []float64{0, 0.5, 0.5, 1, 1},
+			thresholds:  []float64{math.Inf(1), 0.8, 0.4, 0.35, 0.1},
+			auc:         0.75,
+		},
+		{
+			predictions: []float64{0.5, 0.5, 0.9, 0.1},
+			labels:      []float64{1, 0, 1, 0},
+			fpr:         []float64{0, 0, 0.5, 1},
+			tpr:         []float64{0, 0.5, 1, 1},
+			thresholds:  []float64{math.Inf(1), 0.9, 0.5, 0.1},
+			auc:         0.875,
+		},
+	}
+
+	for i, test := range tests {
+		curve := datautils.NewROCCurve(test.predictions, test.labels)
+
+		if !floats.Equal(curve.FPR, test.fpr) {
+			t.Errorf("Test %d: Expected FPR: %v but received %v", i+1, test.fpr, curve.FPR)
+		}
+		if !floats.Equal(curve.TPR, test.tpr) {
+			t.Errorf("Test %d: Expected TPR: %v but received %v", i+1, test.tpr, curve.TPR)
+		}
+		if !floats.Equal(curve.Thresholds, test.thresholds) {
+			t.Errorf("Test %d: Expected thresholds: %v but received %v", i+1, test.thresholds, curve.Thresholds)
+		}
+		if auc := curve.AUC(); auc != test.auc {
+			t.Errorf("Test %d: Expected AUC: %v but received %v", i+1, test.auc, auc)
+		}
+	}
+}
+
+func TestROCCurveTPRAt(t *testing.T) {
+	curve := datautils.NewROCCurve([]float64{0.1, 0.4, 0.35, 0.8}, []float64{0, 0, 1, 1})
+
+	tests := []struct {
+		fpr float64
+		tpr float64
+	}{
+		{fpr: 0, tpr: 0.5},
+		{fpr: 0.25, tpr: 0.5},
+		{fpr: 0.5, tpr: 1},
+		{fpr: 0.75, tpr: 1},
+		{fpr: 1, tpr: 1},
+	}
+
+	for i, test := range tests {
+		if tpr := curve.TPRAt(test.fpr); tpr != test.tpr {
+			t.Errorf("Test %d: Expected TPR@%v: %v but received %v", i+1, test.fpr, test.tpr, tpr)
+		}
+	}
+}
+
+func TestROCConvexHull(t *testing.T) {
+	tests := []struct {
+		predictions []float64
+		labels      []float64
+		fpr         []float64
+		tpr         []float64
+		thresholds  []float64
+	}{
+		{
+			predictions: []float64{0.1, 0.4, 0.35, 0.8},
+			labels:      []float64{0, 0, 1, 1},
+			fpr:         []float64{0, 0, 0.5, 1},
+			tpr:         []float64{0, 0.5, 1, 1},
+			thresholds:  []float64{math.Inf(1), 0.8, 0.35, 0.1},
+		},
+		{
+			// collinear points are omitted from the hull
+			predictions: []float64{0.9, 0.8, 0.7, 0.6},
+			labels:      []float64{1, 1, 0, 0},
+			fpr:         []float64{0, 0, 1},
+			tpr:         []float64{0, 1, 1},
+			thresholds:  []float64{math.Inf(1), 0.8, 0.6},
+		},
+	}
+
+	for i, test := range tests {
+		hull := datautils.NewROCCurve(test.predictions, test.labels).ConvexHull()
+		if !floats.Equal(test.fpr, hull.FPR) || !floats.Equal(test.tpr, hull.TPR) || !floats.Equal(test.thresholds, hull.Thresholds) {
+			t.Errorf("Test %d: Expected hull FPR: %v, TPR: %v, thresholds: %v but received %v, %v, %v",
+				i+1, test.fpr, test.tpr, test.thresholds, hull.FPR, hull.TPR, hull.Thresholds)
+		}
+	}
+}
+
+func TestROCOptimalOperatingPoint(t *testing.T) {
+	roc := datautils.NewROCCurve([]float64{0.1, 0.4, 0.35, 0.8}, []float64{0, 0, 1, 1})
+
+	tests := []struct {
+		prior, costFN, costFP float64
+		threshold, fpr, tpr   float64
+	}{
+		{prior: 0.5, costFN: 1, costFP: 1, threshold: 0.8, fpr: 0, tpr: 0.5},
+		{prior: 0.5, costFN: 4, costFP: 1, threshold: 0.35, fpr: 0.5, tpr: 1},
+		{prior: 0.5, costFN: 1, costFP: 10, threshold: 0.8, fpr: 0, tpr: 0.5},
+		{prior: 0, costFN: 1, costFP: 1, threshold: math.Inf(1), fpr: 0, tpr: 0},
+		{prior: 1, costFN: 1, costFP: 1, threshold: 0.35, fpr: 0.5, tpr: 1},
+	}
+
+	for i, test := range tests {
+		threshold, fpr, tpr := roc.OptimalOperatingPoint(test.prior, test.costFN, test.costFP)
+		if threshold != test.threshold || fpr != test.fpr || tpr != test.tpr {
+			t.Errorf("Test %d: Expected threshold: %v, FPR: %v, TPR: %v but received %v, %v, %v",
+				i+1, test.threshold, test.fpr, test.tpr, threshold, fpr, tpr)
+		}
+		if p := roc.PlotWithIsoCost(test.prior, test.costFN, test.costFP); p == nil {
+			t.Errorf("Test %d: Expected plot but received nil", i+1)
+		}
+	}
+}
+
+func TestTPRAtFPRAndFPRAtTPR(t *testing.T) {
+	roc := datautils.NewROCCurve([]float64{0.1, 0.4, 0.35, 0.8}, []float64{0, 0, 1, 1})
+
+	tprTests := []struct {
+		fpr, tpr, threshold float64
+	}{
+		{fpr: 0, tpr: 0.5, threshold: 0.8},
+		{fpr: 0.4, tpr: 0.5, threshold: 0.8},
+		{fpr: 0.5, tpr: 1, threshold: 0.35},
+		{fpr: 1, tpr: 1, threshold: 0.35},
+	}
+	for i, test := range tprTests {
+		if tpr, threshold := roc.TPRAtFPR(test.fpr); tpr != test.tpr || threshold != test.threshold {
+			t.Errorf("Test %d: Expected TPR: %v, threshold: %v but received %v, %v", i+1, test.tpr, test.threshold, tpr, threshold)
+		}
+	}
+
+	fprTests := []struct {
+		tpr, fpr, threshold float64
+	}{
+		{tpr: 1, fpr: 0.5, threshold: 0.35},
+		{tpr: 0.5, fpr: 0, threshold: 0.8},
+		{tpr: 0.75, fpr: 0.5, threshold: 0.35},
+	}
+	for i, test := range fprTests {
+		if fpr, threshold := roc.FPRAtTPR(test.tpr); fpr != test.fpr || threshold != test.threshold {
+			t.Errorf("Test %d: Expected FPR: %v, threshold: %v but received %v, %v", i+1, test.fpr, test.threshold, fpr, threshold)
+		}
+	}
+
+	noPositives := datautils.NewROCCurve([]float64{0.1, 0.4}, []float64{0, 0})
+	if fpr, threshold := noPositives.FPRAtTPR(0.5); !math.IsNaN(fpr) || !math.IsNaN(threshold) {
+		t.Errorf("Expected FPR: NaN, threshold: NaN but received %v, %v", fpr, threshold)
+	}
+}
@@ -0,0 +1,83 @@
+package datautils
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// SampleStratified randomly samples fraction of the indices of the supplied labels (0 < fraction <= 1) preserving
+// the proportions of each class (distinct label value).  fraction of the indices of each class (rounded to the
+// nearest index) are sampled.  The sampled indices are returned in ascending order and the seed is used to
+// initialise the random number generator so that sampling is reproducible.
+func SampleStratified(labels []float64, fraction float64, seed int64) []int {
+	if fraction <= 0 || fraction > 1 {
+		panic("datautils: sample fraction must be greater than 0 and at most 1")
+	}
+	rnd := rand.New(rand.NewSource(seed))
+
+	var sample []int
+	for _, class := range classIndices(labels) {
+		_, s := splitIndices(class, fraction, rnd)
+		sample = append(sample, s...)
+	}
+	sort.Ints(sample)
+	return sample
+}
+
+// ReservoirSampler maintains a uniform random sample of fixed size from a stream of predictions and their
+// corresponding labels of unknown (and potentially unbounded) length using reservoir sampling (Algorithm R).
+// After n observations have been added, each has an equal probability, Size/n, of being in the sample.  This
+// allows huge prediction logs to be downsampled in a single pass and in bounded memory before computing curves
+// or metrics.  A ReservoirSampler is not safe for concurrent use.
+type ReservoirSampler struct {
+	// Size is the maximum number of observations retained within the sample
+	Size int
+
+	predictions []float64
+	labels      []float64
+	seen        int
+	rnd         *rand.Rand
+}
+
+// NewReservoirSampler creates a new ReservoirSampler retaining a sample of up to size observations.  The seed is
+// used to initialise the random number generator so that sampling is reproducible.
+func NewReservoirSampler(size int, seed int64) *ReservoirSampler {
+	if size < 1 {
+		panic("datautils: reservoir size must be at least 1")
+	}
+	return &ReservoirSampler{
+		Size:        size,
+		predictions: make([]float64, 0, size),
+		labels:      make([]float64, 0, size),
+		rnd:         rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Add offers a single prediction and its corresponding label to the sampler.
+func (s *ReservoirSampler) Add(prediction, label float64) {
+	s.seen++
+	if len(s.predictions) < s.Size {
+		s.predictions = append(s.predictions, prediction)
+		s.labels = append(s.labels, label)
+		return
+	}
+	if i := s.rnd.Intn(s.seen); i < s.Size {
+		s.predictions[i] = prediction
+		s.labels[i] = label
+	}
+}
+
+// Seen returns the total number of observations added to the sampler.
+func (s *ReservoirSampler) Seen() int {
+	return s.seen
+}
+
+// Sample returns copies of the sampled predictions and their corresponding labels suitable for use with
+// NewPrecisionRecallCurve, NewConfusionMatrix, etc.
+func (s *ReservoirSampler) Sample() (predictions, labels []float64) {
+	predictions = make([]float64, len(s.predictions))
+	labels = make([]float64, len(s.labels))
+	copy(predictions, s.predictions)
+	copy(labels, s.labels)
+	return predictions, labels
+}
@@ -0,0 +1,83 @@
+package datautils_test
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestSampleStratified(t *testing.T) {
+	labels := make([]float64, 100)
+	for i := 0; i < 20; i++ {
+		labels[i*5] = 1
+	}
+
+	sample := datautils.SampleStratified(labels, 0.25, 42)
+
+	if len(sample) != 25 {
+		t.Errorf("Expected sample size: %d but received %d", 25, len(sample))
+	}
+	if !sort.IntsAreSorted(sample) {
+		t.Errorf("Expected sampled indices in ascending order but received %v", sample)
+	}
+	var positives int
+	for _, i := range sample {
+		if labels[i] == 1 {
+			positives++
+		}
+	}
+	if positives != 5 {
+		t.Errorf("Expected positives: %d but received %d", 5, positives)
+	}
+
+	if again := datautils.SampleStratified(labels, 0.25, 42); !reflect.DeepEqual(sample, again) {
+		t.Errorf("Expected identical samples for the same seed but received %v and %v", sample, again)
+	}
+}
+
+func TestReservoirSampler(t *testing.T) {
+	const size, n, trials = 10, 100, 2000
+
+	// each observation should be sampled with probability size/n
+	counts := make([]int, n)
+	for trial := 0; trial < trials; trial++ {
+		s := datautils.NewReservoirSampler(size, int64(trial))
+		for i := 0; i < n; i++ {
+			s.Add(float64(i), float64(i%2))
+		}
+		if s.Seen() != n {
+			t.Fatalf("Expected seen: %d but received %d", n, s.Seen())
+		}
+		predictions, labels := s.Sample()
+		if len(predictions) != size || len(labels) != size {
+			t.Fatalf("Expected sample size: %d but received %d predictions and %d labels", size, len(predictions), len(labels))
+		}
+		for i, p := range predictions {
+			if labels[i] != float64(int(p)%2) {
+				t.Fatalf("Expected label %v for prediction %v but received %v", float64(int(p)%2), p, labels[i])
+			}
+			counts[int(p)]++
+		}
+	}
+
+	expected := float64(trials*size) / n
+	for i, c := range counts {
+		if math.Abs(float64(c)-expected) > 0.35*expected {
+			t.Errorf("Expected observation %d to be sampled approximately %v times but received %d", i, expected, c)
+		}
+	}
+}
+
+func TestReservoirSamplerSmallStream(t *testing.T) {
+	s := datautils.NewReservoirSampler(10, 1)
+	s.Add(0.5, 1)
+	s.Add(0.2, 0)
+
+	predictions, labels := s.Sample()
+	if len(predictions) != 2 || predictions[0] != 0.5 || labels[1] != 0 {
+		t.Errorf("Expected entire stream to be retained but received %v %v", predictions, labels)
+	}
+}
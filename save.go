@@ -0,0 +1,112 @@
+package datautils
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// SavePlot saves the plot to the file at path with the specified width and height in centimetres.  The format
+// of the file (e.g. PNG, SVG, PDF, etc.) is determined by the file extension of path.
+func SavePlot(p *plot.Plot, path string, widthCm, heightCm float64) error {
+	return p.Save(vg.Length(widthCm)*vg.Centimeter, vg.Length(heightCm)*vg.Centimeter, path)
+}
+
+// SavePlotGrid renders a grid of plots (e.g. from PlotScatterMatrix) as a single image and saves it to the file
+// at path with the specified overall width and height in centimetres.  plots[i][j] is drawn in row i, column j
+// of the grid and nil plots leave their cell empty.  The plots are aligned so that the data areas of the plots in
+// each row and column line up.  As with SavePlot, the format of the file is determined by the file extension of
+// path.  To lay out facets of the same kind of plot with shared axes and a common legend, use FacetGrid.
+func SavePlotGrid(plots [][]*plot.Plot, path string, widthCm, heightCm float64) error {
+	var cols int
+	for _, row := range plots {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+	// pad ragged rows so that the grid is rectangular as required by plot.Align
+	grid := make([][]*plot.Plot, len(plots))
+	for i, row := range plots {
+		grid[i] = make([]*plot.Plot, cols)
+		copy(grid[i], row)
+	}
+
+	w, err := newGridCanvas(path, widthCm, heightCm)
+	if err != nil {
+		return err
+	}
+	drawPlotGrid(grid, cols, draw.New(w))
+	return writeFile(w, path)
+}
+
+// newGridCanvas creates a canvas of the specified width and height in centimetres in the format determined by
+// the file extension of path.
+func newGridCanvas(path string, widthCm, heightCm float64) (vg.CanvasWriterTo, error) {
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	return draw.NewFormattedCanvas(vg.Length(widthCm)*vg.Centimeter, vg.Length(heightCm)*vg.Centimeter, format)
+}
+
+// drawPlotGrid draws the rectangular grid of plots, with the specified number of columns, aligned within c.  nil
+// plots leave their cell empty.
+func drawPlotGrid(grid [][]*plot.Plot, cols int, c draw.Canvas) {
+	tiles := draw.Tiles{
+		Rows: len(grid),
+		Cols: cols,
+		PadX: vg.Millimeter,
+		PadY: vg.Millimeter,
+	}
+	canvases := plot.Align(grid, tiles, c)
+	for i := range grid {
+		for j, p := range grid[i] {
+			if p != nil {
+				p.Draw(canvases[i][j])
+			}
+		}
+	}
+}
+
+// writeFile writes the rendered image to the file at path.
+func writeFile(w io.WriterTo, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err = w.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// savePlotAs saves the plot to the file at path in the specified format (e.g. "png") regardless of the file
+// extension of path.
+func savePlotAs(p *plot.Plot, path, format string, widthCm, heightCm float64) error {
+	w, err := p.WriterTo(vg.Length(widthCm)*vg.Centimeter, vg.Length(heightCm)*vg.Centimeter, format)
+	if err != nil {
+		return err
+	}
+	return writeFile(w, path)
+}
+
+// SavePNG renders the precision recall curve (see Plot) and saves it to the file at path as a PNG image with
+// the specified width and height in centimetres.
+func (c PrecisionRecallCurve) SavePNG(path string, widthCm, heightCm float64) error {
+	return savePlotAs(c.Plot(), path, "png", widthCm, heightCm)
+}
+
+// SaveSVG renders the precision recall curve (see Plot) and saves it to the file at path as an SVG image with
+// the specified width and height in centimetres.
+func (c PrecisionRecallCurve) SaveSVG(path string, widthCm, heightCm float64) error {
+	return savePlotAs(c.Plot(), path, "svg", widthCm, heightCm)
+}
+
+// SavePDF renders the precision recall curve (see Plot) and saves it to the file at path as a PDF document with
+// the specified width and height in centimetres.
+func (c PrecisionRecallCurve) SavePDF(path string, widthCm, heightCm float64) error {
+	return savePlotAs(c.Plot(), path, "pdf", widthCm, heightCm)
+}
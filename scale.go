@@ -0,0 +1,182 @@
+package datautils
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Scaler is a feature scaling transformer.  The scaling parameters of each column (feature) are learned from a
+// matrix of training data with Fit and then applied to other matrices (e.g. a test set) with Transform so that
+// all data is scaled consistently.  InverseTransform reverses the scaling e.g. to report values in their
+// original units.  Missing values, represented as NaN, are ignored when fitting and remain NaN when transformed.
+type Scaler interface {
+	Fit(m mat.Matrix)
+	Transform(m mat.Matrix) *mat.Dense
+	InverseTransform(m mat.Matrix) *mat.Dense
+}
+
+// StandardScaler standardises features by removing the mean and scaling to unit variance i.e. z = (x - Mean) /
+// Scale where Scale is the (population) standard deviation.  Columns with zero variance are only centred.
+type StandardScaler struct {
+	// Mean contains the mean of each column of the fitted data
+	Mean []float64
+
+	// Scale contains the standard deviation of each column of the fitted data (or 1 for constant columns)
+	Scale []float64
+}
+
+// Fit learns the mean and standard deviation of each column of m.
+func (s *StandardScaler) Fit(m mat.Matrix) {
+	cols := columns(m)
+	s.Mean = make([]float64, len(cols))
+	s.Scale = make([]float64, len(cols))
+	for j, col := range cols {
+		mean, variance := stat.MeanVariance(col, nil)
+		// convert the unbiased sample variance to the population variance
+		n := float64(len(col))
+		s.Mean[j] = mean
+		s.Scale[j] = nonZero(math.Sqrt(variance * (n - 1) / n))
+	}
+}
+
+// Transform returns a new matrix containing the standardised values of m.
+func (s *StandardScaler) Transform(m mat.Matrix) *mat.Dense {
+	return affine(m, s.Mean, s.Scale, false)
+}
+
+// InverseTransform returns a new matrix containing the values of m in their original (unstandardised) units.
+func (s *StandardScaler) InverseTransform(m mat.Matrix) *mat.Dense {
+	return affine(m, s.Mean, s.Scale, true)
+}
+
+// MinMaxScaler scales features to the range [0, 1] i.e. (x - Min) / (Max - Min).  Values outside of the range of
+// the fitted data will be scaled outside of [0, 1].  Constant columns are scaled to 0.
+type MinMaxScaler struct {
+	// Min contains the minimum value of each column of the fitted data
+	Min []float64
+
+	// Max contains the maximum value of each column of the fitted data
+	Max []float64
+}
+
+// Fit learns the minimum and maximum values of each column of m.
+func (s *MinMaxScaler) Fit(m mat.Matrix) {
+	cols := columns(m)
+	s.Min = make([]float64, len(cols))
+	s.Max = make([]float64, len(cols))
+	for j, col := range cols {
+		s.Min[j], s.Max[j] = math.NaN(), math.NaN()
+		for i, v := range col {
+			if i == 0 || v < s.Min[j] {
+				s.Min[j] = v
+			}
+			if i == 0 || v > s.Max[j] {
+				s.Max[j] = v
+			}
+		}
+	}
+}
+
+// Transform returns a new matrix containing the values of m scaled according to the fitted range.
+func (s *MinMaxScaler) Transform(m mat.Matrix) *mat.Dense {
+	return affine(m, s.Min, s.ranges(), false)
+}
+
+// InverseTransform returns a new matrix containing the values of m in their original (unscaled) units.
+func (s *MinMaxScaler) InverseTransform(m mat.Matrix) *mat.Dense {
+	return affine(m, s.Min, s.ranges(), true)
+}
+
+func (s *MinMaxScaler) ranges() []float64 {
+	ranges := make([]float64, len(s.Min))
+	for j := range ranges {
+		ranges[j] = nonZero(s.Max[j] - s.Min[j])
+	}
+	return ranges
+}
+
+// RobustScaler scales features using statistics that are robust to outliers by removing the median and scaling
+// by the interquartile range (IQR) i.e. (x - Median) / IQR.  Columns with an IQR of zero are only centred.
+type RobustScaler struct {
+	// Median contains the median of each column of the fitted data
+	Median []float64
+
+	// IQR contains the interquartile range (75th percentile - 25th percentile) of each column of the fitted
+	// data (or 1 where the IQR is zero)
+	IQR []float64
+}
+
+// Fit learns the median and interquartile range of each column of m.
+func (s *RobustScaler) Fit(m mat.Matrix) {
+	cols := columns(m)
+	s.Median = make([]float64, len(cols))
+	s.IQR = make([]float64, len(cols))
+	for j, col := range cols {
+		if len(col) == 0 {
+			s.Median[j], s.IQR[j] = math.NaN(), 1
+			continue
+		}
+		sort.Float64s(col)
+		s.Median[j] = stat.Quantile(0.5, stat.Empirical, col, nil)
+		s.IQR[j] = nonZero(stat.Quantile(0.75, stat.Empirical, col, nil) - stat.Quantile(0.25, stat.Empirical, col, nil))
+	}
+}
+
+// Transform returns a new matrix containing the robustly scaled values of m.
+func (s *RobustScaler) Transform(m mat.Matrix) *mat.Dense {
+	return affine(m, s.Median, s.IQR, false)
+}
+
+// InverseTransform returns a new matrix containing the values of m in their original (unscaled) units.
+func (s *RobustScaler) InverseTransform(m mat.Matrix) *mat.Dense {
+	return affine(m, s.Median, s.IQR, true)
+}
+
+// columns returns the non-missing (non-NaN) values of each column of m.
+func columns(m mat.Matrix) [][]float64 {
+	r, c := m.Dims()
+	cols := make([][]float64, c)
+	for j := range cols {
+		for i := 0; i < r; i++ {
+			if v := m.At(i, j); !math.IsNaN(v) {
+				cols[j] = append(cols[j], v)
+			}
+		}
+	}
+	return cols
+}
+
+// nonZero returns v unless it is zero (or undefined) in which case 1 is returned so that it may safely be used
+// as a divisor.
+func nonZero(v float64) float64 {
+	if v == 0 || math.IsNaN(v) {
+		return 1
+	}
+	return v
+}
+
+// affine returns a new matrix containing (x - centre) / scale for each value x of m, using the centre and scale
+// of the corresponding column, or the inverse, x * scale + centre, if inverse is true.
+func affine(m mat.Matrix, centre, scale []float64, inverse bool) *mat.Dense {
+	r, c := m.Dims()
+	if centre == nil {
+		panic("datautils: scaler has not been fitted")
+	}
+	if c != len(centre) {
+		panic(mat.ErrShape)
+	}
+	t := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if inverse {
+				t.Set(i, j, m.At(i, j)*scale[j]+centre[j])
+			} else {
+				t.Set(i, j, (m.At(i, j)-centre[j])/scale[j])
+			}
+		}
+	}
+	return t
+}
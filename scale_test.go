@@ -0,0 +1,82 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestScalers(t *testing.T) {
+	train := mat.NewDense(4, 2, []float64{
+		1, 5,
+		2, 5,
+		3, math.NaN(),
+		6, 5,
+	})
+
+	tests := []struct {
+		name   string
+		scaler datautils.Scaler
+		want   []float64
+	}{
+		{
+			name:   "StandardScaler",
+			scaler: &datautils.StandardScaler{},
+			// mean 3, population standard deviation sqrt(14/4) for column 0 and constant column 1
+			want: []float64{-2 / math.Sqrt(3.5), 0, -1 / math.Sqrt(3.5), 0, 0, math.NaN(), 3 / math.Sqrt(3.5), 0},
+		},
+		{
+			name:   "MinMaxScaler",
+			scaler: &datautils.MinMaxScaler{},
+			want:   []float64{0, 0, 0.2, 0, 0.4, math.NaN(), 1, 0},
+		},
+		{
+			name:   "RobustScaler",
+			scaler: &datautils.RobustScaler{},
+			// empirical quartiles of column 0 are 1, 2 and 3
+			want: []float64{-0.5, 0, 0, 0, 0.5, math.NaN(), 2, 0},
+		},
+	}
+
+	for _, test := range tests {
+		test.scaler.Fit(train)
+		scaled := test.scaler.Transform(train)
+
+		if got := flatten(scaled); !equalWithNaN(got, test.want) {
+			t.Errorf("%s: Expected scaled values: %v but received %v", test.name, test.want, got)
+		}
+
+		restored := test.scaler.InverseTransform(scaled)
+		if got, want := flatten(restored), flatten(train); !equalWithNaN(got, want) {
+			t.Errorf("%s: Expected restored values: %v but received %v", test.name, want, got)
+		}
+	}
+}
+
+// equalWithNaN reports whether a and b are approximately equal treating NaN values in the same positions as equal.
+func equalWithNaN(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.IsNaN(a[i]) != math.IsNaN(b[i]) {
+			return false
+		}
+		if !math.IsNaN(a[i]) && math.Abs(a[i]-b[i]) > 1e-12 {
+			return false
+		}
+	}
+	return true
+}
+
+// flatten returns the values of m in row major order.
+func flatten(m mat.Matrix) []float64 {
+	r, _ := m.Dims()
+	var values []float64
+	for i := 0; i < r; i++ {
+		values = append(values, mat.Row(nil, i, m)...)
+	}
+	return values
+}
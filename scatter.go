@@ -0,0 +1,115 @@
+package datautils
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// scatterMatrixBins is the number of bins of the histograms on the diagonal of a scatter matrix.
+const scatterMatrixBins = 20
+
+// PlotScatterMatrix renders a scatter matrix (pair plot) of the columns (features) of m for exploratory analysis,
+// complementing the correlation heatmap (see PlotHeatmap).  The returned grid contains a plot for every pair of
+// features with plots[i][j] showing a scatter plot of feature j (x axis) against feature i (y axis) and a
+// histogram of each feature on the diagonal.  Plots in the bottom row and left column are labelled with the
+// corresponding feature names from labels which, if specified, must match the number of columns of m.  If nil,
+// features are labelled by index.  If classColors is specified, it must contain a class index for each row of m
+// and the points (and histograms) of each class are drawn in a distinct colour (see plotutil.Color).  The grid may
+// be saved as a single image using SavePlotGrid.
+func PlotScatterMatrix(m mat.Matrix, labels []string, classColors []int) ([][]*plot.Plot, error) {
+	r, c := m.Dims()
+	if labels != nil && len(labels) != c {
+		return nil, fmt.Errorf("datautils: %d labels specified for matrix with %d columns", len(labels), c)
+	}
+	if classColors != nil && len(classColors) != r {
+		return nil, fmt.Errorf("datautils: %d class colours specified for matrix with %d rows", len(classColors), r)
+	}
+
+	cols := make([][]float64, c)
+	for j := range cols {
+		cols[j] = mat.Col(nil, j, m)
+	}
+
+	// group the rows by class so that each class can be drawn in its own colour
+	groups := [][]int{allIndices(r)}
+	if classColors != nil {
+		colors := make([]float64, r)
+		for i, v := range classColors {
+			colors[i] = float64(v)
+		}
+		groups = classIndices(colors)
+	}
+	groupColor := func(group []int) color.Color {
+		if classColors == nil || len(group) == 0 {
+			return PlotTheme.color(0)
+		}
+		return PlotTheme.color(classColors[group[0]])
+	}
+
+	plots := make([][]*plot.Plot, c)
+	for i := range plots {
+		plots[i] = make([]*plot.Plot, c)
+		for j := range plots[i] {
+			p, err := newPlot()
+			if err != nil {
+				return nil, err
+			}
+			if i == c-1 {
+				p.X.Label.Text = featureName(labels, j)
+			}
+			if j == 0 {
+				p.Y.Label.Text = featureName(labels, i)
+			}
+
+			if i == j {
+				edges := NewHistogram(cols[j], scatterMatrixBins).Edges
+				for _, group := range groups {
+					h := NewHistogramRange(selectValues(cols[j], group), scatterMatrixBins, edges[0], edges[scatterMatrixBins])
+					p.Add(h.histogram(false, rgba(groupColor(group))))
+				}
+			} else {
+				pts := make(plotter.XYs, r)
+				for k := range pts {
+					pts[k].X, pts[k].Y = cols[j][k], cols[i][k]
+				}
+				s, err := plotter.NewScatter(pts)
+				if err != nil {
+					return nil, err
+				}
+				s.GlyphStyle.Radius = vg.Points(1.5)
+				s.GlyphStyle.Shape = draw.CircleGlyph{}
+				s.GlyphStyle.Color = PlotTheme.color(0)
+				if classColors != nil {
+					s.GlyphStyleFunc = func(k int) draw.GlyphStyle {
+						style := s.GlyphStyle
+						style.Color = PlotTheme.color(classColors[k])
+						return style
+					}
+				}
+				p.Add(s)
+			}
+			plots[i][j] = p
+		}
+	}
+	return plots, nil
+}
+
+// featureName returns the name of the feature in column j or, if no names are specified, its index.
+func featureName(names []string, j int) string {
+	if names == nil {
+		return strconv.Itoa(j)
+	}
+	return names[j]
+}
+
+// rgba converts c to a color.RGBA.
+func rgba(c color.Color) color.RGBA {
+	return color.RGBAModel.Convert(c).(color.RGBA)
+}
@@ -0,0 +1,79 @@
+package datautils_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPlotScatterMatrix(t *testing.T) {
+	m := mat.NewDense(4, 3, []float64{
+		1, 2, 3,
+		2, 4, 1,
+		3, 5, 2,
+		4, 8, 0,
+	})
+
+	tests := []struct {
+		labels      []string
+		classColors []int
+		valid       bool
+		xlabel      string
+		ylabel      string
+	}{
+		{labels: []string{"a", "b", "c"}, classColors: []int{0, 1, 0, 1}, valid: true, xlabel: "b", ylabel: "c"},
+		{valid: true, xlabel: "1", ylabel: "2"},
+		{labels: []string{"a", "b"}, valid: false},
+		{classColors: []int{0, 1}, valid: false},
+	}
+
+	for i, test := range tests {
+		plots, err := datautils.PlotScatterMatrix(m, test.labels, test.classColors)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("Test %d: Expected error but received nil", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: Unexpected error: %v", i+1, err)
+			continue
+		}
+		if len(plots) != 3 || len(plots[0]) != 3 {
+			t.Errorf("Test %d: Expected 3x3 grid of plots but received %dx%d", i+1, len(plots), len(plots[0]))
+			continue
+		}
+		// only the bottom row and left column are labelled
+		if plots[2][1].X.Label.Text != test.xlabel || plots[1][1].X.Label.Text != "" {
+			t.Errorf("Test %d: Expected x label: %q but received %q", i+1, test.xlabel, plots[2][1].X.Label.Text)
+		}
+		if plots[2][0].Y.Label.Text != test.ylabel || plots[2][1].Y.Label.Text != "" {
+			t.Errorf("Test %d: Expected y label: %q but received %q", i+1, test.ylabel, plots[2][0].Y.Label.Text)
+		}
+	}
+}
+
+func TestSavePlotGrid(t *testing.T) {
+	plots, err := datautils.PlotScatterMatrix(mat.NewDense(2, 2, []float64{1, 2, 3, 4}), nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// ragged rows leave empty cells
+	plots = append(plots, plots[0][:1])
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "grid.png")
+	if err := datautils.SavePlotGrid(plots, path, 20, 20); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected file %s to be created but received %v", path, err)
+	}
+
+	if err := datautils.SavePlotGrid(plots, filepath.Join(dir, "grid.unknown"), 20, 20); err == nil {
+		t.Errorf("Expected error for unsupported format but received nil")
+	}
+}
@@ -0,0 +1,191 @@
+package datautils
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Params is a set of hyperparameter values keyed by hyperparameter name representing a single candidate
+// configuration of a model.  Categorical hyperparameters may be encoded as an index into their categories.
+type Params map[string]float64
+
+// ParamGrid specifies the values to try for each hyperparameter, keyed by hyperparameter name, for an exhaustive
+// grid search.
+type ParamGrid map[string][]float64
+
+// Candidates returns every combination of the hyperparameter values in the grid (the cartesian product).
+// Hyperparameters are enumerated in sorted name order with the values of the last hyperparameter varying fastest
+// so that the order of the candidates is deterministic.  If any hyperparameter has no values, there are no
+// candidates.
+func (g ParamGrid) Candidates() []Params {
+	names := make([]string, 0, len(g))
+	n := 1
+	for name, values := range g {
+		names = append(names, name)
+		n *= len(values)
+	}
+	sort.Strings(names)
+
+	candidates := make([]Params, n)
+	for i := range candidates {
+		candidates[i] = make(Params, len(names))
+		r := i
+		for j := len(names) - 1; j >= 0; j-- {
+			values := g[names[j]]
+			candidates[i][names[j]] = values[r%len(values)]
+			r /= len(values)
+		}
+	}
+	return candidates
+}
+
+// Distribution is a probability distribution from which values of a hyperparameter may be sampled for a random
+// search.
+type Distribution interface {
+	// Sample draws a single value from the distribution using the supplied random number generator
+	Sample(rnd *rand.Rand) float64
+}
+
+// Uniform is a continuous uniform distribution over the interval [Min, Max).
+type Uniform struct {
+	Min, Max float64
+}
+
+// Sample draws a single value from the distribution.
+func (u Uniform) Sample(rnd *rand.Rand) float64 {
+	return u.Min + rnd.Float64()*(u.Max-u.Min)
+}
+
+// LogUniform is a distribution whose logarithm is uniformly distributed over the interval [log(Min), log(Max))
+// making it suitable for hyperparameters such as learning rates or regularisation strengths that are searched
+// over several orders of magnitude.  Min and Max must both be positive.
+type LogUniform struct {
+	Min, Max float64
+}
+
+// Sample draws a single value from the distribution.
+func (u LogUniform) Sample(rnd *rand.Rand) float64 {
+	if u.Min <= 0 || u.Max <= 0 {
+		panic("datautils: log uniform distribution bounds must be positive")
+	}
+	lo, hi := math.Log(u.Min), math.Log(u.Max)
+	return math.Exp(lo + rnd.Float64()*(hi-lo))
+}
+
+// Choice is a discrete uniform distribution over a set of values.
+type Choice []float64
+
+// Sample draws a single value from the distribution.
+func (c Choice) Sample(rnd *rand.Rand) float64 {
+	return c[rnd.Intn(len(c))]
+}
+
+// ParamDistributions specifies the distribution to sample for each hyperparameter, keyed by hyperparameter name,
+// for a random search.
+type ParamDistributions map[string]Distribution
+
+// Sample returns n candidates, each with a value sampled independently from the distribution of every
+// hyperparameter.  Hyperparameters are sampled in sorted name order and the seed is used to initialise the random
+// number generator so that the candidates are reproducible.
+func (d ParamDistributions) Sample(n int, seed int64) []Params {
+	names := make([]string, 0, len(d))
+	for name := range d {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rnd := rand.New(rand.NewSource(seed))
+	candidates := make([]Params, n)
+	for i := range candidates {
+		candidates[i] = make(Params, len(names))
+		for _, name := range names {
+			candidates[i][name] = d[name].Sample(rnd)
+		}
+	}
+	return candidates
+}
+
+// SearchResult represents the evaluation of a single candidate of a hyperparameter search.
+type SearchResult struct {
+	// Params contains the hyperparameter values of the candidate
+	Params Params
+
+	// Metrics contains the value of each of the search's metrics for the candidate keyed by metric name
+	Metrics map[string]float64
+
+	// Rank is the 1-based rank of the candidate by the search's RankBy metric
+	Rank int
+}
+
+// Search evaluates candidate hyperparameter configurations of a model (e.g. those of a ParamGrid or sampled from
+// ParamDistributions) against one or more metrics.
+type Search struct {
+	// Metrics contains the metrics to calculate for each candidate keyed by metric name
+	Metrics map[string]MetricFunc
+
+	// RankBy is the name of the metric by which candidates are ranked.  Higher values rank first so metrics for
+	// which lower values are better e.g. loss, should be negated.
+	RankBy string
+
+	// Workers is the number of candidates to evaluate concurrently in separate goroutines.  Values less than 2
+	// evaluate candidates sequentially.  If greater than 1, the evaluate function must be safe for concurrent use.
+	Workers int
+}
+
+// Run evaluates each of the candidates and returns the results ranked by the RankBy metric.  The evaluate function
+// would typically train a model with the candidate's hyperparameter values and return its predictions for a
+// validation set along with the corresponding ground truth labels, from which each of the search's metrics is
+// calculated.  Candidates for which the RankBy metric is NaN are ranked last and ties retain the order in which the
+// candidates were supplied.
+func (s Search) Run(candidates []Params, evaluate func(params Params) (predictions, labels []float64)) []SearchResult {
+	if _, ok := s.Metrics[s.RankBy]; !ok {
+		panic("datautils: search must rank by one of its metrics")
+	}
+
+	results := make([]SearchResult, len(candidates))
+	run := func(i int) {
+		predictions, labels := evaluate(candidates[i])
+		metrics := make(map[string]float64, len(s.Metrics))
+		for name, metric := range s.Metrics {
+			metrics[name] = metric(predictions, labels)
+		}
+		results[i] = SearchResult{Params: candidates[i], Metrics: metrics}
+	}
+
+	if s.Workers < 2 {
+		for i := range candidates {
+			run(i)
+		}
+	} else {
+		next := make(chan int)
+		var wg sync.WaitGroup
+		wg.Add(s.Workers)
+		for w := 0; w < s.Workers; w++ {
+			go func() {
+				defer wg.Done()
+				for i := range next {
+					run(i)
+				}
+			}()
+		}
+		for i := range candidates {
+			next <- i
+		}
+		close(next)
+		wg.Wait()
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Metrics[s.RankBy], results[j].Metrics[s.RankBy]
+		if math.IsNaN(b) {
+			return !math.IsNaN(a)
+		}
+		return a > b
+	})
+	for i := range results {
+		results[i].Rank = i + 1
+	}
+	return results
+}
@@ -0,0 +1,117 @@
+package datautils_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestParamGridCandidates(t *testing.T) {
+	tests := []struct {
+		grid       datautils.ParamGrid
+		candidates []datautils.Params
+	}{
+		{
+			grid: datautils.ParamGrid{"b": {1, 2}, "a": {0.1, 0.2, 0.3}},
+			candidates: []datautils.Params{
+				{"a": 0.1, "b": 1}, {"a": 0.1, "b": 2},
+				{"a": 0.2, "b": 1}, {"a": 0.2, "b": 2},
+				{"a": 0.3, "b": 1}, {"a": 0.3, "b": 2},
+			},
+		},
+		{grid: datautils.ParamGrid{"a": {1}, "b": {}}, candidates: []datautils.Params{}},
+		{grid: datautils.ParamGrid{}, candidates: []datautils.Params{{}}},
+	}
+
+	for i, test := range tests {
+		if candidates := test.grid.Candidates(); fmt.Sprint(candidates) != fmt.Sprint(test.candidates) {
+			t.Errorf("Test %d: Expected candidates: %v but received %v", i+1, test.candidates, candidates)
+		}
+	}
+}
+
+func TestParamDistributionsSample(t *testing.T) {
+	d := datautils.ParamDistributions{
+		"alpha": datautils.LogUniform{Min: 0.001, Max: 10},
+		"ratio": datautils.Uniform{Min: 0.25, Max: 0.75},
+		"depth": datautils.Choice{2, 4, 8},
+	}
+
+	candidates := d.Sample(50, 1)
+	if len(candidates) != 50 {
+		t.Fatalf("Expected 50 candidates but received %d", len(candidates))
+	}
+	for _, c := range candidates {
+		if c["alpha"] < 0.001 || c["alpha"] >= 10 {
+			t.Errorf("Expected alpha in [0.001, 10) but received %v", c["alpha"])
+		}
+		if c["ratio"] < 0.25 || c["ratio"] >= 0.75 {
+			t.Errorf("Expected ratio in [0.25, 0.75) but received %v", c["ratio"])
+		}
+		if d := c["depth"]; d != 2 && d != 4 && d != 8 {
+			t.Errorf("Expected depth to be one of 2, 4 or 8 but received %v", d)
+		}
+	}
+
+	if again := d.Sample(50, 1); fmt.Sprint(again) != fmt.Sprint(candidates) {
+		t.Errorf("Expected samples with the same seed to be identical")
+	}
+
+	r := rand.New(rand.NewSource(1))
+	if v := (datautils.LogUniform{Min: 1, Max: 1}).Sample(r); v != 1 {
+		t.Errorf("Expected degenerate log uniform sample 1 but received %v", v)
+	}
+}
+
+func TestSearchRun(t *testing.T) {
+	labels := []float64{0, 1, 0, 1, 1, 0, 1, 0}
+	candidates := datautils.ParamGrid{"noise": {0, 0.5, 1, 2}}.Candidates()
+	candidates = append(candidates, datautils.Params{"noise": math.NaN()})
+
+	// higher noise degrades the separation of the classes and NaN noise produces no usable predictions
+	evaluate := func(params datautils.Params) ([]float64, []float64) {
+		if math.IsNaN(params["noise"]) {
+			return nil, nil
+		}
+		preds := make([]float64, len(labels))
+		for i, l := range labels {
+			preds[i] = l + params["noise"]*float64(i%4)
+		}
+		return preds, labels
+	}
+	metrics := map[string]datautils.MetricFunc{
+		"auc": func(predictions, labels []float64) float64 {
+			if len(labels) == 0 {
+				return math.NaN()
+			}
+			return datautils.NewROCCurve(predictions, labels).AUC()
+		},
+		"n": func(predictions, labels []float64) float64 { return float64(len(labels)) },
+	}
+
+	for _, workers := range []int{0, 3} {
+		results := datautils.Search{Metrics: metrics, RankBy: "auc", Workers: workers}.Run(candidates, evaluate)
+
+		expected := []float64{0, 0.5, 1, 2, math.NaN()}
+		if len(results) != len(expected) {
+			t.Fatalf("Workers %d: Expected %d results but received %d", workers, len(expected), len(results))
+		}
+		for i, r := range results {
+			if r.Rank != i+1 {
+				t.Errorf("Workers %d: Expected rank %d but received %d", workers, i+1, r.Rank)
+			}
+			if n := r.Params["noise"]; n != expected[i] && !(math.IsNaN(n) && math.IsNaN(expected[i])) {
+				t.Errorf("Workers %d: Expected noise %v at rank %d but received %v", workers, expected[i], i+1, n)
+			}
+			if i > 0 && results[i-1].Metrics["auc"] < r.Metrics["auc"] {
+				t.Errorf("Workers %d: Expected results in descending AUC order but received %v", workers, results)
+			}
+		}
+		if results[0].Metrics["auc"] != 1 || results[0].Metrics["n"] != 8 {
+			t.Errorf("Workers %d: Expected best candidate AUC 1 across 8 labels but received %v", workers, results[0].Metrics)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package datautils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// FeatureScorer scores the relevance of a feature's values to the corresponding target values (e.g. class labels)
+// returning the score, where higher scores indicate greater relevance, and the p-value of the score under the null
+// hypothesis that the feature is independent of the target.  Scorers without a statistical test return a NaN
+// p-value.
+type FeatureScorer func(values, target []float64) (score, pValue float64)
+
+// ChiSquare is a FeatureScorer calculating the chi-square statistic between a non-negative feature (e.g. term
+// counts or frequencies) and a categorical target.  The observed value for each class is the sum of the feature's
+// values for observations of the class and the expected value is the feature's total sum shared between the
+// classes in proportion to their frequency.  The p-value is from the chi-square distribution with one fewer
+// degrees of freedom than the number of classes.  If there are fewer than 2 classes or the feature's values sum
+// to 0, NaN is returned.  ChiSquare will panic if any of the feature's values are negative.
+func ChiSquare(values, target []float64) (chi2, pValue float64) {
+	if len(values) != len(target) {
+		panic(ErrLengthMismatch)
+	}
+	var total float64
+	for _, v := range values {
+		if v < 0 {
+			panic("datautils: chi-square requires non-negative feature values")
+		}
+		total += v
+	}
+	classes := classIndices(target)
+	if len(classes) < 2 || total == 0 {
+		return math.NaN(), math.NaN()
+	}
+
+	for _, ind := range classes {
+		var observed float64
+		for _, i := range ind {
+			observed += values[i]
+		}
+		expected := total * float64(len(ind)) / float64(len(values))
+		chi2 += (observed - expected) * (observed - expected) / expected
+	}
+	return chi2, distuv.ChiSquared{K: float64(len(classes) - 1)}.Survival(chi2)
+}
+
+// ANOVAF is a FeatureScorer calculating the one-way analysis of variance (ANOVA) F statistic between a continuous
+// feature and a categorical target i.e. the ratio of the variance of the feature's mean between the classes to the
+// variance of the feature within the classes.  The p-value is from the F distribution with k - 1 and n - k degrees
+// of freedom for n observations of k classes.  If there are fewer than 2 classes or no more observations than
+// classes, NaN is returned.  If the feature is constant within every class but varies between classes, the F
+// statistic is +Inf.
+func ANOVAF(values, target []float64) (f, pValue float64) {
+	if len(values) != len(target) {
+		panic(ErrLengthMismatch)
+	}
+	classes := classIndices(target)
+	n, k := len(values), len(classes)
+	if k < 2 || n <= k {
+		return math.NaN(), math.NaN()
+	}
+
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(n)
+
+	var between, within float64
+	for _, ind := range classes {
+		var classMean float64
+		for _, i := range ind {
+			classMean += values[i]
+		}
+		classMean /= float64(len(ind))
+		between += float64(len(ind)) * (classMean - mean) * (classMean - mean)
+		for _, i := range ind {
+			within += (values[i] - classMean) * (values[i] - classMean)
+		}
+	}
+	if within == 0 {
+		if between == 0 {
+			return math.NaN(), math.NaN()
+		}
+		return math.Inf(1), 0
+	}
+
+	d1, d2 := float64(k-1), float64(n-k)
+	f = (between / d1) / (within / d2)
+	return f, distuv.F{D1: d1, D2: d2}.Survival(f)
+}
+
+// MutualInformation calculates the mutual information, in nats, between two discrete variables whose values are
+// treated as categories.  Mutual information measures how much knowing the value of one variable reduces
+// uncertainty about the other, capturing any kind of dependency rather than only linear relationships.  It is 0
+// for independent variables.
+func MutualInformation(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic(ErrLengthMismatch)
+	}
+	if len(x) == 0 {
+		return 0
+	}
+	type pair struct{ x, y float64 }
+	joint := make(map[pair]int)
+	px := make(map[float64]int)
+	py := make(map[float64]int)
+	for i := range x {
+		joint[pair{x[i], y[i]}]++
+		px[x[i]]++
+		py[y[i]]++
+	}
+
+	// sum in sorted order so that the result is deterministic
+	pairs := make([]pair, 0, len(joint))
+	for p := range joint {
+		pairs = append(pairs, p)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].x < pairs[j].x || pairs[i].x == pairs[j].x && pairs[i].y < pairs[j].y
+	})
+
+	n := float64(len(x))
+	var mi float64
+	for _, p := range pairs {
+		c := float64(joint[p])
+		mi += c / n * math.Log(c*n/(float64(px[p.x])*float64(py[p.y])))
+	}
+	// guard against small negative values arising from rounding error
+	return math.Max(mi, 0)
+}
+
+// MutualInformationScorer returns a FeatureScorer calculating the mutual information (see MutualInformation)
+// between a feature and a categorical target.  If bins is 0 the feature's values are treated as discrete
+// categories, otherwise continuous features are first discretised into (up to) the specified number of equal
+// frequency bins (see EqualFrequencyEdges).  The returned p-value is always NaN.
+func MutualInformationScorer(bins int) FeatureScorer {
+	if bins < 0 {
+		panic("datautils: number of bins must not be negative")
+	}
+	return func(values, target []float64) (float64, float64) {
+		if bins == 0 {
+			return MutualInformation(values, target), math.NaN()
+		}
+		edges := EqualFrequencyEdges(values, bins)
+		binned := make([]float64, len(values))
+		for i, v := range values {
+			binned[i] = float64(Digitise(v, edges))
+		}
+		return MutualInformation(binned, target), math.NaN()
+	}
+}
+
+// FeatureScore is the relevance score of a single feature.
+type FeatureScore struct {
+	// Column is the index of the feature's column
+	Column int
+
+	// Feature is the name of the feature
+	Feature string
+
+	// Score is the relevance score of the feature
+	Score float64
+
+	// PValue is the p-value of the score or NaN if the scorer has no statistical test
+	PValue float64
+}
+
+// FeatureScores contains the relevance scores of a set of features ranked in descending order of score.
+type FeatureScores []FeatureScore
+
+// ScoreFeatures scores the relevance of each column (feature) of m to the target values using the specified
+// scorer and returns the scores ranked in descending order.  Features with NaN scores are ranked last and ties
+// retain column order.  names contains the name of each column or, if nil, columns are named by index.
+// Observations with a missing (NaN) feature value or target value are excluded from the scoring of that feature.
+func ScoreFeatures(m mat.Matrix, names []string, target []float64, scorer FeatureScorer) FeatureScores {
+	r, c := m.Dims()
+	if len(target) != r || names != nil && len(names) != c {
+		panic(ErrLengthMismatch)
+	}
+
+	scores := make(FeatureScores, c)
+	for j := range scores {
+		values := make([]float64, 0, r)
+		labels := make([]float64, 0, r)
+		for i := 0; i < r; i++ {
+			if v := m.At(i, j); !math.IsNaN(v) && !math.IsNaN(target[i]) {
+				values = append(values, v)
+				labels = append(labels, target[i])
+			}
+		}
+		score, p := scorer(values, labels)
+		scores[j] = FeatureScore{Column: j, Feature: featureName(names, j), Score: score, PValue: p}
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		a, b := scores[i].Score, scores[j].Score
+		return !math.IsNaN(a) && (math.IsNaN(b) || a > b)
+	})
+	return scores
+}
+
+// String returns a fixed width textual representation of the ranked feature scores suitable for printing.
+func (s FeatureScores) String() string {
+	str := "Rank | Feature              |      Score     |   P-Value\n"
+	str = str + "----------------------------------------------------------\n"
+	for i, f := range s {
+		str = fmt.Sprintf("%s%4d | %-20s | %14.6g | %9.4g\n", str, i+1, f.Feature, f.Score, f.PValue)
+	}
+	return str
+}
+
+// SelectKBest selects the K features most relevant to a target, according to a FeatureScorer, discarding the
+// remaining features.  The features are selected from a matrix of training data with Fit and then applied to
+// other matrices (e.g. a test set) with Transform so that the same features are selected from all data.
+type SelectKBest struct {
+	// K is the number of features to select
+	K int
+
+	// Scorer scores the relevance of each feature to the target e.g. ChiSquare, ANOVAF or
+	// MutualInformationScorer
+	Scorer FeatureScorer
+
+	// Scores contains the scores of all the features, ranked in descending order, as calculated by Fit
+	Scores FeatureScores
+
+	// Selected contains the indices of the columns of the selected features in ascending order
+	Selected []int
+}
+
+// Fit scores each column of m against the target values and selects the K highest scoring columns.
+func (s *SelectKBest) Fit(m mat.Matrix, target []float64) {
+	_, c := m.Dims()
+	if s.K < 1 || s.K > c {
+		panic(ErrOutOfBounds)
+	}
+	s.Scores = ScoreFeatures(m, nil, target, s.Scorer)
+	s.Selected = make([]int, s.K)
+	for i := range s.Selected {
+		s.Selected[i] = s.Scores[i].Column
+	}
+	sort.Ints(s.Selected)
+}
+
+// Transform returns a new matrix containing only the selected columns of m.
+func (s *SelectKBest) Transform(m mat.Matrix) *mat.Dense {
+	if s.Selected == nil {
+		panic("datautils: feature selector has not been fitted")
+	}
+	r, c := m.Dims()
+	for _, j := range s.Selected {
+		if j >= c {
+			panic(mat.ErrShape)
+		}
+	}
+	t := mat.NewDense(r, len(s.Selected), nil)
+	for i := 0; i < r; i++ {
+		for k, j := range s.Selected {
+			t.Set(i, k, m.At(i, j))
+		}
+	}
+	return t
+}
@@ -0,0 +1,116 @@
+package datautils_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestChiSquare(t *testing.T) {
+	chi2, p := datautils.ChiSquare([]float64{1, 2, 3, 4}, []float64{0, 0, 1, 1})
+	if math.Abs(chi2-1.6) > 1e-12 {
+		t.Errorf("Expected chi-square: 1.6 but received %v", chi2)
+	}
+	if expected := math.Erfc(math.Sqrt(0.8)); math.Abs(p-expected) > 1e-9 {
+		t.Errorf("Expected p-value: %v but received %v", expected, p)
+	}
+
+	if chi2, _ := datautils.ChiSquare([]float64{0, 0}, []float64{0, 1}); !math.IsNaN(chi2) {
+		t.Errorf("Expected NaN chi-square for zero feature but received %v", chi2)
+	}
+	if chi2, _ := datautils.ChiSquare([]float64{1, 2}, []float64{1, 1}); !math.IsNaN(chi2) {
+		t.Errorf("Expected NaN chi-square for single class but received %v", chi2)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for negative feature values but received none")
+		}
+	}()
+	datautils.ChiSquare([]float64{1, -2}, []float64{0, 1})
+}
+
+func TestANOVAF(t *testing.T) {
+	f, p := datautils.ANOVAF([]float64{1, 2, 5, 6}, []float64{0, 0, 1, 1})
+	if math.Abs(f-32) > 1e-12 {
+		t.Errorf("Expected F: 32 but received %v", f)
+	}
+	// F(1, 2) is the square of Student's t with 2 degrees of freedom
+	if expected := 1 - math.Sqrt(32.0/34); math.Abs(p-expected) > 1e-9 {
+		t.Errorf("Expected p-value: %v but received %v", expected, p)
+	}
+
+	if f, p := datautils.ANOVAF([]float64{1, 1, 5, 5}, []float64{0, 0, 1, 1}); !math.IsInf(f, 1) || p != 0 {
+		t.Errorf("Expected F: +Inf and p-value: 0 for perfectly separated classes but received %v, %v", f, p)
+	}
+	if f, _ := datautils.ANOVAF([]float64{3, 3, 3, 3}, []float64{0, 0, 1, 1}); !math.IsNaN(f) {
+		t.Errorf("Expected NaN F for constant feature but received %v", f)
+	}
+}
+
+func TestMutualInformation(t *testing.T) {
+	tests := []struct {
+		x, y     []float64
+		expected float64
+	}{
+		{x: []float64{0, 0, 1, 1}, y: []float64{0, 0, 1, 1}, expected: math.Ln2},
+		{x: []float64{0, 1, 0, 1}, y: []float64{0, 0, 1, 1}, expected: 0},
+		{x: []float64{0, 1, 2, 3}, y: []float64{5, 5, 5, 5}, expected: 0},
+		{x: []float64{}, y: []float64{}, expected: 0},
+	}
+
+	for i, test := range tests {
+		if mi := datautils.MutualInformation(test.x, test.y); math.Abs(mi-test.expected) > 1e-12 {
+			t.Errorf("Test %d: Expected mutual information: %v but received %v", i+1, test.expected, mi)
+		}
+	}
+
+	// continuous values are discretised before calculating mutual information
+	mi, p := datautils.MutualInformationScorer(2)([]float64{0.1, 0.2, 5, 6}, []float64{0, 0, 1, 1})
+	if math.Abs(mi-math.Ln2) > 1e-12 || !math.IsNaN(p) {
+		t.Errorf("Expected binned mutual information: %v with NaN p-value but received %v, %v", math.Ln2, mi, p)
+	}
+}
+
+func TestScoreFeaturesAndSelectKBest(t *testing.T) {
+	m := mat.NewDense(4, 3, []float64{
+		3, 1, 1,
+		3, 5, 2,
+		3, 2, 5,
+		3, 6, 6,
+	})
+	target := []float64{0, 0, 1, 1}
+
+	scores := datautils.ScoreFeatures(m, []string{"constant", "noise", "signal"}, target, datautils.ANOVAF)
+	expected := []string{"signal", "noise", "constant"}
+	for i, s := range scores {
+		if s.Feature != expected[i] {
+			t.Errorf("Expected feature %d: %s but received %s", i+1, expected[i], s.Feature)
+		}
+	}
+	if math.Abs(scores[0].Score-32) > 1e-12 || math.Abs(scores[1].Score-0.125) > 1e-12 || !math.IsNaN(scores[2].Score) {
+		t.Errorf("Unexpected feature scores: %v", scores)
+	}
+	if s := scores.String(); !strings.Contains(s, "signal") || strings.Count(s, "\n") != 5 {
+		t.Errorf("Unexpected feature scores table:\n%s", s)
+	}
+
+	// missing values are excluded
+	m.Set(1, 1, math.NaN())
+	if s := datautils.ScoreFeatures(m, nil, target, datautils.ANOVAF); s[1].Feature != "1" || math.Abs(s[1].Score-0.75) > 1e-12 {
+		t.Errorf("Expected ANOVA F excluding missing values: 0.75 but received %v", s[1])
+	}
+
+	selector := datautils.SelectKBest{K: 2, Scorer: datautils.ANOVAF}
+	selector.Fit(m, target)
+	if len(selector.Selected) != 2 || selector.Selected[0] != 1 || selector.Selected[1] != 2 {
+		t.Errorf("Expected selected columns: [1 2] but received %v", selector.Selected)
+	}
+	transformed := selector.Transform(mat.NewDense(1, 3, []float64{7, 8, 9}))
+	if !mat.Equal(transformed, mat.NewDense(1, 2, []float64{8, 9})) {
+		t.Errorf("Expected transformed matrix: [8 9] but received %v", transformed)
+	}
+}
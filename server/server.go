@@ -0,0 +1,330 @@
+// Package server exposes the metric computations of datautils over HTTP with JSON requests and responses so that
+// services written in other languages can evaluate models using exactly the same implementations as Go code.  The
+// package is optional and importing datautils alone does not depend upon it e.g.
+//
+//	http.Handle("/", server.Server{}.Handler())
+//	log.Fatal(http.ListenAndServe(":8080", nil))
+//
+// The following endpoints are served, each accepting a JSON request body with the POST method:
+//
+//	/v1/classification        binary classification metrics and confusion matrix
+//	/v1/ranking               ranking metrics aggregated over queries
+//	/v1/plot/precision-recall precision recall curve image
+//	/v1/plot/roc              ROC curve image
+//
+// Supported binary classification metrics are ap (average precision), auc (area under the ROC curve), precision,
+// recall, f1, accuracy, mcc and kappa (the last 6 at the request's threshold).  Supported ranking metrics are mrr,
+// ndcg@k and hr@k (hit rate).  Undefined (NaN) and infinite metric values are returned as the strings "NaN",
+// "Infinity" and "-Infinity" (see datautils.JSONFloat).  Errors are returned with an appropriate status code and a
+// JSON body of the form {"error": "..."}.
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+)
+
+// DefaultMaxRequestBytes is the maximum size of request bodies if Server.MaxRequestBytes is not specified.
+const DefaultMaxRequestBytes = 32 << 20
+
+// DefaultThreshold is the decision threshold used for threshold based metrics if a request does not specify one.
+const DefaultThreshold = 0.5
+
+// contentTypes are the MIME types of the supported plot image formats.
+var contentTypes = map[string]string{
+	"png": "image/png",
+	"svg": "image/svg+xml",
+	"pdf": "application/pdf",
+}
+
+// Server serves the evaluation API over HTTP.
+type Server struct {
+	// MaxRequestBytes is the maximum size of request bodies.  If 0, DefaultMaxRequestBytes is used
+	MaxRequestBytes int64
+}
+
+// ClassificationRequest is the request body of the classification and plot endpoints.
+type ClassificationRequest struct {
+	// Predictions and Labels are the model's predictions and the corresponding ground truth labels.  As with
+	// datautils.NewConfusionMatrix, labels of 1 are positive
+	Predictions []float64 `json:"predictions"`
+	Labels      []float64 `json:"labels"`
+
+	// Threshold is the decision threshold for threshold based metrics.  If nil, DefaultThreshold is used
+	Threshold *float64 `json:"threshold,omitempty"`
+
+	// Metrics are the names of the metrics to calculate.  If empty, ap, auc and f1 are calculated
+	Metrics []string `json:"metrics,omitempty"`
+}
+
+// ClassificationResponse is the response body of the classification endpoint.
+type ClassificationResponse struct {
+	// Metrics contains the value of each requested metric keyed by name
+	Metrics map[string]datautils.JSONFloat `json:"metrics"`
+
+	// Threshold is the decision threshold used for threshold based metrics
+	Threshold float64 `json:"threshold"`
+
+	// ConfusionMatrix is the confusion matrix at Threshold
+	ConfusionMatrix datautils.ConfusionMatrix `json:"confusion_matrix"`
+}
+
+// RankingRequest is the request body of the ranking endpoint.
+type RankingRequest struct {
+	// Predictions, Labels and Queries are the predicted scores, the corresponding degrees of relevance and query
+	// IDs of the ranked items of all queries (see datautils.NewQueryEvaluationSet)
+	Predictions []float64 `json:"predictions"`
+	Labels      []float64 `json:"labels"`
+	Queries     []string  `json:"queries"`
+
+	// Metrics are the names of the metrics to calculate.  If empty, mrr and ndcg@10 are calculated
+	Metrics []string `json:"metrics,omitempty"`
+}
+
+// RankingResponse is the response body of the ranking endpoint.
+type RankingResponse struct {
+	// Metrics contains the value of each requested metric keyed by name
+	Metrics map[string]datautils.JSONFloat `json:"metrics"`
+
+	// Queries is the number of distinct queries evaluated
+	Queries int `json:"queries"`
+}
+
+// statusError is an error to be reported to the client with the specified HTTP status code.
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e statusError) Error() string { return e.err.Error() }
+
+// badRequest returns an error reported to the client with the 400 Bad Request status code.
+func badRequest(format string, args ...interface{}) error {
+	return statusError{status: http.StatusBadRequest, err: fmt.Errorf(format, args...)}
+}
+
+// Handler returns an http.Handler serving the evaluation API.
+func (s Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/classification", s.handle(classification))
+	mux.HandleFunc("/v1/ranking", s.handle(ranking))
+	mux.HandleFunc("/v1/plot/precision-recall", s.handle(plotImage(func(r ClassificationRequest) *plot.Plot {
+		return datautils.NewPrecisionRecallCurve(r.Predictions, r.Labels).Plot()
+	})))
+	mux.HandleFunc("/v1/plot/roc", s.handle(plotImage(func(r ClassificationRequest) *plot.Plot {
+		return datautils.NewROCCurve(r.Predictions, r.Labels).Plot()
+	})))
+	return mux
+}
+
+// handle adapts an endpoint implementation to an http.HandlerFunc accepting only POST requests and reporting any
+// error returned to the client as JSON.
+func (s Server) handle(endpoint func(w http.ResponseWriter, r *http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		max := s.MaxRequestBytes
+		if max == 0 {
+			max = DefaultMaxRequestBytes
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, max)
+
+		if err := endpoint(w, r); err != nil {
+			status := http.StatusInternalServerError
+			var se statusError
+			if errors.As(err, &se) {
+				status = se.status
+			}
+			writeError(w, status, err)
+		}
+	}
+}
+
+// decode decodes the JSON request body into v.
+func decode(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return statusError{status: http.StatusRequestEntityTooLarge, err: err}
+		}
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+// writeJSON writes v to w as JSON with the 200 OK status code.
+func writeJSON(w http.ResponseWriter, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// writeError writes err to w as JSON with the specified status code.
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+// decodeClassification decodes and validates a ClassificationRequest.
+func decodeClassification(r *http.Request) (ClassificationRequest, error) {
+	var req ClassificationRequest
+	if err := decode(r, &req); err != nil {
+		return req, err
+	}
+	if len(req.Predictions) == 0 {
+		return req, badRequest("no predictions specified")
+	}
+	if err := datautils.ValidateLengths(req.Predictions, req.Labels); err != nil {
+		return req, badRequest("%v", err)
+	}
+	return req, nil
+}
+
+// classification calculates the requested binary classification metrics.
+func classification(w http.ResponseWriter, r *http.Request) error {
+	req, err := decodeClassification(r)
+	if err != nil {
+		return err
+	}
+	threshold := DefaultThreshold
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+	}
+	names := req.Metrics
+	if len(names) == 0 {
+		names = []string{"ap", "auc", "f1"}
+	}
+
+	matrix := datautils.NewConfusionMatrix(req.Predictions, req.Labels, threshold)
+	metrics := map[string]func() float64{
+		"ap": func() float64 {
+			return datautils.NewPrecisionRecallCurve(req.Predictions, req.Labels).AveragePrecision()
+		},
+		"auc":       func() float64 { return datautils.NewROCCurve(req.Predictions, req.Labels).AUC() },
+		"precision": matrix.Precision,
+		"recall":    matrix.Recall,
+		"f1":        matrix.F1,
+		"accuracy":  matrix.Accuracy,
+		"mcc":       matrix.MCC,
+		"kappa":     matrix.Kappa,
+	}
+
+	resp := ClassificationResponse{Metrics: make(map[string]datautils.JSONFloat, len(names)), Threshold: threshold, ConfusionMatrix: matrix}
+	for _, name := range names {
+		f, ok := metrics[strings.ToLower(name)]
+		if !ok {
+			return badRequest("unknown classification metric %q", name)
+		}
+		resp.Metrics[name] = datautils.JSONFloat(f())
+	}
+	return writeJSON(w, resp)
+}
+
+// ranking calculates the requested ranking metrics aggregated over all queries.
+func ranking(w http.ResponseWriter, r *http.Request) error {
+	var req RankingRequest
+	if err := decode(r, &req); err != nil {
+		return err
+	}
+	if len(req.Predictions) == 0 {
+		return badRequest("no predictions specified")
+	}
+	set, err := datautils.NewQueryEvaluationSetE(req.Predictions, req.Labels, req.Queries)
+	if err != nil {
+		return badRequest("%v", err)
+	}
+	names := req.Metrics
+	if len(names) == 0 {
+		names = []string{"mrr", "ndcg@10"}
+	}
+
+	resp := RankingResponse{Metrics: make(map[string]datautils.JSONFloat, len(names)), Queries: len(set)}
+	for _, name := range names {
+		metric := strings.ToLower(name)
+		var v float64
+		switch {
+		case metric == "mrr":
+			v = set.MeanReciprocalRank()
+		case strings.HasPrefix(metric, "ndcg@"), strings.HasPrefix(metric, "hr@"):
+			k, err := strconv.Atoi(metric[strings.Index(metric, "@")+1:])
+			if err != nil || k < 1 {
+				return badRequest("invalid cut-off for ranking metric %q", name)
+			}
+			if strings.HasPrefix(metric, "hr@") {
+				v = set.HitRate(k)
+			} else {
+				v = set.MeanNormalisedDiscountedCumulativeGains([]int{k}, datautils.TraditionalRelevancy)[0]
+			}
+		default:
+			return badRequest("unknown ranking metric %q", name)
+		}
+		resp.Metrics[name] = datautils.JSONFloat(v)
+	}
+	return writeJSON(w, resp)
+}
+
+// plotImage returns an endpoint rendering the plot created by render from a ClassificationRequest.  The image format
+// and its width and height, in centimetres, are specified by the format (png, svg or pdf), width and height query
+// parameters which default to png, 15 and 15 respectively.
+func plotImage(render func(ClassificationRequest) *plot.Plot) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		query := r.URL.Query()
+		format := query.Get("format")
+		if format == "" {
+			format = "png"
+		}
+		contentType, ok := contentTypes[format]
+		if !ok {
+			return badRequest("unsupported image format %q", format)
+		}
+		size := make([]vg.Length, 2)
+		for i, param := range []string{"width", "height"} {
+			size[i] = 15 * vg.Centimeter
+			if v := query.Get(param); v != "" {
+				cm, err := strconv.ParseFloat(v, 64)
+				if err != nil || cm <= 0 || cm > 100 {
+					return badRequest("invalid %s %q", param, v)
+				}
+				size[i] = vg.Length(cm) * vg.Centimeter
+			}
+		}
+
+		req, err := decodeClassification(r)
+		if err != nil {
+			return err
+		}
+		img, err := render(req).WriterTo(size[0], size[1], format)
+		if err != nil {
+			return err
+		}
+		// render the complete image before responding so that rendering errors can still be reported
+		var buf bytes.Buffer
+		if _, err := img.WriteTo(&buf); err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, err = buf.WriteTo(w)
+		return err
+	}
+}
@@ -0,0 +1,138 @@
+package server_test
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"github.com/james-bowman/datautils/server"
+)
+
+func post(t *testing.T, h http.Handler, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestClassification(t *testing.T) {
+	predictions := []float64{0.9, 0.8, 0.3, 0.6, 0.1}
+	labels := []float64{1, 0, 1, 0, 0}
+	body := `{"predictions": [0.9, 0.8, 0.3, 0.6, 0.1], "labels": [1, 0, 1, 0, 0], "threshold": 0.5, "metrics": ["ap", "auc", "precision", "mcc"]}`
+
+	rec := post(t, server.Server{}.Handler(), "/v1/classification", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but received %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	var resp struct {
+		Metrics         map[string]float64
+		Threshold       float64
+		ConfusionMatrix datautils.ConfusionMatrix `json:"confusion_matrix"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	matrix := datautils.NewConfusionMatrix(predictions, labels, 0.5)
+	expected := map[string]float64{
+		"ap":        datautils.NewPrecisionRecallCurve(predictions, labels).AveragePrecision(),
+		"auc":       datautils.NewROCCurve(predictions, labels).AUC(),
+		"precision": matrix.Precision(),
+		"mcc":       matrix.MCC(),
+	}
+	for name, v := range expected {
+		if math.Abs(resp.Metrics[name]-v) > 1e-12 {
+			t.Errorf("Expected %s: %v but received %v", name, v, resp.Metrics[name])
+		}
+	}
+	if resp.ConfusionMatrix != matrix || resp.Threshold != 0.5 {
+		t.Errorf("Expected confusion matrix %v at 0.5 but received %v at %v", matrix, resp.ConfusionMatrix, resp.Threshold)
+	}
+}
+
+func TestClassificationUndefined(t *testing.T) {
+	// precision is undefined when nothing is predicted positive
+	rec := post(t, server.Server{}.Handler(), "/v1/classification", `{"predictions": [0.1, 0.2], "labels": [1, 0], "metrics": ["precision"]}`)
+	if body := strings.TrimSpace(rec.Body.String()); rec.Code != http.StatusOK || !strings.Contains(body, `"precision":"NaN"`) {
+		t.Errorf("Expected NaN precision but received %d: %s", rec.Code, body)
+	}
+}
+
+func TestRanking(t *testing.T) {
+	body := `{"predictions": [0.9, 0.4, 0.7, 0.2], "labels": [1, 0, 0, 1], "queries": ["a", "a", "b", "b"], "metrics": ["mrr", "hr@1", "ndcg@2"]}`
+	rec := post(t, server.Server{}.Handler(), "/v1/ranking", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but received %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	var resp struct {
+		Metrics map[string]float64
+		Queries int
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Queries != 2 || resp.Metrics["mrr"] != 0.75 || resp.Metrics["hr@1"] != 0.5 {
+		t.Errorf("Unexpected ranking response: %+v", resp)
+	}
+	if _, ok := resp.Metrics["ndcg@2"]; !ok {
+		t.Errorf("Expected ndcg@2 in response: %+v", resp)
+	}
+}
+
+func TestPlot(t *testing.T) {
+	body := `{"predictions": [0.9, 0.8, 0.3], "labels": [1, 0, 1]}`
+	tests := []struct {
+		path        string
+		contentType string
+	}{
+		{path: "/v1/plot/roc", contentType: "image/png"},
+		{path: "/v1/plot/precision-recall?format=svg&width=10&height=8", contentType: "image/svg+xml"},
+	}
+
+	for _, test := range tests {
+		rec := post(t, server.Server{}.Handler(), test.path, body)
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != test.contentType || rec.Body.Len() == 0 {
+			t.Errorf("%s: Expected %s image but received %d %q", test.path, test.contentType, rec.Code, rec.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		path   string
+		body   string
+		status int
+	}{
+		{path: "/v1/classification", body: `{"predictions": [0.1], "labels": [1, 0]}`, status: http.StatusBadRequest},
+		{path: "/v1/classification", body: `{"predictions": []}`, status: http.StatusBadRequest},
+		{path: "/v1/classification", body: `{"predictions": [0.1], "labels": [1], "metrics": ["nope"]}`, status: http.StatusBadRequest},
+		{path: "/v1/classification", body: `{"predictions": [0.1], "labels": [1], "unknown": 1}`, status: http.StatusBadRequest},
+		{path: "/v1/classification", body: `not json`, status: http.StatusBadRequest},
+		{path: "/v1/ranking", body: `{"predictions": [0.1], "labels": [1]}`, status: http.StatusBadRequest},
+		{path: "/v1/ranking", body: `{"predictions": [0.1], "labels": [1], "queries": ["a"], "metrics": ["ndcg@0"]}`, status: http.StatusBadRequest},
+		{path: "/v1/plot/roc?format=gif", body: `{"predictions": [0.1], "labels": [1]}`, status: http.StatusBadRequest},
+		{path: "/v1/plot/roc?width=-1", body: `{"predictions": [0.1], "labels": [1]}`, status: http.StatusBadRequest},
+		{path: "/v1/classification", body: `{"predictions": [` + strings.Repeat("0.1,", 100) + `0.1]}`, status: http.StatusRequestEntityTooLarge},
+	}
+
+	h := server.Server{MaxRequestBytes: 100}.Handler()
+	for i, test := range tests {
+		rec := post(t, h, test.path, test.body)
+		var resp struct{ Error string }
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != test.status || resp.Error == "" {
+			t.Errorf("Test %d: Expected status %d with error but received %d: %s", i+1, test.status, rec.Code, rec.Body)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/classification", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("Expected status %d but received %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
@@ -0,0 +1,171 @@
+package datautils
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// PermutationTest performs a paired permutation (randomisation) test to determine whether the difference in the
+// specified metric between two models' predictions (predictionsA and predictionsB) for the same observations
+// (labels) is statistically significant.  For each of the n permutations, the predictions of the two models for
+// each observation are randomly swapped and the difference in the metric recalculated.  The two-sided p-value
+// returned is the proportion of permutations producing an absolute difference at least as large as the observed
+// difference.  The seed is used to initialise the random number generator so that results are reproducible.
+func PermutationTest(predictionsA, predictionsB, labels []float64, metric MetricFunc, n int, seed int64) float64 {
+	if err := ValidateLengths(predictionsA, labels); err != nil {
+		panic(err)
+	}
+	if err := ValidateLengths(predictionsB, labels); err != nil {
+		panic(err)
+	}
+
+	observed := math.Abs(metric(predictionsA, labels) - metric(predictionsB, labels))
+
+	a := make([]float64, len(predictionsA))
+	b := make([]float64, len(predictionsB))
+
+	return permutationPValue(observed, n, seed, func(rnd *rand.Rand) float64 {
+		for i := range a {
+			if rnd.Intn(2) == 0 {
+				a[i], b[i] = predictionsA[i], predictionsB[i]
+			} else {
+				a[i], b[i] = predictionsB[i], predictionsA[i]
+			}
+		}
+		return math.Abs(metric(a, labels) - metric(b, labels))
+	})
+}
+
+// PairedPermutationTest performs a paired permutation (randomisation) test to determine whether the difference
+// between the means of two sets of paired per query metric values (e.g. the per query average precision of two
+// retrieval models over the same set of queries, the means being MAP) is statistically significant.  For each of
+// the n permutations, the sign of each per query difference is randomly flipped and the mean difference
+// recalculated.  The two-sided p-value returned is the proportion of permutations producing an absolute mean
+// difference at least as large as the observed mean difference.  The seed is used to initialise the random number
+// generator so that results are reproducible.
+func PairedPermutationTest(scoresA, scoresB []float64, n int, seed int64) float64 {
+	if len(scoresA) != len(scoresB) {
+		panic(ErrLengthMismatch)
+	}
+
+	diffs := make([]float64, len(scoresA))
+	var sum float64
+	for i := range scoresA {
+		diffs[i] = scoresA[i] - scoresB[i]
+		sum += diffs[i]
+	}
+	observed := math.Abs(sum)
+
+	return permutationPValue(observed, n, seed, func(rnd *rand.Rand) float64 {
+		var sum float64
+		for _, d := range diffs {
+			if rnd.Intn(2) == 0 {
+				sum += d
+			} else {
+				sum -= d
+			}
+		}
+		return math.Abs(sum)
+	})
+}
+
+// permutationPValue calculates the p-value for a permutation test given the observed (absolute) test statistic
+// and a function that calculates the test statistic for a single random permutation.  The p-value includes the
+// observed statistic as one of the permutations so that it is never 0.
+func permutationPValue(observed float64, n int, seed int64, permute func(*rand.Rand) float64) float64 {
+	if n < 1 {
+		panic("datautils: number of permutations must be at least 1")
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+
+	// allow for floating point error when comparing statistics that would be equal in exact arithmetic
+	tolerance := 1e-12 * math.Max(1, observed)
+
+	count := 1
+	for i := 0; i < n; i++ {
+		if permute(rnd) >= observed-tolerance {
+			count++
+		}
+	}
+	return float64(count) / float64(n+1)
+}
+
+// McNemarTest performs McNemar's test to determine whether the difference in accuracy between two classifiers'
+// predictions (predictionsA and predictionsB) for the same observations (labels) is statistically significant.
+// Predictions greater than or equal to threshold are predicted positive and, as with NewConfusionMatrix, labels
+// of 1 are considered positive and all other values negative.  The test considers only the observations where
+// exactly one of the classifiers is correct and returns the chi-squared statistic (with continuity correction)
+// and the corresponding two-sided p-value with 1 degree of freedom.  If the classifiers are never discordant the
+// statistic is 0 and the p-value 1.
+func McNemarTest(predictionsA, predictionsB, labels []float64, threshold float64) (statistic, p float64) {
+	if err := ValidateLengths(predictionsA, labels); err != nil {
+		panic(err)
+	}
+	if err := ValidateLengths(predictionsB, labels); err != nil {
+		panic(err)
+	}
+
+	// onlyA counts the observations only A classified correctly and onlyB those only B classified correctly
+	var onlyA, onlyB float64
+	for i, label := range labels {
+		actual := label == 1
+		correctA := (predictionsA[i] >= threshold) == actual
+		correctB := (predictionsB[i] >= threshold) == actual
+		if correctA && !correctB {
+			onlyA++
+		} else if correctB && !correctA {
+			onlyB++
+		}
+	}
+	if onlyA+onlyB == 0 {
+		return 0, 1
+	}
+
+	d := math.Max(math.Abs(onlyA-onlyB)-1, 0)
+	statistic = d * d / (onlyA + onlyB)
+	// the chi-squared distribution with 1 degree of freedom is the square of a standard normal variable
+	return statistic, math.Erfc(math.Sqrt(statistic / 2))
+}
+
+// FiveByTwoCVTest performs Dietterich's 5x2cv paired t-test to determine whether the difference in performance
+// between two learning algorithms is statistically significant.  The observations with the specified labels are
+// split into 2 folds (see KFold), optionally stratified, 5 times with different random shuffles.  For each fold,
+// evaluate should train both algorithms using the training set of the fold and return the value of the metric
+// for each algorithm evaluated using the test set.  The t statistic and corresponding two-sided p-value, from
+// the t-distribution with 5 degrees of freedom, are returned.  The seed is used to initialise the random number
+// generator so that results are reproducible.  If the differences in every replication are equal the variance is
+// 0 and the t statistic is undefined: t is 0 and p 1 if the differences are all 0 (the algorithms performed
+// identically) otherwise an error wrapping ErrZeroDivision is returned.
+func FiveByTwoCVTest(labels []float64, stratify bool, seed int64, evaluate func(fold Fold) (scoreA, scoreB float64)) (t, p float64, err error) {
+	rnd := rand.New(rand.NewSource(seed))
+
+	var first, variance float64
+	for i := 0; i < 5; i++ {
+		folds := KFold{K: 2, Shuffle: true, Stratify: stratify, Seed: rnd.Int63()}.Split(labels)
+
+		var diffs [2]float64
+		for j, fold := range folds {
+			a, b := evaluate(fold)
+			diffs[j] = a - b
+		}
+		if i == 0 {
+			first = diffs[0]
+		}
+		mean := (diffs[0] + diffs[1]) / 2
+		variance += (diffs[0]-mean)*(diffs[0]-mean) + (diffs[1]-mean)*(diffs[1]-mean)
+	}
+
+	if variance == 0 {
+		if first == 0 {
+			return 0, 1, nil
+		}
+		return 0, 0, fmt.Errorf("%w: 5x2cv t statistic undefined as the differences have zero variance", ErrZeroDivision)
+	}
+
+	t = first / math.Sqrt(variance/5)
+	return t, 2 * distuv.StudentsT{Mu: 0, Sigma: 1, Nu: 5}.Survival(math.Abs(t)), nil
+}
@@ -0,0 +1,167 @@
+package datautils_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestPermutationTest(t *testing.T) {
+	labels := []float64{1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0}
+	perfect := []float64{0.9, 0.85, 0.8, 0.75, 0.7, 0.65, 0.3, 0.25, 0.2, 0.15, 0.1, 0.05}
+	inverse := []float64{0.05, 0.1, 0.15, 0.2, 0.25, 0.3, 0.65, 0.7, 0.75, 0.8, 0.85, 0.9}
+
+	tests := []struct {
+		a, b []float64
+		minP float64
+		maxP float64
+	}{
+		{a: perfect, b: perfect, minP: 1, maxP: 1},
+		{a: perfect, b: inverse, minP: 0, maxP: 0.05},
+	}
+
+	for i, test := range tests {
+		p := datautils.PermutationTest(test.a, test.b, labels, averagePrecision, 500, 7)
+		if p < test.minP || p > test.maxP {
+			t.Errorf("Test %d: Expected p-value in range [%v, %v] but received %v", i+1, test.minP, test.maxP, p)
+		}
+	}
+}
+
+func TestPairedPermutationTest(t *testing.T) {
+	tests := []struct {
+		a, b []float64
+		minP float64
+		maxP float64
+	}{
+		{
+			a:    []float64{0.5, 0.6, 0.7},
+			b:    []float64{0.5, 0.6, 0.7},
+			minP: 1, maxP: 1,
+		},
+		{
+			a:    []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+			b:    []float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			minP: 0, maxP: 0.05,
+		},
+		{
+			a:    []float64{0.6, 0.4, 0.6, 0.4},
+			b:    []float64{0.4, 0.6, 0.4, 0.6},
+			minP: 1, maxP: 1,
+		},
+	}
+
+	for i, test := range tests {
+		p := datautils.PairedPermutationTest(test.a, test.b, 1000, 3)
+		if p < test.minP || p > test.maxP {
+			t.Errorf("Test %d: Expected p-value in range [%v, %v] but received %v", i+1, test.minP, test.maxP, p)
+		}
+	}
+}
+
+func TestMcNemarTest(t *testing.T) {
+	labels := make([]float64, 20)
+	a := make([]float64, 20)
+	b := make([]float64, 20)
+	for i := range labels {
+		labels[i] = 1
+		switch {
+		case i < 10:
+			// only A correct
+			a[i], b[i] = 0.9, 0.1
+		case i < 12:
+			// only B correct
+			a[i], b[i] = 0.1, 0.9
+		case i < 16:
+			// both correct
+			a[i], b[i] = 0.9, 0.9
+		}
+	}
+
+	tests := []struct {
+		a, b      []float64
+		statistic float64
+		p         float64
+	}{
+		{a: a, b: b, statistic: 49.0 / 12, p: 0.04330814281079198},
+		{a: b, b: a, statistic: 49.0 / 12, p: 0.04330814281079198},
+		{a: a, b: a, statistic: 0, p: 1},
+	}
+
+	for i, test := range tests {
+		statistic, p := datautils.McNemarTest(test.a, test.b, labels, 0.5)
+		if math.Abs(statistic-test.statistic) > 1e-12 || math.Abs(p-test.p) > 1e-9 {
+			t.Errorf("Test %d: Expected statistic: %v, p-value: %v but received %v, %v", i+1, test.statistic, test.p, statistic, p)
+		}
+	}
+}
+
+func TestFiveByTwoCVTest(t *testing.T) {
+	labels := []float64{1, 1, 1, 1, 0, 0, 0, 0, 0, 0}
+	diffs := []float64{0.1, 0.3, 0.2, 0.2, 0.1, 0.1, 0.3, 0.1, 0.2, 0.4}
+
+	var calls int
+	statistic, p, err := datautils.FiveByTwoCVTest(labels, true, 42, func(fold datautils.Fold) (float64, float64) {
+		if len(fold.Train)+len(fold.Test) != len(labels) || len(fold.Test) != len(labels)/2 {
+			t.Errorf("Expected fold of %d training and %d test observations but received %v", len(labels)/2, len(labels)/2, fold)
+		}
+		d := diffs[calls]
+		calls++
+		return 0.5 + d, 0.5
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 10 {
+		t.Errorf("Expected evaluations: %d but received %d", 10, calls)
+	}
+	expected := 0.1 / math.Sqrt(0.012)
+	if math.Abs(statistic-expected) > 1e-9 {
+		t.Errorf("Expected t statistic: %v but received %v", expected, statistic)
+	}
+	if math.Abs(p-0.4031803339968466) > 1e-9 {
+		t.Errorf("Expected p-value: %v but received %v", 0.4031803339968466, p)
+	}
+}
+
+func TestFiveByTwoCVTestZeroVariance(t *testing.T) {
+	labels := []float64{1, 1, 1, 1, 0, 0, 0, 0, 0, 0}
+
+	tests := []struct {
+		diff      float64
+		statistic float64
+		p         float64
+		err       error
+	}{
+		{diff: 0, statistic: 0, p: 1},
+		{diff: 0.1, err: datautils.ErrZeroDivision},
+	}
+
+	for i, test := range tests {
+		statistic, p, err := datautils.FiveByTwoCVTest(labels, false, 42, func(fold datautils.Fold) (float64, float64) {
+			return 0.5 + test.diff, 0.5
+		})
+		if !errors.Is(err, test.err) {
+			t.Errorf("Test %d: Expected error: %v but received %v", i+1, test.err, err)
+		}
+		if err == nil && (statistic != test.statistic || p != test.p) {
+			t.Errorf("Test %d: Expected statistic: %v, p-value: %v but received %v, %v", i+1, test.statistic, test.p, statistic, p)
+		}
+	}
+}
+
+func TestPermutationTestReproducible(t *testing.T) {
+	labels := []float64{1, 0, 1, 0, 1, 0}
+	a := []float64{0.9, 0.2, 0.4, 0.6, 0.7, 0.3}
+	b := []float64{0.6, 0.5, 0.8, 0.1, 0.3, 0.4}
+
+	if p, again := datautils.PermutationTest(a, b, labels, averagePrecision, 200, 11), datautils.PermutationTest(a, b, labels, averagePrecision, 200, 11); p != again {
+		t.Errorf("Expected identical p-values for identical seeds but received %v and %v", p, again)
+	}
+	if p, again := datautils.PairedPermutationTest(a, b, 200, 11), datautils.PairedPermutationTest(a, b, 200, 11); p != again {
+		t.Errorf("Expected identical paired p-values for identical seeds but received %v and %v", p, again)
+	}
+}
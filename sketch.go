@@ -0,0 +1,128 @@
+package datautils
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrIncompatibleHistograms is returned when attempting to merge ScoreHistograms with different bins.
+var ErrIncompatibleHistograms = errors.New("datautils: histograms have different bins")
+
+// ScoreHistogram is a fixed size sketch of a stream of (prediction, label) pairs supporting approximate
+// calculation of ROC AUC and average precision for datasets too large to hold in memory.  Predictions are
+// counted in equal width bins over a fixed range, separately for positive and negative observations, so memory
+// use is constant regardless of the number of observations.  Predictions falling within the same bin are treated
+// as tied so the accuracy of the approximation improves with the number of bins.  A ScoreHistogram is not safe
+// for concurrent use so, to accumulate across goroutines or shards, use a separate histogram for each and combine
+// them using Merge.
+type ScoreHistogram struct {
+	// Min and Max define the range of predictions covered by the bins.  Predictions outside the range are
+	// counted in the first or last bin as appropriate
+	Min, Max float64
+
+	pos, neg []float64
+}
+
+// NewScoreHistogram creates a new ScoreHistogram with the specified number of equal width bins covering the
+// range of predictions [min, max] e.g. [0, 1] for predicted probabilities.
+func NewScoreHistogram(bins int, min, max float64) *ScoreHistogram {
+	if bins < 1 {
+		panic("datautils: number of bins must be at least 1")
+	}
+	if !(max > min) {
+		panic("datautils: max must be greater than min")
+	}
+	return &ScoreHistogram{
+		Min: min,
+		Max: max,
+		pos: make([]float64, bins),
+		neg: make([]float64, bins),
+	}
+}
+
+// bin returns the index of the bin for the specified prediction.
+func (h *ScoreHistogram) bin(prediction float64) int {
+	b := int((prediction - h.Min) / (h.Max - h.Min) * float64(len(h.pos)))
+	if b < 0 {
+		return 0
+	}
+	if b >= len(h.pos) {
+		return len(h.pos) - 1
+	}
+	return b
+}
+
+// Add adds a single observation with the specified prediction and ground truth label.  As with
+// NewPrecisionRecallCurve, any label value greater than 0 is considered positive.
+func (h *ScoreHistogram) Add(prediction, label float64) {
+	h.AddWeighted(prediction, label, 1)
+}
+
+// AddWeighted adds a single observation with the specified prediction, ground truth label and weight.
+func (h *ScoreHistogram) AddWeighted(prediction, label, weight float64) {
+	if label > 0 {
+		h.pos[h.bin(prediction)] += weight
+	} else {
+		h.neg[h.bin(prediction)] += weight
+	}
+}
+
+// Merge adds all the observations accumulated by other into h.  Both histograms must have identical bins
+// otherwise ErrIncompatibleHistograms is returned.
+func (h *ScoreHistogram) Merge(other *ScoreHistogram) error {
+	if h.Min != other.Min || h.Max != other.Max || len(h.pos) != len(other.pos) {
+		return ErrIncompatibleHistograms
+	}
+	for i := range h.pos {
+		h.pos[i] += other.pos[i]
+		h.neg[i] += other.neg[i]
+	}
+	return nil
+}
+
+// totals returns the total (weighted) number of positive and negative observations.
+func (h *ScoreHistogram) totals() (pos, neg float64) {
+	for i := range h.pos {
+		pos += h.pos[i]
+		neg += h.neg[i]
+	}
+	return pos, neg
+}
+
+// AUC calculates the approximate area under the ROC curve.  This is the probability that a randomly chosen
+// positive observation is ranked above a randomly chosen negative observation, with observations in the same bin
+// counted as ties (contributing 0.5).  If there are no positive or no negative observations, NaN is returned.
+func (h *ScoreHistogram) AUC() float64 {
+	totalPos, totalNeg := h.totals()
+	if totalPos == 0 || totalNeg == 0 {
+		return math.NaN()
+	}
+
+	var sum, posAbove float64
+	for i := len(h.pos) - 1; i >= 0; i-- {
+		sum += h.neg[i] * (posAbove + 0.5*h.pos[i])
+		posAbove += h.pos[i]
+	}
+	return sum / (totalPos * totalNeg)
+}
+
+// AveragePrecision calculates the approximate average precision.  Bins are visited in descending order of
+// prediction with the observations in each bin treated as tied so that precision is evaluated once all the
+// observations in a bin have been ranked.  As with PrecisionRecallCurve, if there are no positive observations
+// 0 is returned.
+func (h *ScoreHistogram) AveragePrecision() float64 {
+	totalPos, _ := h.totals()
+	if totalPos == 0 {
+		return 0
+	}
+
+	var sum, tp, fp float64
+	for i := len(h.pos) - 1; i >= 0; i-- {
+		tp += h.pos[i]
+		fp += h.neg[i]
+		if h.pos[i] > 0 {
+			sum += (h.pos[i] / totalPos) * (tp / (tp + fp))
+		}
+	}
+	return sum
+}
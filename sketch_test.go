@@ -0,0 +1,65 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestScoreHistogram(t *testing.T) {
+	tests := []struct {
+		bins int
+		auc  float64
+		ap   float64
+	}{
+		// with sufficient bins, each prediction falls into its own bin so the results are exact
+		{bins: 100, auc: 0.75, ap: 0.8333333333333333},
+		// with a single bin all predictions are tied
+		{bins: 1, auc: 0.5, ap: 0.5},
+	}
+
+	for i, test := range tests {
+		a := datautils.NewScoreHistogram(test.bins, 0, 1)
+		b := datautils.NewScoreHistogram(test.bins, 0, 1)
+		for j := range datasets[0].probs {
+			if j < 2 {
+				a.Add(datasets[0].probs[j], datasets[0].labels[j])
+			} else {
+				b.Add(datasets[0].probs[j], datasets[0].labels[j])
+			}
+		}
+		if err := a.Merge(b); err != nil {
+			t.Fatalf("Test %d: Unexpected error: %v", i+1, err)
+		}
+
+		if auc := a.AUC(); math.Abs(auc-test.auc) > 0.000001 {
+			t.Errorf("Test %d: Expected AUC: %v but received %v", i+1, test.auc, auc)
+		}
+		if ap := a.AveragePrecision(); math.Abs(ap-test.ap) > 0.000001 {
+			t.Errorf("Test %d: Expected AP: %v but received %v", i+1, test.ap, ap)
+		}
+	}
+}
+
+func TestScoreHistogramEdgeCases(t *testing.T) {
+	h := datautils.NewScoreHistogram(10, 0, 1)
+	h.Add(0.5, 0)
+	h.Add(1.5, 0)
+	if auc := h.AUC(); !math.IsNaN(auc) {
+		t.Errorf("Expected NaN AUC with no positives but received %v", auc)
+	}
+	if ap := h.AveragePrecision(); ap != 0 {
+		t.Errorf("Expected AP 0 with no positives but received %v", ap)
+	}
+
+	// out of range predictions are clamped to the end bins
+	h.Add(-1, 1)
+	if auc := h.AUC(); auc != 0 {
+		t.Errorf("Expected AUC: %v but received %v", 0, auc)
+	}
+
+	if err := h.Merge(datautils.NewScoreHistogram(5, 0, 1)); err != datautils.ErrIncompatibleHistograms {
+		t.Errorf("Expected error: %v but received %v", datautils.ErrIncompatibleHistograms, err)
+	}
+}
@@ -0,0 +1,97 @@
+package datautils
+
+// Source is a stream of (prediction, label) pairs e.g. read from a file on disk or a database cursor, allowing
+// metrics to be calculated without first building slices of predictions and labels.  Next returns the next
+// pair with ok set to true or, once the stream is exhausted, ok set to false.  Sources reading from input that
+// may fail should stop the stream on error and record the error for the caller to check once the metric has
+// been constructed (in the same way as bufio.Scanner).
+type Source interface {
+	Next() (prediction, label float64, ok bool)
+}
+
+// SourceFunc is an adapter allowing a callback function to be used as a Source.  The function is called once
+// for each pair and should return ok set to false once there are no more pairs.
+type SourceFunc func() (prediction, label float64, ok bool)
+
+// Next calls f.
+func (f SourceFunc) Next() (prediction, label float64, ok bool) {
+	return f()
+}
+
+// NewSliceSource creates a Source streaming the supplied predictions and their corresponding labels in order.
+// The lengths of the slices must match (see ValidateLengths).
+func NewSliceSource[P, L Float](predictions []P, labels []L) Source {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+	var i int
+	return SourceFunc(func() (float64, float64, bool) {
+		if i >= len(predictions) {
+			return 0, 0, false
+		}
+		i++
+		return float64(predictions[i-1]), float64(labels[i-1]), true
+	})
+}
+
+// collect reads all the remaining pairs from src into slices.
+func collect(src Source) (predictions, labels []float64) {
+	for {
+		p, l, ok := src.Next()
+		if !ok {
+			return predictions, labels
+		}
+		predictions = append(predictions, p)
+		labels = append(labels, l)
+	}
+}
+
+// AddSource adds all the remaining observations from src to the builder.  The observations are consumed one at
+// a time so memory use is constant regardless of the length of the stream.
+func (b *ConfusionMatrixBuilder) AddSource(src Source) {
+	for {
+		p, l, ok := src.Next()
+		if !ok {
+			return
+		}
+		b.Add(p, l)
+	}
+}
+
+// AddSource adds all the remaining observations from src to the histogram.  The observations are consumed one
+// at a time so memory use is constant regardless of the length of the stream.
+func (h *ScoreHistogram) AddSource(src Source) {
+	for {
+		p, l, ok := src.Next()
+		if !ok {
+			return
+		}
+		h.Add(p, l)
+	}
+}
+
+// NewConfusionMatrixFromSource creates a new ConfusionMatrix, in the same way as NewConfusionMatrix, from all
+// the remaining observations of src.  Only the counts are retained so memory use is constant regardless of the
+// length of the stream.
+func NewConfusionMatrixFromSource(src Source, threshold float64) ConfusionMatrix {
+	b := NewConfusionMatrixBuilder(threshold)
+	b.AddSource(src)
+	return b.Matrix()
+}
+
+// NewPrecisionRecallCurveFromSource creates a new PrecisionRecallCurve, in the same way as
+// NewPrecisionRecallCurve, from all the remaining observations of src.  As the curve requires the predictions
+// to be ranked, the observations are read into memory before the curve is constructed.  For streams too large
+// to hold in memory, use NewExternalEvaluation to calculate average precision exactly or a ScoreHistogram to
+// approximate it instead.
+func NewPrecisionRecallCurveFromSource(src Source) PrecisionRecallCurve {
+	return NewPrecisionRecallCurve(collect(src))
+}
+
+// NewROCCurveFromSource creates a new ROCCurve, in the same way as NewROCCurve, from all the remaining
+// observations of src.  As the curve requires the predictions to be ranked, the observations are read into memory
+// before the curve is constructed.  For streams too large to hold in memory, use NewExternalEvaluation to calculate
+// the AUC exactly or a ScoreHistogram to approximate it instead.
+func NewROCCurveFromSource(src Source) ROCCurve {
+	return NewROCCurve(collect(src))
+}
@@ -0,0 +1,81 @@
+package datautils_test
+
+import (
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestSourceConstructors(t *testing.T) {
+	tests := []struct {
+		predictions []float64
+		labels      []float64
+		threshold   float64
+	}{
+		{
+			predictions: []float64{0.1, 0.4, 0.35, 0.8},
+			labels:      []float64{0, 0, 1, 1},
+			threshold:   0.3,
+		},
+		{
+			predictions: []float64{0.5, 0.2, 0.5, 0.7, 0.1, 0.5},
+			labels:      []float64{1, 0, 0, 1, 0, 1},
+			threshold:   0.5,
+		},
+		{
+			predictions: []float64{},
+			labels:      []float64{},
+			threshold:   0.5,
+		},
+	}
+
+	for ti, test := range tests {
+		expectedMatrix := datautils.NewConfusionMatrix(test.predictions, test.labels, test.threshold)
+		matrix := datautils.NewConfusionMatrixFromSource(datautils.NewSliceSource(test.predictions, test.labels), test.threshold)
+		if expectedMatrix != matrix {
+			t.Errorf("Test %d: Expected confusion matrix: %v but received %v", ti+1, expectedMatrix, matrix)
+		}
+
+		expectedPR := datautils.NewPrecisionRecallCurve(test.predictions, test.labels)
+		pr := datautils.NewPrecisionRecallCurveFromSource(datautils.NewSliceSource(test.predictions, test.labels))
+		if !floats.Equal(expectedPR.Precision, pr.Precision) || !floats.Equal(expectedPR.Recall, pr.Recall) ||
+			!floats.Equal(expectedPR.Thresholds, pr.Thresholds) {
+			t.Errorf("Test %d: Expected precision recall curve: %v but received %v", ti+1, expectedPR, pr)
+		}
+
+		expectedROC := datautils.NewROCCurve(test.predictions, test.labels)
+		roc := datautils.NewROCCurveFromSource(datautils.NewSliceSource(test.predictions, test.labels))
+		if !equalWithNaN(expectedROC.FPR, roc.FPR) || !equalWithNaN(expectedROC.TPR, roc.TPR) ||
+			!floats.Equal(expectedROC.Thresholds, roc.Thresholds) {
+			t.Errorf("Test %d: Expected ROC curve: %v but received %v", ti+1, expectedROC, roc)
+		}
+	}
+}
+
+func TestSourceFunc(t *testing.T) {
+	// callback generating predictions at the centre of each of 10 bins with every other observation positive
+	var i int
+	src := datautils.SourceFunc(func() (float64, float64, bool) {
+		if i == 10 {
+			return 0, 0, false
+		}
+		i++
+		return (float64(i) - 0.5) / 10, float64(i % 2), true
+	})
+
+	h := datautils.NewScoreHistogram(10, 0, 1)
+	h.AddSource(src)
+
+	expected := 0.4
+	if auc := h.AUC(); auc != expected {
+		t.Errorf("Expected AUC: %v but received %v", expected, auc)
+	}
+
+	// source is exhausted so no further observations should be added
+	b := datautils.NewConfusionMatrixBuilder(0.5)
+	b.AddSource(src)
+	if n := b.Matrix().Observations; n != 0 {
+		t.Errorf("Expected observations: %d but received %d", 0, n)
+	}
+}
@@ -0,0 +1,138 @@
+package datautils
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// TrainTestSplit randomly partitions the rows of the features matrix and their corresponding labels into
+// a training set and a test set.  testFraction is the proportion of rows (0 < testFraction < 1) to be placed
+// in the test set (rounded to the nearest row) and the seed is used to initialise the random number generator
+// so that splits are reproducible.  Rows retain their original relative ordering within each set.  As gonum
+// does not support empty matrices, TrainTestSplit panics if the rounded split would leave either set empty e.g. a
+// testFraction of 0.1 of 3 rows.
+func TrainTestSplit(features mat.Matrix, labels []float64, testFraction float64, seed int64) (trainX, testX *mat.Dense, trainY, testY []float64) {
+	validateSplit(features, labels, testFraction)
+	rnd := rand.New(rand.NewSource(seed))
+	train, test := splitIndices(allIndices(len(labels)), testFraction, rnd)
+	validateSplitSizes(train, test)
+	return selectRows(features, train), selectRows(features, test), selectValues(labels, train), selectValues(labels, test)
+}
+
+// StratifiedTrainTestSplit randomly partitions the rows of the features matrix and their corresponding labels
+// into a training set and a test set, in the same way as TrainTestSplit, but preserving the proportions of each
+// class (distinct label value) in both sets.  testFraction of the rows of each class (rounded to the nearest row)
+// are placed in the test set.  As with TrainTestSplit, StratifiedTrainTestSplit panics if the rounded split would
+// leave either set empty.
+func StratifiedTrainTestSplit(features mat.Matrix, labels []float64, testFraction float64, seed int64) (trainX, testX *mat.Dense, trainY, testY []float64) {
+	validateSplit(features, labels, testFraction)
+	rnd := rand.New(rand.NewSource(seed))
+
+	var train, test []int
+	for _, class := range classIndices(labels) {
+		tr, te := splitIndices(class, testFraction, rnd)
+		train = append(train, tr...)
+		test = append(test, te...)
+	}
+	validateSplitSizes(train, test)
+	sort.Ints(train)
+	sort.Ints(test)
+
+	return selectRows(features, train), selectRows(features, test), selectValues(labels, train), selectValues(labels, test)
+}
+
+func validateSplit(features mat.Matrix, labels []float64, testFraction float64) {
+	if r, _ := features.Dims(); r != len(labels) {
+		panic(ErrLengthMismatch)
+	}
+	if testFraction <= 0 || testFraction >= 1 {
+		panic("datautils: test fraction must be between 0 and 1")
+	}
+}
+
+func validateSplitSizes(train, test []int) {
+	if len(train) == 0 || len(test) == 0 {
+		panic("datautils: test fraction leaves the training or test set empty")
+	}
+}
+
+// allIndices returns the indices 0 to n-1.
+func allIndices(n int) []int {
+	ind := make([]int, n)
+	for i := range ind {
+		ind[i] = i
+	}
+	return ind
+}
+
+// splitIndices randomly partitions the specified indices into two sets with testFraction of the indices (rounded
+// to the nearest index) placed in the test set.  The indices within each set are returned in ascending order.
+func splitIndices(indices []int, testFraction float64, rnd *rand.Rand) (train, test []int) {
+	shuffled := make([]int, len(indices))
+	copy(shuffled, indices)
+	rnd.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	n := int(math.Round(testFraction * float64(len(shuffled))))
+	test, train = shuffled[:n], shuffled[n:]
+	sort.Ints(train)
+	sort.Ints(test)
+	return train, test
+}
+
+// classIndices groups the indices of the supplied labels by class (distinct label value).  The groups are
+// returned ordered by ascending label value so that iteration over them is deterministic.  As NaN is not equal to
+// itself and so cannot be used as a map key, NaN labels are grouped explicitly into a class of their own ordered
+// last.
+func classIndices(labels []float64) [][]int {
+	groups := make(map[float64][]int)
+	var nan []int
+	for i, v := range labels {
+		if math.IsNaN(v) {
+			nan = append(nan, i)
+			continue
+		}
+		groups[v] = append(groups[v], i)
+	}
+	classes := make([]float64, 0, len(groups))
+	for k := range groups {
+		classes = append(classes, k)
+	}
+	sort.Float64s(classes)
+
+	indices := make([][]int, len(classes), len(classes)+1)
+	for i, k := range classes {
+		indices[i] = groups[k]
+	}
+	if len(nan) > 0 {
+		indices = append(indices, nan)
+	}
+	return indices
+}
+
+// selectRows returns a new matrix containing the specified rows of m in the order specified.  If no rows are
+// specified then nil is returned as gonum does not support empty matrices.
+func selectRows(m mat.Matrix, rows []int) *mat.Dense {
+	if len(rows) == 0 {
+		return nil
+	}
+	_, c := m.Dims()
+	s := mat.NewDense(len(rows), c, nil)
+	for i, r := range rows {
+		for j := 0; j < c; j++ {
+			s.Set(i, j, m.At(r, j))
+		}
+	}
+	return s
+}
+
+// selectValues returns a new slice containing the values of s at the specified indices in the order specified.
+func selectValues[T Float](s []T, indices []int) []float64 {
+	v := make([]float64, len(indices))
+	for i, ind := range indices {
+		v[i] = float64(s[ind])
+	}
+	return v
+}
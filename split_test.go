@@ -0,0 +1,124 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func splitDataset() (*mat.Dense, []float64) {
+	labels := []float64{0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	features := mat.NewDense(len(labels), 2, nil)
+	for i := range labels {
+		// encode the row index in the features so rows can be matched to labels after splitting
+		features.Set(i, 0, float64(i))
+		features.Set(i, 1, labels[i])
+	}
+	return features, labels
+}
+
+func checkSplitRows(t *testing.T, name string, x *mat.Dense, y []float64) {
+	r, _ := x.Dims()
+	if r != len(y) {
+		t.Errorf("%s: Expected %d rows but received %d", name, len(y), r)
+	}
+	for i := range y {
+		if x.At(i, 1) != y[i] {
+			t.Errorf("%s: Row %d features do not match label %v", name, i, y[i])
+		}
+		if i > 0 && x.At(i, 0) <= x.At(i-1, 0) {
+			t.Errorf("%s: Expected rows in original order", name)
+		}
+	}
+}
+
+func TestTrainTestSplit(t *testing.T) {
+	features, labels := splitDataset()
+	trainX, testX, trainY, testY := datautils.TrainTestSplit(features, labels, 0.25, 1)
+
+	if len(trainY) != 15 || len(testY) != 5 {
+		t.Errorf("Expected 15/5 split but received %d/%d", len(trainY), len(testY))
+	}
+	checkSplitRows(t, "train", trainX, trainY)
+	checkSplitRows(t, "test", testX, testY)
+
+	_, againX, _, _ := datautils.TrainTestSplit(features, labels, 0.25, 1)
+	if !mat.Equal(testX, againX) {
+		t.Errorf("Expected identical splits for identical seeds")
+	}
+}
+
+func TestStratifiedTrainTestSplit(t *testing.T) {
+	features, labels := splitDataset()
+
+	for seed := int64(0); seed < 5; seed++ {
+		trainX, testX, trainY, testY := datautils.StratifiedTrainTestSplit(features, labels, 0.25, seed)
+
+		checkSplitRows(t, "train", trainX, trainY)
+		checkSplitRows(t, "test", testX, testY)
+
+		var trainPos, testPos int
+		for _, v := range trainY {
+			trainPos += int(v)
+		}
+		for _, v := range testY {
+			testPos += int(v)
+		}
+		if len(testY) != 5 || testPos != 3 || trainPos != 9 {
+			t.Errorf("Seed %d: Expected 3 of 5 test and 9 of 15 training labels positive but received %d of %d and %d of %d",
+				seed, testPos, len(testY), trainPos, len(trainY))
+		}
+	}
+}
+
+func TestStratifiedTrainTestSplitNaNLabels(t *testing.T) {
+	features, labels := splitDataset()
+	for _, i := range []int{0, 1, 8, 9} {
+		labels[i] = math.NaN()
+	}
+
+	trainX, testX, trainY, testY := datautils.StratifiedTrainTestSplit(features, labels, 0.5, 1)
+
+	if r, _ := trainX.Dims(); r != len(trainY) || len(trainY) != 10 {
+		t.Errorf("Expected 10 training rows and labels but received %d and %d", r, len(trainY))
+	}
+	var nan int
+	for i, v := range testY {
+		if math.IsNaN(v) {
+			nan++
+			if !math.IsNaN(labels[int(testX.At(i, 0))]) {
+				t.Errorf("Test row %d does not match its NaN label", i)
+			}
+		}
+	}
+	if len(testY) != 10 || nan != 2 {
+		t.Errorf("Expected 2 of 10 test labels NaN but received %d of %d", nan, len(testY))
+	}
+}
+
+func TestTrainTestSplitEmptySet(t *testing.T) {
+	features := mat.NewDense(3, 1, []float64{1, 2, 3})
+	labels := []float64{0, 1, 1}
+
+	tests := []struct {
+		split        func(mat.Matrix, []float64, float64, int64) (*mat.Dense, *mat.Dense, []float64, []float64)
+		testFraction float64
+	}{
+		{split: datautils.TrainTestSplit, testFraction: 0.1},
+		{split: datautils.TrainTestSplit, testFraction: 0.9},
+		{split: datautils.StratifiedTrainTestSplit, testFraction: 0.1},
+	}
+
+	for i, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Test %d: Expected panic but received none", i+1)
+				}
+			}()
+			test.split(features, labels, test.testFraction, 1)
+		}()
+	}
+}
@@ -0,0 +1,109 @@
+package datautils
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// RowsSource is a Source streaming (prediction, label) pairs from the rows of a database query result, allowing
+// metrics to be calculated directly from predictions stored in a database (e.g. Postgres) without intermediate
+// files.  The rows are consumed one at a time so memory use is constant regardless of the size of the result.  As
+// with other sources, the stream stops at the first error which may be checked with Err once the rows have been
+// consumed.  The caller remains responsible for closing the rows.
+type RowsSource struct {
+	rows   *sql.Rows
+	dest   []interface{}
+	pred   *sqlFloat
+	label  *sqlFloat
+	err    error
+	closed bool
+}
+
+// NewRowsSource creates a new RowsSource reading the predictions and labels from the columns of rows named
+// prediction and label respectively.  Other columns are ignored.  Values may be of any numeric or boolean (read as
+// 1 or 0) type, or strings containing numbers, and NULL values are read as NaN.  An error is returned if either
+// column is not present.
+func NewRowsSource(rows *sql.Rows, prediction, label string) (*RowsSource, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("datautils: failed to read columns: %w", err)
+	}
+	p, l := indexOf(columns, prediction), indexOf(columns, label)
+	if p == -1 {
+		return nil, fmt.Errorf("datautils: prediction column %q not found", prediction)
+	}
+	if l == -1 {
+		return nil, fmt.Errorf("datautils: label column %q not found", label)
+	}
+
+	src := &RowsSource{rows: rows, dest: make([]interface{}, len(columns)), pred: new(sqlFloat), label: new(sqlFloat)}
+	for i := range src.dest {
+		src.dest[i] = new(sql.RawBytes)
+	}
+	src.dest[p], src.dest[l] = src.pred, src.label
+	return src, nil
+}
+
+// Next returns the prediction and label of the next row.
+func (s *RowsSource) Next() (prediction, label float64, ok bool) {
+	if s.closed {
+		return 0, 0, false
+	}
+	if !s.rows.Next() {
+		s.closed = true
+		if err := s.rows.Err(); err != nil {
+			s.err = fmt.Errorf("datautils: failed to read rows: %w", err)
+		}
+		return 0, 0, false
+	}
+	if err := s.rows.Scan(s.dest...); err != nil {
+		s.closed = true
+		s.err = fmt.Errorf("datautils: failed to scan row: %w", err)
+		return 0, 0, false
+	}
+	return float64(*s.pred), float64(*s.label), true
+}
+
+// Err returns the first error encountered while reading the rows, if any.
+func (s *RowsSource) Err() error {
+	return s.err
+}
+
+// sqlFloat is a float64 implementing sql.Scanner that may be scanned from any numeric, boolean or string database
+// value with NULL scanned as NaN.
+type sqlFloat float64
+
+// Scan implements the sql.Scanner interface.
+func (f *sqlFloat) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*f = sqlFloat(math.NaN())
+	case float64:
+		*f = sqlFloat(v)
+	case int64:
+		*f = sqlFloat(v)
+	case bool:
+		*f = 0
+		if v {
+			*f = 1
+		}
+	case []byte:
+		return f.parse(string(v))
+	case string:
+		return f.parse(v)
+	default:
+		return fmt.Errorf("unsupported type %T", src)
+	}
+	return nil
+}
+
+func (f *sqlFloat) parse(s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*f = sqlFloat(v)
+	return nil
+}
@@ -0,0 +1,137 @@
+package datautils_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+// predictionsDriver is a minimal database driver returning a fixed query result of predictions.  Queries for
+// "broken" fail after the first row.
+type predictionsDriver struct{}
+
+func (predictionsDriver) Open(name string) (driver.Conn, error) { return predictionsConn{}, nil }
+
+type predictionsConn struct{}
+
+func (predictionsConn) Prepare(query string) (driver.Stmt, error) { return predictionsStmt(query), nil }
+func (predictionsConn) Close() error                              { return nil }
+func (predictionsConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type predictionsStmt string
+
+func (predictionsStmt) Close() error  { return nil }
+func (predictionsStmt) NumInput() int { return 0 }
+func (predictionsStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s predictionsStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &predictionsRows{broken: s == "broken", values: [][]driver.Value{
+		{"a", 0.9, true},
+		{"b", int64(0), false},
+		{"c", []byte("0.35"), int64(1)},
+		{"d", nil, nil},
+	}}, nil
+}
+
+type predictionsRows struct {
+	broken bool
+	values [][]driver.Value
+	i      int
+}
+
+func (r *predictionsRows) Columns() []string { return []string{"id", "score", "label"} }
+func (r *predictionsRows) Close() error      { return nil }
+func (r *predictionsRows) Next(dest []driver.Value) error {
+	if r.broken && r.i == 1 {
+		return errors.New("connection lost")
+	}
+	if r.i == len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.i])
+	r.i++
+	return nil
+}
+
+func init() {
+	sql.Register("datautils-predictions", predictionsDriver{})
+}
+
+func query(t *testing.T, q string) *sql.Rows {
+	db, err := sql.Open("datautils-predictions", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	rows, err := db.Query(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { rows.Close() })
+	return rows
+}
+
+func TestRowsSource(t *testing.T) {
+	src, err := datautils.NewRowsSource(query(t, "predictions"), "score", "label")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := datautils.NewConfusionMatrixBuilder(0.5)
+	b.AddSource(src)
+	if err := src.Err(); err != nil {
+		t.Fatal(err)
+	}
+	expected := datautils.NewConfusionMatrix([]float64{0.9, 0, 0.35, math.NaN()}, []float64{1, 0, 1, math.NaN()}, 0.5)
+	if m := b.Matrix(); m != expected {
+		t.Errorf("Expected confusion matrix: %v but received %v", expected, m)
+	}
+}
+
+func TestRowsSourceErrors(t *testing.T) {
+	if _, err := datautils.NewRowsSource(query(t, "predictions"), "prediction", "label"); err == nil {
+		t.Errorf("Expected error for missing prediction column")
+	}
+	if _, err := datautils.NewRowsSource(query(t, "predictions"), "score", "relevance"); err == nil {
+		t.Errorf("Expected error for missing label column")
+	}
+
+	// the id column contains strings which cannot be read as predictions
+	src, err := datautils.NewRowsSource(query(t, "predictions"), "id", "label")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := src.Next(); ok || src.Err() == nil {
+		t.Errorf("Expected error scanning non-numeric prediction")
+	}
+
+	src, err = datautils.NewRowsSource(query(t, "broken"), "score", "label")
+	if err != nil {
+		t.Fatal(err)
+	}
+	predictions, _ := collectSource(src)
+	if len(predictions) != 1 || src.Err() == nil {
+		t.Errorf("Expected the stream to stop with an error after 1 row but received %d rows and error %v", len(predictions), src.Err())
+	}
+	if _, _, ok := src.Next(); ok {
+		t.Errorf("Expected the stream to remain stopped after an error")
+	}
+}
+
+// collectSource reads all the remaining pairs from src.
+func collectSource(src datautils.Source) (predictions, labels []float64) {
+	for {
+		p, l, ok := src.Next()
+		if !ok {
+			return predictions, labels
+		}
+		predictions = append(predictions, p)
+		labels = append(labels, l)
+	}
+}
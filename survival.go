@@ -0,0 +1,135 @@
+package datautils
+
+import (
+	"math"
+	"sort"
+)
+
+// validateSurvival checks that the risk scores, event times and event indicators of a survival model evaluation
+// are of equal length.
+func validateSurvival(risks, times []float64, events []bool) {
+	if len(risks) != len(times) || len(times) != len(events) {
+		panic(ErrLengthMismatch)
+	}
+}
+
+// comparable reports whether observations i and j form a comparable pair for the concordance index with i
+// experiencing the event first.  Pairs are comparable if the event of i is observed and occurs before the time of
+// j or, where the times are tied, j is censored (and so known to have survived at least as long as i).
+func comparable(times []float64, events []bool, i, j int) bool {
+	if !events[i] {
+		return false
+	}
+	return times[i] < times[j] || times[i] == times[j] && !events[j]
+}
+
+// concordance returns 1 if the risk scores of the comparable pair i, j are concordant (i, experiencing the event
+// first, has the higher risk), 0.5 if the risk scores are tied and 0 otherwise.
+func concordance(risks []float64, i, j int) float64 {
+	switch {
+	case risks[i] > risks[j]:
+		return 1
+	case risks[i] == risks[j]:
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// HarrellsCIndex calculates Harrell's concordance index (C-index) for evaluating a survival model.  risks contains
+// the risk score predicted by the model for each observation, where higher scores indicate a shorter expected time
+// to the event, times contains the time of each observation's event or censoring and events indicates whether each
+// observation's event was observed (true) or censored (false).  The C-index is the proportion of comparable pairs
+// of observations, where the observation with the earlier time experienced the event, for which the model assigns
+// the higher risk to the observation experiencing the event first.  Pairs with tied risk scores count as half
+// concordant.  As with AUC, which it generalises to censored data, 1 indicates perfect ranking and 0.5 random
+// ranking.  If there are no comparable pairs, NaN is returned.
+func HarrellsCIndex(risks, times []float64, events []bool) float64 {
+	validateSurvival(risks, times, events)
+
+	var concordant, pairs float64
+	for i := range times {
+		for j := range times {
+			if i != j && comparable(times, events, i, j) {
+				concordant += concordance(risks, i, j)
+				pairs++
+			}
+		}
+	}
+	if pairs == 0 {
+		return math.NaN()
+	}
+	return concordant / pairs
+}
+
+// censoringSurvival returns a function evaluating the Kaplan-Meier estimate of the censoring distribution's
+// survival function, G, immediately before time t i.e. the probability of remaining uncensored beyond all times
+// earlier than t.  The roles of events and censoring are reversed so that censored observations are the events of
+// the estimate.
+func censoringSurvival(times []float64, events []bool) func(t float64) float64 {
+	order := allIndices(len(times))
+	sort.Slice(order, func(a, b int) bool { return times[order[a]] < times[order[b]] })
+
+	var steps, survival []float64
+	g := 1.0
+	for k := 0; k < len(order); {
+		t := times[order[k]]
+		atRisk := len(order) - k
+		var censored int
+		for ; k < len(order) && times[order[k]] == t; k++ {
+			if !events[order[k]] {
+				censored++
+			}
+		}
+		if censored > 0 {
+			g *= 1 - float64(censored)/float64(atRisk)
+			steps = append(steps, t)
+			survival = append(survival, g)
+		}
+	}
+
+	return func(t float64) float64 {
+		// number of censoring times strictly before t
+		k := sort.SearchFloat64s(steps, t)
+		if k == 0 {
+			return 1
+		}
+		return survival[k-1]
+	}
+}
+
+// UnosCIndex calculates Uno's concordance index for evaluating a survival model (see HarrellsCIndex for a
+// description of the arguments).  Harrell's C-index depends on the censoring distribution of the data, becoming
+// biased when censoring is heavy.  Uno's C-index corrects for this by weighting each comparable pair by the inverse
+// of the squared probability of the earlier observation remaining uncensored at its event time, estimated from the
+// censoring of the supplied observations using the Kaplan-Meier estimator.  Only pairs whose earlier event occurs
+// before the truncation time tau are considered; tau should be chosen such that a reasonable number of
+// observations remain uncensored beyond it.  Use math.Inf(1) to consider all pairs.  If there are no comparable
+// pairs, NaN is returned.
+func UnosCIndex(risks, times []float64, events []bool, tau float64) float64 {
+	validateSurvival(risks, times, events)
+
+	g := censoringSurvival(times, events)
+	var concordant, pairs float64
+	for i := range times {
+		if times[i] >= tau || !events[i] {
+			continue
+		}
+		gi := g(times[i])
+		if gi == 0 {
+			continue
+		}
+		w := 1 / (gi * gi)
+		for j := range times {
+			// Uno's estimator only compares observations with strictly later times
+			if i != j && times[i] < times[j] {
+				concordant += w * concordance(risks, i, j)
+				pairs += w
+			}
+		}
+	}
+	if pairs == 0 {
+		return math.NaN()
+	}
+	return concordant / pairs
+}
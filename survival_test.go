@@ -0,0 +1,73 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestHarrellsCIndex(t *testing.T) {
+	tests := []struct {
+		risks  []float64
+		times  []float64
+		events []bool
+		c      float64
+	}{
+		{
+			risks:  []float64{5, 4, 3, 1, 2},
+			times:  []float64{1, 2, 3, 4, 5},
+			events: []bool{true, false, true, true, false},
+			c:      6.0 / 7.0,
+		},
+		{
+			// tied risks count half and tied times are comparable only if the later observation is censored
+			risks:  []float64{2, 2, 1},
+			times:  []float64{1, 1, 1},
+			events: []bool{true, false, true},
+			c:      (0.5 + 0) / 2,
+		},
+		{
+			risks:  []float64{1, 2},
+			times:  []float64{1, 2},
+			events: []bool{false, false},
+			c:      math.NaN(),
+		},
+	}
+
+	for i, test := range tests {
+		if c := datautils.HarrellsCIndex(test.risks, test.times, test.events); !equalWithNaN([]float64{c}, []float64{test.c}) {
+			t.Errorf("Test %d: Expected C-index: %v but received %v", i+1, test.c, c)
+		}
+	}
+}
+
+func TestUnosCIndex(t *testing.T) {
+	risks := []float64{5, 4, 3, 1, 2}
+	times := []float64{1, 2, 3, 4, 5}
+	events := []bool{true, false, true, true, false}
+
+	// censoring at time 2 reduces the censoring survival to 0.75 so later events are weighted by 1 / 0.75^2
+	w := 1 / (0.75 * 0.75)
+
+	tests := []struct {
+		tau float64
+		c   float64
+	}{
+		{tau: math.Inf(1), c: (4 + 2*w) / (4 + 3*w)},
+		{tau: 3.5, c: 1},
+		{tau: 1, c: math.NaN()},
+	}
+
+	for i, test := range tests {
+		if c := datautils.UnosCIndex(risks, times, events, test.tau); !equalWithNaN([]float64{c}, []float64{test.c}) {
+			t.Errorf("Test %d: Expected C-index: %v but received %v", i+1, test.c, c)
+		}
+	}
+
+	// without censoring Uno's C-index is equal to Harrell's
+	uncensored := []bool{true, true, true, true, true}
+	if u, h := datautils.UnosCIndex(risks, times, uncensored, math.Inf(1)), datautils.HarrellsCIndex(risks, times, uncensored); math.Abs(u-h) > 1e-12 {
+		t.Errorf("Expected Uno's C-index %v to equal Harrell's %v without censoring", u, h)
+	}
+}
@@ -0,0 +1,257 @@
+package datautils
+
+import (
+	"math"
+	"strings"
+)
+
+// ngramCounts returns the number of occurrences of each n-gram of the tokens keyed by the n-gram's tokens joined
+// by a separator that cannot occur within text.
+func ngramCounts(tokens []string, n int) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i+n <= len(tokens); i++ {
+		counts[strings.Join(tokens[i:i+n], "\x00")]++
+	}
+	return counts
+}
+
+// BLEUSmoothing specifies how BLEU is smoothed when higher order n-grams have no matches which would otherwise
+// give a score of 0.  Smoothing is mostly of use for sentence level BLEU as corpus level n-gram counts are rarely
+// 0.  The methods are those described by Chen & Cherry (2014).
+type BLEUSmoothing int
+
+const (
+	// NoSmoothing applies no smoothing so any n-gram order without matches gives a score of 0
+	NoSmoothing BLEUSmoothing = iota
+
+	// EpsilonSmoothing replaces a match count of 0 with 0.1 (Chen & Cherry method 1)
+	EpsilonSmoothing
+
+	// AddOneSmoothing adds 1 to the match and total counts of n-grams of order 2 and above (Chen & Cherry
+	// method 2, Lin & Och 2004)
+	AddOneSmoothing
+
+	// ExponentialSmoothing replaces the k'th match count of 0 with 1 / 2^k (Chen & Cherry method 3, as used by
+	// the NIST mteval script)
+	ExponentialSmoothing
+)
+
+// CorpusBLEU calculates the BLEU score (Papineni et al., 2002) of a corpus of candidate translations (or other
+// generated text), each a slice of tokens, against one or more reference translations of each candidate.  BLEU is
+// the geometric mean of the modified n-gram precisions, for n-grams of order 1 to maxN (4 is typical), multiplied
+// by a brevity penalty for candidates shorter than their references.  The n-gram precisions are calculated over the
+// whole corpus, clipping the count of each candidate n-gram to the maximum count within any one of its references,
+// and the brevity penalty uses the reference length closest to each candidate's length (the shorter on ties).
+// The score ranges from 0 to 1.  If there are no candidate tokens, 0 is returned.
+func CorpusBLEU(candidates [][]string, references [][][]string, maxN int, smoothing BLEUSmoothing) float64 {
+	if len(candidates) != len(references) {
+		panic(ErrLengthMismatch)
+	}
+	if maxN < 1 {
+		panic("datautils: maximum n-gram order must be at least 1")
+	}
+
+	matches := make([]float64, maxN)
+	totals := make([]float64, maxN)
+	var candidateLength, referenceLength int
+	for i, candidate := range candidates {
+		candidateLength += len(candidate)
+		referenceLength += closestLength(len(candidate), references[i])
+
+		for n := 1; n <= maxN; n++ {
+			max := make(map[string]int)
+			for _, ref := range references[i] {
+				for g, c := range ngramCounts(ref, n) {
+					if c > max[g] {
+						max[g] = c
+					}
+				}
+			}
+			for g, c := range ngramCounts(candidate, n) {
+				if c > max[g] {
+					c = max[g]
+				}
+				matches[n-1] += float64(c)
+			}
+			if len(candidate) >= n {
+				totals[n-1] += float64(len(candidate) - n + 1)
+			}
+		}
+	}
+	if candidateLength == 0 {
+		return 0
+	}
+
+	var logPrecision float64
+	k := 1
+	for n := range matches {
+		m, t := matches[n], totals[n]
+		switch {
+		case smoothing == AddOneSmoothing && n > 0:
+			m, t = m+1, t+1
+		case m == 0 && smoothing == EpsilonSmoothing:
+			m = 0.1
+		case m == 0 && smoothing == ExponentialSmoothing:
+			k *= 2
+			m = 1 / float64(k)
+		}
+		if m == 0 || t == 0 {
+			return 0
+		}
+		logPrecision += math.Log(m/t) / float64(maxN)
+	}
+
+	brevity := 1.0
+	if candidateLength < referenceLength {
+		brevity = math.Exp(1 - float64(referenceLength)/float64(candidateLength))
+	}
+	return brevity * math.Exp(logPrecision)
+}
+
+// SentenceBLEU calculates the BLEU score of a single candidate against its references (see CorpusBLEU).  Sentence
+// level BLEU is noisy and generally requires smoothing.  Scores of a corpus should be calculated with CorpusBLEU
+// rather than by averaging sentence level scores.
+func SentenceBLEU(candidate []string, references [][]string, maxN int, smoothing BLEUSmoothing) float64 {
+	return CorpusBLEU([][]string{candidate}, [][][]string{references}, maxN, smoothing)
+}
+
+// closestLength returns the length of the reference closest in length to the candidate length, preferring the
+// shorter reference on ties.
+func closestLength(length int, references [][]string) int {
+	closest := -1
+	for _, ref := range references {
+		d, c := abs(len(ref)-length), abs(closest-length)
+		if closest == -1 || d < c || d == c && len(ref) < closest {
+			closest = len(ref)
+		}
+	}
+	if closest == -1 {
+		return 0
+	}
+	return closest
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// ROUGEScore contains the precision, recall and F1 score of a ROUGE measure.
+type ROUGEScore struct {
+	Precision, Recall, F1 float64
+}
+
+func newROUGEScore(overlap float64, candidateLength, referenceLength int) ROUGEScore {
+	var s ROUGEScore
+	if candidateLength > 0 {
+		s.Precision = overlap / float64(candidateLength)
+	}
+	if referenceLength > 0 {
+		s.Recall = overlap / float64(referenceLength)
+	}
+	if s.Precision+s.Recall > 0 {
+		s.F1 = f1(s.Precision, s.Recall)
+	}
+	return s
+}
+
+// ROUGEN calculates ROUGE-N (Lin, 2004) of a candidate summary (or other generated text) against a reference, both
+// slices of tokens, from the overlap of their n-grams e.g. ROUGE-1 for unigrams and ROUGE-2 for bigrams.  Each
+// n-gram of the candidate matches at most as many times as it occurs within the reference.  Recall is the
+// proportion of the reference's n-grams matched and precision the proportion of the candidate's n-grams matched.
+func ROUGEN(candidate, reference []string, n int) ROUGEScore {
+	if n < 1 {
+		panic("datautils: n-gram order must be at least 1")
+	}
+	ref := ngramCounts(reference, n)
+	var overlap float64
+	for g, c := range ngramCounts(candidate, n) {
+		if c > ref[g] {
+			c = ref[g]
+		}
+		overlap += float64(c)
+	}
+	return newROUGEScore(overlap, maxInt(len(candidate)-n+1, 0), maxInt(len(reference)-n+1, 0))
+}
+
+// ROUGEL calculates ROUGE-L (Lin, 2004) of a candidate summary against a reference, both slices of tokens, from
+// the length of their longest common subsequence (LCS) i.e. the longest sequence of tokens occurring in both in
+// the same order but not necessarily contiguously.  Recall is the LCS length relative to the length of the
+// reference and precision relative to the length of the candidate.
+func ROUGEL(candidate, reference []string) ROUGEScore {
+	// dynamic programming over the rows of the LCS table retaining only the previous row
+	prev := make([]int, len(reference)+1)
+	curr := make([]int, len(reference)+1)
+	for i := range candidate {
+		for j := range reference {
+			switch {
+			case candidate[i] == reference[j]:
+				curr[j+1] = prev[j] + 1
+			case prev[j+1] > curr[j]:
+				curr[j+1] = prev[j+1]
+			default:
+				curr[j+1] = curr[j]
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return newROUGEScore(float64(prev[len(reference)]), len(candidate), len(reference))
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// editDistance returns the Levenshtein distance between the token sequences a and b i.e. the minimum number of
+// token substitutions, insertions and deletions required to transform a into b.
+func editDistance(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range a {
+		curr[0] = i + 1
+		for j := range b {
+			cost := 1
+			if a[i] == b[j] {
+				cost = 0
+			}
+			curr[j+1] = minInt(prev[j]+cost, minInt(prev[j+1]+1, curr[j]+1))
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// WordErrorRate calculates the word error rate (WER) of a corpus of candidate transcriptions (or other generated
+// text), each a slice of tokens (words), against their corresponding reference transcriptions.  WER is the total
+// number of word substitutions, deletions and insertions required to transform the candidates into their
+// references divided by the total number of reference words.  0 indicates a perfect match and, as insertions are
+// counted, WER may exceed 1.  If the references contain no words, NaN is returned.
+func WordErrorRate(candidates, references [][]string) float64 {
+	if len(candidates) != len(references) {
+		panic(ErrLengthMismatch)
+	}
+	var errors, words int
+	for i, c := range candidates {
+		errors += editDistance(c, references[i])
+		words += len(references[i])
+	}
+	if words == 0 {
+		return math.NaN()
+	}
+	return float64(errors) / float64(words)
+}
@@ -0,0 +1,90 @@
+package datautils_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestCorpusBLEU(t *testing.T) {
+	tok := strings.Fields
+
+	tests := []struct {
+		candidate  string
+		references []string
+		maxN       int
+		smoothing  datautils.BLEUSmoothing
+		bleu       float64
+	}{
+		{candidate: "the cat sat on the mat", references: []string{"the cat sat on the mat"}, maxN: 4, bleu: 1},
+		{candidate: "the the the the the the the", references: []string{"the cat is on the mat", "there is a cat on the mat"}, maxN: 1, bleu: 2.0 / 7.0},
+		{candidate: "the cat", references: []string{"the cat sat on the mat"}, maxN: 2, bleu: math.Exp(-2)},
+		{candidate: "the cat", references: []string{"the cat sat on the mat", "the cat sat"}, maxN: 2, bleu: math.Exp(1 - 3.0/2.0)},
+		{candidate: "the cat sat", references: []string{"the cat ran"}, maxN: 3, smoothing: datautils.NoSmoothing, bleu: 0},
+		{candidate: "the cat sat", references: []string{"the cat ran"}, maxN: 3, smoothing: datautils.EpsilonSmoothing, bleu: math.Cbrt(2.0 / 3.0 * 1.0 / 2.0 * 0.1)},
+		{candidate: "the cat sat", references: []string{"the cat ran"}, maxN: 3, smoothing: datautils.AddOneSmoothing, bleu: math.Cbrt(2.0 / 3.0 * 2.0 / 3.0 * 1.0 / 2.0)},
+		{candidate: "the cat sat", references: []string{"the cat ran"}, maxN: 3, smoothing: datautils.ExponentialSmoothing, bleu: math.Cbrt(2.0 / 3.0 * 1.0 / 2.0 * 1.0 / 2.0)},
+		{candidate: "", references: []string{"the cat"}, maxN: 1, bleu: 0},
+	}
+
+	for i, test := range tests {
+		refs := make([][]string, len(test.references))
+		for j, r := range test.references {
+			refs[j] = tok(r)
+		}
+		if bleu := datautils.SentenceBLEU(tok(test.candidate), refs, test.maxN, test.smoothing); math.Abs(bleu-test.bleu) > 1e-12 {
+			t.Errorf("Test %d: Expected BLEU: %v but received %v", i+1, test.bleu, bleu)
+		}
+	}
+
+	// corpus BLEU pools n-gram counts rather than averaging sentence scores
+	candidates := [][]string{tok("the cat"), tok("a dog ran")}
+	references := [][][]string{{tok("the cat")}, {tok("a dog sat")}}
+	expected := math.Sqrt(4.0 / 5.0 * 2.0 / 3.0)
+	if bleu := datautils.CorpusBLEU(candidates, references, 2, datautils.NoSmoothing); math.Abs(bleu-expected) > 1e-12 {
+		t.Errorf("Expected corpus BLEU: %v but received %v", expected, bleu)
+	}
+}
+
+func TestROUGE(t *testing.T) {
+	candidate := strings.Fields("the cat was found under the bed")
+	reference := strings.Fields("the cat was under the bed")
+
+	tests := []struct {
+		name  string
+		score datautils.ROUGEScore
+		p, r  float64
+	}{
+		{name: "ROUGE-1", score: datautils.ROUGEN(candidate, reference, 1), p: 6.0 / 7.0, r: 1},
+		{name: "ROUGE-2", score: datautils.ROUGEN(candidate, reference, 2), p: 4.0 / 6.0, r: 4.0 / 5.0},
+		{name: "ROUGE-L", score: datautils.ROUGEL(candidate, reference), p: 6.0 / 7.0, r: 1},
+		{name: "ROUGE-L empty", score: datautils.ROUGEL(nil, reference), p: 0, r: 0},
+		{name: "ROUGE-3 short", score: datautils.ROUGEN(candidate[:2], reference, 3), p: 0, r: 0},
+	}
+
+	for _, test := range tests {
+		f1 := 0.0
+		if test.p+test.r > 0 {
+			f1 = 2 * test.p * test.r / (test.p + test.r)
+		}
+		s := test.score
+		if math.Abs(s.Precision-test.p) > 1e-12 || math.Abs(s.Recall-test.r) > 1e-12 || math.Abs(s.F1-f1) > 1e-12 {
+			t.Errorf("%s: Expected precision %v, recall %v and F1 %v but received %+v", test.name, test.p, test.r, f1, s)
+		}
+	}
+}
+
+func TestWordErrorRate(t *testing.T) {
+	candidates := [][]string{strings.Fields("the cat sit on mat"), strings.Fields("a b")}
+	references := [][]string{strings.Fields("the cat sat on the mat"), {}}
+
+	// 1 substitution and 1 deletion in the first and 2 insertions in the second
+	if wer := datautils.WordErrorRate(candidates, references); math.Abs(wer-4.0/6.0) > 1e-12 {
+		t.Errorf("Expected WER: %v but received %v", 4.0/6.0, wer)
+	}
+	if wer := datautils.WordErrorRate(candidates[1:], references[1:]); !math.IsNaN(wer) {
+		t.Errorf("Expected NaN WER without reference words but received %v", wer)
+	}
+}
@@ -0,0 +1,138 @@
+package datautils
+
+import (
+	"fmt"
+	"image/color"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Theme is a house style applied to all the plots created by the package (e.g. PR, ROC, calibration and heatmap
+// plots) so that the figures of a report share a consistent style without editing each plot after it is created.
+// Zero valued fields leave the corresponding gonum/plot defaults unchanged.
+type Theme struct {
+	// Primary is the colour of the series of single series plots (e.g. the curve of a ROC curve plot) and the
+	// positive class of class distribution plots
+	Primary color.Color
+
+	// Secondary is the colour of the contrasting series of two series plots e.g. the negative class of class
+	// distribution plots and the training scores of validation curves
+	Secondary color.Color
+
+	// Colors are the colours of the series of plots with many series (e.g. ROC curves of several models) in order.
+	// If empty, the plotutil.DefaultColors are used
+	Colors []color.Color
+
+	// Background is the background colour of the plots
+	Background color.Color
+
+	// Font is the name of the font used for all text e.g. "Helvetica"
+	Font string
+
+	// TitleSize, LabelSize, TickSize and LegendSize are the font sizes of the title, axis labels, axis tick labels
+	// and legend respectively.  Heatmaps keep their own tick label sizes (see WithLabelFontSize)
+	TitleSize, LabelSize, TickSize, LegendSize vg.Length
+
+	// Grid adds horizontal and vertical grid lines behind the plotted data
+	Grid bool
+
+	// TitleFormat is a format string, containing a single %s verb, used to format the title of each plot e.g.
+	// "ranker-v2: %s".  If empty, titles are left unchanged
+	TitleFormat string
+}
+
+// DefaultTheme is the default style of plots using the package's colours and gonum/plot's default fonts.
+var DefaultTheme = Theme{
+	Primary:   color.RGBA{R: 255, B: 128, A: 255},
+	Secondary: color.RGBA{R: 64, G: 96, B: 255, A: 255},
+}
+
+// PlotTheme is the theme applied to all plots as they are created.  Set it, typically once at the start of a
+// program, to give all figures a house style e.g.
+//
+//	theme := datautils.DefaultTheme
+//	theme.Font, theme.Grid = "Helvetica", true
+//	datautils.PlotTheme = theme
+//
+// PlotTheme should not be modified while plots are being created concurrently.
+var PlotTheme = DefaultTheme
+
+// color returns the colour of the ith series of a plot with many series.
+func (t Theme) color(i int) color.Color {
+	if len(t.Colors) == 0 {
+		return plotutil.Color(i)
+	}
+	return t.Colors[i%len(t.Colors)]
+}
+
+// title formats the title of a plot using the TitleFormat.
+func (t Theme) title(s string) string {
+	if t.TitleFormat == "" {
+		return s
+	}
+	return fmt.Sprintf(t.TitleFormat, s)
+}
+
+// apply applies the theme's background, fonts and grid to a newly created plot.
+func (t Theme) apply(p *plot.Plot) error {
+	if t.Background != nil {
+		p.BackgroundColor = t.Background
+	}
+
+	styles := []struct {
+		style *draw.TextStyle
+		size  vg.Length
+	}{
+		{style: &p.Title.TextStyle, size: t.TitleSize},
+		{style: &p.X.Label.TextStyle, size: t.LabelSize},
+		{style: &p.Y.Label.TextStyle, size: t.LabelSize},
+		{style: &p.X.Tick.Label, size: t.TickSize},
+		{style: &p.Y.Tick.Label, size: t.TickSize},
+		{style: &p.Legend.TextStyle, size: t.LegendSize},
+	}
+	for _, s := range styles {
+		if t.Font == "" && s.size == 0 {
+			continue
+		}
+		name, size := t.Font, s.size
+		if name == "" {
+			name = s.style.Font.Name()
+		}
+		if size == 0 {
+			size = s.style.Font.Size
+		}
+		font, err := vg.MakeFont(name, size)
+		if err != nil {
+			return fmt.Errorf("datautils: theme font: %w", err)
+		}
+		s.style.Font = font
+	}
+
+	if t.Grid {
+		p.Add(plotter.NewGrid())
+	}
+	return nil
+}
+
+// newPlot creates a new plot styled with the PlotTheme.
+func newPlot() (*plot.Plot, error) {
+	p, err := plot.New()
+	if err != nil {
+		return nil, err
+	}
+	if err := PlotTheme.apply(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// translucent returns the colour c with the specified alpha e.g. for shading confidence bands.
+func translucent(c color.Color, alpha uint8) color.Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	n.A = alpha
+	return n
+}
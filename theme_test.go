@@ -0,0 +1,54 @@
+package datautils_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestPlotTheme(t *testing.T) {
+	defer func(theme datautils.Theme) { datautils.PlotTheme = theme }(datautils.PlotTheme)
+
+	background := color.RGBA{R: 250, G: 250, B: 240, A: 255}
+	theme := datautils.DefaultTheme
+	theme.Background = background
+	theme.Font = "Helvetica"
+	theme.TitleSize, theme.TickSize = 16, 8
+	theme.Grid = true
+	theme.TitleFormat = "ranker-v2: %s"
+	datautils.PlotTheme = theme
+
+	predictions := []float64{0.1, 0.4, 0.35, 0.8}
+	labels := []float64{0, 0, 1, 1}
+	p := datautils.NewROCCurve(predictions, labels).Plot()
+
+	if expected := "ranker-v2: ROC Curve, AUC=0.750000"; p.Title.Text != expected {
+		t.Errorf("Expected title: %q but received %q", expected, p.Title.Text)
+	}
+	if p.BackgroundColor != background {
+		t.Errorf("Expected background: %v but received %v", background, p.BackgroundColor)
+	}
+	if p.Title.Font.Name() != "Helvetica" || p.Title.Font.Size != 16 {
+		t.Errorf("Expected 16pt Helvetica title but received %s %v", p.Title.Font.Name(), p.Title.Font.Size)
+	}
+	if p.X.Tick.Label.Font.Size != 8 || p.Y.Tick.Label.Font.Size != 8 {
+		t.Errorf("Expected 8pt tick labels but received %v and %v", p.X.Tick.Label.Font.Size, p.Y.Tick.Label.Font.Size)
+	}
+	if p.X.Label.Font.Name() != "Helvetica" || p.Legend.Font.Name() != "Helvetica" {
+		t.Errorf("Expected Helvetica axis label and legend but received %s and %s", p.X.Label.Font.Name(), p.Legend.Font.Name())
+	}
+
+	// plots with many series use the theme's colours
+	datautils.PlotTheme.Colors = []color.Color{color.Black}
+	curves := map[string]datautils.ROCCurve{"a": datautils.NewROCCurve(predictions, labels)}
+	if p := datautils.PlotROCCurves(curves); p.Title.Text != "ranker-v2: ROC Curves" {
+		t.Errorf("Unexpected title: %q", p.Title.Text)
+	}
+
+	datautils.PlotTheme = datautils.DefaultTheme
+	if p := datautils.NewROCCurve(predictions, labels).Plot(); p.Title.Text != "ROC Curve, AUC=0.750000" || p.Title.Font.Size == vg.Length(16) {
+		t.Errorf("Expected default style but received title %q", p.Title.Text)
+	}
+}
@@ -0,0 +1,148 @@
+package datautils
+
+import (
+	"math"
+	"sort"
+)
+
+// thresholdSweep calculates the confusion matrix for every candidate decision threshold in a single pass
+// over the predictions sorted in descending order.  The candidate thresholds are +Inf (all observations
+// predicted negative) followed by each distinct prediction value in descending order.  As with
+// NewConfusionMatrix, predictions greater than or equal to the threshold are predicted positive and
+// labels of 1 are considered positive.  NaN predictions are never greater than or equal to a threshold so are
+// ordered last and never predicted positive (see HandleNonFinite to treat them differently).
+func thresholdSweep[P, L Float](predictions []P, labels []L) ([]float64, []ConfusionMatrix) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+
+	// sorted ascending with NaN first so that NaN predictions are swept last
+	sorted, ind := sortedPredictions(predictions)
+	return sweepSorted(sorted, ind, labels)
+}
+
+// sweepSorted performs the sweep of thresholdSweep over predictions already sorted into ascending order along with
+// their original indices (as returned by sortedPredictions) and the corresponding labels.  Any NaN predictions
+// must be sorted first, as they are by sortedPredictions.
+func sweepSorted[L Float](sorted []float64, ind []int, labels []L) ([]float64, []ConfusionMatrix) {
+	var matrix ConfusionMatrix
+	for _, v := range labels {
+		matrix.add(false, float64(v), 1)
+	}
+
+	thresholds := []float64{math.Inf(1)}
+	matrices := []ConfusionMatrix{matrix}
+
+	for k := len(sorted) - 1; k >= 0 && !math.IsNaN(sorted[k]); k-- {
+		matrix.predictPositive(float64(labels[ind[k]]))
+		// only record a threshold once all observations with the same prediction have been included
+		if k > 0 && sorted[k-1] == sorted[k] {
+			continue
+		}
+		thresholds = append(thresholds, sorted[k])
+		matrices = append(matrices, matrix)
+	}
+
+	return thresholds, matrices
+}
+
+// matricesAt returns the confusion matrix of the sweep (see thresholdSweep) at each of the specified thresholds,
+// in the same order as thresholds.  The matrix at a threshold is that of the lowest candidate threshold greater
+// than or equal to it as this predicts the same observations positive.
+func matricesAt(candidates []float64, sweep []ConfusionMatrix, thresholds []float64) []ConfusionMatrix {
+	matrices := make([]ConfusionMatrix, len(thresholds))
+	for i, t := range thresholds {
+		if math.IsNaN(t) {
+			// nothing is greater than or equal to NaN
+			matrices[i] = sweep[0]
+			continue
+		}
+		// candidates are in descending order starting with +Inf so at least one is greater than or equal to t
+		j := sort.Search(len(candidates), func(j int) bool { return candidates[j] < t }) - 1
+		matrices[i] = sweep[j]
+	}
+	return matrices
+}
+
+// OptimalThreshold finds the decision threshold that maximises the supplied objective function, returning the
+// threshold along with the resulting ConfusionMatrix.  Candidate thresholds are each distinct prediction value
+// along with +Inf (predicting all observations as negative).  Where several thresholds score equally, the highest
+// is returned.  Thresholds for which the objective evaluates to NaN (e.g. precision where nothing is predicted
+// positive) are only selected if no other threshold is available.
+func OptimalThreshold(predictions, labels []float64, objective func(ConfusionMatrix) float64) (float64, ConfusionMatrix) {
+	return mustThreshold(OptimalThresholdE(predictions, labels, objective))
+}
+
+// OptimalThresholdE finds the optimal decision threshold in the same way as OptimalThreshold but returns
+// ErrLengthMismatch, rather than panicking, if the lengths of the predictions and labels do not match.
+func OptimalThresholdE(predictions, labels []float64, objective func(ConfusionMatrix) float64) (float64, ConfusionMatrix, error) {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		return 0, ConfusionMatrix{}, err
+	}
+	thresholds, matrices := thresholdSweep(predictions, labels)
+
+	best := 0
+	bestScore := objective(matrices[0])
+	for i := 1; i < len(matrices); i++ {
+		score := objective(matrices[i])
+		if score > bestScore || (math.IsNaN(bestScore) && !math.IsNaN(score)) {
+			best = i
+			bestScore = score
+		}
+	}
+	return thresholds[best], matrices[best], nil
+}
+
+// mustThreshold returns the threshold and matrix, panicking if err is not nil, in the same way as must for the
+// threshold optimisation functions which return both.
+func mustThreshold(threshold float64, matrix ConfusionMatrix, err error) (float64, ConfusionMatrix) {
+	if err != nil {
+		panic(err)
+	}
+	return threshold, matrix
+}
+
+// F1Threshold finds the decision threshold that maximises the F1 score (see OptimalThreshold).  F1 is undefined
+// where there are no positive observations or predictions (see ConfusionMatrix.F1) and is treated as 0 so that,
+// as the highest of equally scoring thresholds, +Inf is selected if there are no positive observations.
+func F1Threshold(predictions, labels []float64) (float64, ConfusionMatrix) {
+	return mustThreshold(F1ThresholdE(predictions, labels))
+}
+
+// F1ThresholdE finds the decision threshold that maximises the F1 score in the same way as F1Threshold but returns
+// ErrLengthMismatch, rather than panicking, if the lengths of the predictions and labels do not match.
+func F1ThresholdE(predictions, labels []float64) (float64, ConfusionMatrix, error) {
+	return OptimalThresholdE(predictions, labels, func(c ConfusionMatrix) float64 {
+		c.ZeroDivision = ZeroDivisionZero
+		return c.F1()
+	})
+}
+
+// YoudensJ calculates Youden's J statistic (sensitivity + specificity - 1) for the confusion matrix.
+func YoudensJ(c ConfusionMatrix) float64 {
+	return c.Recall() + c.Specificity() - 1
+}
+
+// YoudensJThreshold finds the decision threshold that maximises Youden's J statistic (see OptimalThreshold).
+func YoudensJThreshold(predictions, labels []float64) (float64, ConfusionMatrix) {
+	return mustThreshold(YoudensJThresholdE(predictions, labels))
+}
+
+// YoudensJThresholdE finds the decision threshold that maximises Youden's J statistic in the same way as
+// YoudensJThreshold but returns ErrLengthMismatch, rather than panicking, if the lengths of the predictions and
+// labels do not match.
+func YoudensJThresholdE(predictions, labels []float64) (float64, ConfusionMatrix, error) {
+	return OptimalThresholdE(predictions, labels, YoudensJ)
+}
+
+// MinCostThreshold finds the decision threshold that minimises the supplied cost function (see OptimalThreshold).
+func MinCostThreshold(predictions, labels []float64, cost func(ConfusionMatrix) float64) (float64, ConfusionMatrix) {
+	return mustThreshold(MinCostThresholdE(predictions, labels, cost))
+}
+
+// MinCostThresholdE finds the decision threshold that minimises the supplied cost function in the same way as
+// MinCostThreshold but returns ErrLengthMismatch, rather than panicking, if the lengths of the predictions and
+// labels do not match.
+func MinCostThresholdE(predictions, labels []float64, cost func(ConfusionMatrix) float64) (float64, ConfusionMatrix, error) {
+	return OptimalThresholdE(predictions, labels, func(c ConfusionMatrix) float64 { return -cost(c) })
+}
@@ -0,0 +1,109 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+)
+
+func TestOptimalThresholds(t *testing.T) {
+	cost := func(c datautils.ConfusionMatrix) float64 {
+		return float64(c.FalsePos) + 5*float64(c.FalseNeg)
+	}
+
+	tests := []struct {
+		name      string
+		optimise  func(predictions, labels []float64) (float64, datautils.ConfusionMatrix)
+		threshold float64
+	}{
+		{name: "F1", optimise: datautils.F1Threshold, threshold: 0.35},
+		{name: "Youden's J", optimise: datautils.YoudensJThreshold, threshold: 0.8},
+		{
+			name: "Cost",
+			optimise: func(predictions, labels []float64) (float64, datautils.ConfusionMatrix) {
+				return datautils.MinCostThreshold(predictions, labels, cost)
+			},
+			threshold: 0.35,
+		},
+	}
+
+	for _, test := range tests {
+		threshold, matrix := test.optimise(datasets[0].probs, datasets[0].labels)
+		if threshold != test.threshold {
+			t.Errorf("%s: Expected threshold: %v but received %v", test.name, test.threshold, threshold)
+		}
+		expected := datautils.NewConfusionMatrix(datasets[0].probs, datasets[0].labels, threshold)
+		if matrix != expected {
+			t.Errorf("%s: Expected confusion matrix: %+v but received %+v", test.name, expected, matrix)
+		}
+	}
+}
+
+func TestOptimalThresholdNoPositives(t *testing.T) {
+	threshold, matrix := datautils.F1Threshold(datasets[3].probs, datasets[3].labels)
+	if !math.IsInf(threshold, 1) {
+		t.Errorf("Expected threshold: %v but received %v", math.Inf(1), threshold)
+	}
+	if matrix.TrueNeg != 2 || matrix.FalsePos != 0 {
+		t.Errorf("Expected all observations predicted negative but received %+v", matrix)
+	}
+}
+
+func TestOptimalThresholdNaNPrediction(t *testing.T) {
+	// the NaN prediction is never predicted positive so is a false negative at every threshold
+	predictions := []float64{0.9, math.NaN(), 0.8, 0.3, 0.7}
+	labels := []float64{1, 1, 0, 0, 1}
+
+	threshold, matrix := datautils.F1Threshold(predictions, labels)
+	if threshold != 0.7 {
+		t.Errorf("Expected threshold: %v but received %v", 0.7, threshold)
+	}
+	if expected := datautils.NewConfusionMatrix(predictions, labels, threshold); matrix != expected {
+		t.Errorf("Expected matrix: %+v but received %+v", expected, matrix)
+	}
+	if matrix.TruePos != 2 || matrix.FalseNeg != 1 {
+		t.Errorf("Expected 2 true positives and 1 false negative but received %+v", matrix)
+	}
+
+	table := datautils.NewThresholdTable(predictions, labels)
+	if expected := []float64{math.Inf(1), 0.9, 0.8, 0.7, 0.3}; !floats.Equal(expected, table.Thresholds) {
+		t.Errorf("Expected thresholds: %v but received %v", expected, table.Thresholds)
+	}
+}
+
+func TestOptimalThresholdErrLengthMismatch(t *testing.T) {
+	predictions, labels := []float64{0.1}, []float64{0, 1}
+	cost := func(c datautils.ConfusionMatrix) float64 { return float64(c.FalsePos) }
+	optimisers := map[string]func() error{
+		"OptimalThresholdE": func() error {
+			_, _, err := datautils.OptimalThresholdE(predictions, labels, datautils.YoudensJ)
+			return err
+		},
+		"F1ThresholdE": func() error {
+			_, _, err := datautils.F1ThresholdE(predictions, labels)
+			return err
+		},
+		"YoudensJThresholdE": func() error {
+			_, _, err := datautils.YoudensJThresholdE(predictions, labels)
+			return err
+		},
+		"MinCostThresholdE": func() error {
+			_, _, err := datautils.MinCostThresholdE(predictions, labels, cost)
+			return err
+		},
+	}
+	for name, optimise := range optimisers {
+		if err := optimise(); err != datautils.ErrLengthMismatch {
+			t.Errorf("%s: Expected error: %v but received %v", name, datautils.ErrLengthMismatch, err)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != datautils.ErrLengthMismatch {
+			t.Errorf("Expected panic: %v but received %v", datautils.ErrLengthMismatch, r)
+		}
+	}()
+	datautils.F1Threshold(predictions, labels)
+}
@@ -0,0 +1,112 @@
+package datautils
+
+import (
+	"io"
+	"math"
+	"sort"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+// ThresholdTable contains the classification performance of a set of predictions at each of a series of decision
+// thresholds to help choose a threshold for deployment.  The thresholds are in descending order.
+type ThresholdTable struct {
+	// Thresholds are the decision thresholds in descending order
+	Thresholds []float64
+
+	// Matrices contains the confusion matrix at each threshold
+	Matrices []ConfusionMatrix
+}
+
+// NewThresholdTable creates a new ThresholdTable of the performance of the predictions at every distinct prediction
+// value along with +Inf (predicting all observations negative).  As with NewConfusionMatrix, predictions greater
+// than or equal to the threshold are predicted positive and labels of 1 are considered positive.  The table is
+// calculated in a single pass over the sorted predictions.
+func NewThresholdTable(predictions, labels []float64) ThresholdTable {
+	thresholds, matrices := thresholdSweep(predictions, labels)
+	return ThresholdTable{Thresholds: thresholds, Matrices: matrices}
+}
+
+// NewThresholdGridTable creates a new ThresholdTable of the performance of the predictions at each of the
+// specified thresholds (e.g. an evenly spaced grid created with floats.Span) rather than every distinct prediction.
+// This keeps the table to a manageable size for large numbers of predictions.  The thresholds may be in any order
+// but are reported in descending order.
+func NewThresholdGridTable(predictions, labels, thresholds []float64) ThresholdTable {
+	t := ThresholdTable{Thresholds: make([]float64, len(thresholds))}
+	copy(t.Thresholds, thresholds)
+	sort.Sort(sort.Reverse(sort.Float64Slice(t.Thresholds)))
+	t.Matrices = NewConfusionMatrices(predictions, labels, t.Thresholds)
+	return t
+}
+
+// thresholdMetrics are the metrics reported at each threshold by a ThresholdTable in column order.
+var thresholdMetrics = []struct {
+	name   string
+	metric func(ConfusionMatrix) float64
+}{
+	{name: "precision", metric: ConfusionMatrix.Precision},
+	{name: "recall", metric: ConfusionMatrix.Recall},
+	{name: "f1", metric: ConfusionMatrix.F1},
+	{name: "fpr", metric: func(c ConfusionMatrix) float64 { return 1 - c.Specificity() }},
+	{name: "accuracy", metric: ConfusionMatrix.Accuracy},
+}
+
+// WriteCSV writes the table to w as CSV with a header followed by a record per threshold in descending order of
+// threshold.  The columns are threshold, tp, fp, tn, fn, precision, recall, f1, fpr and accuracy.  comma is the
+// field delimiter e.g. '\t' for TSV (or ',' if zero).
+func (t ThresholdTable) WriteCSV(w io.Writer, comma rune) error {
+	header := []string{"threshold", "tp", "fp", "tn", "fn"}
+	for _, m := range thresholdMetrics {
+		header = append(header, m.name)
+	}
+	records := [][]string{header}
+	for i, threshold := range t.Thresholds {
+		c := t.Matrices[i]
+		tp, tn, fp, fn := c.cells()
+		record := []string{formatFloat(threshold), formatFloat(tp), formatFloat(fp), formatFloat(tn), formatFloat(fn)}
+		for _, m := range thresholdMetrics {
+			record = append(record, formatFloat(m.metric(c)))
+		}
+		records = append(records, record)
+	}
+	return writeCSV(w, comma, records)
+}
+
+// Plot renders the precision, recall, F1 score, false positive rate and accuracy against the threshold as a line
+// per metric.  Infinite thresholds and undefined (NaN) metric values are omitted.
+func (t ThresholdTable) Plot() *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+
+	p.Title.Text = PlotTheme.title("Metrics by Threshold")
+	p.X.Label.Text = "Threshold"
+	p.Y.Label.Text = "Value"
+	p.Y.Min, p.Y.Max = 0, 1
+
+	for i, m := range thresholdMetrics {
+		var pts plotter.XYs
+		for j, threshold := range t.Thresholds {
+			v := m.metric(t.Matrices[j])
+			if math.IsInf(threshold, 0) || math.IsNaN(v) {
+				continue
+			}
+			pts = append(pts, plotter.XY{X: threshold, Y: v})
+		}
+		if len(pts) == 0 {
+			continue
+		}
+		line, err := plotter.NewLine(pts)
+		if err != nil {
+			panic(err)
+		}
+		line.Color = PlotTheme.color(i)
+		p.Add(line)
+		p.Legend.Add(m.name, line)
+	}
+	p.Legend.Top = true
+
+	return p
+}
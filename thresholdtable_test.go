@@ -0,0 +1,54 @@
+package datautils_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestThresholdTable(t *testing.T) {
+	predictions := []float64{0.9, 0.8, 0.8, 0.4, 0.1}
+	labels := []float64{1, 0, 1, 1, 0}
+
+	table := datautils.NewThresholdTable(predictions, labels)
+	if expected := []float64{math.Inf(1), 0.9, 0.8, 0.4, 0.1}; !equalWithNaN(table.Thresholds, expected) {
+		t.Errorf("Expected thresholds: %v but received %v", expected, table.Thresholds)
+	}
+	for i, threshold := range table.Thresholds {
+		if expected := datautils.NewConfusionMatrix(predictions, labels, threshold); table.Matrices[i] != expected {
+			t.Errorf("Threshold %v: Expected %v but received %v", threshold, expected, table.Matrices[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := table.WriteCSV(&buf, ','); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 || lines[0] != "threshold,tp,fp,tn,fn,precision,recall,f1,fpr,accuracy" || lines[1] != "+Inf,0,0,2,3,NaN,0,0,0,0.4" || lines[3] != "0.8,2,1,1,1,0.6666666666666666,0.6666666666666666,0.6666666666666666,0.5,0.6" {
+		t.Errorf("Unexpected CSV:\n%s", buf.String())
+	}
+	table.Plot()
+}
+
+func TestThresholdGridTable(t *testing.T) {
+	predictions := []float64{0.9, 0.8, 0.8, 0.4, 0.1}
+	labels := []float64{1, 0, 1, 1, 0}
+
+	grid := []float64{0, 0.25, 0.5, 0.75, 0.8, 1}
+	table := datautils.NewThresholdGridTable(predictions, labels, grid)
+	if expected := []float64{1, 0.8, 0.75, 0.5, 0.25, 0}; !equalWithNaN(table.Thresholds, expected) {
+		t.Errorf("Expected thresholds: %v but received %v", expected, table.Thresholds)
+	}
+	for i, threshold := range table.Thresholds {
+		if expected := datautils.NewConfusionMatrix(predictions, labels, threshold); table.Matrices[i] != expected {
+			t.Errorf("Threshold %v: Expected %v but received %v", threshold, expected, table.Matrices[i])
+		}
+	}
+	if grid[0] != 0 {
+		t.Errorf("Expected the supplied thresholds to be unmodified but received %v", grid)
+	}
+}
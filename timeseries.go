@@ -0,0 +1,85 @@
+package datautils
+
+import (
+	"sort"
+	"time"
+)
+
+// TimeSeriesSplit partitions a temporal dataset into successive training and test sets for cross-validation such
+// that no training observation postdates any test observation.  Standard k-fold cross-validation (see KFold) trains
+// on observations from after the test period, leaking information about the future into the model and so producing
+// overly optimistic evaluations.  The test sets of successive splits are consecutive, equally sized blocks of the
+// most recent observations and the training set of each split contains the observations preceding its test set,
+// either all of them (an expanding window) or only the most recent MaxTrainSize (a sliding window).
+type TimeSeriesSplit struct {
+	// Splits is the number of splits
+	Splits int
+
+	// TestSize is the number of observations in the test set of each split.  If 0, the observations are divided
+	// into Splits + 1 blocks of (near) equal size with the first block used only for training.
+	TestSize int
+
+	// MaxTrainSize is the maximum number of observations in the training set of each split.  If 0, training sets
+	// include all the observations preceding the test set (an expanding window), otherwise only the most recent
+	// MaxTrainSize observations (a sliding window).
+	MaxTrainSize int
+
+	// Gap is the number of observations immediately preceding each test set to exclude from the training set.
+	// Excluding a gap prevents leakage where labels are only known after a delay or features are computed over
+	// trailing windows that overlap the test period.
+	Gap int
+
+	// Embargo excludes from the training set any observations within Embargo of the earliest observation of the
+	// test set, in addition to those excluded by Gap.
+	Embargo time.Duration
+}
+
+// Split partitions the observations with the specified timestamps into Splits folds.  The timestamps need not be
+// sorted; observations are ordered by timestamp, with ties retaining their original order, before being divided
+// into training and test sets.  The indices within the training and test sets of each fold are in ascending
+// order.  Split will panic if the observations are too few to provide a non-empty training set for every split
+// before any Embargo is applied.
+func (s TimeSeriesSplit) Split(timestamps []time.Time) []Fold {
+	n := len(timestamps)
+	if s.Splits < 1 {
+		panic("datautils: number of splits must be at least 1")
+	}
+	if s.TestSize < 0 || s.MaxTrainSize < 0 || s.Gap < 0 || s.Embargo < 0 {
+		panic("datautils: test size, maximum train size, gap and embargo must not be negative")
+	}
+	testSize := s.TestSize
+	if testSize == 0 {
+		testSize = n / (s.Splits + 1)
+	}
+	if testSize < 1 || n-s.Splits*testSize-s.Gap < 1 {
+		panic("datautils: too few observations for the number of splits, test size and gap")
+	}
+
+	order := allIndices(n)
+	sort.SliceStable(order, func(i, j int) bool { return timestamps[order[i]].Before(timestamps[order[j]]) })
+
+	folds := make([]Fold, s.Splits)
+	for f := range folds {
+		testStart := n - (s.Splits-f)*testSize
+		trainEnd := testStart - s.Gap
+		if s.Embargo > 0 {
+			cutoff := timestamps[order[testStart]].Add(-s.Embargo)
+			for trainEnd > 0 && timestamps[order[trainEnd-1]].After(cutoff) {
+				trainEnd--
+			}
+		}
+		var trainStart int
+		if s.MaxTrainSize > 0 && trainEnd-s.MaxTrainSize > 0 {
+			trainStart = trainEnd - s.MaxTrainSize
+		}
+
+		train := make([]int, trainEnd-trainStart)
+		copy(train, order[trainStart:trainEnd])
+		test := make([]int, testSize)
+		copy(test, order[testStart:testStart+testSize])
+		sort.Ints(train)
+		sort.Ints(test)
+		folds[f] = Fold{Train: train, Test: test}
+	}
+	return folds
+}
@@ -0,0 +1,79 @@
+package datautils_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestTimeSeriesSplit(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// observations are daily but supplied in reverse order
+	timestamps := make([]time.Time, 10)
+	for i := range timestamps {
+		timestamps[i] = start.AddDate(0, 0, 9-i)
+	}
+	// day returns the indices of the observations of the specified days
+	day := func(days ...int) []int {
+		ind := make([]int, len(days))
+		for i, d := range days {
+			ind[len(days)-1-i] = 9 - d
+		}
+		return ind
+	}
+
+	tests := []struct {
+		split datautils.TimeSeriesSplit
+		folds []datautils.Fold
+	}{
+		{
+			split: datautils.TimeSeriesSplit{Splits: 3},
+			folds: []datautils.Fold{
+				{Train: day(0, 1, 2, 3), Test: day(4, 5)},
+				{Train: day(0, 1, 2, 3, 4, 5), Test: day(6, 7)},
+				{Train: day(0, 1, 2, 3, 4, 5, 6, 7), Test: day(8, 9)},
+			},
+		},
+		{
+			split: datautils.TimeSeriesSplit{Splits: 2, TestSize: 3, MaxTrainSize: 2, Gap: 1},
+			folds: []datautils.Fold{
+				{Train: day(1, 2), Test: day(4, 5, 6)},
+				{Train: day(4, 5), Test: day(7, 8, 9)},
+			},
+		},
+		{
+			split: datautils.TimeSeriesSplit{Splits: 2, TestSize: 2, Embargo: 36 * time.Hour},
+			folds: []datautils.Fold{
+				{Train: day(0, 1, 2, 3, 4), Test: day(6, 7)},
+				{Train: day(0, 1, 2, 3, 4, 5, 6), Test: day(8, 9)},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		folds := test.split.Split(timestamps)
+		if fmt.Sprint(folds) != fmt.Sprint(test.folds) {
+			t.Errorf("Test %d: Expected folds: %v but received %v", i+1, test.folds, folds)
+		}
+		for _, fold := range folds {
+			for _, tr := range fold.Train {
+				for _, te := range fold.Test {
+					if timestamps[tr].After(timestamps[te]) {
+						t.Errorf("Test %d: Training observation %d postdates test observation %d", i+1, tr, te)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestTimeSeriesSplitTooFewObservations(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for too few observations")
+		}
+	}()
+	datautils.TimeSeriesSplit{Splits: 3, TestSize: 3}.Split(make([]time.Time, 9))
+}
@@ -0,0 +1,193 @@
+package datautils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Qrels represents TREC relevance judgements as a map of query ID to a map of document ID to degree of relevance.
+type Qrels map[string]map[string]float64
+
+// Run represents a TREC run (the ranked output of a retrieval system) as a map of query ID to a map of document
+// ID to retrieval score.
+type Run map[string]map[string]float64
+
+// ReadQrels reads TREC relevance judgements (qrels) in the standard whitespace separated format with 4 fields
+// per line: query ID, iteration (ignored), document ID and degree of relevance.
+func ReadQrels(r io.Reader) (Qrels, error) {
+	qrels := make(Qrels)
+	err := readTRECLines(r, 4, func(fields []string) error {
+		rel, err := strconv.ParseFloat(fields[3], 64)
+		if err != nil {
+			return err
+		}
+		addTRECEntry(qrels, fields[0], fields[2], rel)
+		return nil
+	})
+	return qrels, err
+}
+
+// ReadRun reads a TREC run file in the standard whitespace separated format with 6 fields per line: query ID,
+// the literal "Q0" (ignored), document ID, rank (ignored), score and run tag (ignored).  As with trec_eval, the
+// documents are ranked by score rather than the specified rank.
+func ReadRun(r io.Reader) (Run, error) {
+	run := make(Run)
+	err := readTRECLines(r, 6, func(fields []string) error {
+		score, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return err
+		}
+		addTRECEntry(run, fields[0], fields[2], score)
+		return nil
+	})
+	return run, err
+}
+
+func addTRECEntry(m map[string]map[string]float64, query, doc string, v float64) {
+	docs, ok := m[query]
+	if !ok {
+		docs = make(map[string]float64)
+		m[query] = docs
+	}
+	docs[doc] = v
+}
+
+// readTRECLines reads whitespace separated lines from r, checking each non blank line has the expected number
+// of fields before passing them to parse.
+func readTRECLines(r io.Reader, n int, parse func(fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	var line int
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != n {
+			return fmt.Errorf("datautils: line %d: expected %d fields but found %d", line, n, len(fields))
+		}
+		if err := parse(fields); err != nil {
+			return fmt.Errorf("datautils: line %d: %w", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// NewTRECEvaluationSet creates an EvaluationSet from a TREC run and its corresponding relevance judgements.
+// The set contains an evaluation for each query within the run.  Documents retrieved by the run without a
+// relevance judgement are considered non-relevant.  Relevant documents that were not retrieved by the run are
+// excluded from the ranking, so they do not count towards metrics such as reciprocal rank or hit rate, but
+// their relevancy values are recorded in the evaluation's Unretrieved field so that they still count towards
+// recall, average precision and the perfect ranking used to normalise NDCG.
+func NewTRECEvaluationSet(run Run, qrels Qrels) EvaluationSet {
+	set := make(EvaluationSet, len(run))
+
+	for query, docs := range run {
+		judged := qrels[query]
+
+		ids := make([]string, 0, len(docs))
+		for doc := range docs {
+			ids = append(ids, doc)
+		}
+		// sort document IDs so that ties are ordered consistently between runs
+		sort.Strings(ids)
+
+		predictions := make([]float64, len(ids))
+		labels := make([]float64, len(ids))
+		for i, doc := range ids {
+			predictions[i] = docs[doc]
+			labels[i] = judged[doc]
+		}
+		eval := NewRankingEvaluation(predictions, labels)
+
+		for doc, rel := range judged {
+			if _, ok := docs[doc]; !ok && rel > 0 {
+				eval.Unretrieved = append(eval.Unretrieved, rel)
+			}
+		}
+		sort.Sort(sort.Reverse(sort.Float64Slice(eval.Unretrieved)))
+		set[query] = eval
+	}
+
+	return set
+}
+
+// ReadLETOR reads learning to rank data in the LETOR (SVMlight) format used by the LETOR and MSLR datasets
+// where each line has the form "<label> qid:<query id> <feature>:<value> ... # <comment>".  Feature numbers
+// start at 1 and features absent from a line are treated as 0.  The returned queries slice contains the query
+// ID of each row of the returned Dataset.
+func ReadLETOR(r io.Reader) (Dataset, []string, error) {
+	var labels []float64
+	var queries []string
+	var rows []map[int]float64
+	var maxFeature int
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var line int
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], "qid:") {
+			return Dataset{}, nil, fmt.Errorf("datautils: line %d: expected label and qid", line)
+		}
+		label, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return Dataset{}, nil, fmt.Errorf("datautils: line %d: %w", line, err)
+		}
+		row := make(map[int]float64)
+		for _, f := range fields[2:] {
+			parts := strings.SplitN(f, ":", 2)
+			if len(parts) != 2 {
+				return Dataset{}, nil, fmt.Errorf("datautils: line %d: malformed feature %q", line, f)
+			}
+			n, err := strconv.Atoi(parts[0])
+			if err != nil || n < 1 {
+				return Dataset{}, nil, fmt.Errorf("datautils: line %d: malformed feature number %q", line, parts[0])
+			}
+			v, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil {
+				return Dataset{}, nil, fmt.Errorf("datautils: line %d: %w", line, err)
+			}
+			row[n] = v
+			if n > maxFeature {
+				maxFeature = n
+			}
+		}
+		labels = append(labels, label)
+		queries = append(queries, strings.TrimPrefix(fields[1], "qid:"))
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return Dataset{}, nil, err
+	}
+	if len(rows) == 0 || maxFeature == 0 {
+		return Dataset{}, nil, fmt.Errorf("datautils: no LETOR data found")
+	}
+
+	features := mat.NewDense(len(rows), maxFeature, nil)
+	for i, row := range rows {
+		for n, v := range row {
+			features.Set(i, n-1, v)
+		}
+	}
+	columns := make([]string, maxFeature)
+	for i := range columns {
+		columns[i] = strconv.Itoa(i + 1)
+	}
+
+	return Dataset{Features: features, Labels: labels, Columns: columns}, queries, nil
+}
@@ -0,0 +1,142 @@
+package datautils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+const qrelsData = `1 0 d1 1
+1 0 d2 0
+1 0 d3 2
+2 0 d4 1
+`
+
+const runData = `1 Q0 d2 1 0.9 test
+1 Q0 d1 2 0.8 test
+1 Q0 d5 3 0.7 test
+2 Q0 d6 1 0.5 test
+2 Q0 d4 2 0.4 test
+`
+
+func TestReadQrelsAndRun(t *testing.T) {
+	qrels, err := datautils.ReadQrels(strings.NewReader(qrelsData))
+	if err != nil {
+		t.Fatalf("Unexpected error reading qrels: %v", err)
+	}
+	if len(qrels) != 2 || qrels["1"]["d3"] != 2 || qrels["2"]["d4"] != 1 {
+		t.Errorf("Unexpected qrels: %v", qrels)
+	}
+
+	run, err := datautils.ReadRun(strings.NewReader(runData))
+	if err != nil {
+		t.Fatalf("Unexpected error reading run: %v", err)
+	}
+	if len(run) != 2 || run["1"]["d5"] != 0.7 || run["2"]["d6"] != 0.5 {
+		t.Errorf("Unexpected run: %v", run)
+	}
+
+	if _, err := datautils.ReadRun(strings.NewReader("1 Q0 d1 1 x test\n")); err == nil {
+		t.Errorf("Expected error for malformed score but received none")
+	}
+	if _, err := datautils.ReadQrels(strings.NewReader("1 0 d1\n")); err == nil {
+		t.Errorf("Expected error for missing field but received none")
+	}
+}
+
+func TestNewTRECEvaluationSet(t *testing.T) {
+	qrels, _ := datautils.ReadQrels(strings.NewReader(qrelsData))
+	run, _ := datautils.ReadRun(strings.NewReader(runData))
+
+	set := datautils.NewTRECEvaluationSet(run, qrels)
+
+	if len(set) != 2 {
+		t.Fatalf("Expected 2 queries but received %d", len(set))
+	}
+	// query 1: d2 (0), d1 (1), d5 (unjudged) with d3 relevant but not retrieved
+	if len(set["1"].Relevancies) != 3 {
+		t.Errorf("Expected 3 documents for query 1 but received %d", len(set["1"].Relevancies))
+	}
+	if u := set["1"].Unretrieved; len(u) != 1 || u[0] != 2 {
+		t.Errorf("Expected query 1 unretrieved relevancies: %v but received %v", []float64{2}, u)
+	}
+	if r := set["1"].RecallAt(3); r != 0.5 {
+		t.Errorf("Expected query 1 recall@3: %v but received %v", 0.5, r)
+	}
+	if rr := set["1"].ReciprocalRank(); rr != 0.5 {
+		t.Errorf("Expected query 1 reciprocal rank: %v but received %v", 0.5, rr)
+	}
+	if cg := set["1"].CumulativeGain(3); cg != 1 {
+		t.Errorf("Expected query 1 CG@3: %v but received %v", 1, cg)
+	}
+	if mrr := set.MeanReciprocalRank(); mrr != 0.5 {
+		t.Errorf("Expected MRR: %v but received %v", 0.5, mrr)
+	}
+}
+
+func TestNewTRECEvaluationSetNoneRetrieved(t *testing.T) {
+	qrels := datautils.Qrels{"1": {"d4": 1}}
+	run := datautils.Run{"1": {"d1": 0.9, "d2": 0.8, "d3": 0.7}}
+
+	set := datautils.NewTRECEvaluationSet(run, qrels)
+	r := set["1"]
+
+	if len(r.Relevancies) != 3 {
+		t.Errorf("Expected 3 ranked documents but received %d", len(r.Relevancies))
+	}
+	if hr := set.HitRate(10); hr != 0 {
+		t.Errorf("Expected hit rate: %v but received %v", 0, hr)
+	}
+	if rr := r.ReciprocalRank(); rr != 0 {
+		t.Errorf("Expected reciprocal rank: %v but received %v", 0, rr)
+	}
+	if recall := r.RecallAt(3); recall != 0 {
+		t.Errorf("Expected recall@3: %v but received %v", 0, recall)
+	}
+	if ap := r.AveragePrecisionAt(3, 1); ap != 0 {
+		t.Errorf("Expected AP@3: %v but received %v", 0, ap)
+	}
+	if ndcg := r.NormalisedDiscountedCumulativeGain(3, datautils.TraditionalRelevancy); ndcg != 0 {
+		t.Errorf("Expected NDCG@3: %v but received %v", 0, ndcg)
+	}
+}
+
+func TestNewTRECEvaluationSetEmptyRun(t *testing.T) {
+	// query 1 retrieved nothing but has a relevant document while query 2 retrieved nothing and has no relevant
+	// documents
+	qrels := datautils.Qrels{"1": {"d1": 1}, "2": {"d2": 0}}
+	run := datautils.Run{"1": {}, "2": {}}
+
+	set := datautils.NewTRECEvaluationSet(run, qrels)
+
+	if ndcg := set.MeanNormalisedDiscountedCumulativeGains([]int{10}, datautils.TraditionalRelevancy)[0]; ndcg != 0.5 {
+		t.Errorf("Expected mean NDCG@10: %v but received %v", 0.5, ndcg)
+	}
+	if ndcg := set.MeanNormalisedDiscountedCumulativeGainWith(10, datautils.TraditionalRelevancy, datautils.LogarithmicDiscount); ndcg != 0.5 {
+		t.Errorf("Expected mean NDCG@10 with discount: %v but received %v", 0.5, ndcg)
+	}
+}
+
+func TestReadLETOR(t *testing.T) {
+	data := `2 qid:10 1:0.5 3:1.5 # doc1
+0 qid:10 2:0.25
+1 qid:11 1:1 2:2 3:3
+`
+	dataset, queries, err := datautils.ReadLETOR(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(queries, ",") != "10,10,11" {
+		t.Errorf("Expected queries: %v but received %v", "10,10,11", queries)
+	}
+	if r, c := dataset.Features.Dims(); r != 3 || c != 3 {
+		t.Errorf("Expected 3x3 features but received %dx%d", r, c)
+	}
+	if dataset.Features.At(0, 2) != 1.5 || dataset.Features.At(0, 1) != 0 || dataset.Features.At(1, 1) != 0.25 {
+		t.Errorf("Unexpected features: %v", dataset.Features)
+	}
+	if dataset.Labels[0] != 2 || dataset.Labels[2] != 1 {
+		t.Errorf("Unexpected labels: %v", dataset.Labels)
+	}
+}
@@ -0,0 +1,105 @@
+package datautils
+
+import (
+	"image/color"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+// ValidationCurve represents the training and validation scores of a model across a range of values of one of its
+// hyperparameters.  Comparing the two scores as the hyperparameter varies shows where the model underfits (both
+// scores poor) or overfits (training score good but validation score poor) and so helps choose a suitable value.
+type ValidationCurve struct {
+	// Params contains the hyperparameter values in the order they were supplied
+	Params []float64
+
+	// Train and Validation contain the training and validation scores across the cross-validation folds for each
+	// hyperparameter value
+	Train, Validation []CrossValidationScore
+}
+
+// NewValidationCurve creates a new validation curve by cross-validating the model for each of the specified
+// hyperparameter values using the supplied folds (see KFold and CrossValidate).  The evaluate function would
+// typically train a model with the hyperparameter value using the training set of the fold and return the metric
+// evaluated using the model's predictions for both the training set and the test (validation) set of the fold.
+func NewValidationCurve(params []float64, folds []Fold, evaluate func(param float64, fold Fold) (train, validation float64)) ValidationCurve {
+	c := ValidationCurve{
+		Params:     params,
+		Train:      make([]CrossValidationScore, len(params)),
+		Validation: make([]CrossValidationScore, len(params)),
+	}
+
+	for i, param := range params {
+		scores := CrossValidate(folds, func(fold Fold) map[string]float64 {
+			train, validation := evaluate(param, fold)
+			return map[string]float64{"train": train, "validation": validation}
+		})
+		c.Train[i], c.Validation[i] = scores["train"], scores["validation"]
+	}
+	return c
+}
+
+// Best returns the hyperparameter value with the highest mean validation score along with the score.  Where
+// several values achieve the same score, the first is returned.  For metrics where lower values are better e.g.
+// loss, the metric should be negated by the evaluate function.
+func (c ValidationCurve) Best() (param, score float64) {
+	for i, v := range c.Validation {
+		if i == 0 || v.Mean > score {
+			param, score = c.Params[i], v.Mean
+		}
+	}
+	return param, score
+}
+
+// Plot renders the validation curve as a plot for visualisation.  The mean training and validation scores are
+// plotted against the hyperparameter value with a shaded band of one standard deviation either side of each mean
+// showing the variation across folds.  Hyperparameters swept over several orders of magnitude may be easier to
+// read after setting the x axis of the returned plot to a log scale.
+func (c ValidationCurve) Plot(param, metric string) *plot.Plot {
+	p, err := newPlot()
+	if err != nil {
+		panic(err)
+	}
+
+	p.Title.Text = PlotTheme.title("Validation Curve")
+	p.X.Label.Text = param
+	p.Y.Label.Text = metric
+	p.Legend.Top = true
+
+	addScoreCurve(p, "Training", c.Params, c.Train, PlotTheme.Secondary)
+	addScoreCurve(p, "Validation", c.Params, c.Validation, PlotTheme.Primary)
+
+	return p
+}
+
+// addScoreCurve adds a line of the mean cross-validation scores against x to the plot, along with a translucent
+// band of one standard deviation either side of the mean, in the specified colour.
+func addScoreCurve(p *plot.Plot, name string, x []float64, scores []CrossValidationScore, c color.Color) {
+	pts := make(plotter.XYs, len(x))
+	band := make(plotter.XYs, 2*len(x))
+	for i, v := range scores {
+		pts[i] = plotter.XY{X: x[i], Y: v.Mean}
+		band[i] = plotter.XY{X: x[i], Y: v.Mean + v.StdDev}
+		band[len(band)-1-i] = plotter.XY{X: x[i], Y: v.Mean - v.StdDev}
+	}
+
+	if len(band) > 0 {
+		polygon, err := plotter.NewPolygon(band)
+		if err != nil {
+			panic(err)
+		}
+		polygon.Color = translucent(c, 64)
+		polygon.LineStyle.Width = 0
+		p.Add(polygon)
+	}
+
+	line, points, err := plotter.NewLinePoints(pts)
+	if err != nil {
+		panic(err)
+	}
+	line.Color = c
+	points.Color = c
+	p.Add(line, points)
+	p.Legend.Add(name, line, points)
+}
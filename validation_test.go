@@ -0,0 +1,40 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestValidationCurve(t *testing.T) {
+	folds := datautils.KFold{K: 4}.Split(make([]float64, 8))
+	params := []float64{1, 2, 3, 4}
+
+	c := datautils.NewValidationCurve(params, folds, func(param float64, fold datautils.Fold) (float64, float64) {
+		// training score improves with the parameter while the validation score peaks at 2
+		return param, -math.Abs(param-2) + float64(fold.Test[0])
+	})
+
+	if len(c.Train) != len(params) || len(c.Validation) != len(params) {
+		t.Fatalf("Expected %d scores but received %d training and %d validation", len(params), len(c.Train), len(c.Validation))
+	}
+	for i, param := range params {
+		if c.Train[i].Mean != param || c.Train[i].StdDev != 0 || len(c.Train[i].Scores) != 4 {
+			t.Errorf("Expected training score mean %v and std dev 0 across 4 folds but received %+v", param, c.Train[i])
+		}
+		expected := -math.Abs(param-2) + 3
+		if math.Abs(c.Validation[i].Mean-expected) > 1e-12 || math.Abs(c.Validation[i].StdDev-math.Sqrt(20.0/3.0)) > 1e-12 {
+			t.Errorf("Expected validation score mean %v and std dev %v but received %+v", expected, math.Sqrt(20.0/3.0), c.Validation[i])
+		}
+	}
+
+	if param, score := c.Best(); param != 2 || score != 3 {
+		t.Errorf("Expected best parameter 2 with score 3 but received %v with score %v", param, score)
+	}
+
+	p := c.Plot("C", "Accuracy")
+	if p.X.Label.Text != "C" || p.Y.Label.Text != "Accuracy" {
+		t.Errorf("Expected axis labels C and Accuracy but received %s and %s", p.X.Label.Text, p.Y.Label.Text)
+	}
+}
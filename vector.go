@@ -0,0 +1,55 @@
+package datautils
+
+import "gonum.org/v1/gonum/mat"
+
+// vectorData returns the elements of v as a slice.  If v is a *mat.VecDense with contiguous storage, its
+// underlying data is returned directly without copying, otherwise the elements are copied into a new slice.
+func vectorData(v mat.Vector) []float64 {
+	if d, ok := v.(*mat.VecDense); ok {
+		raw := d.RawVector()
+		if raw.Inc == 1 {
+			return raw.Data[:d.Len()]
+		}
+	}
+	s := make([]float64, v.Len())
+	for i := range s {
+		s[i] = v.AtVec(i)
+	}
+	return s
+}
+
+// NewRankingEvaluationFromVectors creates a new RankingEvaluation, in the same way as NewRankingEvaluation,
+// from gonum vectors of predictions and labels e.g. the output of gonum based models.  Where a vector is a
+// *mat.VecDense with contiguous storage, the RankingEvaluation refers to its underlying data rather than a copy
+// so the vector should not be modified while the evaluation is in use.
+func NewRankingEvaluationFromVectors(predictions, labels mat.Vector) RankingEvaluation {
+	return NewRankingEvaluation(vectorData(predictions), vectorData(labels))
+}
+
+// NewPrecisionRecallCurveFromVectors creates a new PrecisionRecallCurve, in the same way as
+// NewPrecisionRecallCurve, from gonum vectors of predictions and labels.  Where a vector is a *mat.VecDense with
+// contiguous storage, its underlying data is used directly rather than being copied into a slice first.
+func NewPrecisionRecallCurveFromVectors(predictions, labels mat.Vector) PrecisionRecallCurve {
+	return NewPrecisionRecallCurve(vectorData(predictions), vectorData(labels))
+}
+
+// NewROCCurveFromVectors creates a new ROCCurve, in the same way as NewROCCurve, from gonum vectors of
+// predictions and labels.  Where a vector is a *mat.VecDense with contiguous storage, its underlying data is used
+// directly rather than being copied into a slice first.
+func NewROCCurveFromVectors(predictions, labels mat.Vector) ROCCurve {
+	return NewROCCurve(vectorData(predictions), vectorData(labels))
+}
+
+// NewConfusionMatrixFromVectors creates a new ConfusionMatrix, in the same way as NewConfusionMatrix, from gonum
+// vectors of predictions and labels.  The vectors are read element by element so no copies are made.
+func NewConfusionMatrixFromVectors(predictions, labels mat.Vector, threshold float64) ConfusionMatrix {
+	if predictions.Len() != labels.Len() {
+		panic(ErrLengthMismatch)
+	}
+
+	var matrix ConfusionMatrix
+	for i := 0; i < labels.Len(); i++ {
+		matrix.add(predictions.AtVec(i) >= threshold, labels.AtVec(i), 1)
+	}
+	return matrix
+}
@@ -0,0 +1,74 @@
+package datautils_test
+
+import (
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/james-bowman/datautils"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestVectorConstructors(t *testing.T) {
+	predictions := []float64{0.5, 0.2, 0.5, 0.7, 0.1, 0.5}
+	labels := []float64{1, 0, 0, 1, 0, 1}
+
+	// predictions and labels as the columns of a matrix produce strided (non contiguous) vectors
+	m := mat.NewDense(len(predictions), 2, nil)
+	for i := range predictions {
+		m.Set(i, 0, predictions[i])
+		m.Set(i, 1, labels[i])
+	}
+
+	tests := []struct {
+		predictions mat.Vector
+		labels      mat.Vector
+	}{
+		{
+			predictions: mat.NewVecDense(len(predictions), predictions),
+			labels:      mat.NewVecDense(len(labels), labels),
+		},
+		{
+			predictions: m.ColView(0),
+			labels:      m.ColView(1),
+		},
+	}
+
+	expectedPR := datautils.NewPrecisionRecallCurve(predictions, labels)
+	expectedROC := datautils.NewROCCurve(predictions, labels)
+	expectedMatrix := datautils.NewConfusionMatrix(predictions, labels, 0.5)
+	expectedRanking := datautils.NewRankingEvaluation(predictions, labels)
+
+	for ti, test := range tests {
+		pr := datautils.NewPrecisionRecallCurveFromVectors(test.predictions, test.labels)
+		if !floats.Equal(expectedPR.Precision, pr.Precision) || !floats.Equal(expectedPR.Recall, pr.Recall) ||
+			!floats.Equal(expectedPR.Thresholds, pr.Thresholds) {
+			t.Errorf("Test %d: Expected precision recall curve: %v but received %v", ti+1, expectedPR, pr)
+		}
+
+		roc := datautils.NewROCCurveFromVectors(test.predictions, test.labels)
+		if !floats.Equal(expectedROC.FPR, roc.FPR) || !floats.Equal(expectedROC.TPR, roc.TPR) ||
+			!floats.Equal(expectedROC.Thresholds, roc.Thresholds) {
+			t.Errorf("Test %d: Expected ROC curve: %v but received %v", ti+1, expectedROC, roc)
+		}
+
+		matrix := datautils.NewConfusionMatrixFromVectors(test.predictions, test.labels, 0.5)
+		if expectedMatrix != matrix {
+			t.Errorf("Test %d: Expected confusion matrix: %v but received %v", ti+1, expectedMatrix, matrix)
+		}
+
+		ranking := datautils.NewRankingEvaluationFromVectors(test.predictions, test.labels)
+		expected := expectedRanking.NormalisedDiscountedCumulativeGain(3, datautils.TraditionalRelevancy)
+		if ndcg := ranking.NormalisedDiscountedCumulativeGain(3, datautils.TraditionalRelevancy); ndcg != expected {
+			t.Errorf("Test %d: Expected NDCG@3: %v but received %v", ti+1, expected, ndcg)
+		}
+	}
+}
+
+func TestVectorLengthMismatch(t *testing.T) {
+	defer func() {
+		if r := recover(); r != datautils.ErrLengthMismatch {
+			t.Errorf("Expected panic: %v but received %v", datautils.ErrLengthMismatch, r)
+		}
+	}()
+	datautils.NewConfusionMatrixFromVectors(mat.NewVecDense(2, nil), mat.NewVecDense(3, nil), 0.5)
+}
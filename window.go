@@ -0,0 +1,68 @@
+package datautils
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MetricWindow contains the value of a metric calculated over the observations within a single window of time.
+type MetricWindow struct {
+	// Start and End are the inclusive start and exclusive end of the window
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Observations is the number of observations within the window
+	Observations int `json:"observations"`
+
+	// Value is the value of the metric for the observations within the window or NaN if the window is empty
+	Value float64 `json:"value"`
+}
+
+// WindowedMetric calculates the specified metric over consecutive windows of time, returning a time series
+// suitable for plotting or monitoring drift in model performance over days or weeks.  timestamps contains the time
+// of each observation, corresponding to predictions and labels, and need not be sorted.  Each window is of duration
+// window and successive windows start step apart so that tumbling (non-overlapping) windows are produced when step
+// equals window and rolling (overlapping) windows when step is less than window.  Window starts are aligned to
+// multiples of step (see time.Time.Truncate) beginning with the window containing the earliest observation and
+// continue until all observations are covered.  Windows containing no observations are included with a Value of
+// NaN so that gaps are preserved in the series.
+func WindowedMetric(timestamps []time.Time, predictions, labels []float64, metric MetricFunc, window, step time.Duration) []MetricWindow {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+	if len(timestamps) != len(labels) {
+		panic(ErrLengthMismatch)
+	}
+	if window <= 0 || step <= 0 {
+		panic("datautils: window and step durations must be positive")
+	}
+	if len(timestamps) == 0 {
+		return nil
+	}
+
+	ind := allIndices(len(timestamps))
+	sort.SliceStable(ind, func(i, j int) bool { return timestamps[ind[i]].Before(timestamps[ind[j]]) })
+	first, last := timestamps[ind[0]], timestamps[ind[len(ind)-1]]
+
+	// the first window is the earliest aligned window whose end lies after the first observation
+	start := first.Truncate(step)
+	for start.Add(window - step).After(first) {
+		start = start.Add(-step)
+	}
+
+	var series []MetricWindow
+	for ; !start.After(last); start = start.Add(step) {
+		end := start.Add(window)
+		lo := sort.Search(len(ind), func(i int) bool { return !timestamps[ind[i]].Before(start) })
+		hi := sort.Search(len(ind), func(i int) bool { return !timestamps[ind[i]].Before(end) })
+
+		w := MetricWindow{Start: start, End: end, Observations: hi - lo, Value: math.NaN()}
+		if w.Observations > 0 {
+			w.Value = metric(selectValues(predictions, ind[lo:hi]), selectValues(labels, ind[lo:hi]))
+		}
+		series = append(series, w)
+	}
+
+	return series
+}
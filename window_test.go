@@ -0,0 +1,81 @@
+package datautils_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestWindowedMetric(t *testing.T) {
+	day := 24 * time.Hour
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	timestamps := []time.Time{
+		base.Add(2*day + time.Hour),
+		base.Add(time.Hour),
+		base.Add(2 * time.Hour),
+		base.Add(day + time.Hour),
+		base.Add(3*day + time.Hour),
+	}
+	predictions := []float64{0.9, 0.8, 0.2, 0.6, 0.1}
+	labels := []float64{1, 1, 0, 0, 1}
+
+	accuracy := func(predictions, labels []float64) float64 {
+		return datautils.NewConfusionMatrix(predictions, labels, 0.5).Accuracy()
+	}
+
+	tests := []struct {
+		name   string
+		window time.Duration
+		step   time.Duration
+		starts []time.Time
+		counts []int
+		values []float64
+	}{
+		{
+			name:   "tumbling",
+			window: day,
+			step:   day,
+			starts: []time.Time{base, base.Add(day), base.Add(2 * day), base.Add(3 * day)},
+			counts: []int{2, 1, 1, 1},
+			values: []float64{1, 0, 1, 0},
+		},
+		{
+			name:   "rolling",
+			window: 2 * day,
+			step:   day,
+			starts: []time.Time{base.Add(-day), base, base.Add(day), base.Add(2 * day), base.Add(3 * day)},
+			counts: []int{2, 3, 2, 2, 1},
+			values: []float64{1, 2.0 / 3.0, 0.5, 0.5, 0},
+		},
+		{
+			name:   "gap",
+			window: 12 * time.Hour,
+			step:   12 * time.Hour,
+			starts: []time.Time{base, base.Add(12 * time.Hour), base.Add(day), base.Add(36 * time.Hour), base.Add(2 * day), base.Add(60 * time.Hour), base.Add(3 * day)},
+			counts: []int{2, 0, 1, 0, 1, 0, 1},
+			values: []float64{1, math.NaN(), 0, math.NaN(), 1, math.NaN(), 0},
+		},
+	}
+
+	for _, test := range tests {
+		series := datautils.WindowedMetric(timestamps, predictions, labels, accuracy, test.window, test.step)
+
+		if len(series) != len(test.starts) {
+			t.Errorf("%s: Expected %d windows but received %d: %v", test.name, len(test.starts), len(series), series)
+			continue
+		}
+		for i, w := range series {
+			if !w.Start.Equal(test.starts[i]) || !w.End.Equal(test.starts[i].Add(test.window)) {
+				t.Errorf("%s: Expected window %d to start at %v but received %v - %v", test.name, i, test.starts[i], w.Start, w.End)
+			}
+			if w.Observations != test.counts[i] {
+				t.Errorf("%s: Expected window %d to contain %d observations but received %d", test.name, i, test.counts[i], w.Observations)
+			}
+			if !(w.Value == test.values[i] || math.IsNaN(w.Value) && math.IsNaN(test.values[i])) {
+				t.Errorf("%s: Expected window %d value: %v but received %v", test.name, i, test.values[i], w.Value)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package datautils
+
+// Workspace contains buffers that are reused across evaluations to avoid allocating new full length slices for
+// every evaluation e.g. when evaluating very large numbers of predictions or many evaluations in a loop.  The zero
+// value is ready to use and the buffers grow as required to fit the largest evaluation so far.  Evaluations
+// created with a Workspace share its buffers so are only valid until the Workspace is next used.  Large inputs
+// sorted in parallel (see ParallelThreshold) still allocate temporary buffers while merging.  A Workspace must
+// not be used concurrently by multiple goroutines.
+type Workspace struct {
+	scratch   []float64
+	precision []float64
+	recall    []float64
+	ind       []int
+	perfInd   []int
+}
+
+// growFloats returns s resliced to length n, allocating a new slice if the capacity of s is insufficient.
+func growFloats(s []float64, n int) []float64 {
+	if cap(s) < n {
+		return make([]float64, n)
+	}
+	return s[:n]
+}
+
+// growInts returns s resliced to length n, allocating a new slice if the capacity of s is insufficient.
+func growInts(s []int, n int) []int {
+	if cap(s) < n {
+		return make([]int, n)
+	}
+	return s[:n]
+}
+
+// PrecisionRecallCurve creates a new PrecisionRecallCurve in the same way as NewPrecisionRecallCurve but using
+// the Workspace's buffers rather than allocating new slices.  The Precision, Recall and Thresholds of the
+// returned curve refer to the buffers so are overwritten by the next use of the Workspace.
+func (w *Workspace) PrecisionRecallCurve(predictions, labels []float64) PrecisionRecallCurve {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+
+	var positives int
+	for _, v := range labels {
+		if v > 0 {
+			positives++
+		}
+	}
+
+	n := len(predictions)
+	w.precision, w.recall = growFloats(w.precision, n), growFloats(w.recall, n)
+	var sorted []float64
+	var ind []int
+	if positives > 0 {
+		sorted, ind = w.sort(predictions)
+	}
+	return precisionRecallCurve(sorted, ind, labels, positives, w.precision, w.recall)
+}
+
+// ROCCurve creates a new ROCCurve in the same way as NewROCCurve but sorting the predictions using the
+// Workspace's buffers rather than allocating new slices.  The points of the returned curve are newly allocated
+// and remain valid after the Workspace is reused.
+func (w *Workspace) ROCCurve(predictions, labels []float64) ROCCurve {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+
+	var positives, negatives float64
+	for _, v := range labels {
+		if v > 0 {
+			positives++
+		} else {
+			negatives++
+		}
+	}
+
+	sorted, ind := w.sort(predictions)
+	return rocCurve(sorted, ind, labels, positives, negatives)
+}
+
+// RankingEvaluation creates a new RankingEvaluation in the same way as NewRankingEvaluation but using the
+// Workspace's buffers rather than allocating new slices.  The PredictedRankInd and PerfectRankInd of the
+// returned evaluation refer to the buffers so are overwritten by the next use of the Workspace.
+func (w *Workspace) RankingEvaluation(predictions, labels []float64) RankingEvaluation {
+	if err := ValidateLengths(predictions, labels); err != nil {
+		panic(err)
+	}
+
+	n := len(predictions)
+	w.scratch, w.ind, w.perfInd = growFloats(w.scratch, n), growInts(w.ind, n), growInts(w.perfInd, n)
+	return rankingEvaluation(predictions, labels, w.scratch, w.ind, w.perfInd)
+}
+
+// sort sorts a copy of the predictions into ascending order in the Workspace's buffers in the same way as
+// sortedPredictions, returning the sorted predictions and their original indices.
+func (w *Workspace) sort(predictions []float64) ([]float64, []int) {
+	n := len(predictions)
+	w.scratch, w.ind = growFloats(w.scratch, n), growInts(w.ind, n)
+	copy(w.scratch, predictions)
+	argsort(w.scratch, w.ind)
+	return w.scratch, w.ind
+}
@@ -0,0 +1,56 @@
+package datautils_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/james-bowman/datautils"
+)
+
+func TestWorkspace(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var w datautils.Workspace
+
+	// evaluate decreasing as well as increasing sizes to exercise reuse of larger buffers
+	for _, n := range []int{10, 100, 5, 50} {
+		predictions := make([]float64, n)
+		labels := make([]float64, n)
+		for i := range predictions {
+			predictions[i] = rnd.Float64()
+			labels[i] = float64(rnd.Intn(2))
+		}
+
+		if pr, expected := w.PrecisionRecallCurve(predictions, labels), datautils.NewPrecisionRecallCurve(predictions, labels); !reflect.DeepEqual(pr, expected) {
+			t.Errorf("n=%d: Expected precision recall curve: %v but received %v", n, expected, pr)
+		}
+		if roc, expected := w.ROCCurve(predictions, labels), datautils.NewROCCurve(predictions, labels); !reflect.DeepEqual(roc, expected) {
+			t.Errorf("n=%d: Expected ROC curve: %v but received %v", n, expected, roc)
+		}
+		if r, expected := w.RankingEvaluation(predictions, labels), datautils.NewRankingEvaluation(predictions, labels); !reflect.DeepEqual(r, expected) {
+			t.Errorf("n=%d: Expected ranking evaluation: %v but received %v", n, expected, r)
+		}
+	}
+
+	if pr := w.PrecisionRecallCurve([]float64{0.5, 0.2}, []float64{0, 0}); !reflect.DeepEqual(pr.Precision, []float64{1}) || !reflect.DeepEqual(pr.Recall, []float64{0}) {
+		t.Errorf("Expected precision [1] and recall [0] without positives but received %v and %v", pr.Precision, pr.Recall)
+	}
+}
+
+func TestWorkspaceReusesBuffers(t *testing.T) {
+	predictions := []float64{0.9, 0.1, 0.4, 0.6}
+	labels := []float64{1, 0, 0, 1}
+
+	var w datautils.Workspace
+	first := w.PrecisionRecallCurve(predictions, labels)
+	second := w.PrecisionRecallCurve(predictions[:3], labels[:3])
+	if &first.Precision[0] != &second.Precision[0] || &first.Recall[0] != &second.Recall[0] {
+		t.Error("Expected precision recall curves to share the workspace buffers")
+	}
+
+	r1 := w.RankingEvaluation(predictions, labels)
+	r2 := w.RankingEvaluation(predictions, labels)
+	if &r1.PredictedRankInd[0] != &r2.PredictedRankInd[0] || &r1.PerfectRankInd[0] != &r2.PerfectRankInd[0] {
+		t.Error("Expected ranking evaluations to share the workspace buffers")
+	}
+}